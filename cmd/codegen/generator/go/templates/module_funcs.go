@@ -7,6 +7,7 @@ import (
 	"maps"
 	"strconv"
 	"strings"
+	"time"
 
 	. "github.com/dave/jennifer/jen" //nolint:stylecheck
 )
@@ -28,6 +29,15 @@ func (ps *parseState) parseGoFunc(parentType *types.Named, fn *types.Func) (*fun
 	}
 	spec.doc = funcDecl.Doc.Text()
 
+	docPragmas, docComment := parsePragmaComment(spec.doc)
+	if v, ok := docPragmas["cache"]; ok {
+		spec.doc = docComment
+		spec.cachePolicy, spec.cacheTimeToLive, err = parseCachePragma(v)
+		if err != nil {
+			return nil, fmt.Errorf("method %s: %w", fn.Name(), err)
+		}
+	}
+
 	sig, ok := fn.Type().(*types.Signature)
 	if !ok {
 		return nil, fmt.Errorf("expected method to be a func, got %T", fn.Type())
@@ -81,6 +91,11 @@ type funcTypeSpec struct {
 	name string
 	doc  string
 
+	// cachePolicy is the name of the FunctionCachePolicy enum value set by a
+	// +cache pragma, or empty for the default
+	cachePolicy     string
+	cacheTimeToLive string
+
 	argSpecs []paramSpec
 
 	returnSpec   ParsedType // nil if void return
@@ -109,6 +124,16 @@ func (spec *funcTypeSpec) TypeDefCode() (*Statement, error) {
 		fnTypeDefCode = dotLine(fnTypeDefCode, "WithDescription").Call(Lit(strings.TrimSpace(spec.doc)))
 	}
 
+	if spec.cachePolicy != "" {
+		cacheArgsCode := []Code{Id(spec.cachePolicy)}
+		if spec.cacheTimeToLive != "" {
+			cacheArgsCode = append(cacheArgsCode, Id("FunctionWithCachePolicyOpts").Values(
+				Id("TimeToLive").Op(":").Lit(spec.cacheTimeToLive),
+			))
+		}
+		fnTypeDefCode = dotLine(fnTypeDefCode, "WithCachePolicy").Call(cacheArgsCode...)
+	}
+
 	for _, argSpec := range spec.argSpecs {
 		if argSpec.isContext {
 			// ignore ctx arg
@@ -146,6 +171,22 @@ func (spec *funcTypeSpec) TypeDefCode() (*Statement, error) {
 	return fnTypeDefCode, nil
 }
 
+// parseCachePragma parses the value of a +cache pragma, which is either "pure",
+// "session", or a duration to reuse results for (e.g. "10m").
+func parseCachePragma(value string) (policy string, timeToLive string, err error) {
+	value = strings.Trim(strings.TrimSpace(value), `"`)
+	switch value {
+	case "pure":
+		return "PureCache", "", nil
+	case "session":
+		return "SessionCache", "", nil
+	}
+	if _, err := time.ParseDuration(value); err != nil {
+		return "", "", fmt.Errorf("invalid +cache value %q: must be pure, session or a duration", value)
+	}
+	return "TtlCache", value, nil
+}
+
 func (spec *funcTypeSpec) GoType() types.Type {
 	return spec.goType
 }
@@ -74,3 +74,28 @@ func TestParsePragmaComment(t *testing.T) {
 		})
 	}
 }
+
+func TestParseCachePragma(t *testing.T) {
+	tests := []struct {
+		value      string
+		policy     string
+		timeToLive string
+	}{
+		{value: "pure", policy: "PureCache"},
+		{value: "session", policy: "SessionCache"},
+		{value: "10m", policy: "TtlCache", timeToLive: "10m"},
+		{value: `"1h30m"`, policy: "TtlCache", timeToLive: "1h30m"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.value, func(t *testing.T) {
+			policy, timeToLive, err := parseCachePragma(test.value)
+			require.NoError(t, err)
+			require.Equal(t, test.policy, policy)
+			require.Equal(t, test.timeToLive, timeToLive)
+		})
+	}
+
+	_, _, err := parseCachePragma("forever")
+	require.ErrorContains(t, err, "invalid +cache value")
+}
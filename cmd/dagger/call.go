@@ -13,6 +13,8 @@ import (
 )
 
 var outputPath string
+var outputWipe bool
+var outputDryRun bool
 var jsonOutput bool
 
 var callCmd = &FuncCommand{
@@ -26,6 +28,13 @@ Providing the ´--output´ option (shorthand: ´-o´) is equivalent to calling
 ´export´ instead. To print a property of these core objects, continue chaining
 by appending it to the end of the command (for example, ´stdout´, ´entries´, or
 ´contents´).
+
+When exporting a Directory, ´--wipe´ also removes anything in the output path
+that isn't in the result, making it an exact copy. Add ´--dry-run´ to only print
+what would be added (A), modified (M) or deleted (D) instead.
+
+Functions with a pure or TTL cache policy may reuse results from previous
+sessions. Use ´--no-cache´ to call them again.
 `,
 		"´",
 		"`",
@@ -33,16 +42,41 @@ by appending it to the end of the command (for example, ´stdout´, ´entries´,
 	Example: strings.TrimSpace(`
 dagger call test
 dagger call build -o ./bin/myapp
+dagger call generate -o ./gen --wipe --dry-run
 dagger call lint stdout
 `,
 	),
 	Init: func(cmd *cobra.Command) {
 		cmd.PersistentFlags().BoolVar(&jsonOutput, "json", false, "Present result as JSON")
 		cmd.PersistentFlags().StringVarP(&outputPath, "output", "o", "", "Path in the host to save the result to")
+		cmd.PersistentFlags().BoolVar(&outputWipe, "wipe", false, "Remove anything in the output path that isn't in the resulting directory")
+		cmd.PersistentFlags().BoolVar(&outputDryRun, "dry-run", false, "Print the changes to the output path instead of saving the resulting directory")
+		cmd.PersistentFlags().StringVar(&junitOutput, "junit", "", "Path to write a JUnit XML report of the call to")
+		cmd.PersistentFlags().BoolVar(&noCache, "no-cache", false, "Call module functions again instead of reusing results from previous sessions")
 	},
 	OnSelectObjectLeaf: func(c *FuncCommand, name string) error {
+		if (outputWipe || outputDryRun) && name != Directory {
+			return fmt.Errorf("--wipe and --dry-run are only supported for a Directory, not %s", name)
+		}
 		switch name {
-		case Container, Directory, File:
+		case Directory:
+			if outputPath == "" {
+				if outputWipe || outputDryRun {
+					return fmt.Errorf("--wipe and --dry-run require --output")
+				}
+				c.Select("sync")
+				return nil
+			}
+			if outputDryRun {
+				c.Select("exportDryRun")
+			} else {
+				c.Select("export")
+			}
+			c.Arg("path", outputPath)
+			if outputWipe {
+				c.Arg("wipe", true)
+			}
+		case Container, File:
 			if outputPath != "" {
 				c.Select("export")
 				c.Arg("path", outputPath)
@@ -84,6 +118,19 @@ dagger call lint stdout
 			}
 			return attachToShell(cmd.Context(), c.c, termEndpoint)
 		case Container, Directory, File:
+			if outputDryRun {
+				changes, ok := response.([]any)
+				if !ok {
+					return fmt.Errorf("unexpected response %T: %+v", response, response)
+				}
+				if len(changes) == 0 {
+					cmd.PrintErrf("No changes to %q.\n", outputPath)
+				}
+				for _, change := range changes {
+					fmt.Fprintln(cmd.OutOrStdout(), change)
+				}
+				return nil
+			}
 			if outputPath != "" {
 				logOutputSuccess(cmd, outputPath)
 				return nil
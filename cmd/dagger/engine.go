@@ -56,11 +56,15 @@ type runClientCallback func(context.Context, *client.Client) error
 
 var useLegacyTUI = os.Getenv("_EXPERIMENTAL_DAGGER_LEGACY_TUI") != ""
 
+// noCache disables reusing module function results from previous sessions,
+// set by the --no-cache flag on commands that support it.
+var noCache bool
+
 func withEngineAndTUI(
 	ctx context.Context,
 	params client.Params,
 	fn runClientCallback,
-) error {
+) (rerr error) {
 	if params.RunnerHost == "" {
 		var err error
 		params.RunnerHost, err = engine.RunnerHost()
@@ -70,11 +74,31 @@ func withEngineAndTUI(
 	}
 
 	params.DisableHostRW = disableHostRW
+	params.NoCache = params.NoCache || noCache
 
 	if params.JournalFile == "" {
 		params.JournalFile = os.Getenv("_EXPERIMENTAL_DAGGER_JOURNAL")
 	}
 
+	if junitOutput != "" {
+		// the report is built from the journal once the session is closed, so
+		// write one to a temporary file if it's not kept anyway
+		if params.JournalFile == "" {
+			journal, err := os.CreateTemp("", "dagger-journal-*.json")
+			if err != nil {
+				return fmt.Errorf("create journal for junit report: %w", err)
+			}
+			journal.Close()
+			defer os.Remove(journal.Name())
+			params.JournalFile = journal.Name()
+		}
+		defer func() {
+			if err := writeJUnitFile(junitOutput, params.JournalFile, "dagger"); err != nil {
+				rerr = errors.Join(rerr, fmt.Errorf("write junit report: %w", err))
+			}
+		}()
+	}
+
 	if interactive {
 		return interactiveTUI(ctx, params, fn)
 	}
@@ -0,0 +1,252 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"dagger.io/dagger"
+	"github.com/dagger/dagger/engine/client"
+	"github.com/docker/go-units"
+	"github.com/juju/ansiterm/tabwriter"
+	"github.com/muesli/termenv"
+	"github.com/spf13/cobra"
+)
+
+var engineCacheFilters []string
+var engineKeepStorage string
+var engineOlderThan time.Duration
+var enginePruneAll bool
+
+var engineCmd = &cobra.Command{
+	Use:   "engine",
+	Short: "Manage the Dagger engine",
+}
+
+var engineDiskUsageCmd = &cobra.Command{
+	Use:   "du [flags]",
+	Short: "Show the disk space used by the engine's local cache",
+	Long: `Show the disk space used by the engine's local cache, and how much of it
+can be reclaimed by pruning, for each type of cache entry.`,
+	Example: strings.TrimSpace(`
+dagger engine du
+dagger engine du --filter type=exec.cachemount
+`,
+	),
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		return withEngineAndTUI(ctx, client.Params{}, func(ctx context.Context, engineClient *client.Client) error {
+			var res struct {
+				Engine struct {
+					LocalCache struct {
+						KeepBytes int
+						Entries   []engineCacheEntry
+					}
+				}
+			}
+			err := engineClient.Dagger().Do(ctx, &dagger.Request{
+				Query: `query EngineCacheEntries($filters: [String!]) {
+					engine {
+						localCache {
+							keepBytes
+							entries(filters: $filters) {
+								recordType
+								diskSpaceBytes
+								activelyUsed
+							}
+						}
+					}
+				}`,
+				Variables: map[string]any{
+					"filters": buildkitFilters(engineCacheFilters),
+				},
+			}, &dagger.Response{
+				Data: &res,
+			})
+			if err != nil {
+				return fmt.Errorf("list engine cache entries: %w", err)
+			}
+
+			cache := res.Engine.LocalCache
+			if err := printEngineCacheUsage(cmd.OutOrStdout(), cache.Entries); err != nil {
+				return err
+			}
+			if cache.KeepBytes > 0 {
+				fmt.Fprintf(cmd.OutOrStdout(), "\nGarbage collection keeps the cache under %s.\n", units.BytesSize(float64(cache.KeepBytes)))
+			}
+			return nil
+		})
+	},
+}
+
+var enginePruneCmd = &cobra.Command{
+	Use:   "prune [flags]",
+	Short: "Remove unused entries from the engine's local cache",
+	Long: `Remove entries that aren't in use from the engine's local cache, and show the
+disk space reclaimed for each type of cache entry.
+
+Either narrow down the entries to remove with --filter, --keep-storage or
+--older-than, or pass --all to remove every entry that isn't in use.`,
+	Example: strings.TrimSpace(`
+dagger engine prune --all
+dagger engine prune --filter type=exec.cachemount
+dagger engine prune --keep-storage 10GB --older-than 72h
+`,
+	),
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		narrowed := len(engineCacheFilters) > 0 || engineKeepStorage != "" || engineOlderThan > 0
+		if !narrowed && !enginePruneAll {
+			return fmt.Errorf("refusing to remove every unused cache entry: pass --all, or narrow it down with --filter, --keep-storage or --older-than")
+		}
+
+		var keepBytes int64
+		if engineKeepStorage != "" {
+			var err error
+			keepBytes, err = units.RAMInBytes(engineKeepStorage)
+			if err != nil {
+				return fmt.Errorf("invalid --keep-storage: %w", err)
+			}
+		}
+
+		ctx := cmd.Context()
+		return withEngineAndTUI(ctx, client.Params{}, func(ctx context.Context, engineClient *client.Client) error {
+			var res struct {
+				Engine struct {
+					LocalCache struct {
+						Prune []engineCacheEntry
+					}
+				}
+			}
+			err := engineClient.Dagger().Do(ctx, &dagger.Request{
+				Query: `query PruneEngineCache($all: Boolean, $filters: [String!], $keepBytes: Int, $olderThan: Int) {
+					engine {
+						localCache {
+							prune(all: $all, filters: $filters, keepBytes: $keepBytes, olderThan: $olderThan) {
+								recordType
+								diskSpaceBytes
+								activelyUsed
+							}
+						}
+					}
+				}`,
+				Variables: map[string]any{
+					"all":       enginePruneAll,
+					"filters":   buildkitFilters(engineCacheFilters),
+					"keepBytes": keepBytes,
+					"olderThan": int(engineOlderThan.Seconds()),
+				},
+			}, &dagger.Response{
+				Data: &res,
+			})
+			if err != nil {
+				return fmt.Errorf("prune engine cache: %w", err)
+			}
+
+			pruned := res.Engine.LocalCache.Prune
+			if len(pruned) == 0 {
+				fmt.Fprintln(cmd.OutOrStdout(), "Nothing to prune.")
+				return nil
+			}
+			return printEngineCacheUsage(cmd.OutOrStdout(), pruned)
+		})
+	},
+}
+
+func init() {
+	engineDiskUsageCmd.Flags().StringArrayVar(&engineCacheFilters, "filter", nil, "Only include cache entries matching the filter (e.g., type=exec.cachemount)")
+
+	enginePruneCmd.Flags().StringArrayVar(&engineCacheFilters, "filter", nil, "Only remove cache entries matching the filter (e.g., type=exec.cachemount)")
+	enginePruneCmd.Flags().StringVar(&engineKeepStorage, "keep-storage", "", "Keep cache entries until the cache uses at most this much disk space (e.g., 10GB)")
+	enginePruneCmd.Flags().DurationVar(&engineOlderThan, "older-than", 0, "Only remove cache entries that were not used for this long (e.g., 72h)")
+	enginePruneCmd.Flags().BoolVar(&enginePruneAll, "all", false, "Remove every cache entry that isn't in use")
+
+	engineCmd.AddCommand(engineDiskUsageCmd, enginePruneCmd)
+	rootCmd.AddCommand(engineCmd)
+}
+
+type engineCacheEntry struct {
+	RecordType     string
+	DiskSpaceBytes int
+	ActivelyUsed   bool
+}
+
+// engineCacheUsage is the disk space used by the cache entries of a type.
+type engineCacheUsage struct {
+	RecordType  string
+	Entries     int
+	Size        int
+	Reclaimable int
+}
+
+func summarizeEngineCache(entries []engineCacheEntry) (usages []*engineCacheUsage, total engineCacheUsage) {
+	byType := map[string]*engineCacheUsage{}
+	for _, entry := range entries {
+		usage, found := byType[entry.RecordType]
+		if !found {
+			usage = &engineCacheUsage{RecordType: entry.RecordType}
+			byType[entry.RecordType] = usage
+			usages = append(usages, usage)
+		}
+		usage.Entries++
+		usage.Size += entry.DiskSpaceBytes
+		total.Entries++
+		total.Size += entry.DiskSpaceBytes
+		if !entry.ActivelyUsed {
+			usage.Reclaimable += entry.DiskSpaceBytes
+			total.Reclaimable += entry.DiskSpaceBytes
+		}
+	}
+	sort.SliceStable(usages, func(i, j int) bool {
+		return usages[i].Size > usages[j].Size
+	})
+	return usages, total
+}
+
+func printEngineCacheUsage(w io.Writer, entries []engineCacheEntry) error {
+	usages, total := summarizeEngineCache(entries)
+
+	tw := tabwriter.NewWriter(w, 0, 0, 3, ' ', 0)
+	fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t\n",
+		termenv.String("Type").Bold(),
+		termenv.String("Entries").Bold(),
+		termenv.String("Size").Bold(),
+		termenv.String("Reclaimable").Bold(),
+	)
+	for _, usage := range append(usages, &total) {
+		recordType := usage.RecordType
+		if usage == &total {
+			recordType = "total"
+		}
+		fmt.Fprintf(tw, "%s\t%d\t%s\t%s\t\n",
+			recordType,
+			usage.Entries,
+			units.BytesSize(float64(usage.Size)),
+			units.BytesSize(float64(usage.Reclaimable)),
+		)
+	}
+	return tw.Flush()
+}
+
+// buildkitFilters converts filters like "type=exec.cachemount" to the
+// "type==exec.cachemount" form that buildkit expects, joined into a single
+// filter so that entries have to match all of them rather than any.
+func buildkitFilters(filters []string) []string {
+	if len(filters) == 0 {
+		return []string{}
+	}
+	converted := make([]string, 0, len(filters))
+	for _, f := range filters {
+		if !strings.Contains(f, "==") && !strings.Contains(f, "!=") && !strings.Contains(f, "~=") {
+			f = strings.Replace(f, "=", "==", 1)
+		}
+		converted = append(converted, f)
+	}
+	return []string{strings.Join(converted, ",")}
+}
@@ -0,0 +1,16 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildkitFilters(t *testing.T) {
+	require.Equal(t, []string{}, buildkitFilters(nil))
+	require.Equal(t, []string{"type==exec.cachemount"}, buildkitFilters([]string{"type=exec.cachemount"}))
+	require.Equal(t,
+		[]string{"type==exec.cachemount,description~=go"},
+		buildkitFilters([]string{"type=exec.cachemount", "description~=go"}),
+	)
+}
@@ -284,6 +284,7 @@ const (
 	envSecretSource     = "env"
 	fileSecretSource    = "file"
 	commandSecretSource = "cmd"
+	engineSecretSource  = "engine"
 )
 
 func (v *secretValue) Type() string {
@@ -308,6 +309,16 @@ func (v *secretValue) String() string {
 }
 
 func (v *secretValue) Get(ctx context.Context, c *dagger.Client) (any, error) {
+	if v.secretSource == engineSecretSource {
+		// e.g. `engine:vault://secret/data/ci#token` is resolved by a secret
+		// provider in the engine rather than here; unlike `env:`, `file:` and
+		// `cmd:`, `engine:env://TOKEN` reads the engine's environment
+		uri := v.sourceVal
+		hash := sha256.Sum256([]byte(uri))
+		secretName := hex.EncodeToString(hash[:])
+		return c.Secret(secretName, dagger.SecretOpts{URI: uri}), nil
+	}
+
 	var plaintext string
 
 	switch v.secretSource {
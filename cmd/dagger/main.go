@@ -58,6 +58,8 @@ func init() {
 		configCmd,
 		moduleInitCmd,
 		moduleInstallCmd,
+		moduleUninstallCmd,
+		moduleUpdateCmd,
 		moduleDevelopCmd,
 		modulePublishCmd,
 		sessionCmd(),
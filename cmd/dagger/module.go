@@ -1,9 +1,11 @@
 package main
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 	"net/url"
 	"os"
@@ -20,6 +22,7 @@ import (
 	"github.com/iancoleman/strcase"
 	"github.com/juju/ansiterm/tabwriter"
 	"github.com/moby/buildkit/util/gitutil"
+	"github.com/pmezard/go-difflib/difflib"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
 	"github.com/vito/progrock"
@@ -46,6 +49,8 @@ var (
 	developSourcePath string
 
 	force bool
+
+	frozen bool
 )
 
 const (
@@ -57,6 +62,7 @@ const (
 func init() {
 	moduleFlags.StringVarP(&moduleURL, "mod", "m", "", "Path to dagger.json config file for the module or a directory containing that file. Either local path (e.g. \"/path/to/some/dir\") or a github repo (e.g. \"github.com/dagger/dagger/path/to/some/subdir\")")
 	moduleFlags.BoolVar(&focus, "focus", true, "Only show output for focused commands")
+	moduleFlags.BoolVar(&frozen, "frozen", false, "Fail if any git dependency of the module, including transitive ones, is not pinned to a commit")
 
 	listenCmd.PersistentFlags().AddFlagSet(moduleFlags)
 	queryCmd.PersistentFlags().AddFlagSet(moduleFlags)
@@ -73,6 +79,10 @@ func init() {
 	moduleInstallCmd.Flags().StringVarP(&installName, "name", "n", "", "Name to use for the dependency in the module. Defaults to the name of the module being installed.")
 	moduleInstallCmd.Flags().AddFlagSet(moduleFlags)
 
+	moduleUpdateCmd.Flags().AddFlagSet(moduleFlags)
+
+	moduleUninstallCmd.Flags().AddFlagSet(moduleFlags)
+
 	moduleDevelopCmd.Flags().StringVar(&developSDK, "sdk", "", "New SDK for the module")
 	moduleDevelopCmd.Flags().StringVar(&developSourcePath, "source", "", "Directory to store the module implementation source code in")
 	moduleDevelopCmd.PersistentFlags().AddFlagSet(moduleFlags)
@@ -266,7 +276,7 @@ var moduleInstallCmd = &cobra.Command{
 			}
 
 			depRefStr := extraArgs[0]
-			depSrc := dag.ModuleSource(depRefStr)
+			depSrc := dag.ModuleSource(localModuleRef(depRefStr, depRefStr))
 			depSrcKind, err := depSrc.Kind(ctx)
 			if err != nil {
 				return fmt.Errorf("failed to get module ref kind: %w", err)
@@ -282,7 +292,7 @@ var moduleInstallCmd = &cobra.Command{
 					return fmt.Errorf("failed to get dep relative path: %w", err)
 				}
 
-				depSrc = dag.ModuleSource(depRelPath)
+				depSrc = dag.ModuleSource(localModuleRef(depRelPath, depAbsPath))
 			}
 			dep := dag.ModuleDependency(depSrc, dagger.ModuleDependencyOpts{
 				Name: installName,
@@ -357,6 +367,139 @@ var moduleInstallCmd = &cobra.Command{
 	},
 }
 
+var moduleUpdateCmd = &cobra.Command{
+	Use:   "update [flags] [NAME...]",
+	Short: "Update the pinned versions of a Dagger module's dependencies",
+	Long: `Update the pinned versions of a Dagger module's dependencies.
+
+Git dependencies are pinned to the commit their source ref resolved to when
+they were installed. This command resolves the source refs of the named
+dependencies again, or of all dependencies if no name is given, and updates
+their pins in the module's config.`,
+	Example: "dagger update hello",
+	GroupID: moduleGroup.ID,
+	RunE: func(cmd *cobra.Command, extraArgs []string) (rerr error) {
+		ctx := cmd.Context()
+		return withEngineAndTUI(ctx, client.Params{}, func(ctx context.Context, engineClient *client.Client) (err error) {
+			dag := engineClient.Dagger()
+			modConf, err := getDefaultModuleConfiguration(ctx, dag, false)
+			if err != nil {
+				return fmt.Errorf("failed to get configured module: %w", err)
+			}
+			if modConf.SourceKind != dagger.LocalSource {
+				return fmt.Errorf("module must be local")
+			}
+			if !modConf.FullyInitialized() {
+				return fmt.Errorf("module must be fully initialized")
+			}
+
+			depNames := extraArgs
+			if len(depNames) == 0 {
+				configPath := filepath.Join(modConf.LocalRootSourcePath, modules.Filename)
+				contents, err := os.ReadFile(configPath)
+				if err != nil {
+					return fmt.Errorf("failed to read %s: %w", configPath, err)
+				}
+				var modCfg modules.ModuleConfig
+				if err := json.Unmarshal(contents, &modCfg); err != nil {
+					return fmt.Errorf("failed to unmarshal %s: %s", configPath, err)
+				}
+				for _, dep := range modCfg.Dependencies {
+					if dep.Name != "" {
+						depNames = append(depNames, dep.Name)
+					}
+				}
+			}
+
+			configPath := filepath.Join(modConf.LocalRootSourcePath, modules.Filename)
+			oldConfig, err := os.ReadFile(configPath)
+			if err != nil {
+				return fmt.Errorf("failed to read %s: %w", configPath, err)
+			}
+
+			_, err = modConf.Source.
+				WithUpdateDependencies(depNames).
+				ResolveFromCaller().
+				AsModule().
+				GeneratedContextDiff().
+				Export(ctx, modConf.LocalContextPath)
+			if err != nil {
+				return fmt.Errorf("failed to update dependencies: %w", err)
+			}
+
+			return printConfigDiff(cmd.OutOrStdout(), configPath, oldConfig)
+		})
+	},
+}
+
+var moduleUninstallCmd = &cobra.Command{
+	Use:     "uninstall [flags] NAME...",
+	Short:   "Remove a dependency from a Dagger module",
+	Long:    "Remove dependencies from a local module by name, regenerating the module's code without them.",
+	Example: "dagger uninstall hello",
+	GroupID: moduleGroup.ID,
+	Args:    cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, extraArgs []string) (rerr error) {
+		ctx := cmd.Context()
+		return withEngineAndTUI(ctx, client.Params{}, func(ctx context.Context, engineClient *client.Client) (err error) {
+			dag := engineClient.Dagger()
+			modConf, err := getDefaultModuleConfiguration(ctx, dag, false)
+			if err != nil {
+				return fmt.Errorf("failed to get configured module: %w", err)
+			}
+			if modConf.SourceKind != dagger.LocalSource {
+				return fmt.Errorf("module must be local")
+			}
+			if !modConf.FullyInitialized() {
+				return fmt.Errorf("module must be fully initialized")
+			}
+
+			configPath := filepath.Join(modConf.LocalRootSourcePath, modules.Filename)
+			oldConfig, err := os.ReadFile(configPath)
+			if err != nil {
+				return fmt.Errorf("failed to read %s: %w", configPath, err)
+			}
+
+			_, err = modConf.Source.
+				WithoutDependencies(extraArgs).
+				ResolveFromCaller().
+				AsModule().
+				GeneratedContextDiff().
+				Export(ctx, modConf.LocalContextPath)
+			if err != nil {
+				return fmt.Errorf("failed to uninstall dependencies: %w", err)
+			}
+
+			return printConfigDiff(cmd.OutOrStdout(), configPath, oldConfig)
+		})
+	},
+}
+
+// printConfigDiff prints a unified diff of the module config at configPath
+// against its previous contents.
+func printConfigDiff(w io.Writer, configPath string, oldConfig []byte) error {
+	newConfig, err := os.ReadFile(configPath)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", configPath, err)
+	}
+	if bytes.Equal(oldConfig, newConfig) {
+		fmt.Fprintf(w, "%s is up to date\n", modules.Filename)
+		return nil
+	}
+	diff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(string(oldConfig)),
+		B:        difflib.SplitLines(string(newConfig)),
+		FromFile: "a/" + modules.Filename,
+		ToFile:   "b/" + modules.Filename,
+		Context:  3,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to diff %s: %w", configPath, err)
+	}
+	_, err = fmt.Fprint(w, diff)
+	return err
+}
+
 var moduleDevelopCmd = &cobra.Command{
 	Use:   "develop",
 	Short: "Setup or update all the resources needed to develop on a module locally",
@@ -602,6 +745,23 @@ func (c *configuredModule) FullyInitialized() bool {
 	return c.ModuleSourceConfigExists
 }
 
+// localModuleRef returns the ref to load the module at the given local path
+// by. A relative ref without a version that starts with a hostname, like
+// my.app/mod, is taken for a git ref unless it's explicitly local, so it's
+// prefixed with ./ if the path exists.
+func localModuleRef(refStr string, localPath string) string {
+	if filepath.IsAbs(refStr) || strings.HasPrefix(refStr, ".") || strings.ContainsAny(refStr, "@:") {
+		return refStr
+	}
+	if host, _, ok := strings.Cut(refStr, "/"); !ok || !strings.Contains(host, ".") {
+		return refStr
+	}
+	if _, err := os.Stat(localPath); err != nil {
+		return refStr
+	}
+	return "./" + refStr
+}
+
 func getExplicitModuleSourceRef() (string, bool) {
 	if moduleURL != "" {
 		return moduleURL, true
@@ -639,10 +799,38 @@ func getModuleConfigurationForSourceRef(
 	dag *dagger.Client,
 	srcRefStr string,
 	resolveFromCaller bool,
+) (*configuredModule, error) {
+	conf, err := loadModuleConfigurationForSourceRef(ctx, dag, srcRefStr, "", resolveFromCaller)
+	if err != nil {
+		return nil, err
+	}
+	if frozen && conf.ModuleSourceConfigExists {
+		src := conf.Source
+		if conf.SourceKind == dagger.LocalSource && !resolveFromCaller {
+			src = src.ResolveFromCaller()
+		}
+		if err := checkFrozenDependencies(ctx, dag, src, map[string]struct{}{}); err != nil {
+			return nil, err
+		}
+	}
+	return conf, nil
+}
+
+func loadModuleConfigurationForSourceRef(
+	ctx context.Context,
+	dag *dagger.Client,
+	srcRefStr string,
+	srcRefPin string,
+	resolveFromCaller bool,
 ) (*configuredModule, error) {
 	conf := &configuredModule{}
 
-	conf.Source = dag.ModuleSource(srcRefStr)
+	if srcRefPin == "" {
+		srcRefStr = localModuleRef(srcRefStr, srcRefStr)
+	}
+	conf.Source = dag.ModuleSource(srcRefStr, dagger.ModuleSourceOpts{
+		RefPin: srcRefPin,
+	})
 	var err error
 	conf.SourceKind, err = conf.Source.Kind(ctx)
 	if err != nil {
@@ -671,13 +859,17 @@ func getModuleConfigurationForSourceRef(
 				return nil, fmt.Errorf("failed to unmarshal %s: %s", configPath, err)
 			}
 			if namedDep, ok := modCfg.DependencyByName(srcRefStr); ok {
-				src := dag.ModuleSource(namedDep.Source)
+				src := dag.ModuleSource(localModuleRef(namedDep.Source, filepath.Join(defaultConfigDir, namedDep.Source)))
 				kind, err := src.Kind(ctx)
 				if err != nil {
 					return nil, err
 				}
 				if kind == dagger.GitSource {
-					return getModuleConfigurationForSourceRef(ctx, dag, namedDep.Source, resolveFromCaller)
+					version, err := src.AsGitSource().Version(ctx)
+					if err != nil {
+						return nil, err
+					}
+					return loadModuleConfigurationForSourceRef(ctx, dag, namedDep.Source, namedDep.PinFor(version), resolveFromCaller)
 				}
 				depPath := filepath.Join(defaultConfigDir, namedDep.Source)
 				srcRefStr = depPath
@@ -698,7 +890,7 @@ func getModuleConfigurationForSourceRef(
 		if err := os.MkdirAll(srcRefStr, 0755); err != nil {
 			return nil, fmt.Errorf("failed to create directory for %s: %w", srcRefStr, err)
 		}
-		conf.Source = dag.ModuleSource(srcRefStr)
+		conf.Source = dag.ModuleSource(localModuleRef(srcRefStr, srcRefStr))
 
 		conf.LocalContextPath, err = conf.Source.ResolveContextPathFromCaller(ctx)
 		if err != nil {
@@ -720,6 +912,71 @@ func getModuleConfigurationForSourceRef(
 	return conf, nil
 }
 
+// checkFrozenDependencies returns an error if any git dependency of the
+// module source, including transitive ones, is not pinned in the config of
+// the module depending on it, or its pin was resolved from another version.
+func checkFrozenDependencies(ctx context.Context, dag *dagger.Client, src *dagger.ModuleSource, seen map[string]struct{}) error {
+	refStr, err := src.AsString(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get module source ref: %w", err)
+	}
+	if _, ok := seen[refStr]; ok {
+		return nil
+	}
+	seen[refStr] = struct{}{}
+
+	rootSubpath, err := src.SourceRootSubpath(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get module source root subpath: %w", err)
+	}
+	contents, err := src.ContextDirectory().File(filepath.Join(rootSubpath, modules.Filename)).Contents(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to read module config of %s: %w", refStr, err)
+	}
+	var modCfg modules.ModuleConfig
+	if err := json.Unmarshal([]byte(contents), &modCfg); err != nil {
+		return fmt.Errorf("failed to unmarshal module config of %s: %w", refStr, err)
+	}
+
+	deps, err := src.Dependencies(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get dependencies of %s: %w", refStr, err)
+	}
+	for _, dep := range deps {
+		name, err := dep.Name(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to get dependency name: %w", err)
+		}
+		depCfg, ok := modCfg.DependencyByName(name)
+		if !ok {
+			return fmt.Errorf("dependency %q of module %s is not in its config", name, refStr)
+		}
+		// deps local to the module's context (which may itself be a git
+		// repo) are pinned along with it
+		depSrc := dag.ModuleSource(depCfg.Source)
+		kind, err := depSrc.Kind(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to get dependency %q source kind: %w", name, err)
+		}
+		if kind == dagger.GitSource {
+			if depCfg.Pin == "" {
+				return fmt.Errorf("dependency %q of module %s is not pinned, run `dagger update` to pin it", name, refStr)
+			}
+			version, err := depSrc.AsGitSource().Version(ctx)
+			if err != nil {
+				return fmt.Errorf("failed to get dependency %q version: %w", name, err)
+			}
+			if depCfg.PinFor(version) == "" {
+				return fmt.Errorf("dependency %q of module %s is pinned to version %q but requires %q, run `dagger update` to pin it again", name, refStr, depCfg.PinVersion, version)
+			}
+		}
+		if err := checkFrozenDependencies(ctx, dag, dep.Source(), seen); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func findUp(curDirPath string) (string, bool, error) {
 	if !filepath.IsAbs(curDirPath) {
 		return "", false, fmt.Errorf("path is not absolute: %s", curDirPath)
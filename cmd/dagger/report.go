@@ -0,0 +1,110 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/dagger/dagger/telemetry"
+	"github.com/spf13/cobra"
+	"github.com/vito/progrock"
+)
+
+// junitOutput is the path to write a JUnit XML report of the session to, set
+// by the --junit flag on commands that support it.
+var junitOutput string
+
+var reportJUnitOutput string
+var reportJUnitName string
+
+var reportCmd = &cobra.Command{
+	Use:   "report",
+	Short: "Generate reports from progress journals",
+}
+
+var reportJUnitCmd = &cobra.Command{
+	Use:   "junit [flags] JOURNAL",
+	Short: "Convert a progress journal to a JUnit XML report",
+	Long: strings.ReplaceAll(`Convert a progress journal to a JUnit XML report.
+
+Each pipeline becomes a test suite, and each of its steps becomes a test case
+with its duration, error and logs. Journals are written when the
+´_EXPERIMENTAL_DAGGER_JOURNAL´ environment variable is set.
+`,
+		"´",
+		"`",
+	),
+	Example: strings.TrimSpace(`
+_EXPERIMENTAL_DAGGER_JOURNAL=journal.json dagger call test
+dagger report junit journal.json -o report.xml
+`,
+	),
+	Args:         cobra.ExactArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if reportJUnitOutput == "" {
+			return writeJUnit(cmd.OutOrStdout(), args[0], reportJUnitName)
+		}
+		return writeJUnitFile(reportJUnitOutput, args[0], reportJUnitName)
+	},
+}
+
+func init() {
+	reportJUnitCmd.Flags().StringVarP(&reportJUnitOutput, "output", "o", "", "Path to write the report to, instead of stdout")
+	reportJUnitCmd.Flags().StringVar(&reportJUnitName, "name", "dagger", "Name of the report, also used for steps outside of any pipeline")
+
+	reportCmd.AddCommand(reportJUnitCmd)
+	rootCmd.AddCommand(reportCmd)
+}
+
+// writeJUnitFile converts the journal to a JUnit XML report at path, creating
+// the parent directories if needed.
+func writeJUnitFile(path, journal, name string) (rerr error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		rerr = errors.Join(rerr, f.Close())
+	}()
+	return writeJUnit(f, journal, name)
+}
+
+func writeJUnit(w io.Writer, journal, name string) error {
+	junit := telemetry.NewJUnit()
+	if err := readJournal(journal, junit); err != nil {
+		return err
+	}
+	return junit.WriteXML(w, name)
+}
+
+// readJournal sends every update in the journal to the writer.
+func readJournal(journal string, w progrock.Writer) error {
+	f, err := os.Open(journal)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	dec := json.NewDecoder(f)
+	for {
+		var update progrock.StatusUpdate
+		if err := dec.Decode(&update); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return fmt.Errorf("read journal %s: %w", journal, err)
+		}
+		if err := w.WriteStatus(&update); err != nil {
+			return err
+		}
+	}
+	return w.Close()
+}
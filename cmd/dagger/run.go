@@ -68,6 +68,10 @@ func init() {
 	)
 
 	runCmd.Flags().BoolVar(&runFocus, "focus", false, "Only show output for focused commands.")
+
+	runCmd.Flags().StringVar(&junitOutput, "junit", "", "Path to write a JUnit XML report of the session to")
+
+	runCmd.Flags().BoolVar(&noCache, "no-cache", false, "Call module functions again instead of reusing results from previous sessions")
 }
 
 func Run(cmd *cobra.Command, args []string) {
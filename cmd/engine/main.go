@@ -22,11 +22,14 @@ import (
 	"github.com/containerd/containerd/remotes/docker"
 	"github.com/containerd/containerd/sys"
 	sddaemon "github.com/coreos/go-systemd/v22/daemon"
+	"github.com/dagger/dagger/core/secretprovider"
+	"github.com/dagger/dagger/dagql"
 	"github.com/dagger/dagger/engine/cache"
 	"github.com/dagger/dagger/engine/server"
 	"github.com/dagger/dagger/network"
 	"github.com/dagger/dagger/network/netinst"
 	"github.com/docker/docker/pkg/reexec"
+	units "github.com/docker/go-units"
 	"github.com/gofrs/flock"
 	grpc_middleware "github.com/grpc-ecosystem/go-grpc-middleware"
 	"github.com/moby/buildkit/cache/remotecache"
@@ -221,6 +224,23 @@ func main() { //nolint:gocyclo
 			Usage: "address range to use for networked containers",
 			Value: network.DefaultCIDR,
 		},
+		cli.StringFlag{
+			Name:  "dagql-cache",
+			Usage: "implementation of the per-session API result cache (unbounded, lru)",
+			Value: dagqlCacheUnbounded,
+		},
+		cli.IntFlag{
+			Name:  "dagql-cache-max-entries",
+			Usage: "maximum number of API results kept by the lru cache (0 for no limit)",
+		},
+		cli.StringFlag{
+			Name:  "dagql-cache-max-size",
+			Usage: "maximum estimated memory used by the lru cache, e.g. 512MiB (empty for no limit)",
+		},
+		cli.StringSliceFlag{
+			Name:  "secret-provider",
+			Usage: "allows clients to reference secrets by URI from an engine-side provider (env, file, cmd, vault, op, sops)",
+		},
 	)
 	app.Flags = append(app.Flags, appFlags...)
 
@@ -814,6 +834,16 @@ func newController(ctx context.Context, c *cli.Context, cfg *config.Config) (*se
 		},
 	}
 
+	dagqlCacheOpts, err := dagqlCacheOptsFromFlags(c)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	secretProviders, err := secretprovider.Enabled(c.GlobalStringSlice("secret-provider"))
+	if err != nil {
+		return nil, nil, err
+	}
+
 	bklog.G(context.Background()).Debugf("engine name: %s", engineName)
 	ctrler, err := server.NewBuildkitController(server.BuildkitControllerOpts{
 		WorkerController:       wc,
@@ -828,6 +858,8 @@ func newController(ctx context.Context, c *cli.Context, cfg *config.Config) (*se
 		UpstreamCacheExporters: remoteCacheExporterFuncs,
 		UpstreamCacheImporters: remoteCacheImporterFuncs,
 		DNSConfig:              getDNSConfig(cfg.DNS),
+		DagqlCacheOpts:         dagqlCacheOpts,
+		SecretProviders:        secretProviders,
 	})
 	if err != nil {
 		return nil, nil, err
@@ -836,6 +868,32 @@ func newController(ctx context.Context, c *cli.Context, cfg *config.Config) (*se
 	return ctrler, cacheManager, nil
 }
 
+const (
+	dagqlCacheUnbounded = "unbounded"
+	dagqlCacheLRU       = "lru"
+)
+
+func dagqlCacheOptsFromFlags(c *cli.Context) (*dagql.LRUCacheOpts, error) {
+	switch kind := c.GlobalString("dagql-cache"); kind {
+	case "", dagqlCacheUnbounded:
+		return nil, nil
+	case dagqlCacheLRU:
+		opts := &dagql.LRUCacheOpts{
+			MaxEntries: c.GlobalInt("dagql-cache-max-entries"),
+		}
+		if size := c.GlobalString("dagql-cache-max-size"); size != "" {
+			maxBytes, err := units.RAMInBytes(size)
+			if err != nil {
+				return nil, fmt.Errorf("invalid dagql-cache-max-size %q: %w", size, err)
+			}
+			opts.MaxBytes = maxBytes
+		}
+		return opts, nil
+	default:
+		return nil, fmt.Errorf("unknown dagql-cache %q", kind)
+	}
+}
+
 func resolverFunc(cfg *config.Config) docker.RegistryHosts {
 	return resolver.NewRegistryConfig(cfg.Registries)
 }
@@ -39,10 +39,22 @@ const (
 	metaMountPath = "/.dagger_meta_mount"
 	stdinPath     = metaMountPath + "/stdin"
 	exitCodePath  = metaMountPath + "/exitCode"
+	timeoutPath   = metaMountPath + "/timeout"
 	runcPath      = "/usr/local/bin/runc"
-	shimPath      = "/_shim"
+	shimPath      = core.ShimPath
 
 	errorExitCode = 125
+
+	// timeoutExitCode is the exit code of commands killed for exceeding their
+	// timeout, matching timeout(1).
+	timeoutExitCode = 124
+
+	// timeoutWaitDelay is how long to wait for the output of a command killed
+	// for exceeding its timeout, e.g. if a child process still holds stdout.
+	timeoutWaitDelay = 10 * time.Second
+
+	// cpuPeriod is the CFS scheduler period CPU quotas are enforced over.
+	cpuPeriod = 100000
 )
 
 var (
@@ -98,6 +110,12 @@ func internalCommand() int {
 			return errorExitCode
 		}
 		return 0
+	case "probe":
+		if err := probe(args); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		return 0
 	case "tunnel":
 		if err := tunnel(args); err != nil {
 			fmt.Fprintln(os.Stderr, err)
@@ -138,6 +156,49 @@ func check(args []string) error {
 	return nil
 }
 
+// probe makes a single attempt at a service health check from inside the
+// service's container. Retries are driven by the engine.
+func probe(args []string) error {
+	if len(args) < 2 {
+		return fmt.Errorf("usage: probe http <url> [status] | probe tcp <addr>")
+	}
+
+	switch kind, target := args[0], args[1]; kind {
+	case "http":
+		expectStatus := http.StatusOK
+		if len(args) > 2 {
+			var err error
+			expectStatus, err = strconv.Atoi(args[2])
+			if err != nil {
+				return fmt.Errorf("invalid status %q: %w", args[2], err)
+			}
+		}
+		resp, err := http.Get(target) //nolint:gosec
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+		fmt.Println("GET", target, resp.Status)
+		if resp.StatusCode != expectStatus {
+			body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+			if len(body) > 0 {
+				fmt.Println(string(body))
+			}
+			return fmt.Errorf("expected status %d, got %d", expectStatus, resp.StatusCode)
+		}
+		return nil
+	case "tcp":
+		conn, err := net.DialTimeout("tcp", target, time.Second)
+		if err != nil {
+			return err
+		}
+		fmt.Println("connected to", conn.RemoteAddr())
+		return conn.Close()
+	default:
+		return fmt.Errorf("unknown probe: %s", kind)
+	}
+}
+
 func pollForPort(network, addr string) (string, error) {
 	retry := backoff.NewExponentialBackOff()
 	retry.InitialInterval = 100 * time.Millisecond
@@ -185,7 +246,27 @@ func shim() (returnExitCode int) {
 		args = os.Args[2:]
 	}
 
-	cmd := exec.Command(name, args...)
+	var timeout time.Duration
+	if timeoutVal, found := internalEnv("_DAGGER_TIMEOUT"); found {
+		secs, err := strconv.Atoi(timeoutVal)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "invalid timeout %q: %v\n", timeoutVal, err)
+			return errorExitCode
+		}
+		timeout = time.Duration(secs) * time.Second
+		var cancelTimeout context.CancelFunc
+		ctx, cancelTimeout = context.WithTimeout(ctx, timeout)
+		defer cancelTimeout()
+	}
+
+	cmd := exec.CommandContext(ctx, name, args...)
+	if timeout > 0 {
+		cmd.WaitDelay = timeoutWaitDelay
+	}
+	expect, found := internalEnv("_DAGGER_EXPECT")
+	if !found {
+		expect = string(core.ReturnSuccess)
+	}
 	_, isTTY := internalEnv(core.ShimEnableTTYEnvVar)
 	if isTTY {
 		// Re-enable onlcr now that we're in the container.
@@ -289,20 +370,46 @@ func shim() (returnExitCode int) {
 	}
 
 	exitCode := 0
-	if err := runWithNesting(ctx, cmd); err != nil {
+	err := runWithNesting(ctx, cmd)
+	if timeout > 0 && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		fmt.Fprintf(os.Stderr, "command timed out after %s\n", timeout)
+		if err := os.WriteFile(timeoutPath, []byte(strconv.Itoa(int(timeout.Seconds()))), 0o600); err != nil {
+			panic(err)
+		}
+		writeExitCode(timeoutExitCode)
+		return timeoutExitCode
+	}
+	if err != nil {
 		if exiterr, ok := err.(*exec.ExitError); ok {
 			exitCode = exiterr.ExitCode()
 		} else {
-			exitCode = errorExitCode
+			// the command couldn't be run at all, which is never expected
 			fmt.Fprintln(os.Stderr, err.Error())
+			writeExitCode(errorExitCode)
+			return errorExitCode
 		}
 	}
 
+	writeExitCode(exitCode)
+
+	switch core.ReturnType(expect) {
+	case core.ReturnAny:
+		return 0
+	case core.ReturnFailure:
+		if exitCode == 0 {
+			fmt.Fprintln(os.Stderr, "expected a non-zero exit code, but the command succeeded")
+			return errorExitCode
+		}
+		return 0
+	default:
+		return exitCode
+	}
+}
+
+func writeExitCode(exitCode int) {
 	if err := os.WriteFile(exitCodePath, []byte(fmt.Sprintf("%d", exitCode)), 0o600); err != nil {
 		panic(err)
 	}
-
-	return exitCode
 }
 
 func setupBundle() int {
@@ -493,6 +600,14 @@ func setupBundle() int {
 				fmt.Fprintln(os.Stderr, "host alias:", err)
 				return errorExitCode
 			}
+		case strings.HasPrefix(env, "_DAGGER_MEMORY_LIMIT="),
+			strings.HasPrefix(env, "_DAGGER_CPU_QUOTA="),
+			strings.HasPrefix(env, "_DAGGER_PIDS_LIMIT="):
+			// NB: don't keep these env vars, they're applied to the spec
+			if err := applyResourceLimit(&spec, env); err != nil {
+				fmt.Fprintln(os.Stderr, "resource limit:", err)
+				return errorExitCode
+			}
 		case strings.HasPrefix(env, "_EXPERIMENTAL_DAGGER_GPU_PARAMS"):
 			splits := strings.Split(env, "=")
 			gpuParams = splits[1]
@@ -546,6 +661,42 @@ func setupBundle() int {
 
 const aliasPrefix = "_DAGGER_HOSTNAME_ALIAS_"
 
+// applyResourceLimit sets the cgroup limit configured by one of the
+// _DAGGER_*_LIMIT/_DAGGER_CPU_QUOTA env vars on the spec.
+func applyResourceLimit(spec *specs.Spec, env string) error {
+	name, val, _ := strings.Cut(env, "=")
+	n, err := strconv.ParseInt(val, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid %s: %w", name, err)
+	}
+	if spec.Linux == nil {
+		spec.Linux = &specs.Linux{}
+	}
+	if spec.Linux.Resources == nil {
+		spec.Linux.Resources = &specs.LinuxResources{}
+	}
+	res := spec.Linux.Resources
+	switch name {
+	case "_DAGGER_MEMORY_LIMIT":
+		if res.Memory == nil {
+			res.Memory = &specs.LinuxMemory{}
+		}
+		// also limit swap, so the limit can't be exceeded by swapping
+		res.Memory.Limit = &n
+		res.Memory.Swap = &n
+	case "_DAGGER_CPU_QUOTA":
+		if res.CPU == nil {
+			res.CPU = &specs.LinuxCPU{}
+		}
+		period := uint64(cpuPeriod)
+		res.CPU.Quota = &n
+		res.CPU.Period = &period
+	case "_DAGGER_PIDS_LIMIT":
+		res.Pids = &specs.LinuxPids{Limit: n}
+	}
+	return nil
+}
+
 func appendHostAlias(hostsFilePath string, env string, searchDomains []string) error {
 	alias, target, ok := strings.Cut(strings.TrimPrefix(env, aliasPrefix), "=")
 	if !ok {
@@ -0,0 +1,25 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/opencontainers/runtime-spec/specs-go"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplyResourceLimit(t *testing.T) {
+	spec := specs.Spec{}
+
+	require.NoError(t, applyResourceLimit(&spec, "_DAGGER_MEMORY_LIMIT=536870912"))
+	require.NoError(t, applyResourceLimit(&spec, "_DAGGER_CPU_QUOTA=150000"))
+	require.NoError(t, applyResourceLimit(&spec, "_DAGGER_PIDS_LIMIT=64"))
+
+	res := spec.Linux.Resources
+	require.Equal(t, int64(536870912), *res.Memory.Limit)
+	require.Equal(t, int64(536870912), *res.Memory.Swap)
+	require.Equal(t, int64(150000), *res.CPU.Quota)
+	require.Equal(t, uint64(cpuPeriod), *res.CPU.Period)
+	require.Equal(t, int64(64), res.Pids.Limit)
+
+	require.Error(t, applyResourceLimit(&spec, "_DAGGER_PIDS_LIMIT=lots"))
+}
@@ -1,20 +1,27 @@
 package core
 
 import (
+	"context"
 	"crypto/sha256"
 	"encoding/base64"
 	"encoding/json"
+	"sort"
 	"strings"
 
 	"github.com/dagger/dagger/dagql"
 	"github.com/dagger/dagger/dagql/idproto"
+	"github.com/dagger/dagger/engine/buildkit"
 	"github.com/pkg/errors"
 	"github.com/vektah/gqlparser/v2/ast"
 )
 
-// CacheVolume is a persistent volume with a globally scoped identifier.
+// CacheVolume is a persistent volume identified by its keys within a
+// namespace. Cache volumes without a namespace are shared globally.
 type CacheVolume struct {
-	Keys []string `json:"keys"`
+	Query *Query
+
+	Keys      []string `json:"keys"`
+	Namespace string   `json:"namespace,omitempty"`
 }
 
 func (*CacheVolume) Type() *ast.Type {
@@ -40,9 +47,126 @@ func (cache *CacheVolume) Clone() *CacheVolume {
 	return &cp
 }
 
+// Key returns the key the cache volume was constructed with.
+func (cache *CacheVolume) Key() string {
+	return cache.Keys[0]
+}
+
+// CacheVolumeNamespace returns the namespace that cache volumes are scoped to
+// by default: the identity of the calling module's source, or "" for the main
+// client, whose cache volumes are shared.
+//
+// Modules from git are identified by their ref without a version, so that
+// their cache volumes carry over to new versions, and local modules by the
+// digest of their source's ID, which includes where they were loaded from.
+func CacheVolumeNamespace(ctx context.Context, query *Query) (string, error) {
+	mod, err := query.CurrentModule(ctx)
+	if err != nil {
+		if errors.Is(err, ErrNoCurrentModule) {
+			return "", nil
+		}
+		return "", err
+	}
+	src := mod.Source
+	if src.Self.Kind == ModuleSourceKindGit && src.Self.AsGitSource.Valid {
+		return src.Self.AsGitSource.Value.refPath(), nil
+	}
+	dig, err := src.ID().Digest()
+	if err != nil {
+		return "", err
+	}
+	return dig.String(), nil
+}
+
+// Snapshot returns a copy of the current contents of the cache volume.
+func (cache *CacheVolume) Snapshot(ctx context.Context) (*Directory, error) {
+	engineHostPlatform := cache.Query.Platform
+	pbDef, err := cache.Query.Buildkit.CacheVolumeSnapshot(ctx, engineHostPlatform.Spec(), cache.Sum())
+	if err != nil {
+		return nil, err
+	}
+	return NewDirectory(cache.Query, pbDef, "", engineHostPlatform, nil), nil
+}
+
+// Usage returns the disk space used by the cache volume and when it was last
+// used.
+func (cache *CacheVolume) Usage(ctx context.Context) (buildkit.CacheVolumeUsage, error) {
+	return cache.Query.Buildkit.CacheVolumeUsage(ctx, cache.Sum())
+}
+
+// Clear empties the cache volume for its subsequent uses.
+func (cache *CacheVolume) Clear(ctx context.Context) error {
+	return cache.Query.Buildkit.ClearCacheVolume(ctx, cache.Sum())
+}
+
+// Seed copies the contents of the directory into the cache volume. Files
+// already in the volume are kept unless the directory has a file at the same
+// path.
+func (cache *CacheVolume) Seed(ctx context.Context, source *Directory) error {
+	svcs := cache.Query.Services
+	bk := cache.Query.Buildkit
+
+	detach, _, err := svcs.StartBindings(ctx, source.Services)
+	if err != nil {
+		return err
+	}
+	defer detach()
+
+	if err := bk.SeedCacheVolume(ctx, cache.Sum(), source.LLB, source.Dir); err != nil {
+		return err
+	}
+	rememberCacheVolume(cache)
+	return nil
+}
+
+// visibleFrom returns whether the cache volume is listed for callers in the
+// given namespace: modules only see their own cache volumes and shared ones,
+// while the main client sees all of them.
+func (cache *CacheVolume) visibleFrom(namespace string) bool {
+	return namespace == "" || cache.Namespace == "" || cache.Namespace == namespace
+}
+
+// rememberCacheVolume records the cache volume in SeenCacheKeys.
+func rememberCacheVolume(cache *CacheVolume) {
+	seen := cache.Clone()
+	seen.Query = nil
+	SeenCacheKeys.Store(seen.Sum(), seen)
+}
+
+// KnownCacheVolumes returns the cache volumes used since the engine started
+// that are visible to the caller, sorted by namespace and key.
+func KnownCacheVolumes(ctx context.Context, query *Query) ([]*CacheVolume, error) {
+	namespace, err := CacheVolumeNamespace(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	var caches []*CacheVolume
+	SeenCacheKeys.Range(func(k any, v any) bool {
+		cache := v.(*CacheVolume)
+		if !cache.visibleFrom(namespace) {
+			return true
+		}
+		cache = cache.Clone()
+		cache.Query = query
+		caches = append(caches, cache)
+		return true
+	})
+	sort.Slice(caches, func(i, j int) bool {
+		if caches[i].Namespace != caches[j].Namespace {
+			return caches[i].Namespace < caches[j].Namespace
+		}
+		return caches[i].Key() < caches[j].Key()
+	})
+	return caches, nil
+}
+
 // Sum returns a checksum of the cache tokens suitable for use as a cache key.
 func (cache *CacheVolume) Sum() string {
 	hash := sha256.New()
+	if cache.Namespace != "" {
+		// shared cache volumes keep the checksum they had before namespaces
+		_, _ = hash.Write([]byte("namespace:" + cache.Namespace + "\x00"))
+	}
 	for _, tok := range cache.Keys {
 		_, _ = hash.Write([]byte(tok + "\x00"))
 	}
@@ -23,6 +23,7 @@ import (
 	"github.com/dagger/dagger/dagql/idproto"
 	"github.com/dagger/dagger/engine"
 	"github.com/docker/distribution/reference"
+	units "github.com/docker/go-units"
 	"github.com/moby/buildkit/client/llb"
 	"github.com/moby/buildkit/exporter/containerimage/exptypes"
 	"github.com/moby/buildkit/frontend/dockerui"
@@ -84,6 +85,13 @@ type Container struct {
 
 	// The args to invoke when using the terminal api on this container.
 	DefaultTerminalCmd []string `json:"defaultTerminalCmd,omitempty"`
+
+	// Default limits for commands executed in the container.
+	ResourceLimits ContainerResourceLimits `json:"resourceLimits"`
+
+	// Healthcheck used to wait for the container to be ready when run as a
+	// service. If nil, its exposed ports are checked instead.
+	Healthcheck *ContainerHealthcheck `json:"healthcheck,omitempty"`
 }
 
 func (*Container) Type() *ast.Type {
@@ -123,6 +131,16 @@ func (container *Container) PBDefinitions(ctx context.Context) ([]*pb.Definition
 	return defs, nil
 }
 
+var _ dagql.CacheSizer = (*Container)(nil)
+
+func (container *Container) CacheSize() int64 {
+	size := definitionsCacheSize(container)
+	if container.Meta != nil {
+		size += int64(container.Meta.Size())
+	}
+	return size
+}
+
 func NewContainer(root *Query, platform Platform) (*Container, error) {
 	if root == nil {
 		panic("query must be non-nil")
@@ -320,6 +338,9 @@ func (container *Container) From(ctx context.Context, addr string) (*Container,
 	buildkit.RecordVertexes(subRecorder, container.FS)
 
 	container.Config = mergeImageConfig(container.Config, imgSpec.Config)
+	if hc, ok := healthcheckFromImage(cfgBytes); ok {
+		container.Healthcheck = hc
+	}
 	container.ImageRef = digested.String()
 
 	return container, nil
@@ -436,6 +457,9 @@ func (container *Container) Build(
 		}
 
 		container.Config = mergeImageConfig(container.Config, imgSpec.Config)
+		if hc, ok := healthcheckFromImage(cfgBytes); ok {
+			container.Healthcheck = hc
+		}
 	}
 
 	return container, nil
@@ -539,6 +563,8 @@ func (container *Container) WithMountedFile(ctx context.Context, target string,
 	return container.withMounted(ctx, target, file.LLB, file.File, file.Services, owner, readonly)
 }
 
+// SeenCacheKeys holds every cache volume used since the engine started, by
+// checksum.
 var SeenCacheKeys = new(sync.Map)
 
 func (container *Container) WithMountedCache(ctx context.Context, target string, cache *CacheVolume, source *Directory, sharingMode CacheSharingMode, owner string) (*Container, error) {
@@ -580,7 +606,7 @@ func (container *Container) WithMountedCache(ctx context.Context, target string,
 	// set image ref to empty string
 	container.ImageRef = ""
 
-	SeenCacheKeys.Store(cache.Keys[0], struct{}{})
+	rememberCacheVolume(cache)
 
 	return container, nil
 }
@@ -980,9 +1006,39 @@ func (container *Container) WithGPU(ctx context.Context, gpuOpts ContainerGPUOpt
 	return container, nil
 }
 
+func (container *Container) WithResourceLimits(ctx context.Context, limits ContainerResourceLimits) (*Container, error) {
+	if err := limits.validate(); err != nil {
+		return nil, err
+	}
+	container = container.Clone()
+	container.ResourceLimits = limits
+	return container, nil
+}
+
+func (container *Container) WithHealthcheck(ctx context.Context, hc ContainerHealthcheck) (*Container, error) {
+	if err := hc.validate(); err != nil {
+		return nil, err
+	}
+	container = container.Clone()
+	container.Healthcheck = &hc
+	return container, nil
+}
+
+func (container *Container) WithoutHealthcheck(ctx context.Context) (*Container, error) {
+	container = container.Clone()
+	container.Healthcheck = nil
+	return container, nil
+}
+
 func (container *Container) WithExec(ctx context.Context, opts ContainerExecOpts) (*Container, error) { //nolint:gocyclo
 	container = container.Clone()
 
+	limits := container.ResourceLimits.merge(opts.ContainerResourceLimits)
+	limitOpts, err := limits.runOptions()
+	if err != nil {
+		return nil, err
+	}
+
 	cfg := container.Config
 	mounts := container.Mounts
 	platform := container.Platform
@@ -1006,6 +1062,7 @@ func (container *Container) WithExec(ctx context.Context, opts ContainerExecOpts
 		llb.Args(args),
 		llb.WithCustomNamef(namef, strings.Join(args, " ")),
 	}
+	runOpts = append(runOpts, limitOpts...)
 
 	// this allows executed containers to communicate back to this API
 	if opts.ExperimentalPrivilegedNesting {
@@ -1035,6 +1092,10 @@ func (container *Container) WithExec(ctx context.Context, opts ContainerExecOpts
 		runOpts = append(runOpts, llb.AddEnv("_DAGGER_REDIRECT_STDERR", opts.RedirectStderr))
 	}
 
+	if opts.Expect != "" && opts.Expect != ReturnSuccess {
+		runOpts = append(runOpts, llb.AddEnv("_DAGGER_EXPECT", string(opts.Expect)))
+	}
+
 	for _, bnd := range container.Services {
 		for _, alias := range bnd.Aliases {
 			runOpts = append(runOpts,
@@ -1284,6 +1345,19 @@ func (container *Container) MetaFileContents(ctx context.Context, filePath strin
 	return string(content), nil
 }
 
+// ExitCode returns the exit code of the last executed command.
+func (container *Container) ExitCode(ctx context.Context) (int, error) {
+	contents, err := container.MetaFileContents(ctx, "exitCode")
+	if err != nil {
+		return 0, err
+	}
+	exitCode, err := strconv.Atoi(strings.TrimSpace(contents))
+	if err != nil {
+		return 0, fmt.Errorf("parse exit code %q: %w", contents, err)
+	}
+	return exitCode, nil
+}
+
 func (container *Container) Publish(
 	ctx context.Context,
 	ref string,
@@ -1607,6 +1681,7 @@ func (container *Container) Import(
 	}
 
 	container.Config = imgSpec.Config
+	container.Healthcheck, _ = healthcheckFromImage(configBlob)
 
 	return container, nil
 }
@@ -1658,7 +1733,7 @@ func (container *Container) WithoutExposedPort(port int, protocol NetworkProtoco
 	return container, nil
 }
 
-func (container *Container) WithServiceBinding(ctx context.Context, id *idproto.ID, svc *Service, alias string) (*Container, error) {
+func (container *Container) WithServiceBinding(ctx context.Context, id *idproto.ID, svc *Service, alias string, dependsOn ...string) (*Container, error) {
 	container = container.Clone()
 
 	host, err := svc.Hostname(ctx, id)
@@ -1673,10 +1748,11 @@ func (container *Container) WithServiceBinding(ctx context.Context, id *idproto.
 
 	container.Services.Merge(ServiceBindings{
 		{
-			ID:       id,
-			Service:  svc,
-			Hostname: host,
-			Aliases:  aliases,
+			ID:        id,
+			Service:   svc,
+			Hostname:  host,
+			Aliases:   aliases,
+			DependsOn: dependsOn,
 		},
 	})
 
@@ -1714,7 +1790,7 @@ func (container *Container) ownership(ctx context.Context, owner string) (*Owner
 		return nil, err
 	}
 
-	return resolveUIDGID(ctx, fsSt, container.Query.Buildkit, container.Platform, owner)
+	return resolveUIDGID(ctx, fsSt, "/", container.Query.Buildkit, container.Platform, owner)
 }
 
 func (container *Container) command(opts ContainerExecOpts) ([]string, error) {
@@ -1779,6 +1855,12 @@ type ContainerExecOpts struct {
 	// Grant the process all root capabilities
 	InsecureRootCapabilities bool `default:"false"`
 
+	// Exit codes this exec is allowed to exit with
+	Expect ReturnType `default:"SUCCESS"`
+
+	// Limits for this exec, overriding the container's defaults
+	ContainerResourceLimits
+
 	// (Internal-only) If this exec is for a module function, this digest will be set in the
 	// grpc context metadata for any api requests back to the engine. It's used by the API
 	// server to determine which schema to serve and other module context metadata.
@@ -1789,6 +1871,88 @@ type ContainerExecOpts struct {
 	NestedInSameSession bool `name:"-"`
 }
 
+// ContainerResourceLimits bounds the time and resources used by an exec. Zero
+// values mean no limit.
+type ContainerResourceLimits struct {
+	// Maximum duration of the command, in seconds
+	Timeout int `default:"0"`
+
+	// Maximum amount of memory the command may use, e.g. 512M or 2G
+	MemoryLimit string `default:""`
+
+	// CPU time the command may use per 100ms period, in microseconds, e.g.
+	// 150000 for 1.5 CPUs
+	CPUQuota int `name:"cpuQuota" default:"0"`
+
+	// Maximum number of processes the command may run
+	PidsLimit int `default:"0"`
+}
+
+// minCPUQuota is the smallest CPU quota, in microseconds, the kernel accepts.
+const minCPUQuota = 1000
+
+func (limits ContainerResourceLimits) validate() error {
+	if limits.Timeout < 0 {
+		return fmt.Errorf("invalid timeout %d: must not be negative", limits.Timeout)
+	}
+	if limits.MemoryLimit != "" {
+		if _, err := units.RAMInBytes(limits.MemoryLimit); err != nil {
+			return fmt.Errorf("invalid memory limit %q: %w", limits.MemoryLimit, err)
+		}
+	}
+	if limits.CPUQuota < 0 {
+		return fmt.Errorf("invalid CPU quota %d: must not be negative", limits.CPUQuota)
+	}
+	if limits.CPUQuota > 0 && limits.CPUQuota < minCPUQuota {
+		return fmt.Errorf("invalid CPU quota %d: must be at least %d", limits.CPUQuota, minCPUQuota)
+	}
+	if limits.PidsLimit < 0 {
+		return fmt.Errorf("invalid pids limit %d: must not be negative", limits.PidsLimit)
+	}
+	return nil
+}
+
+// merge returns the limits with any limits set in override replacing them.
+func (limits ContainerResourceLimits) merge(override ContainerResourceLimits) ContainerResourceLimits {
+	if override.Timeout != 0 {
+		limits.Timeout = override.Timeout
+	}
+	if override.MemoryLimit != "" {
+		limits.MemoryLimit = override.MemoryLimit
+	}
+	if override.CPUQuota != 0 {
+		limits.CPUQuota = override.CPUQuota
+	}
+	if override.PidsLimit != 0 {
+		limits.PidsLimit = override.PidsLimit
+	}
+	return limits
+}
+
+// runOptions passes the limits to the shim, which applies them to the OCI
+// spec of the exec (or enforces them itself, for the timeout).
+func (limits ContainerResourceLimits) runOptions() ([]llb.RunOption, error) {
+	if err := limits.validate(); err != nil {
+		return nil, err
+	}
+	var opts []llb.RunOption
+	if limits.Timeout > 0 {
+		opts = append(opts, llb.AddEnv("_DAGGER_TIMEOUT", strconv.Itoa(limits.Timeout)))
+	}
+	if limits.MemoryLimit != "" {
+		// already validated
+		mem, _ := units.RAMInBytes(limits.MemoryLimit)
+		opts = append(opts, llb.AddEnv("_DAGGER_MEMORY_LIMIT", strconv.FormatInt(mem, 10)))
+	}
+	if limits.CPUQuota > 0 {
+		opts = append(opts, llb.AddEnv("_DAGGER_CPU_QUOTA", strconv.Itoa(limits.CPUQuota)))
+	}
+	if limits.PidsLimit > 0 {
+		opts = append(opts, llb.AddEnv("_DAGGER_PIDS_LIMIT", strconv.Itoa(limits.PidsLimit)))
+	}
+	return opts, nil
+}
+
 type BuildArg struct {
 	Name  string `field:"true" doc:"The build argument name."`
 	Value string `field:"true" doc:"The build argument value."`
@@ -1889,6 +2053,38 @@ func (proto ImageLayerCompression) ToLiteral() *idproto.Literal {
 	return ImageLayerCompressions.Literal(proto)
 }
 
+type ReturnType string
+
+var ReturnTypes = dagql.NewEnum[ReturnType]()
+
+var (
+	ReturnSuccess = ReturnTypes.Register("SUCCESS",
+		"A successful execution (exit code 0)")
+	ReturnFailure = ReturnTypes.Register("FAILURE",
+		"A failed execution (non-zero exit code)")
+	ReturnAny = ReturnTypes.Register("ANY",
+		"Any execution, regardless of its exit code")
+)
+
+func (expect ReturnType) Type() *ast.Type {
+	return &ast.Type{
+		NamedType: "ReturnType",
+		NonNull:   true,
+	}
+}
+
+func (expect ReturnType) TypeDescription() string {
+	return "Expected return type of an execution"
+}
+
+func (expect ReturnType) Decoder() dagql.InputDecoder {
+	return ReturnTypes
+}
+
+func (expect ReturnType) ToLiteral() *idproto.Literal {
+	return ReturnTypes.Literal(expect)
+}
+
 type ImageMediaTypes string
 
 var ImageMediaTypesEnum = dagql.NewEnum[ImageMediaTypes]()
@@ -1,6 +1,8 @@
 package core
 
 import (
+	"archive/tar"
+	"bytes"
 	"context"
 	"fmt"
 	"io/fs"
@@ -15,6 +17,7 @@ import (
 	"github.com/moby/buildkit/identity"
 	"github.com/moby/buildkit/solver/pb"
 	"github.com/moby/patternmatcher"
+	"github.com/opencontainers/go-digest"
 	"github.com/pkg/errors"
 	fstypes "github.com/tonistiigi/fsutil/types"
 	"github.com/vektah/gqlparser/v2/ast"
@@ -22,6 +25,7 @@ import (
 
 	"github.com/dagger/dagger/core/pipeline"
 	"github.com/dagger/dagger/dagql"
+	"github.com/dagger/dagger/dagql/idproto"
 	"github.com/dagger/dagger/engine/buildkit"
 )
 
@@ -69,6 +73,12 @@ func (dir *Directory) PBDefinitions(ctx context.Context) ([]*pb.Definition, erro
 	return defs, nil
 }
 
+var _ dagql.CacheSizer = (*Directory)(nil)
+
+func (dir *Directory) CacheSize() int64 {
+	return definitionsCacheSize(dir)
+}
+
 func NewDirectory(query *Query, def *pb.Definition, dir string, platform Platform, services ServiceBindings) *Directory {
 	if query == nil {
 		panic("query must be non-nil")
@@ -218,7 +228,53 @@ func (dir *Directory) Stat(ctx context.Context, bk *buildkit.Client, svcs *Servi
 	})
 }
 
+// Digest returns a digest of the directory's contents. Unless
+// excludeMetadata is true, it also covers the permissions and ownership of its
+// entries, but never their timestamps.
+func (dir *Directory) Digest(ctx context.Context, excludeMetadata bool) (digest.Digest, error) {
+	svcs := dir.Query.Services
+	bk := dir.Query.Buildkit
+
+	detach, _, err := svcs.StartBindings(ctx, dir.Services)
+	if err != nil {
+		return "", err
+	}
+	defer detach()
+
+	return bk.DirectoryDigest(ctx, dir.LLB, dir.Dir, excludeMetadata)
+}
+
 func (dir *Directory) Entries(ctx context.Context, src string) ([]string, error) {
+	entries, err := dir.readDir(ctx, src)
+	if err != nil {
+		return nil, err
+	}
+
+	paths := []string{}
+	for _, entry := range entries {
+		paths = append(paths, entry.GetPath())
+	}
+
+	return paths, nil
+}
+
+// EntriesWithStat returns the metadata of each entry in the directory at the
+// given path.
+func (dir *Directory) EntriesWithStat(ctx context.Context, src string) ([]Stat, error) {
+	entries, err := dir.readDir(ctx, src)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := []Stat{}
+	for _, entry := range entries {
+		stats = append(stats, NewStat(entry))
+	}
+
+	return stats, nil
+}
+
+func (dir *Directory) readDir(ctx context.Context, src string) ([]*fstypes.Stat, error) {
 	src = path.Join(dir.Dir, src)
 
 	svcs := dir.Query.Services
@@ -244,24 +300,14 @@ func (dir *Directory) Entries(ctx context.Context, src string) ([]string, error)
 	// empty directory, i.e. llb.Scratch()
 	if ref == nil {
 		if clean := path.Clean(src); clean == "." || clean == "/" {
-			return []string{}, nil
+			return nil, nil
 		}
 		return nil, fmt.Errorf("%s: no such file or directory", src)
 	}
 
-	entries, err := ref.ReadDir(ctx, bkgw.ReadDirRequest{
+	return ref.ReadDir(ctx, bkgw.ReadDirRequest{
 		Path: src,
 	})
-	if err != nil {
-		return nil, err
-	}
-
-	paths := []string{}
-	for _, entry := range entries {
-		paths = append(paths, entry.GetPath())
-	}
-
-	return paths, nil
 }
 
 // Glob returns a list of files that matches the given pattern.
@@ -685,6 +731,100 @@ func (dir *Directory) Diff(ctx context.Context, other *Directory) (*Directory, e
 	return dir, nil
 }
 
+// AsPatch returns a unified diff from this directory to the other, in the
+// format written by git diff --binary.
+func (dir *Directory) AsPatch(ctx context.Context, other *Directory) (*File, error) {
+	svcs := dir.Query.Services
+	bk := dir.Query.Buildkit
+	engineHostPlatform := dir.Query.Platform
+
+	services := ServiceBindings{}
+	services.Merge(dir.Services)
+	services.Merge(other.Services)
+	detach, _, err := svcs.StartBindings(ctx, services)
+	if err != nil {
+		return nil, err
+	}
+	defer detach()
+
+	// when the directories can be diffed, only the files in the diff need to
+	// be compared
+	var changesDef *pb.Definition
+	if dir.Dir == other.Dir && reflect.DeepEqual(dir.Platform, other.Platform) {
+		changes, err := dir.Diff(ctx, other)
+		if err != nil {
+			return nil, err
+		}
+		changesDef = changes.LLB
+	}
+
+	fileName := "changes.patch"
+	pbDef, err := bk.DirectoryPatch(ctx, engineHostPlatform.Spec(), dir.LLB, dir.Dir, other.LLB, other.Dir, changesDef, fileName)
+	if err != nil {
+		return nil, err
+	}
+	return NewFile(dir.Query, pbDef, fileName, engineHostPlatform, nil), nil
+}
+
+// WithPatch applies a unified diff to the directory, stripping the given
+// number of leading components from the paths in it. The patch either applies
+// entirely or not at all.
+func (dir *Directory) WithPatch(ctx context.Context, patch *File, strip int) (*Directory, error) {
+	svcs := dir.Query.Services
+	bk := dir.Query.Buildkit
+
+	if strip < 0 {
+		return nil, fmt.Errorf("invalid strip %d: must not be negative", strip)
+	}
+
+	services := ServiceBindings{}
+	services.Merge(dir.Services)
+	services.Merge(patch.Services)
+	detach, _, err := svcs.StartBindings(ctx, services)
+	if err != nil {
+		return nil, err
+	}
+	defer detach()
+
+	pbDef, written, removed, err := bk.ApplyPatch(ctx, dir.Query.Platform.Spec(), dir.LLB, dir.Dir, patch.LLB, patch.File, strip)
+	if err != nil {
+		return nil, fmt.Errorf("failed to apply patch: %w", err)
+	}
+
+	dir = dir.Clone()
+
+	st, err := dir.State()
+	if err != nil {
+		return nil, err
+	}
+	patched, err := defToState(pbDef)
+	if err != nil {
+		return nil, err
+	}
+
+	// copy the patched files one by one rather than the whole directory, so
+	// that the parent directories they're in are left as they are
+	var action *llb.FileAction
+	for _, p := range removed {
+		action = action.Rm(path.Join(dir.Dir, p))
+	}
+	for _, p := range written {
+		// remove what's there first, so that files that became symlinks or
+		// the other way around are replaced rather than written through
+		action = action.Rm(path.Join(dir.Dir, p), llb.WithAllowNotFound(true))
+		action = action.Copy(patched, path.Join("/", p), path.Join(dir.Dir, p), &llb.CopyInfo{
+			CreateDestPath: true,
+		})
+	}
+	if action == nil {
+		return dir, nil
+	}
+	if err := dir.SetState(ctx, st.File(action)); err != nil {
+		return nil, err
+	}
+	return dir, nil
+}
+
 func (dir *Directory) Without(ctx context.Context, path string) (*Directory, error) {
 	dir = dir.Clone()
 
@@ -702,27 +842,263 @@ func (dir *Directory) Without(ctx context.Context, path string) (*Directory, err
 	return dir, nil
 }
 
-func (dir *Directory) Export(ctx context.Context, destPath string) (rerr error) {
+// WithSymlink creates a symlink at linkName pointing to target, replacing any
+// existing entry at linkName.
+func (dir *Directory) WithSymlink(ctx context.Context, target, linkName string) (*Directory, error) {
+	dir = dir.Clone()
+
+	err := validateFileName(linkName)
+	if err != nil {
+		return nil, err
+	}
+
+	linkName = strings.TrimPrefix(path.Clean(linkName), "/")
+	if linkName == "." || linkName == "" {
+		return nil, fmt.Errorf("invalid symlink name: %q", linkName)
+	}
+	if linkName == ".." || strings.HasPrefix(linkName, "../") {
+		return nil, fmt.Errorf("cannot create symlink outside parent: %s", linkName)
+	}
+
+	st, err := dir.State()
+	if err != nil {
+		return nil, err
+	}
+
+	st, err = symlinkViaArchive(st, path.Join("/", dir.Dir), linkName, target)
+	if err != nil {
+		return nil, err
+	}
+
+	err = dir.SetState(ctx, st)
+	if err != nil {
+		return nil, err
+	}
+
+	return dir, nil
+}
+
+// symlinkViaArchive creates a symlink at linkName, relative to dir, pointing
+// to target.
+//
+// HACK: the FileOp of the buildkit version we're on has no symlink action, so
+// this writes a tarball containing just the link and has buildkit unpack it in
+// place. Replace it with a FileOp once buildkit supports one.
+func symlinkViaArchive(st llb.State, dir, linkName, target string) (llb.State, error) {
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	err := tw.WriteHeader(&tar.Header{
+		Typeflag: tar.TypeSymlink,
+		Name:     linkName,
+		Linkname: target,
+		Mode:     0o777,
+		ModTime:  time.Unix(0, 0),
+	})
+	if err != nil {
+		return llb.State{}, err
+	}
+	if err := tw.Close(); err != nil {
+		return llb.State{}, err
+	}
+
+	archive := llb.Scratch().File(llb.Mkfile("/symlink.tar", 0o644, buf.Bytes()))
+	return st.File(llb.Copy(archive, "/symlink.tar", dir, &llb.CopyInfo{
+		AttemptUnpack:  true,
+		CreateDestPath: true,
+	})), nil
+}
+
+// WithPermissions sets the permissions of the file or directory at the given
+// path. If recursive is true, the contents of a directory are updated too.
+func (dir *Directory) WithPermissions(ctx context.Context, target string, permissions fs.FileMode, recursive bool) (*Directory, error) {
+	if recursive {
+		return dir.rewrite(ctx, target, &llb.CopyInfo{Mode: &permissions})
+	}
+
+	info, err := dir.Stat(ctx, dir.Query.Buildkit, dir.Query.Services, target)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return dir.rewrite(ctx, target, &llb.CopyInfo{Mode: &permissions})
+	}
+
+	dir = dir.Clone()
+
+	target = path.Join("/", dir.Dir, target)
+	if target == "/" {
+		return nil, fmt.Errorf("cannot change permissions of the root directory without recursive")
+	}
+
+	st, err := dir.State()
+	if err != nil {
+		return nil, err
+	}
+
+	// recreate the directory with the new permissions and copy its original
+	// contents back in, which leaves the directory's own metadata alone
+	st = st.File(
+		llb.Rm(target).
+			Mkdir(target, permissions, llb.WithUIDGID(int(info.Uid), int(info.Gid))).
+			Copy(st, target, target, &llb.CopyInfo{
+				CopyDirContentsOnly: true,
+			}),
+	)
+
+	err = dir.SetState(ctx, st)
+	if err != nil {
+		return nil, err
+	}
+
+	return dir, nil
+}
+
+// WithOwner recursively changes the ownership of the file or directory at the
+// given path. User and group names are resolved against the etc/passwd and
+// etc/group files in the directory itself, e.g. those of a container's root
+// filesystem.
+func (dir *Directory) WithOwner(ctx context.Context, target string, owner string) (*Directory, error) {
+	if owner == "" {
+		return nil, fmt.Errorf("owner must not be empty")
+	}
+
+	st, err := dir.State()
+	if err != nil {
+		return nil, err
+	}
+
+	ownership, err := resolveUIDGID(ctx, st, dir.Dir, dir.Query.Buildkit, dir.Platform, owner)
+	if err != nil {
+		return nil, err
+	}
+
+	return dir.rewrite(ctx, target, &llb.CopyInfo{}, ownership.Opt())
+}
+
+// WithMove moves the file or directory at src to dest. As with mv, if dest
+// is an existing directory the source is moved into it.
+func (dir *Directory) WithMove(ctx context.Context, src, dest string) (*Directory, error) {
+	dir = dir.Clone()
+
+	src = path.Join("/", dir.Dir, src)
+	dest = path.Join("/", dir.Dir, dest)
+	if src == dest {
+		return nil, fmt.Errorf("cannot move %s to itself", src)
+	}
+	if strings.HasPrefix(dest, strings.TrimSuffix(src, "/")+"/") {
+		return nil, fmt.Errorf("cannot move %s into itself", src)
+	}
+
+	st, err := dir.State()
+	if err != nil {
+		return nil, err
+	}
+
+	st = st.File(
+		llb.Copy(st, src, dest, &llb.CopyInfo{
+			CreateDestPath: true,
+		}).Rm(src),
+	)
+
+	err = dir.SetState(ctx, st)
+	if err != nil {
+		return nil, err
+	}
+
+	return dir, nil
+}
+
+// AsArchive packs the directory's contents into an archive. If reproducible
+// is true, the same contents always produce the same archive.
+func (dir *Directory) AsArchive(
+	ctx context.Context,
+	format ArchiveFormat,
+	compression ImageLayerCompression,
+	reproducible bool,
+) (*File, error) {
 	svcs := dir.Query.Services
 	bk := dir.Query.Buildkit
-
-	var defPB *pb.Definition
-	if dir.Dir != "" {
-		src, err := dir.State()
-		if err != nil {
-			return err
+	engineHostPlatform := dir.Query.Platform
+
+	opts := buildkit.ArchiveOpts{
+		Format:       strings.ToLower(string(format)),
+		Reproducible: reproducible,
+	}
+	fileName := "archive." + opts.Format
+	switch compression {
+	case CompressionUncompressed:
+	case CompressionGzip:
+		opts.Compression = "gzip"
+		if format == ArchiveFormatTar {
+			fileName += ".gz"
 		}
-		src = llb.Scratch().File(llb.Copy(src, dir.Dir, ".", &llb.CopyInfo{
-			CopyDirContentsOnly: true,
-		}))
-
-		def, err := src.Marshal(ctx, llb.Platform(dir.Platform.Spec()))
-		if err != nil {
-			return err
+	case CompressionZstd:
+		if format != ArchiveFormatTar {
+			return nil, fmt.Errorf("%s compression is not supported for %s archives", compression, format)
 		}
-		defPB = def.ToPB()
+		opts.Compression = "zstd"
+		fileName += ".zst"
+	default:
+		return nil, fmt.Errorf("%s compression is not supported for archives", compression)
+	}
+
+	detach, _, err := svcs.StartBindings(ctx, dir.Services)
+	if err != nil {
+		return nil, err
+	}
+	defer detach()
+
+	pbDef, err := bk.DirectoryToArchive(ctx, engineHostPlatform.Spec(), dir.LLB, dir.Dir, fileName, opts)
+	if err != nil {
+		return nil, err
+	}
+	return NewFile(dir.Query, pbDef, fileName, engineHostPlatform, nil), nil
+}
+
+// WithArchive extracts the given archive into the directory at the given
+// path.
+func (dir *Directory) WithArchive(ctx context.Context, destDir string, src *File) (*Directory, error) {
+	extracted, err := src.Extract(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+	return dir.WithDirectory(ctx, destDir, extracted, CopyFilter{}, nil)
+}
+
+// rewrite replaces the entry at the given path with a copy of itself, letting
+// the copy options change its metadata along the way.
+func (dir *Directory) rewrite(ctx context.Context, target string, info *llb.CopyInfo, opts ...llb.CopyOption) (*Directory, error) {
+	dir = dir.Clone()
+
+	st, err := dir.State()
+	if err != nil {
+		return nil, err
+	}
+
+	target = path.Join("/", dir.Dir, target)
+	if target == "/" {
+		// the root can't be removed, so copy everything onto scratch instead
+		info.CopyDirContentsOnly = true
+		st = llb.Scratch().File(llb.Copy(st, target, target, append([]llb.CopyOption{info}, opts...)...))
 	} else {
-		defPB = dir.LLB
+		st = st.File(llb.Rm(target).Copy(st, target, target, append([]llb.CopyOption{info}, opts...)...))
+	}
+
+	err = dir.SetState(ctx, st)
+	if err != nil {
+		return nil, err
+	}
+
+	return dir, nil
+}
+
+func (dir *Directory) Export(ctx context.Context, destPath string, wipe bool) (rerr error) {
+	svcs := dir.Query.Services
+	bk := dir.Query.Buildkit
+
+	defPB, err := dir.exportDef(ctx)
+	if err != nil {
+		return err
 	}
 
 	ctx, vtx := progrock.Span(ctx, identity.NewID(),
@@ -735,7 +1111,49 @@ func (dir *Directory) Export(ctx context.Context, destPath string) (rerr error)
 	}
 	defer detach()
 
-	return bk.LocalDirExport(ctx, defPB, destPath)
+	return bk.LocalDirExport(ctx, defPB, destPath, wipe)
+}
+
+// ExportDryRun reports what Export would add, modify and, if wipe is set,
+// delete at the path on the host, without writing anything.
+func (dir *Directory) ExportDryRun(ctx context.Context, destPath string, wipe bool) ([]string, error) {
+	svcs := dir.Query.Services
+	bk := dir.Query.Buildkit
+
+	defPB, err := dir.exportDef(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	detach, _, err := svcs.StartBindings(ctx, dir.Services)
+	if err != nil {
+		return nil, err
+	}
+	defer detach()
+
+	return bk.LocalDirExportDryRun(ctx, defPB, destPath, wipe)
+}
+
+// exportDef returns a definition with the contents of the directory at its
+// root.
+func (dir *Directory) exportDef(ctx context.Context) (*pb.Definition, error) {
+	if dir.Dir == "" {
+		return dir.LLB, nil
+	}
+
+	src, err := dir.State()
+	if err != nil {
+		return nil, err
+	}
+	src = llb.Scratch().File(llb.Copy(src, dir.Dir, ".", &llb.CopyInfo{
+		CopyDirContentsOnly: true,
+	}))
+
+	def, err := src.Marshal(ctx, llb.Platform(dir.Platform.Spec()))
+	if err != nil {
+		return nil, err
+	}
+	return def.ToPB(), nil
 }
 
 // Root removes any relative path from the directory.
@@ -785,3 +1203,34 @@ func validateFileName(file string) error {
 	}
 	return nil
 }
+
+// ArchiveFormat is a GraphQL enum type.
+type ArchiveFormat string
+
+var ArchiveFormats = dagql.NewEnum[ArchiveFormat]()
+
+var (
+	ArchiveFormatTar = ArchiveFormats.Register("TAR",
+		"A tarball")
+	ArchiveFormatZip = ArchiveFormats.Register("ZIP",
+		"A zip archive")
+)
+
+func (format ArchiveFormat) Type() *ast.Type {
+	return &ast.Type{
+		NamedType: "ArchiveFormat",
+		NonNull:   true,
+	}
+}
+
+func (format ArchiveFormat) TypeDescription() string {
+	return "File format of an archive."
+}
+
+func (format ArchiveFormat) Decoder() dagql.InputDecoder {
+	return ArchiveFormats
+}
+
+func (format ArchiveFormat) ToLiteral() *idproto.Literal {
+	return ArchiveFormats.Literal(format)
+}
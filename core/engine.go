@@ -0,0 +1,77 @@
+package core
+
+import (
+	bkclient "github.com/moby/buildkit/client"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// Engine is the Dagger engine that the client is connected to.
+type Engine struct {
+	Query *Query
+}
+
+func (*Engine) Type() *ast.Type {
+	return &ast.Type{
+		NamedType: "Engine",
+		NonNull:   true,
+	}
+}
+
+func (*Engine) TypeDescription() string {
+	return "The Dagger engine the client is connected to."
+}
+
+// EngineCache is the local cache of an engine.
+type EngineCache struct {
+	Query *Query
+}
+
+func (*EngineCache) Type() *ast.Type {
+	return &ast.Type{
+		NamedType: "EngineCache",
+		NonNull:   true,
+	}
+}
+
+func (*EngineCache) TypeDescription() string {
+	return "The local cache of the Dagger engine."
+}
+
+// EngineCacheEntry is a record in the local cache of an engine.
+type EngineCacheEntry struct {
+	Description       string `field:"true" doc:"The description of the cache entry."`
+	RecordType        string `field:"true" doc:"The type of the cache entry (e.g., \"regular\", \"exec.cachemount\", \"source.local\")."`
+	DiskSpaceBytes    int    `field:"true" doc:"The disk space used by the cache entry, in bytes."`
+	ActivelyUsed      bool   `field:"true" doc:"Whether the cache entry is in use, in which case it can't be pruned."`
+	Shared            bool   `field:"true" doc:"Whether the cache entry shares its data with other entries."`
+	CreatedTime       int    `field:"true" doc:"The time the cache entry was created, in seconds following Unix epoch."`
+	MostRecentUseTime int    `field:"true" doc:"The time the cache entry was last used, in seconds following Unix epoch."`
+}
+
+func (EngineCacheEntry) Type() *ast.Type {
+	return &ast.Type{
+		NamedType: "EngineCacheEntry",
+		NonNull:   true,
+	}
+}
+
+func (EngineCacheEntry) TypeDescription() string {
+	return "An entry in the local cache of the Dagger engine."
+}
+
+// NewEngineCacheEntry converts a usage record returned by buildkit.
+func NewEngineCacheEntry(info *bkclient.UsageInfo) EngineCacheEntry {
+	entry := EngineCacheEntry{
+		Description:       info.Description,
+		RecordType:        string(info.RecordType),
+		DiskSpaceBytes:    int(info.Size),
+		ActivelyUsed:      info.InUse,
+		Shared:            info.Shared,
+		CreatedTime:       int(info.CreatedAt.Unix()),
+		MostRecentUseTime: int(info.CreatedAt.Unix()),
+	}
+	if info.LastUsedAt != nil {
+		entry.MostRecentUseTime = int(info.LastUsedAt.Unix())
+	}
+	return entry
+}
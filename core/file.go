@@ -7,18 +7,21 @@ import (
 
 	"io"
 	"path"
+	"strings"
 	"time"
 
 	"github.com/moby/buildkit/client/llb"
 	bkgw "github.com/moby/buildkit/frontend/gateway/client"
 	"github.com/moby/buildkit/identity"
 	"github.com/moby/buildkit/solver/pb"
+	"github.com/opencontainers/go-digest"
 	fstypes "github.com/tonistiigi/fsutil/types"
 	"github.com/vektah/gqlparser/v2/ast"
 	"github.com/vito/progrock"
 
 	"github.com/dagger/dagger/core/pipeline"
 	"github.com/dagger/dagger/core/reffs"
+	"github.com/dagger/dagger/dagql"
 	"github.com/dagger/dagger/engine/buildkit"
 )
 
@@ -66,6 +69,12 @@ func (file *File) PBDefinitions(ctx context.Context) ([]*pb.Definition, error) {
 	return defs, nil
 }
 
+var _ dagql.CacheSizer = (*File)(nil)
+
+func (file *File) CacheSize() int64 {
+	return definitionsCacheSize(file)
+}
+
 func NewFile(query *Query, def *pb.Definition, file string, platform Platform, services ServiceBindings) *File {
 	return &File{
 		Query:    query,
@@ -209,6 +218,20 @@ func (file *File) Stat(ctx context.Context) (*fstypes.Stat, error) {
 	})
 }
 
+// Digest returns the SHA-256 digest of the file's contents.
+func (file *File) Digest(ctx context.Context) (digest.Digest, error) {
+	svcs := file.Query.Services
+	bk := file.Query.Buildkit
+
+	detach, _, err := svcs.StartBindings(ctx, file.Services)
+	if err != nil {
+		return "", err
+	}
+	defer detach()
+
+	return bk.FileDigest(ctx, file.LLB, file.File)
+}
+
 func (file *File) WithTimestamps(ctx context.Context, unix int) (*File, error) {
 	file = file.Clone()
 
@@ -231,6 +254,54 @@ func (file *File) WithTimestamps(ctx context.Context, unix int) (*File, error) {
 	return file, nil
 }
 
+// Extract unpacks the file as an archive of the given format, detecting the
+// format if it's empty. Compressed tarballs are detected automatically.
+func (file *File) Extract(ctx context.Context, format ArchiveFormat) (*Directory, error) {
+	svcs := file.Query.Services
+	bk := file.Query.Buildkit
+	engineHostPlatform := file.Query.Platform
+
+	detach, _, err := svcs.StartBindings(ctx, file.Services)
+	if err != nil {
+		return nil, err
+	}
+	defer detach()
+
+	pbDef, err := bk.ExtractArchive(ctx, engineHostPlatform.Spec(), file.LLB, file.File, strings.ToLower(string(format)))
+	if err != nil {
+		return nil, err
+	}
+	return NewDirectory(file.Query, pbDef, "", engineHostPlatform, nil), nil
+}
+
+// WithName returns the file renamed to the given name.
+func (file *File) WithName(ctx context.Context, filename string) (*File, error) {
+	file = file.Clone()
+
+	if err := validateFileName(filename); err != nil {
+		return nil, err
+	}
+	if filename == "" || filename == "." || filename == ".." || path.Base(filename) != filename {
+		return nil, fmt.Errorf("invalid file name: %q", filename)
+	}
+
+	st, err := file.State()
+	if err != nil {
+		return nil, err
+	}
+
+	renamed := llb.Scratch().File(llb.Copy(st, file.File, filename))
+
+	def, err := renamed.Marshal(ctx, llb.Platform(file.Platform.Spec()))
+	if err != nil {
+		return nil, err
+	}
+	file.LLB = def.ToPB()
+	file.File = filename
+
+	return file, nil
+}
+
 func (file *File) Open(ctx context.Context) (io.ReadCloser, error) {
 	bk := file.Query.Buildkit
 	svcs := file.Query.Services
@@ -1,19 +1,36 @@
 package core
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"strconv"
 	"strings"
 	"syscall"
+	"time"
 
 	"github.com/dagger/dagger/engine/buildkit"
 	"github.com/moby/buildkit/client/llb"
+	dockerimage "github.com/moby/buildkit/exporter/containerimage/image"
 	bkgw "github.com/moby/buildkit/frontend/gateway/client"
 	"github.com/moby/buildkit/identity"
 	"github.com/moby/buildkit/solver/pb"
 	"github.com/vito/progrock"
 )
 
+const (
+	defaultHealthcheckInterval = 1
+	defaultHealthcheckTimeout  = 30
+	defaultHealthcheckRetries  = 3
+)
+
+type healthChecker interface {
+	Check(ctx context.Context) error
+}
+
 type portHealthChecker struct {
 	bk    *buildkit.Client
 	host  string
@@ -109,3 +126,241 @@ func (d *portHealthChecker) Check(ctx context.Context) (err error) {
 		return ctx.Err()
 	}
 }
+
+// ContainerHealthcheck configures how a container run as a service is probed
+// for readiness, replacing the default check that its exposed ports accept
+// connections. Exactly one of Command, HTTPGet or TCPPort is set.
+type ContainerHealthcheck struct {
+	// Command is run in the service's container and succeeds if it exits 0.
+	Command []string `json:"command,omitempty"`
+	// HTTPGet requests a path from the service and checks the response status.
+	HTTPGet *HealthcheckHTTPGet `json:"httpGet,omitempty"`
+	// TCPPort is a port the service must accept connections on.
+	TCPPort int `json:"tcpPort,omitempty"`
+
+	// Interval is the number of seconds to wait between probes.
+	Interval int `json:"interval"`
+	// Timeout is the number of seconds a probe may take before it fails.
+	Timeout int `json:"timeout"`
+	// Retries is the number of consecutive failed probes after which the
+	// service is considered unhealthy.
+	Retries int `json:"retries"`
+	// StartPeriod is the number of seconds to give the service to start, during
+	// which failed probes don't count towards Retries.
+	StartPeriod int `json:"startPeriod"`
+}
+
+type HealthcheckHTTPGet struct {
+	Path         string `doc:"The path to request." default:"/"`
+	Port         int    `doc:"The port to send the request to."`
+	ExpectStatus int    `doc:"The response status the service is healthy with." default:"200"`
+}
+
+func (HealthcheckHTTPGet) TypeName() string {
+	return "HealthcheckHTTPGet"
+}
+
+func (HealthcheckHTTPGet) TypeDescription() string {
+	return "An HTTP request used to probe a service's health."
+}
+
+func (hc *ContainerHealthcheck) validate() error {
+	probes := 0
+	if len(hc.Command) > 0 {
+		probes++
+	}
+	if hc.HTTPGet != nil {
+		probes++
+		if hc.HTTPGet.Port <= 0 {
+			return fmt.Errorf("invalid httpGet port %d", hc.HTTPGet.Port)
+		}
+	}
+	if hc.TCPPort != 0 {
+		probes++
+		if hc.TCPPort < 0 {
+			return fmt.Errorf("invalid tcp port %d", hc.TCPPort)
+		}
+	}
+	switch {
+	case probes == 0:
+		return errors.New("one of command, httpGet or tcpPort must be set")
+	case probes > 1:
+		return errors.New("only one of command, httpGet or tcpPort may be set")
+	case hc.Interval <= 0:
+		return fmt.Errorf("invalid interval %d: must be positive", hc.Interval)
+	case hc.Timeout <= 0:
+		return fmt.Errorf("invalid timeout %d: must be positive", hc.Timeout)
+	case hc.Retries <= 0:
+		return fmt.Errorf("invalid retries %d: must be positive", hc.Retries)
+	case hc.StartPeriod < 0:
+		return fmt.Errorf("invalid start period %d: must not be negative", hc.StartPeriod)
+	}
+	return nil
+}
+
+// probeRequest returns the process to run in the service's container for a
+// single probe. HTTP and TCP probes are made from inside the container by the
+// shim, so they don't depend on any tools being installed in the image.
+func (hc *ContainerHealthcheck) probeRequest(svcProc bkgw.StartRequest) bkgw.StartRequest {
+	req := bkgw.StartRequest{
+		Env:          svcProc.Env,
+		Cwd:          svcProc.Cwd,
+		User:         svcProc.User,
+		SecretEnv:    svcProc.SecretEnv,
+		SecurityMode: svcProc.SecurityMode,
+	}
+	switch {
+	case hc.HTTPGet != nil:
+		path := hc.HTTPGet.Path
+		if !strings.HasPrefix(path, "/") {
+			path = "/" + path
+		}
+		req.Args = []string{ShimPath, "probe", "http",
+			fmt.Sprintf("http://127.0.0.1:%d%s", hc.HTTPGet.Port, path),
+			strconv.Itoa(hc.HTTPGet.ExpectStatus)}
+		req.Env = append(cloneSlice(req.Env), "_DAGGER_INTERNAL_COMMAND=")
+	case hc.TCPPort != 0:
+		req.Args = []string{ShimPath, "probe", "tcp", fmt.Sprintf("127.0.0.1:%d", hc.TCPPort)}
+		req.Env = append(cloneSlice(req.Env), "_DAGGER_INTERNAL_COMMAND=")
+	default:
+		req.Args = hc.Command
+	}
+	return req
+}
+
+// healthcheckFromImage returns the healthcheck configured by a Docker image
+// config, and whether the image configures one at all. A HEALTHCHECK NONE
+// results in a nil healthcheck.
+func healthcheckFromImage(cfgBytes []byte) (*ContainerHealthcheck, bool) {
+	var img dockerimage.Image
+	if err := json.Unmarshal(cfgBytes, &img); err != nil {
+		return nil, false
+	}
+	cfg := img.Config.Healthcheck
+	if cfg == nil || len(cfg.Test) == 0 {
+		return nil, false
+	}
+
+	hc := &ContainerHealthcheck{
+		Interval:    defaultHealthcheckInterval,
+		Timeout:     defaultHealthcheckTimeout,
+		Retries:     defaultHealthcheckRetries,
+		StartPeriod: durationSeconds(cfg.StartPeriod),
+	}
+	switch cfg.Test[0] {
+	case "CMD":
+		hc.Command = cfg.Test[1:]
+	case "CMD-SHELL":
+		shell := []string(img.Config.Shell)
+		if len(shell) == 0 {
+			shell = []string{"/bin/sh", "-c"}
+		}
+		hc.Command = append(cloneSlice(shell), strings.Join(cfg.Test[1:], " "))
+	default: // NONE
+		return nil, true
+	}
+	if len(hc.Command) == 0 {
+		return nil, true
+	}
+	if cfg.Interval > 0 {
+		hc.Interval = durationSeconds(cfg.Interval)
+	}
+	if cfg.Timeout > 0 {
+		hc.Timeout = durationSeconds(cfg.Timeout)
+	}
+	if cfg.Retries > 0 {
+		hc.Retries = cfg.Retries
+	}
+	return hc, true
+}
+
+// durationSeconds rounds a duration up to whole seconds.
+func durationSeconds(d time.Duration) int {
+	return int((d + time.Second - 1) / time.Second)
+}
+
+// execHealthChecker probes a running service with its container's
+// healthcheck until it's healthy or has failed too many times.
+type execHealthChecker struct {
+	hc      *ContainerHealthcheck
+	ctr     bkgw.Container
+	svcProc bkgw.StartRequest
+}
+
+func newExecHealth(hc *ContainerHealthcheck, ctr bkgw.Container, svcProc bkgw.StartRequest) *execHealthChecker {
+	return &execHealthChecker{
+		hc:      hc,
+		ctr:     ctr,
+		svcProc: svcProc,
+	}
+}
+
+func (d *execHealthChecker) Check(ctx context.Context) (err error) {
+	req := d.hc.probeRequest(d.svcProc)
+
+	// always show health checks
+	ctx, vtx := progrock.Span(ctx, identity.NewID(), "healthcheck "+strings.Join(req.Args, " "))
+	defer func() { vtx.Done(err) }()
+
+	interval := time.Duration(d.hc.Interval) * time.Second
+	startPeriod := time.Duration(d.hc.StartPeriod) * time.Second
+	started := time.Now()
+
+	var failures int
+	for {
+		output, err := d.probe(ctx, req, vtx.Stdout(), vtx.Stderr())
+		if err == nil {
+			return nil
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if time.Since(started) >= startPeriod {
+			failures++
+		}
+		if failures >= d.hc.Retries {
+			return fmt.Errorf("unhealthy after %d consecutive failed probes: %w\nlast probe output:\n%s",
+				failures, err, output)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(interval):
+		}
+	}
+}
+
+func (d *execHealthChecker) probe(ctx context.Context, req bkgw.StartRequest, stdout, stderr io.Writer) (string, error) {
+	timeout := time.Duration(d.hc.Timeout) * time.Second
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	output := new(bytes.Buffer)
+	req.Stdout = nopCloser{io.MultiWriter(stdout, output)}
+	req.Stderr = nopCloser{io.MultiWriter(stderr, output)}
+
+	proc, err := d.ctr.Start(ctx, req)
+	if err != nil {
+		return output.String(), err
+	}
+
+	exited := make(chan error, 1)
+	go func() {
+		exited <- proc.Wait()
+	}()
+
+	select {
+	case err := <-exited:
+		return output.String(), err
+	case <-ctx.Done():
+		if err := proc.Signal(context.Background(), syscall.SIGKILL); err != nil {
+			return output.String(), fmt.Errorf("interrupt probe: %w", err)
+		}
+		<-exited
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return output.String(), fmt.Errorf("probe timed out after %s", timeout)
+		}
+		return output.String(), ctx.Err()
+	}
+}
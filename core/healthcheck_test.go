@@ -0,0 +1,90 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestHealthcheckFromImage(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		config string
+		found  bool
+		want   *ContainerHealthcheck
+	}{
+		{
+			name:   "none configured",
+			config: `{"config":{"Cmd":["sh"]}}`,
+		},
+		{
+			name:   "disabled",
+			config: `{"config":{"Healthcheck":{"Test":["NONE"]}}}`,
+			found:  true,
+		},
+		{
+			name:   "exec form with defaults",
+			config: `{"config":{"Healthcheck":{"Test":["CMD","pg_isready","-U","postgres"]}}}`,
+			found:  true,
+			want: &ContainerHealthcheck{
+				Command:  []string{"pg_isready", "-U", "postgres"},
+				Interval: defaultHealthcheckInterval,
+				Timeout:  defaultHealthcheckTimeout,
+				Retries:  defaultHealthcheckRetries,
+			},
+		},
+		{
+			name:   "shell form with durations",
+			config: `{"config":{"Healthcheck":{"Test":["CMD-SHELL","curl -f localhost || exit 1"],"Interval":5000000000,"Timeout":1500000000,"StartPeriod":10000000000,"Retries":5}}}`,
+			found:  true,
+			want: &ContainerHealthcheck{
+				Command:     []string{"/bin/sh", "-c", "curl -f localhost || exit 1"},
+				Interval:    5,
+				Timeout:     2,
+				Retries:     5,
+				StartPeriod: 10,
+			},
+		},
+		{
+			name:   "shell form with custom shell",
+			config: `{"config":{"Shell":["pwsh","-Command"],"Healthcheck":{"Test":["CMD-SHELL","exit 0"]}}}`,
+			found:  true,
+			want: &ContainerHealthcheck{
+				Command:  []string{"pwsh", "-Command", "exit 0"},
+				Interval: defaultHealthcheckInterval,
+				Timeout:  defaultHealthcheckTimeout,
+				Retries:  defaultHealthcheckRetries,
+			},
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			hc, found := healthcheckFromImage([]byte(tc.config))
+			require.Equal(t, tc.found, found)
+			require.Equal(t, tc.want, hc)
+		})
+	}
+}
+
+func TestHealthcheckValidate(t *testing.T) {
+	valid := ContainerHealthcheck{Interval: 1, Timeout: 1, Retries: 1}
+
+	hc := valid
+	require.ErrorContains(t, hc.validate(), "one of command, httpGet or tcpPort must be set")
+
+	hc = valid
+	hc.Command = []string{"true"}
+	require.NoError(t, hc.validate())
+
+	hc.HTTPGet = &HealthcheckHTTPGet{Path: "/", Port: 80, ExpectStatus: 200}
+	require.ErrorContains(t, hc.validate(), "only one of")
+
+	hc = valid
+	hc.HTTPGet = &HealthcheckHTTPGet{Path: "/"}
+	require.ErrorContains(t, hc.validate(), "invalid httpGet port 0")
+
+	hc = valid
+	hc.TCPPort = 5432
+	hc.Retries = 0
+	require.ErrorContains(t, hc.validate(), "invalid retries 0")
+}
@@ -120,6 +120,80 @@ func TestCacheVolumeWithSubmount(t *testing.T) {
 	})
 }
 
+func TestCacheVolumeManagement(t *testing.T) {
+	t.Parallel()
+	c, ctx := connect(t)
+
+	key := identity.NewID()
+	cache := c.CacheVolume(key)
+
+	_, err := c.Container().From(alpineImage).
+		WithMountedCache("/cache", cache).
+		WithExec([]string{"sh", "-c", "echo hello > /cache/greeting"}).
+		Sync(ctx)
+	require.NoError(t, err)
+
+	t.Run("snapshot", func(t *testing.T) {
+		contents, err := cache.Snapshot().File("greeting").Contents(ctx)
+		require.NoError(t, err)
+		require.Equal(t, "hello\n", contents)
+	})
+
+	t.Run("size and last use", func(t *testing.T) {
+		size, err := cache.Size(ctx)
+		require.NoError(t, err)
+		require.Greater(t, size, 0)
+
+		usedAt, err := cache.MostRecentUseTime(ctx)
+		require.NoError(t, err)
+		require.Greater(t, usedAt, 0)
+	})
+
+	t.Run("listed", func(t *testing.T) {
+		caches, err := c.CacheVolumes(ctx)
+		require.NoError(t, err)
+		var keys []string
+		for _, cache := range caches {
+			k, err := cache.Key(ctx)
+			require.NoError(t, err)
+			keys = append(keys, k)
+		}
+		require.Contains(t, keys, key)
+	})
+
+	t.Run("clear", func(t *testing.T) {
+		_, err := cache.Clear(ctx)
+		require.NoError(t, err)
+
+		entries, err := cache.Snapshot().Entries(ctx)
+		require.NoError(t, err)
+		require.Empty(t, entries)
+
+		out, err := c.Container().From(alpineImage).
+			WithMountedCache("/cache", cache).
+			WithEnvVariable("CACHEBUST", identity.NewID()).
+			WithExec([]string{"ls", "-A", "/cache"}).
+			Stdout(ctx)
+		require.NoError(t, err)
+		require.Empty(t, out)
+	})
+
+	t.Run("seed", func(t *testing.T) {
+		_, err := cache.Seed(ctx, c.Directory().
+			WithNewFile("greeting", "hi\n").
+			WithNewFile("sub/name", "world\n"))
+		require.NoError(t, err)
+
+		out, err := c.Container().From(alpineImage).
+			WithMountedCache("/cache", cache).
+			WithEnvVariable("CACHEBUST", identity.NewID()).
+			WithExec([]string{"cat", "/cache/greeting", "/cache/sub/name"}).
+			Stdout(ctx)
+		require.NoError(t, err)
+		require.Equal(t, "hi\nworld\n", out)
+	})
+}
+
 func TestLocalImportCacheReuse(t *testing.T) {
 	t.Parallel()
 
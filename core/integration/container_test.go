@@ -13,6 +13,7 @@ import (
 	"strconv"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/containerd/containerd/platforms"
 	"github.com/google/go-containerregistry/pkg/name"
@@ -462,6 +463,143 @@ func TestContainerExecRedirectStdoutStderr(t *testing.T) {
 	require.Equal(t, "goodbye\n", stderr)
 }
 
+func TestContainerExecExpect(t *testing.T) {
+	t.Parallel()
+
+	c, ctx := connect(t)
+
+	ctr := c.Container().From(alpineImage)
+
+	t.Run("success", func(t *testing.T) {
+		code, err := ctr.WithExec([]string{"true"}).ExitCode(ctx)
+		require.NoError(t, err)
+		require.Equal(t, 0, code)
+	})
+
+	t.Run("expected failure", func(t *testing.T) {
+		failed := ctr.WithExec(
+			[]string{"sh", "-c", "echo report > /report.txt; echo oops >&2; exit 3"},
+			dagger.ContainerWithExecOpts{Expect: dagger.Failure},
+		)
+
+		code, err := failed.ExitCode(ctx)
+		require.NoError(t, err)
+		require.Equal(t, 3, code)
+
+		stderr, err := failed.Stderr(ctx)
+		require.NoError(t, err)
+		require.Equal(t, "oops\n", stderr)
+
+		report, err := failed.File("/report.txt").Contents(ctx)
+		require.NoError(t, err)
+		require.Equal(t, "report\n", report)
+	})
+
+	t.Run("unexpected success", func(t *testing.T) {
+		_, err := ctr.WithExec([]string{"true"}, dagger.ContainerWithExecOpts{
+			Expect: dagger.Failure,
+		}).Sync(ctx)
+		require.ErrorContains(t, err, "expected a non-zero exit code")
+	})
+
+	t.Run("any", func(t *testing.T) {
+		for _, want := range []int{0, 1, 42} {
+			code, err := ctr.WithExec(
+				[]string{"sh", "-c", fmt.Sprintf("exit %d", want)},
+				dagger.ContainerWithExecOpts{Expect: dagger.Any},
+			).ExitCode(ctx)
+			require.NoError(t, err)
+			require.Equal(t, want, code)
+		}
+	})
+
+	t.Run("unexpected failure", func(t *testing.T) {
+		_, err := ctr.WithExec([]string{"sh", "-c", "exit 3"}).ExitCode(ctx)
+		var exErr *dagger.ExecError
+		require.ErrorAs(t, err, &exErr)
+		require.Equal(t, 3, exErr.ExitCode)
+	})
+}
+
+func TestContainerExecResourceLimits(t *testing.T) {
+	t.Parallel()
+
+	c, ctx := connect(t)
+
+	ctr := c.Container().From(alpineImage)
+
+	t.Run("timeout", func(t *testing.T) {
+		_, err := ctr.WithExec([]string{"sleep", "60"}, dagger.ContainerWithExecOpts{
+			Timeout: 1,
+		}).Sync(ctx)
+
+		var timeoutErr *dagger.ExecTimeoutError
+		require.ErrorAs(t, err, &timeoutErr)
+		require.Equal(t, time.Second, timeoutErr.Timeout)
+		require.Contains(t, timeoutErr.Stderr, "timed out after 1s")
+
+		// timeouts are still exec errors
+		var execErr *dagger.ExecError
+		require.ErrorAs(t, err, &execErr)
+	})
+
+	t.Run("timeout not exceeded", func(t *testing.T) {
+		out, err := ctr.WithExec([]string{"echo", "hi"}, dagger.ContainerWithExecOpts{
+			Timeout: 60,
+		}).Stdout(ctx)
+		require.NoError(t, err)
+		require.Equal(t, "hi\n", out)
+	})
+
+	t.Run("cgroup limits", func(t *testing.T) {
+		out, err := ctr.WithExec([]string{"cat",
+			"/sys/fs/cgroup/memory.max",
+			"/sys/fs/cgroup/cpu.max",
+			"/sys/fs/cgroup/pids.max",
+		}, dagger.ContainerWithExecOpts{
+			MemoryLimit: "256M",
+			CPUQuota:    50000,
+			PidsLimit:   32,
+		}).Stdout(ctx)
+		require.NoError(t, err)
+		require.Equal(t, "268435456\n50000 100000\n32\n", out)
+	})
+
+	t.Run("container defaults", func(t *testing.T) {
+		limited := ctr.WithResourceLimits(dagger.ContainerWithResourceLimitsOpts{
+			PidsLimit: 16,
+			Timeout:   1,
+		})
+
+		out, err := limited.WithExec([]string{"cat", "/sys/fs/cgroup/pids.max"}).Stdout(ctx)
+		require.NoError(t, err)
+		require.Equal(t, "16\n", out)
+
+		// per-exec limits take precedence
+		out, err = limited.WithExec([]string{"cat", "/sys/fs/cgroup/pids.max"}, dagger.ContainerWithExecOpts{
+			PidsLimit: 8,
+		}).Stdout(ctx)
+		require.NoError(t, err)
+		require.Equal(t, "8\n", out)
+
+		_, err = limited.WithExec([]string{"sleep", "60"}).Sync(ctx)
+		var timeoutErr *dagger.ExecTimeoutError
+		require.ErrorAs(t, err, &timeoutErr)
+	})
+
+	t.Run("invalid limits", func(t *testing.T) {
+		_, err := ctr.WithExec([]string{"true"}, dagger.ContainerWithExecOpts{
+			MemoryLimit: "lots",
+		}).Sync(ctx)
+		require.ErrorContains(t, err, "invalid memory limit")
+
+		_, err = ctr.WithResourceLimits(dagger.ContainerWithResourceLimitsOpts{
+			Timeout: -1,
+		}).Sync(ctx)
+		require.ErrorContains(t, err, "invalid timeout")
+	})
+}
+
 func TestContainerExecWithWorkdir(t *testing.T) {
 	t.Parallel()
 
@@ -2,6 +2,7 @@ package core
 
 import (
 	"os"
+	"path/filepath"
 	"regexp"
 	"strings"
 	"testing"
@@ -499,6 +500,221 @@ func TestDirectoryWithTimestamps(t *testing.T) {
 	})
 }
 
+func TestDirectoryWithSymlink(t *testing.T) {
+	t.Parallel()
+
+	c, ctx := connect(t)
+
+	dir := c.Directory().
+		WithNewFile("lib/foo.so", "foo").
+		WithSymlink("../lib/foo.so", "bin/foo").
+		WithSymlink("foo.so", "lib/bar.so")
+
+	out, err := c.Container().From(alpineImage).
+		WithMountedDirectory("/mnt", dir).
+		WithExec([]string{"sh", "-c", "readlink /mnt/bin/foo /mnt/lib/bar.so && cat /mnt/bin/foo"}).
+		Stdout(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "../lib/foo.so\nfoo.so\nfoo", out)
+
+	t.Run("replaces existing entry", func(t *testing.T) {
+		out, err := c.Container().From(alpineImage).
+			WithMountedDirectory("/mnt", dir.WithSymlink("/etc/hosts", "lib/foo.so")).
+			WithExec([]string{"readlink", "/mnt/lib/foo.so"}).
+			Stdout(ctx)
+		require.NoError(t, err)
+		require.Equal(t, "/etc/hosts\n", out)
+	})
+
+	t.Run("outside parent", func(t *testing.T) {
+		_, err := dir.WithSymlink("foo", "../foo").Sync(ctx)
+		require.ErrorContains(t, err, "cannot create symlink outside parent")
+	})
+}
+
+func TestDirectoryWithPermissions(t *testing.T) {
+	t.Parallel()
+
+	c, ctx := connect(t)
+
+	dir := c.Directory().
+		WithNewFile("run.sh", "echo hi").
+		WithNewFile("sub/a", "a").
+		WithNewFile("sub/b", "b")
+
+	stat := func(dir *dagger.Directory, paths ...string) string {
+		out, err := c.Container().From(alpineImage).
+			WithMountedDirectory("/mnt", dir).
+			WithWorkdir("/mnt").
+			WithExec(append([]string{"stat", "-c", "%n:%a"}, paths...)).
+			Stdout(ctx)
+		require.NoError(t, err)
+		return out
+	}
+
+	t.Run("file", func(t *testing.T) {
+		require.Equal(t, "run.sh:755\n", stat(dir.WithPermissions("run.sh", 0o755), "run.sh"))
+	})
+
+	t.Run("directory", func(t *testing.T) {
+		require.Equal(t,
+			"sub:700\nsub/a:644\nsub/b:644\n",
+			stat(dir.WithPermissions("sub", 0o700), "sub", "sub/a", "sub/b"))
+	})
+
+	t.Run("recursive", func(t *testing.T) {
+		require.Equal(t,
+			"sub:700\nsub/a:700\nsub/b:700\nrun.sh:644\n",
+			stat(dir.WithPermissions("sub", 0o700, dagger.DirectoryWithPermissionsOpts{Recursive: true}), "sub", "sub/a", "sub/b", "run.sh"))
+	})
+}
+
+func TestDirectoryWithOwner(t *testing.T) {
+	t.Parallel()
+
+	c, ctx := connect(t)
+
+	dir := c.Directory().
+		WithNewFile("etc/passwd", "root:x:0:0:root:/root:/bin/sh\nfoo:x:1000:1000::/home/foo:/bin/sh\n").
+		WithNewFile("etc/group", "root:x:0:\nbar:x:1001:\n").
+		WithNewFile("data/a", "a").
+		WithNewFile("b", "b")
+
+	stat := func(dir *dagger.Directory, paths ...string) string {
+		out, err := c.Container().From(alpineImage).
+			WithMountedDirectory("/mnt", dir).
+			WithWorkdir("/mnt").
+			WithExec(append([]string{"stat", "-c", "%n:%u:%g"}, paths...)).
+			Stdout(ctx)
+		require.NoError(t, err)
+		return out
+	}
+
+	require.Equal(t,
+		"data:1000:1000\ndata/a:1000:1000\nb:0:0\n",
+		stat(dir.WithOwner("data", "1000"), "data", "data/a", "b"))
+
+	require.Equal(t,
+		"data:1000:1001\ndata/a:1000:1001\n",
+		stat(dir.WithOwner("data", "foo:bar"), "data", "data/a"))
+
+	// names are resolved against the subdirectory's own /etc/passwd
+	sub := c.Directory().WithDirectory("sub", dir).Directory("sub")
+	require.Equal(t,
+		"data:1000:1001\n",
+		stat(sub.WithOwner("data", "foo:bar"), "data"))
+}
+
+func TestDirectoryWithMove(t *testing.T) {
+	t.Parallel()
+
+	c, ctx := connect(t)
+
+	dir := c.Directory().
+		WithNewFile("build/out/app", "app").
+		WithNewDirectory("dist")
+
+	t.Run("rename", func(t *testing.T) {
+		moved := dir.WithMove("build/out", "release")
+
+		entries, err := moved.Entries(ctx)
+		require.NoError(t, err)
+		require.ElementsMatch(t, []string{"build", "dist", "release"}, entries)
+
+		contents, err := moved.File("release/app").Contents(ctx)
+		require.NoError(t, err)
+		require.Equal(t, "app", contents)
+
+		entries, err = moved.Entries(ctx, dagger.DirectoryEntriesOpts{Path: "build"})
+		require.NoError(t, err)
+		require.Empty(t, entries)
+	})
+
+	t.Run("into existing directory", func(t *testing.T) {
+		contents, err := dir.WithMove("build/out/app", "dist").File("dist/app").Contents(ctx)
+		require.NoError(t, err)
+		require.Equal(t, "app", contents)
+	})
+
+	t.Run("into itself", func(t *testing.T) {
+		_, err := dir.WithMove("build", "build/out/build").Sync(ctx)
+		require.ErrorContains(t, err, "into itself")
+	})
+}
+
+func TestDirectoryAsArchive(t *testing.T) {
+	t.Parallel()
+
+	c, ctx := connect(t)
+
+	dir := c.Directory().
+		WithNewFile("README.md", "readme").
+		WithNewFile("bin/run.sh", "echo hi", dagger.DirectoryWithNewFileOpts{Permissions: 0o755})
+
+	t.Run("tar.gz", func(t *testing.T) {
+		archive := dir.AsArchive(dagger.DirectoryAsArchiveOpts{Compression: dagger.Gzip})
+
+		name, err := archive.Name(ctx)
+		require.NoError(t, err)
+		require.Equal(t, "archive.tar.gz", name)
+
+		out, err := c.Container().From(alpineImage).
+			WithMountedFile("/archive.tar.gz", archive).
+			WithExec([]string{"tar", "tzvf", "/archive.tar.gz"}).
+			Stdout(ctx)
+		require.NoError(t, err)
+		require.Regexp(t, `(?m)^-rw-r--r-- .* README\.md$`, out)
+		require.Regexp(t, `(?m)^-rwxr-xr-x .* bin/run\.sh$`, out)
+	})
+
+	t.Run("zip", func(t *testing.T) {
+		archive := dir.AsArchive(dagger.DirectoryAsArchiveOpts{Format: dagger.Zip, Compression: dagger.Gzip})
+
+		out, err := c.Container().From(alpineImage).
+			WithMountedFile("/archive.zip", archive).
+			WithExec([]string{"unzip", "-p", "/archive.zip", "bin/run.sh"}).
+			Stdout(ctx)
+		require.NoError(t, err)
+		require.Equal(t, "echo hi", out)
+	})
+
+	t.Run("reproducible", func(t *testing.T) {
+		a, err := dir.WithTimestamps(1000).AsArchive().Digest(ctx)
+		require.NoError(t, err)
+		b, err := dir.WithTimestamps(2000).AsArchive().Digest(ctx)
+		require.NoError(t, err)
+		require.Equal(t, a, b)
+	})
+
+	t.Run("round trip", func(t *testing.T) {
+		for _, opts := range []dagger.DirectoryAsArchiveOpts{
+			{Format: dagger.Tar},
+			{Format: dagger.Tar, Compression: dagger.Zstd},
+			{Format: dagger.Zip},
+		} {
+			extracted := dir.AsArchive(opts).Extract()
+
+			expected, err := dir.Digest(ctx, dagger.DirectoryDigestOpts{ExcludeMetadata: true})
+			require.NoError(t, err)
+			actual, err := extracted.Digest(ctx, dagger.DirectoryDigestOpts{ExcludeMetadata: true})
+			require.NoError(t, err)
+			require.Equal(t, expected, actual, opts)
+
+			contents, err := c.Directory().
+				WithArchive("vendor", dir.AsArchive(opts)).
+				File("vendor/bin/run.sh").
+				Contents(ctx)
+			require.NoError(t, err)
+			require.Equal(t, "echo hi", contents)
+		}
+	})
+
+	t.Run("unsupported compression", func(t *testing.T) {
+		_, err := dir.AsArchive(dagger.DirectoryAsArchiveOpts{Format: dagger.Zip, Compression: dagger.Zstd}).Sync(ctx)
+		require.ErrorContains(t, err, "not supported")
+	})
+}
+
 func TestDirectoryWithoutDirectoryWithoutFile(t *testing.T) {
 	t.Parallel()
 	c, ctx := connect(t)
@@ -650,6 +866,120 @@ func TestDirectoryDiff(t *testing.T) {
 	*/
 }
 
+func TestDirectoryPatch(t *testing.T) {
+	t.Parallel()
+
+	c, ctx := connect(t)
+
+	before := c.Directory().
+		WithNewFile("README.md", "# hello\n\nsome text\n").
+		WithNewFile("main.go", "package main\n").
+		WithNewFile("old.txt", "bye\n")
+	after := before.
+		WithNewFile("README.md", "# hello\n\nsome other text\n").
+		WithNewFile("cmd/run.go", "package cmd\n").
+		WithoutFile("old.txt")
+
+	patch := before.AsPatch(after)
+
+	contents, err := patch.Contents(ctx)
+	require.NoError(t, err)
+	require.Contains(t, contents, "diff --git a/README.md b/README.md\n--- a/README.md\n+++ b/README.md\n")
+	require.Contains(t, contents, "-some text\n+some other text\n")
+	require.Contains(t, contents, "deleted file mode 100644\n--- a/old.txt\n+++ /dev/null\n")
+	require.NotContains(t, contents, "main.go")
+
+	t.Run("round trip", func(t *testing.T) {
+		expected, err := after.Digest(ctx, dagger.DirectoryDigestOpts{ExcludeMetadata: true})
+		require.NoError(t, err)
+		actual, err := before.WithPatch(patch).Digest(ctx, dagger.DirectoryDigestOpts{ExcludeMetadata: true})
+		require.NoError(t, err)
+		require.Equal(t, expected, actual)
+	})
+
+	t.Run("strip", func(t *testing.T) {
+		patch := c.Directory().WithNewFile("fix.patch", `--- orig/src/main.go
++++ new/src/main.go
+@@ -1 +1,3 @@
+ package main
++
++func main() {}
+`).File("fix.patch")
+
+		contents, err := before.WithPatch(patch, dagger.DirectoryWithPatchOpts{Strip: 2}).File("main.go").Contents(ctx)
+		require.NoError(t, err)
+		require.Equal(t, "package main\n\nfunc main() {}\n", contents)
+
+		_, err = before.WithPatch(patch).Sync(ctx)
+		require.ErrorContains(t, err, "src/main.go: no such file to patch")
+	})
+
+	t.Run("conflict", func(t *testing.T) {
+		_, err := before.
+			WithNewFile("README.md", "# goodbye\n\nsome text\n").
+			WithPatch(before.WithNewFile("README.md", "# hello\n\nother\n").AsPatch(after)).
+			Sync(ctx)
+		require.ErrorContains(t, err, "README.md: hunk #1")
+		require.ErrorContains(t, err, "does not apply")
+	})
+
+	t.Run("series", func(t *testing.T) {
+		created := before.WithNewFile("cmd/run.go", "package cmd\n")
+		edited := created.WithNewFile("cmd/run.go", "package cmd\n\nfunc Run() {}\n")
+		first, err := before.AsPatch(created).Contents(ctx)
+		require.NoError(t, err)
+		second, err := created.AsPatch(edited).Contents(ctx)
+		require.NoError(t, err)
+		series := c.Directory().WithNewFile("series.patch", first+second).File("series.patch")
+
+		contents, err := before.WithPatch(series).File("cmd/run.go").Contents(ctx)
+		require.NoError(t, err)
+		require.Equal(t, "package cmd\n\nfunc Run() {}\n", contents)
+	})
+
+	t.Run("symlinks and binaries", func(t *testing.T) {
+		withLinks := before.
+			WithSymlink("main.go", "link").
+			WithNewFile("data.bin", "\x00\x01\x02")
+		changed := withLinks.
+			WithoutFile("link").
+			WithSymlink("README.md", "link").
+			WithSymlink("link", "main.go").
+			WithNewFile("data.bin", "\x00\x03")
+		patch := withLinks.AsPatch(changed)
+
+		contents, err := patch.Contents(ctx)
+		require.NoError(t, err)
+		require.Contains(t, contents, "diff --git a/main.go b/main.go\ndeleted file mode 100644\n")
+		require.Contains(t, contents, "diff --git a/main.go b/main.go\nnew file mode 120000\n")
+		require.Contains(t, contents, "GIT binary patch\n")
+
+		expected, err := changed.Digest(ctx, dagger.DirectoryDigestOpts{ExcludeMetadata: true})
+		require.NoError(t, err)
+		actual, err := withLinks.WithPatch(patch).Digest(ctx, dagger.DirectoryDigestOpts{ExcludeMetadata: true})
+		require.NoError(t, err)
+		require.Equal(t, expected, actual)
+	})
+
+	t.Run("ownership", func(t *testing.T) {
+		owned := before.
+			WithNewDirectory("cmd", dagger.DirectoryWithNewDirectoryOpts{Permissions: 0o700}).
+			WithNewFile("cmd/main.go", "package main\n").
+			WithOwner("cmd", "1000:1000")
+		patch := owned.AsPatch(owned.
+			WithNewFile("cmd/main.go", "package main\n\nfunc main() {}\n").
+			WithNewFile("cmd/util.go", "package main\n"))
+
+		out, err := c.Container().From(alpineImage).
+			WithMountedDirectory("/mnt", owned.WithPatch(patch)).
+			WithWorkdir("/mnt").
+			WithExec([]string{"stat", "-c", "%n:%u:%g:%a", "cmd", "cmd/main.go", "cmd/util.go"}).
+			Stdout(ctx)
+		require.NoError(t, err)
+		require.Equal(t, "cmd:1000:1000:700\ncmd/main.go:1000:1000:644\ncmd/util.go:1000:1000:644\n", out)
+	})
+}
+
 func TestDirectoryExport(t *testing.T) {
 	t.Parallel()
 
@@ -687,6 +1017,60 @@ func TestDirectoryExport(t *testing.T) {
 	})
 }
 
+func TestDirectoryExportWipe(t *testing.T) {
+	t.Parallel()
+
+	c, ctx := connect(t)
+
+	dest := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dest, "same"), []byte("same"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dest, "modified"), []byte("before"), 0o644))
+	require.NoError(t, os.MkdirAll(filepath.Join(dest, "stale"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dest, "stale", "file"), []byte("bye"), 0o644))
+
+	dir := c.Directory().
+		WithNewFile("same", "same").
+		WithNewFile("modified", "after").
+		WithNewFile("new/file", "hi")
+
+	t.Run("dry run", func(t *testing.T) {
+		changes, err := dir.ExportDryRun(ctx, dest)
+		require.NoError(t, err)
+		require.Equal(t, []string{"M modified", "A new/", "A new/file"}, changes)
+
+		changes, err = dir.ExportDryRun(ctx, dest, dagger.DirectoryExportDryRunOpts{Wipe: true})
+		require.NoError(t, err)
+		require.Equal(t, []string{"M modified", "A new/", "A new/file", "D stale/", "D stale/file"}, changes)
+
+		changes, err = dir.ExportDryRun(ctx, filepath.Join(dest, "missing"), dagger.DirectoryExportDryRunOpts{Wipe: true})
+		require.NoError(t, err)
+		require.Equal(t, []string{"A modified", "A new/", "A new/file", "A same"}, changes)
+
+		// nothing was written
+		entries, err := ls(dest)
+		require.NoError(t, err)
+		require.Equal(t, []string{"modified", "same", "stale"}, entries)
+	})
+
+	t.Run("wipe", func(t *testing.T) {
+		ok, err := dir.Export(ctx, dest, dagger.DirectoryExportOpts{Wipe: true})
+		require.NoError(t, err)
+		require.True(t, ok)
+
+		entries, err := ls(dest)
+		require.NoError(t, err)
+		require.Equal(t, []string{"modified", "new", "same"}, entries)
+
+		contents, err := os.ReadFile(filepath.Join(dest, "modified"))
+		require.NoError(t, err)
+		require.Equal(t, "after", string(contents))
+
+		changes, err := dir.ExportDryRun(ctx, dest, dagger.DirectoryExportDryRunOpts{Wipe: true})
+		require.NoError(t, err)
+		require.Empty(t, changes)
+	})
+}
+
 func TestDirectoryDockerBuild(t *testing.T) {
 	t.Parallel()
 	c, ctx := connect(t)
@@ -998,6 +1382,146 @@ func TestDirectorySync(t *testing.T) {
 	})
 }
 
+func TestDirectoryDigest(t *testing.T) {
+	t.Parallel()
+
+	c, ctx := connect(t)
+
+	dir := c.Directory().
+		WithNewFile("some-file", "some-content").
+		WithNewFile("some-dir/sub-file", "sub-content")
+
+	dgst, err := dir.Digest(ctx)
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(dgst, "sha256:"))
+
+	t.Run("stable", func(t *testing.T) {
+		again, err := c.Directory().
+			WithNewFile("some-file", "some-content").
+			WithNewFile("some-dir/sub-file", "sub-content").
+			Digest(ctx)
+		require.NoError(t, err)
+		require.Equal(t, dgst, again)
+	})
+
+	t.Run("contents change", func(t *testing.T) {
+		changed, err := dir.WithNewFile("some-file", "other-content").Digest(ctx)
+		require.NoError(t, err)
+		require.NotEqual(t, dgst, changed)
+	})
+
+	t.Run("exclude metadata", func(t *testing.T) {
+		chmodded := c.Directory().
+			WithNewFile("some-file", "some-content", dagger.DirectoryWithNewFileOpts{Permissions: 0o600}).
+			WithNewFile("some-dir/sub-file", "sub-content")
+
+		withMeta, err := chmodded.Digest(ctx)
+		require.NoError(t, err)
+		require.NotEqual(t, dgst, withMeta)
+
+		a, err := dir.Digest(ctx, dagger.DirectoryDigestOpts{ExcludeMetadata: true})
+		require.NoError(t, err)
+		b, err := chmodded.Digest(ctx, dagger.DirectoryDigestOpts{ExcludeMetadata: true})
+		require.NoError(t, err)
+		require.Equal(t, a, b)
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		_, err := c.Directory().Digest(ctx)
+		require.NoError(t, err)
+
+		scratch, err := c.Directory().Digest(ctx, dagger.DirectoryDigestOpts{ExcludeMetadata: true})
+		require.NoError(t, err)
+		emptySubdir, err := c.Directory().
+			WithNewDirectory("empty").
+			Directory("empty").
+			Digest(ctx, dagger.DirectoryDigestOpts{ExcludeMetadata: true})
+		require.NoError(t, err)
+		require.Equal(t, emptySubdir, scratch)
+	})
+}
+
+func TestDirectoryStat(t *testing.T) {
+	t.Parallel()
+
+	c, ctx := connect(t)
+
+	dir := c.Directory().
+		WithNewFile("bin/run.sh", "echo hi", dagger.DirectoryWithNewFileOpts{Permissions: 0o755}).
+		WithSymlink("run.sh", "bin/run").
+		WithTimestamps(1672531199)
+
+	t.Run("file", func(t *testing.T) {
+		stat := dir.Stat("bin/run.sh")
+
+		name, err := stat.Name(ctx)
+		require.NoError(t, err)
+		require.Equal(t, "run.sh", name)
+
+		typ, err := stat.FileType(ctx)
+		require.NoError(t, err)
+		require.Equal(t, dagger.Regular, typ)
+
+		mode, err := stat.Mode(ctx)
+		require.NoError(t, err)
+		require.Equal(t, 0o755, mode)
+
+		size, err := stat.Size(ctx)
+		require.NoError(t, err)
+		require.Equal(t, len("echo hi"), size)
+
+		mtime, err := stat.Mtime(ctx)
+		require.NoError(t, err)
+		require.Equal(t, 1672531199, mtime)
+
+		uid, err := stat.UID(ctx)
+		require.NoError(t, err)
+		require.Equal(t, 0, uid)
+	})
+
+	t.Run("directory", func(t *testing.T) {
+		typ, err := dir.Stat("bin").FileType(ctx)
+		require.NoError(t, err)
+		require.Equal(t, dagger.Dir, typ)
+	})
+
+	t.Run("symlink", func(t *testing.T) {
+		stat := dir.Stat("bin/run")
+
+		typ, err := stat.FileType(ctx)
+		require.NoError(t, err)
+		require.Equal(t, dagger.Symlink, typ)
+
+		target, err := stat.LinkTarget(ctx)
+		require.NoError(t, err)
+		require.Equal(t, "run.sh", target)
+	})
+
+	t.Run("entries", func(t *testing.T) {
+		stats, err := dir.EntriesWithStat(ctx, dagger.DirectoryEntriesWithStatOpts{Path: "bin"})
+		require.NoError(t, err)
+		require.Len(t, stats, 2)
+
+		types := map[string]dagger.FileType{}
+		for _, stat := range stats {
+			name, err := stat.Name(ctx)
+			require.NoError(t, err)
+			typ, err := stat.FileType(ctx)
+			require.NoError(t, err)
+			types[name] = typ
+		}
+		require.Equal(t, map[string]dagger.FileType{
+			"run":    dagger.Symlink,
+			"run.sh": dagger.Regular,
+		}, types)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		_, err := dir.Stat("nope").Name(ctx)
+		require.ErrorContains(t, err, "no such file or directory")
+	})
+}
+
 func TestDirectoryGlob(t *testing.T) {
 	t.Parallel()
 
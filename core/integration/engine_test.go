@@ -245,3 +245,89 @@ func TestClientSendsLabelsInTelemetry(t *testing.T) {
 	require.Contains(t, receivedEvents, "dagger.io/git.title")
 	require.Contains(t, receivedEvents, "init test repo")
 }
+
+func TestEngineLocalCache(t *testing.T) {
+	t.Parallel()
+	c, ctx := connect(t)
+
+	// use a dev engine so pruning doesn't affect other tests
+	devEngine := devEngineContainer(c).
+		WithMountedCache("/var/lib/dagger", c.CacheVolume("dagger-dev-engine-state-"+identity.NewID())).
+		WithExec([]string{"--addr", "tcp://0.0.0.0:1234"}, dagger.ContainerWithExecOpts{
+			InsecureRootCapabilities: true,
+		}).
+		AsService()
+
+	clientCtr, err := engineClientContainer(ctx, t, c, devEngine)
+	require.NoError(t, err)
+
+	clientCtr = clientCtr.
+		WithNewFile("/query.graphql", dagger.ContainerWithNewFileOpts{
+			Contents: `{ container { from(address: "` + alpineImage + `") { withMountedCache(path: "/cache", cache: "` + identity.NewID() + `") { withExec(args: ["sh", "-c", "head -c 1048576 /dev/urandom > /cache/data"]) { sync } } } } }`,
+		}).
+		WithExec([]string{"dagger", "query", "--doc", "/query.graphql"})
+
+	t.Run("du", func(t *testing.T) {
+		out, err := clientCtr.
+			WithExec([]string{"dagger", "engine", "du"}).
+			Stdout(ctx)
+		require.NoError(t, err)
+		require.Contains(t, out, "exec.cachemount")
+		require.Contains(t, out, "total")
+	})
+
+	t.Run("filters all apply", func(t *testing.T) {
+		out, err := clientCtr.
+			WithExec([]string{"dagger", "engine", "du", "--filter", "type=exec.cachemount", "--filter", "type=regular"}).
+			Stdout(ctx)
+		require.NoError(t, err)
+		require.NotContains(t, out, "exec.cachemount")
+		require.NotContains(t, out, "regular")
+	})
+
+	t.Run("prune requires a flag", func(t *testing.T) {
+		_, err := clientCtr.
+			WithExec([]string{"dagger", "engine", "prune"}).
+			Sync(ctx)
+		require.ErrorContains(t, err, "pass --all")
+
+		_, err = clientCtr.
+			WithNewFile("/prune.graphql", dagger.ContainerWithNewFileOpts{
+				Contents: `{ engine { localCache { prune { recordType } } } }`,
+			}).
+			WithExec([]string{"dagger", "query", "--doc", "/prune.graphql"}).
+			Sync(ctx)
+		require.ErrorContains(t, err, "set all, or narrow it down")
+	})
+
+	t.Run("prune", func(t *testing.T) {
+		out, err := clientCtr.
+			WithExec([]string{"dagger", "engine", "prune", "--filter", "type=exec.cachemount"}).
+			WithExec([]string{"dagger", "engine", "du", "--filter", "type=exec.cachemount"}).
+			Stdout(ctx)
+		require.NoError(t, err)
+		require.NotContains(t, out, "exec.cachemount")
+	})
+}
+
+func TestEngineLocalCachePruneFromModule(t *testing.T) {
+	t.Parallel()
+	c, ctx := connect(t)
+
+	_, err := modInit(ctx, t, c, "go", `package main
+
+import "context"
+
+type Test struct{}
+
+func (m *Test) Prune(ctx context.Context) (int, error) {
+	pruned, err := dag.Engine().LocalCache().Prune(ctx, EngineCachePruneOpts{
+		Filters: []string{"id==does-not-exist"},
+	})
+	return len(pruned), err
+}
+`).
+		With(daggerCall("prune")).
+		Sync(ctx)
+	require.ErrorContains(t, err, "cannot be pruned from within a module")
+}
@@ -112,6 +112,81 @@ func TestFileSize(t *testing.T) {
 	require.Equal(t, len("some-content"), res.Directory.WithNewFile.File.Size)
 }
 
+func TestFileDigest(t *testing.T) {
+	t.Parallel()
+
+	c, ctx := connect(t)
+
+	file := c.Directory().WithNewFile("some-file", "some-content").File("some-file")
+
+	dgst, err := file.Digest(ctx)
+	require.NoError(t, err)
+
+	sum, err := c.Container().From(alpineImage).
+		WithMountedFile("/some-file", file).
+		WithExec([]string{"sha256sum", "/some-file"}).
+		Stdout(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "sha256:"+strings.Fields(sum)[0], dgst)
+
+	otherDgst, err := c.Directory().
+		WithNewFile("other-file", "some-content").
+		File("other-file").
+		Digest(ctx)
+	require.NoError(t, err)
+	require.Equal(t, dgst, otherDgst)
+}
+
+func TestFileWithName(t *testing.T) {
+	t.Parallel()
+
+	c, ctx := connect(t)
+
+	file := c.Directory().WithNewFile("foo/bar", "content").File("foo/bar").WithName("baz.txt")
+
+	name, err := file.Name(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "baz.txt", name)
+
+	contents, err := file.Contents(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "content", contents)
+
+	entries, err := c.Directory().WithFile("", file).Entries(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []string{"baz.txt"}, entries)
+
+	_, err = file.WithName("a/b").Sync(ctx)
+	require.ErrorContains(t, err, "invalid file name")
+}
+
+func TestFileStat(t *testing.T) {
+	t.Parallel()
+
+	c, ctx := connect(t)
+
+	stat := c.Directory().
+		WithNewFile("foo/bar", "content", dagger.DirectoryWithNewFileOpts{Permissions: 0o600}).
+		File("foo/bar").
+		Stat()
+
+	name, err := stat.Name(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "bar", name)
+
+	typ, err := stat.FileType(ctx)
+	require.NoError(t, err)
+	require.Equal(t, dagger.Regular, typ)
+
+	mode, err := stat.Mode(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 0o600, mode)
+
+	size, err := stat.Size(ctx)
+	require.NoError(t, err)
+	require.Equal(t, len("content"), size)
+}
+
 func TestFileName(t *testing.T) {
 	t.Parallel()
 
@@ -6,6 +6,7 @@ import (
 
 	"dagger.io/dagger"
 	"github.com/moby/buildkit/identity"
+	"github.com/opencontainers/go-digest"
 	"github.com/stretchr/testify/require"
 )
 
@@ -62,3 +63,119 @@ func TestHTTPServiceStableDigest(t *testing.T) {
 	c2, ctx2 := connect(t)
 	require.Equal(t, hostname(ctx1, c1), hostname(ctx2, c2))
 }
+
+func TestHTTPChecksum(t *testing.T) {
+	t.Parallel()
+
+	c, ctx := connect(t)
+
+	content := identity.NewID()
+	svc, url := httpService(ctx, t, c, content)
+
+	t.Run("matching checksum", func(t *testing.T) {
+		contents, err := c.HTTP(url, dagger.HTTPOpts{
+			ExperimentalServiceHost: svc,
+			Checksum:                digest.FromString(content).String(),
+		}).Contents(ctx)
+		require.NoError(t, err)
+		require.Equal(t, content, contents)
+	})
+
+	t.Run("mismatched checksum", func(t *testing.T) {
+		wrong := digest.FromString("nope")
+		_, err := c.HTTP(url, dagger.HTTPOpts{
+			ExperimentalServiceHost: svc,
+			Checksum:                wrong.String(),
+		}).Contents(ctx)
+		require.Error(t, err)
+		require.ErrorContains(t, err, "checksum mismatch")
+		require.ErrorContains(t, err, "expected "+wrong.String())
+		require.ErrorContains(t, err, "got "+digest.FromString(content).String())
+	})
+
+	t.Run("invalid checksum", func(t *testing.T) {
+		_, err := c.HTTP(url, dagger.HTTPOpts{
+			ExperimentalServiceHost: svc,
+			Checksum:                "md5:abc",
+		}).Contents(ctx)
+		require.ErrorContains(t, err, "invalid checksum")
+	})
+}
+
+func TestHTTPNameAndPermissions(t *testing.T) {
+	t.Parallel()
+
+	c, ctx := connect(t)
+
+	svc, url := httpService(ctx, t, c, "Hello, world!")
+
+	file := c.HTTP(url, dagger.HTTPOpts{
+		ExperimentalServiceHost: svc,
+		Name:                    "hello.txt",
+		Permissions:             0o755,
+	})
+
+	name, err := file.Name(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "hello.txt", name)
+
+	out, err := c.Container().
+		From(alpineImage).
+		WithMountedDirectory("/dl", c.Directory().WithFile("", file)).
+		WithExec([]string{"stat", "-c", "%a", "/dl/hello.txt"}).
+		Stdout(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "755\n", out)
+}
+
+func TestHTTPHeaders(t *testing.T) {
+	t.Parallel()
+
+	c, ctx := connect(t)
+
+	// only serves the file if both the auth header and the custom header are
+	// sent
+	srv := c.Container().
+		From("python").
+		WithNewFile("/srv/server.py", dagger.ContainerWithNewFileOpts{
+			Contents: `
+from http.server import BaseHTTPRequestHandler, HTTPServer
+
+class Handler(BaseHTTPRequestHandler):
+    def do_GET(self):
+        if self.headers.get("Authorization") != "Bearer hunter2" or self.headers.get("X-Custom") != "yes":
+            self.send_response(401)
+            self.end_headers()
+            return
+        self.send_response(200)
+        self.end_headers()
+        self.wfile.write(b"authorized")
+
+HTTPServer(("", 8000), Handler).serve_forever()
+`,
+		}).
+		WithExposedPort(8000).
+		WithExec([]string{"python", "/srv/server.py"}).
+		AsService()
+
+	url, err := srv.Endpoint(ctx, dagger.ServiceEndpointOpts{Scheme: "http"})
+	require.NoError(t, err)
+
+	t.Run("with headers", func(t *testing.T) {
+		contents, err := c.HTTP(url, dagger.HTTPOpts{
+			ExperimentalServiceHost: srv,
+			Headers:                 []dagger.HTTPHeader{{Name: "X-Custom", Value: "yes"}},
+			AuthHeader:              c.SetSecret("http-auth", "Bearer hunter2"),
+		}).Contents(ctx)
+		require.NoError(t, err)
+		require.Equal(t, "authorized", contents)
+	})
+
+	t.Run("without auth header", func(t *testing.T) {
+		_, err := c.HTTP(url, dagger.HTTPOpts{
+			ExperimentalServiceHost: srv,
+			Headers:                 []dagger.HTTPHeader{{Name: "X-Custom", Value: "yes"}},
+		}).Contents(ctx)
+		require.ErrorContains(t, err, "invalid response status 401")
+	})
+}
@@ -1089,10 +1089,115 @@ func (m *Test) Fn(ctx context.Context) (string, error) {
 		var modCfg modules.ModuleConfig
 		require.NoError(t, json.Unmarshal([]byte(out), &modCfg))
 		require.Len(t, modCfg.Dependencies, 1)
-		url, commit, ok := strings.Cut(modCfg.Dependencies[0].Source, "@")
+		url, version, ok := strings.Cut(modCfg.Dependencies[0].Source, "@")
 		require.True(t, ok)
 		require.Equal(t, gitTestRepoURL, url)
-		require.NotEmpty(t, commit)
+		require.NotEmpty(t, version)
+		require.NotEmpty(t, modCfg.Dependencies[0].Pin)
+	})
+
+	t.Run("frozen and update", func(t *testing.T) {
+		t.Parallel()
+		c, ctx := connect(t)
+
+		ctr := goGitBase(t, c).
+			WithWorkdir("/work").
+			With(daggerExec("init", "--name=test", "--sdk=go", "--source=.")).
+			With(daggerExec("install", testGitModuleRef("top-level")))
+
+		out, err := ctr.File("/work/dagger.json").Contents(ctx)
+		require.NoError(t, err)
+		var modCfg modules.ModuleConfig
+		require.NoError(t, json.Unmarshal([]byte(out), &modCfg))
+		require.Len(t, modCfg.Dependencies, 1)
+		require.Equal(t, testGitModuleRef("top-level"), modCfg.Dependencies[0].Source)
+		require.Equal(t, gitTestRepoCommit, modCfg.Dependencies[0].Pin)
+		require.Equal(t, gitTestRepoCommit, modCfg.Dependencies[0].PinVersion)
+
+		_, err = ctr.With(daggerExec("functions", "--frozen")).Sync(ctx)
+		require.NoError(t, err)
+
+		modCfg.Dependencies[0].Pin = ""
+		unpinned, err := json.MarshalIndent(modCfg, "", "  ")
+		require.NoError(t, err)
+		ctr = ctr.WithNewFile("/work/dagger.json", dagger.ContainerWithNewFileOpts{
+			Contents: string(unpinned),
+		})
+
+		_, err = ctr.With(daggerExec("functions", "--frozen")).Sync(ctx)
+		require.ErrorContains(t, err, "is not pinned, run `dagger update` to pin it")
+
+		ctr = ctr.With(daggerExec("update", "top-level"))
+		out, err = ctr.File("/work/dagger.json").Contents(ctx)
+		require.NoError(t, err)
+		modCfg = modules.ModuleConfig{}
+		require.NoError(t, json.Unmarshal([]byte(out), &modCfg))
+		require.Equal(t, gitTestRepoCommit, modCfg.Dependencies[0].Pin)
+
+		_, err = ctr.With(daggerExec("functions", "--frozen")).Sync(ctx)
+		require.NoError(t, err)
+
+		modCfg.Dependencies[0].PinVersion = "v0.0.1"
+		stale, err := json.MarshalIndent(modCfg, "", "  ")
+		require.NoError(t, err)
+		_, err = ctr.WithNewFile("/work/dagger.json", dagger.ContainerWithNewFileOpts{
+			Contents: string(stale),
+		}).With(daggerExec("functions", "--frozen")).Sync(ctx)
+		require.ErrorContains(t, err, `is pinned to version "v0.0.1"`)
+
+		_, err = ctr.With(daggerExec("update", "nope")).Sync(ctx)
+		require.ErrorContains(t, err, `dependency "nope" to update not found in module configuration`)
+	})
+
+	t.Run("version-less source uses its pin", func(t *testing.T) {
+		t.Parallel()
+		c, ctx := connect(t)
+
+		// the pin of a source without a version was resolved from the
+		// default branch, so it's used as long as that's still the default
+		bogusCommit := strings.Repeat("0", 40)
+		_, err := goGitBase(t, c).
+			WithWorkdir("/work").
+			With(configFile(".", &modules.ModuleConfig{
+				Name:   "test",
+				SDK:    "go",
+				Source: ".",
+				Dependencies: []*modules.ModuleConfigDependency{{
+					Name:       "top-level",
+					Source:     gitTestRepoURL + "/top-level",
+					Pin:        bogusCommit,
+					PinVersion: "main",
+				}},
+			})).
+			With(daggerExec("functions")).
+			Sync(ctx)
+		require.ErrorContains(t, err, bogusCommit)
+	})
+
+	t.Run("uninstall", func(t *testing.T) {
+		t.Parallel()
+		c, ctx := connect(t)
+
+		ctr := goGitBase(t, c).
+			WithWorkdir("/work").
+			With(daggerExec("init", "--name=test", "--sdk=go", "--source=.")).
+			With(daggerExec("install", "--name=foo", testGitModuleRef("top-level"))).
+			With(daggerExec("install", "--name=bar", testGitModuleRef("subdir/dep2")))
+
+		ctr = ctr.With(daggerExec("uninstall", "foo"))
+		out, err := ctr.Stdout(ctx)
+		require.NoError(t, err)
+		require.Contains(t, out, `-      "name": "foo",`)
+
+		out, err = ctr.File("/work/dagger.json").Contents(ctx)
+		require.NoError(t, err)
+		var modCfg modules.ModuleConfig
+		require.NoError(t, json.Unmarshal([]byte(out), &modCfg))
+		require.Len(t, modCfg.Dependencies, 1)
+		require.Equal(t, "bar", modCfg.Dependencies[0].Name)
+
+		_, err = ctr.With(daggerExec("uninstall", "foo")).Sync(ctx)
+		require.ErrorContains(t, err, `dependency "foo" to remove not found`)
 	})
 }
 
@@ -1188,6 +1293,117 @@ func TestModuleDaggerGitRefs(t *testing.T) {
 	})
 }
 
+func TestModuleDaggerGitRefsAnyHost(t *testing.T) {
+	t.Parallel()
+	c, ctx := connect(t)
+
+	modDir := goGitBase(t, c).
+		WithWorkdir("/work/mod").
+		With(daggerExec("init", "--name=mod", "--sdk=go", "--source=.")).
+		WithNewFile("/work/mod/main.go", dagger.ContainerWithNewFileOpts{
+			Contents: `package main
+
+type Mod struct {}
+
+func (m *Mod) Fn() string {
+	return "hi from git daemon"
+}
+`,
+		}).
+		Directory("/work")
+
+	svc, repoURL := gitService(ctx, t, c, modDir)
+	_, err := svc.Start(ctx)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_, _ = svc.Stop(ctx)
+	})
+
+	modSrc := c.ModuleSource(repoURL + "/mod@main")
+
+	kind, err := modSrc.Kind(ctx)
+	require.NoError(t, err)
+	require.Equal(t, dagger.GitSource, kind)
+
+	cloneURL, err := modSrc.AsGitSource().CloneURL(ctx)
+	require.NoError(t, err)
+	require.Equal(t, repoURL, cloneURL)
+
+	rootSubpath, err := modSrc.AsGitSource().RootSubpath(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "mod", rootSubpath)
+
+	commit, err := modSrc.AsGitSource().Commit(ctx)
+	require.NoError(t, err)
+	require.NotEmpty(t, commit)
+
+	refStr, err := modSrc.AsString(ctx)
+	require.NoError(t, err)
+	require.Equal(t, fmt.Sprintf("%s/mod@%s", repoURL, commit), refStr)
+
+	name, err := modSrc.ModuleName(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "mod", name)
+}
+
+func TestModuleLocalHostLikePaths(t *testing.T) {
+	t.Parallel()
+	c, ctx := connect(t)
+
+	// local paths that start with something like a hostname are still local
+	// when they exist, even without ./
+	ctr := goGitBase(t, c).
+		WithWorkdir("/work/my.lib/dep").
+		With(daggerExec("init", "--name=dep", "--sdk=go", "--source=.")).
+		WithNewFile("/work/my.lib/dep/main.go", dagger.ContainerWithNewFileOpts{
+			Contents: `package main
+
+type Dep struct {}
+
+func (m *Dep) Fn() string {
+	return "hi from dep"
+}
+`,
+		}).
+		WithWorkdir("/work").
+		With(daggerExec("init", "--name=mod", "--sdk=go", "--source=.")).
+		WithNewFile("/work/main.go", dagger.ContainerWithNewFileOpts{
+			Contents: `package main
+
+import "context"
+
+type Mod struct {}
+
+func (m *Mod) Fn(ctx context.Context) (string, error) {
+	return dag.Dep().Fn(ctx)
+}
+`,
+		})
+
+	t.Run("called with -m", func(t *testing.T) {
+		t.Parallel()
+		out, err := ctr.With(daggerCallAt("my.lib/dep", "fn")).Stdout(ctx)
+		require.NoError(t, err)
+		require.Equal(t, "hi from dep", strings.TrimSpace(out))
+	})
+
+	t.Run("installed", func(t *testing.T) {
+		t.Parallel()
+		ctr := ctr.With(daggerExec("install", "my.lib/dep"))
+		out, err := ctr.With(daggerCall("fn")).Stdout(ctx)
+		require.NoError(t, err)
+		require.Equal(t, "hi from dep", strings.TrimSpace(out))
+
+		// without ./ in the config
+		out, err = ctr.
+			WithExec([]string{"sed", "-i", `s,"\./my\.lib/dep","my.lib/dep",`, "dagger.json"}).
+			With(daggerCall("fn")).
+			Stdout(ctx)
+		require.NoError(t, err)
+		require.Equal(t, "hi from dep", strings.TrimSpace(out))
+	})
+}
+
 func TestModuleDaggerGitWithSources(t *testing.T) {
 	t.Parallel()
 
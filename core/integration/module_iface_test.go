@@ -6,6 +6,7 @@ import (
 
 	"dagger.io/dagger"
 	"github.com/stretchr/testify/require"
+	"github.com/tidwall/gjson"
 )
 
 func TestModuleIfaceBasic(t *testing.T) {
@@ -138,3 +139,80 @@ func (m *Test) GetDuck() Duck {
 	require.NoError(t, err)
 	require.Equal(t, "mallard quack", strings.TrimSpace(out))
 }
+
+func TestModuleIfaceCoreTypes(t *testing.T) {
+	t.Parallel()
+
+	c, ctx := connect(t)
+
+	modGen := c.Container().From(golangImage).
+		WithMountedFile(testCLIBinPath, daggerCliFile(t, c)).
+		WithWorkdir("/work").
+		With(daggerExec("init", "--source=.", "--name=test", "--sdk=go")).
+		WithNewFile("main.go", dagger.ContainerWithNewFileOpts{
+			Contents: `package main
+
+import (
+	"context"
+)
+
+type Test struct {}
+
+type Lister interface {
+	DaggerObject
+	Entries(ctx context.Context) ([]string, error)
+}
+
+type Sized interface {
+	DaggerObject
+	Size(ctx context.Context) (int, error)
+}
+
+type FileGetter interface {
+	DaggerObject
+	File(path string) *File
+}
+
+func (m *Test) List(ctx context.Context, l Lister) ([]string, error) {
+	return l.Entries(ctx)
+}
+
+func (m *Test) SizeOf(ctx context.Context, s Sized) (int, error) {
+	return s.Size(ctx)
+}
+
+func (m *Test) Read(ctx context.Context, g FileGetter, path string) (string, error) {
+	return g.File(path).Contents(ctx)
+}
+	`,
+		})
+
+	t.Run("as interface", func(t *testing.T) {
+		t.Parallel()
+		out, err := modGen.With(daggerQuery(`{directory{withNewFile(path: "foo", contents: "bar"){asTestLister{entries} asTestFileGetter{file(path: "foo"){asTestSized{size}}}}}}`)).Stdout(ctx)
+		require.NoError(t, err)
+		require.JSONEq(t, `{"directory":{"withNewFile":{"asTestLister":{"entries":["foo"]},"asTestFileGetter":{"file":{"asTestSized":{"size":3}}}}}}`, out)
+	})
+
+	t.Run("interface args", func(t *testing.T) {
+		t.Parallel()
+		out, err := modGen.With(daggerQuery(`{directory{withNewFile(path: "foo", contents: "bar"){id file(path: "foo"){id}}}}`)).Stdout(ctx)
+		require.NoError(t, err)
+		dirID := gjson.Get(out, "directory.withNewFile.id").String()
+		fileID := gjson.Get(out, "directory.withNewFile.file.id").String()
+
+		out, err = modGen.With(daggerQuery(`{test{list(l: "%s") sizeOf(s: "%s") read(g: "%s", path: "foo")}}`, dirID, fileID, dirID)).Stdout(ctx)
+		require.NoError(t, err)
+		require.JSONEq(t, `{"test":{"list":["foo"],"sizeOf":3,"read":"bar"}}`, out)
+	})
+
+	t.Run("not implemented", func(t *testing.T) {
+		t.Parallel()
+		out, err := modGen.With(daggerQuery(`{directory{id}}`)).Stdout(ctx)
+		require.NoError(t, err)
+		dirID := gjson.Get(out, "directory.id").String()
+
+		_, err = modGen.With(daggerQuery(`{test{sizeOf(s: "%s")}}`, dirID)).Sync(ctx)
+		require.ErrorContains(t, err, "type Directory does not implement interface TestSized")
+	})
+}
@@ -5493,6 +5493,268 @@ func diffSecret(ctx context.Context, first, second *Secret) error {
 	})
 }
 
+func TestModuleCacheVolumeNamespace(t *testing.T) {
+	t.Parallel()
+
+	c, ctx := connect(t)
+
+	ctr := c.Container().From(golangImage).
+		WithMountedFile(testCLIBinPath, daggerCliFile(t, c))
+
+	ctr = ctr.
+		WithWorkdir("/toplevel/writer").
+		With(daggerExec("init", "--name=writer", "--sdk=go", "--source=.")).
+		WithNewFile("main.go", dagger.ContainerWithNewFileOpts{
+			Contents: `package main
+
+import "context"
+
+type Writer struct {}
+
+func (_ *Writer) Write(ctx context.Context, key, value string) (*CacheVolume, error) {
+	cache := dag.CacheVolume(key)
+	_, err := dag.Container().
+		From("` + alpineImage + `").
+		WithMountedCache("/cache", cache).
+		WithExec([]string{"sh", "-c", "echo -n " + value + " > /cache/value"}).
+		Sync(ctx)
+	return cache, err
+}
+`,
+		})
+
+	ctr = ctr.
+		WithWorkdir("/toplevel").
+		With(daggerExec("init", "--name=toplevel", "--sdk=go", "--source=.")).
+		With(daggerExec("install", "./writer")).
+		WithNewFile("main.go", dagger.ContainerWithNewFileOpts{
+			Contents: `package main
+
+import "context"
+
+type Toplevel struct {}
+
+func (_ *Toplevel) Own(ctx context.Context, key string) (string, error) {
+	if _, err := dag.Writer().Write(key, "theirs").Sync(ctx); err != nil {
+		return "", err
+	}
+	return read(ctx, dag.CacheVolume(key))
+}
+
+func (_ *Toplevel) Theirs(ctx context.Context, key string) (string, error) {
+	return read(ctx, dag.Writer().Write(key, "theirs"))
+}
+
+func (_ *Toplevel) Namespace(ctx context.Context, key string) (string, error) {
+	return dag.Writer().Write(key, "theirs").Namespace(ctx)
+}
+
+func (_ *Toplevel) ClearTheirs(ctx context.Context, key string) (string, error) {
+	ns, err := dag.Writer().Write(key, "theirs").Namespace(ctx)
+	if err != nil {
+		return "", err
+	}
+	cache := dag.CacheVolume(key, CacheVolumeOpts{Namespace: ns})
+	if _, err := cache.Clear(ctx); err != nil {
+		return "", err
+	}
+	return read(ctx, cache)
+}
+
+func (_ *Toplevel) Listed(ctx context.Context, key string) ([]string, error) {
+	if _, err := dag.Writer().Write(key, "theirs").Sync(ctx); err != nil {
+		return nil, err
+	}
+	if _, err := read(ctx, dag.CacheVolume(key)); err != nil {
+		return nil, err
+	}
+	caches, err := dag.CacheVolumes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var listed []string
+	for _, cache := range caches {
+		k, err := cache.Key(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if k == key {
+			ns, err := cache.Namespace(ctx)
+			if err != nil {
+				return nil, err
+			}
+			listed = append(listed, ns)
+		}
+	}
+	return listed, nil
+}
+
+func read(ctx context.Context, cache *CacheVolume) (string, error) {
+	return dag.Container().
+		From("` + alpineImage + `").
+		WithMountedCache("/cache", cache).
+		WithExec([]string{"sh", "-c", "cat /cache/value || true"}).
+		Stdout(ctx)
+}
+`,
+		})
+
+	t.Run("modules don't share cache volumes by default", func(t *testing.T) {
+		out, err := ctr.With(daggerQuery(`{toplevel{own(key: %q)}}`, identity.NewID())).Stdout(ctx)
+		require.NoError(t, err)
+		require.JSONEq(t, `{"toplevel":{"own":""}}`, out)
+	})
+
+	t.Run("returned cache volumes keep their namespace", func(t *testing.T) {
+		out, err := ctr.With(daggerQuery(`{toplevel{theirs(key: %q)}}`, identity.NewID())).Stdout(ctx)
+		require.NoError(t, err)
+		require.JSONEq(t, `{"toplevel":{"theirs":"theirs"}}`, out)
+
+		// local modules are identified by their source rather than their name
+		out, err = ctr.With(daggerQuery(`{toplevel{namespace(key: %q)}}`, identity.NewID())).Stdout(ctx)
+		require.NoError(t, err)
+		var res struct {
+			Toplevel struct {
+				Namespace string
+			}
+		}
+		require.NoError(t, json.Unmarshal([]byte(out), &res))
+		require.True(t, strings.HasPrefix(res.Toplevel.Namespace, "sha256:"), res.Toplevel.Namespace)
+	})
+
+	t.Run("namespaces are not access control", func(t *testing.T) {
+		out, err := ctr.With(daggerQuery(`{toplevel{clearTheirs(key: %q)}}`, identity.NewID())).Stdout(ctx)
+		require.NoError(t, err)
+		require.JSONEq(t, `{"toplevel":{"clearTheirs":""}}`, out)
+	})
+
+	t.Run("modules only list their own cache volumes", func(t *testing.T) {
+		out, err := ctr.With(daggerQuery(`{toplevel{listed(key: %q)}}`, identity.NewID())).Stdout(ctx)
+		require.NoError(t, err)
+		var res struct {
+			Toplevel struct {
+				Listed []string
+			}
+		}
+		require.NoError(t, json.Unmarshal([]byte(out), &res))
+		require.Len(t, res.Toplevel.Listed, 1)
+		require.NotEqual(t, res.Toplevel.Listed[0], "")
+	})
+
+	t.Run("main client cache volumes are shared", func(t *testing.T) {
+		key := identity.NewID()
+		_, err := c.Container().From(alpineImage).
+			WithMountedCache("/cache", c.CacheVolume(key)).
+			WithExec([]string{"sh", "-c", "echo -n mine > /cache/value"}).
+			Sync(ctx)
+		require.NoError(t, err)
+
+		out, err := c.Container().From(alpineImage).
+			WithMountedCache("/cache", c.CacheVolume(key, dagger.CacheVolumeOpts{Shared: true})).
+			WithExec([]string{"cat", "/cache/value"}).
+			Stdout(ctx)
+		require.NoError(t, err)
+		require.Equal(t, "mine", out)
+
+		ns, err := c.CacheVolume(key).Namespace(ctx)
+		require.NoError(t, err)
+		require.Empty(t, ns)
+	})
+}
+
+func TestModuleFunctionCachePolicy(t *testing.T) {
+	t.Parallel()
+
+	c, ctx := connect(t)
+
+	ctr := modInit(ctx, t, c, "go", `package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+type Test struct{}
+
+// +cache=pure
+func (m *Test) Pure(key string) string {
+	return fmt.Sprint(time.Now().UnixNano())
+}
+
+// +cache=pure
+func (m *Test) Read(ctx context.Context, dir *Directory) (string, error) {
+	return dir.File("data").Contents(ctx)
+}
+
+// +cache=1h
+func (m *Test) Fresh(key string) string {
+	return fmt.Sprint(time.Now().UnixNano())
+}
+
+func (m *Test) Session(key string) string {
+	return fmt.Sprint(time.Now().UnixNano())
+}
+`)
+
+	// each call is a separate session; bust the cache of the outer exec so
+	// that only the function's cache policy decides whether it runs again
+	call := func(t *testing.T, args ...string) string {
+		t.Helper()
+		out, err := ctr.
+			WithEnvVariable("BUST", identity.NewID()).
+			With(daggerCall(args...)).
+			Stdout(ctx)
+		require.NoError(t, err)
+		require.NotEmpty(t, out)
+		return out
+	}
+
+	for _, fn := range []string{"pure", "fresh"} {
+		fn := fn
+		t.Run(fn+" results are reused across sessions", func(t *testing.T) {
+			key := identity.NewID()
+			require.Equal(t, call(t, fn, "--key", key), call(t, fn, "--key", key))
+		})
+
+		t.Run(fn+" results are not reused with --no-cache", func(t *testing.T) {
+			key := identity.NewID()
+			require.NotEqual(t, call(t, fn, "--key", key), call(t, "--no-cache", fn, "--key", key))
+		})
+	}
+
+	t.Run("session results are not reused across sessions", func(t *testing.T) {
+		key := identity.NewID()
+		require.NotEqual(t, call(t, "session", "--key", key), call(t, "session", "--key", key))
+	})
+
+	t.Run("results with host inputs are not reused across sessions", func(t *testing.T) {
+		dir := "/input-" + identity.NewID()
+		read := func(t *testing.T, contents string) string {
+			t.Helper()
+			out, err := ctr.
+				WithNewFile(dir+"/data", dagger.ContainerWithNewFileOpts{Contents: contents}).
+				WithEnvVariable("BUST", identity.NewID()).
+				With(daggerCall("read", "--dir", dir)).
+				Stdout(ctx)
+			require.NoError(t, err)
+			return out
+		}
+		require.Equal(t, "before", read(t, "before"))
+		require.Equal(t, "after", read(t, "after"))
+	})
+
+	t.Run("cache policy is part of the type defs", func(t *testing.T) {
+		out, err := ctr.With(daggerQuery(`{host{directory(path:"."){asModule{initialize{objects{asObject{functions{name cachePolicy cacheTimeToLive}}}}}}}}`)).Stdout(ctx)
+		require.NoError(t, err)
+		fns := gjson.Get(out, "host.directory.asModule.initialize.objects.0.asObject.functions")
+		require.Equal(t, "PURE_CACHE", fns.Get("#(name=pure).cachePolicy").String())
+		require.Equal(t, "TTL_CACHE", fns.Get("#(name=fresh).cachePolicy").String())
+		require.EqualValues(t, 3600, fns.Get("#(name=fresh).cacheTimeToLive").Int())
+		require.Equal(t, "SESSION_CACHE", fns.Get("#(name=session).cachePolicy").String())
+	})
+}
+
 func daggerExec(args ...string) dagger.WithContainerFunc {
 	return func(c *dagger.Container) *dagger.Container {
 		return c.WithExec(append([]string{"dagger", "--debug"}, args...), dagger.ContainerWithExecOpts{
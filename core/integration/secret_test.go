@@ -6,8 +6,10 @@ import (
 	"io"
 	"testing"
 
+	"dagger.io/dagger"
 	"github.com/dagger/dagger/dagql/idproto"
 	"github.com/dagger/dagger/internal/testutil"
+	"github.com/moby/buildkit/identity"
 	"github.com/stretchr/testify/require"
 )
 
@@ -129,6 +131,55 @@ func TestSecretSet(t *testing.T) {
 	require.Equal(t, secretValue, plaintext)
 }
 
+func TestSecretURI(t *testing.T) {
+	t.Parallel()
+
+	c, ctx := connect(t)
+
+	// providers run in the engine, so they're disabled unless it enables them
+	_, err := c.Secret("token", dagger.SecretOpts{
+		URI: "cmd://printf very-secret-text",
+	}).Plaintext(ctx)
+	require.ErrorContains(t, err, `secret provider "cmd" is not enabled in the engine`)
+
+	_, err = c.Secret("bogus", dagger.SecretOpts{
+		URI: "bogus://nope",
+	}).Plaintext(ctx)
+	require.ErrorContains(t, err, `unsupported secret provider "bogus"`)
+}
+
+func TestSecretURIEnabledProvider(t *testing.T) {
+	t.Parallel()
+
+	c, ctx := connect(t)
+
+	devEngineSvc := devEngineContainer(c).
+		WithMountedCache("/var/lib/dagger", c.CacheVolume("dagger-dev-engine-state-"+identity.NewID())).
+		WithExec([]string{"--addr", "tcp://0.0.0.0:1234", "--secret-provider", "cmd"}, dagger.ContainerWithExecOpts{
+			InsecureRootCapabilities: true,
+		}).AsService()
+
+	clientCtr, err := engineClientContainer(ctx, t, c, devEngineSvc)
+	require.NoError(t, err)
+
+	out, err := clientCtr.
+		WithNewFile("/query.graphql", dagger.ContainerWithNewFileOpts{
+			Contents: `{ secret(name: "token", uri: "cmd://printf very-secret-text") { plaintext } }`,
+		}).
+		WithExec([]string{"dagger", "query", "--doc", "/query.graphql"}).
+		Stdout(ctx)
+	require.NoError(t, err)
+	require.JSONEq(t, `{"secret": {"plaintext": "very-secret-text"}}`, out)
+
+	_, err = clientCtr.
+		WithNewFile("/query.graphql", dagger.ContainerWithNewFileOpts{
+			Contents: `{ secret(name: "token", uri: "env://DAGGER_TEST_MISSING_SECRET_ENV") { plaintext } }`,
+		}).
+		WithExec([]string{"dagger", "query", "--doc", "/query.graphql"}).
+		Sync(ctx)
+	require.ErrorContains(t, err, `secret provider "env" is not enabled in the engine`)
+}
+
 func TestSecretWhitespaceScrubbed(t *testing.T) {
 	t.Parallel()
 	c, ctx := connect(t)
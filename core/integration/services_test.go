@@ -244,6 +244,109 @@ func TestServicePortsSkipHealthCheck(t *testing.T) {
 	})
 }
 
+func TestServiceHealthcheck(t *testing.T) {
+	t.Parallel()
+
+	t.Run("command", func(t *testing.T) {
+		c, ctx := connect(t)
+
+		srv := c.Container().
+			From(alpineImage).
+			WithHealthcheck(dagger.ContainerWithHealthcheckOpts{
+				Command: []string{"test", "-f", "/tmp/ready"},
+				Retries: 10,
+			}).
+			WithExec([]string{"sh", "-c", "sleep 3; touch /tmp/ready; sleep infinity"}).
+			AsService()
+
+		_, err := srv.Start(ctx)
+		require.NoError(t, err)
+	})
+
+	t.Run("http", func(t *testing.T) {
+		c, ctx := connect(t)
+
+		srv := c.Container().
+			From("python").
+			WithMountedDirectory("/srv/www", c.Directory().WithNewFile("ready", "ok")).
+			WithWorkdir("/srv/www").
+			WithHealthcheck(dagger.ContainerWithHealthcheckOpts{
+				HTTPGet: dagger.HealthcheckHTTPGet{Path: "/ready", Port: 8000},
+			}).
+			WithExec([]string{"python", "-m", "http.server"}).
+			AsService()
+
+		_, err := srv.Start(ctx)
+		require.NoError(t, err)
+	})
+
+	t.Run("tcp", func(t *testing.T) {
+		c, ctx := connect(t)
+
+		srv := c.Container().
+			From(alpineImage).
+			WithHealthcheck(dagger.ContainerWithHealthcheckOpts{
+				TCPPort:     8080,
+				StartPeriod: 2,
+			}).
+			WithExec([]string{"sh", "-c", "sleep 1; nc -lk -p 8080 -e echo hi"}).
+			AsService()
+
+		_, err := srv.Start(ctx)
+		require.NoError(t, err)
+	})
+
+	t.Run("fails with last probe output", func(t *testing.T) {
+		c, ctx := connect(t)
+
+		srv := c.Container().
+			From(alpineImage).
+			WithHealthcheck(dagger.ContainerWithHealthcheckOpts{
+				Command: []string{"sh", "-c", "echo database is still booting; exit 1"},
+				Retries: 2,
+			}).
+			WithExec([]string{"sleep", "infinity"}).
+			AsService()
+
+		_, err := srv.Start(ctx)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "unhealthy after 2 consecutive failed probes")
+		require.Contains(t, err.Error(), "database is still booting")
+	})
+
+	t.Run("probe timeout", func(t *testing.T) {
+		c, ctx := connect(t)
+
+		srv := c.Container().
+			From(alpineImage).
+			WithHealthcheck(dagger.ContainerWithHealthcheckOpts{
+				Command: []string{"sleep", "10"},
+				Timeout: 1,
+				Retries: 1,
+			}).
+			WithExec([]string{"sleep", "infinity"}).
+			AsService()
+
+		_, err := srv.Start(ctx)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "probe timed out after 1s")
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		c, ctx := connect(t)
+
+		_, err := c.Container().
+			From(alpineImage).
+			WithHealthcheck(dagger.ContainerWithHealthcheckOpts{
+				Command: []string{"true"},
+				TCPPort: 8080,
+			}).
+			Sync(ctx)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "only one of command, httpGet or tcpPort may be set")
+	})
+}
+
 func TestContainerPortLifecycle(t *testing.T) {
 	t.Parallel()
 
@@ -1245,6 +1348,160 @@ func TestServiceStartStop(t *testing.T) {
 	require.Empty(t, out)
 }
 
+func TestServiceLogsAndStatus(t *testing.T) {
+	t.Parallel()
+
+	c, ctx := connect(t)
+
+	srv := c.Container().
+		From(alpineImage).
+		WithEnvVariable("BUST", identity.NewID()).
+		WithHealthcheck(dagger.ContainerWithHealthcheckOpts{
+			Command: []string{"test", "-f", "/tmp/ready"},
+		}).
+		WithExec([]string{"sh", "-c", `
+			echo one
+			echo two >&2
+			touch /tmp/ready
+			sleep 2
+			echo three
+			exit 3
+		`}).
+		AsService()
+
+	status, err := srv.Status(ctx)
+	require.NoError(t, err)
+	require.Equal(t, dagger.NotStarted, status)
+
+	_, err = srv.ExitCode(ctx)
+	require.Error(t, err)
+
+	_, err = srv.Start(ctx)
+	require.NoError(t, err)
+
+	status, err = srv.Status(ctx)
+	require.NoError(t, err)
+	require.Equal(t, dagger.Healthy, status)
+
+	_, err = srv.ExitCode(ctx)
+	require.ErrorContains(t, err, "has not exited")
+
+	logs, err := srv.Logs(ctx, dagger.ServiceLogsOpts{Follow: true})
+	require.NoError(t, err)
+	require.Equal(t, "one\ntwo\nthree\n", logs)
+
+	logs, err = srv.Logs(ctx, dagger.ServiceLogsOpts{Tail: 1})
+	require.NoError(t, err)
+	require.Equal(t, "three\n", logs)
+
+	status, err = srv.Status(ctx)
+	require.NoError(t, err)
+	require.Equal(t, dagger.Exited, status)
+
+	code, err := srv.ExitCode(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 3, code)
+
+	// logs are kept after the service is stopped
+	_, err = srv.Stop(ctx)
+	require.NoError(t, err)
+
+	logs, err = srv.Logs(ctx, dagger.ServiceLogsOpts{Since: "1h"})
+	require.NoError(t, err)
+	require.Equal(t, "one\ntwo\nthree\n", logs)
+}
+
+func TestServiceRestartPolicy(t *testing.T) {
+	t.Parallel()
+
+	c, ctx := connect(t)
+
+	runs := c.CacheVolume("service-restarts-" + identity.NewID())
+
+	srv := c.Container().
+		From(alpineImage).
+		WithMountedCache("/runs", runs).
+		WithHealthcheck(dagger.ContainerWithHealthcheckOpts{
+			Command: []string{"true"},
+		}).
+		WithExec([]string{"sh", "-c", `
+			echo >> /runs/log
+			echo run $(wc -l < /runs/log)
+			sleep 1
+			exit 1
+		`}).
+		AsService(dagger.ContainerAsServiceOpts{
+			RestartPolicy: dagger.OnFailure,
+			MaxRestarts:   2,
+		})
+
+	_, err := srv.Start(ctx)
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool {
+		logs, err := srv.Logs(ctx)
+		return err == nil && logs == "run 3\n"
+	}, time.Minute, time.Second)
+
+	logs, err := srv.Logs(ctx, dagger.ServiceLogsOpts{Follow: true})
+	require.NoError(t, err)
+	require.Equal(t, "run 3\n", logs)
+
+	code, err := srv.ExitCode(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 1, code)
+
+	// no more restarts after the max is reached
+	time.Sleep(3 * time.Second)
+	status, err := srv.Status(ctx)
+	require.NoError(t, err)
+	require.Equal(t, dagger.Exited, status)
+}
+
+func TestServiceBindingDependsOn(t *testing.T) {
+	t.Parallel()
+
+	c, ctx := connect(t)
+
+	db := c.Container().
+		From("python").
+		WithExposedPort(5432).
+		WithEnvVariable("BUST", identity.NewID()).
+		WithExec([]string{"sh", "-c", "sleep 3; python -m http.server 5432"}).
+		AsService()
+
+	dbHost, err := db.Hostname(ctx)
+	require.NoError(t, err)
+
+	// the api fails to start if the db isn't up yet
+	api := c.Container().
+		From("python").
+		WithExposedPort(8000).
+		WithEnvVariable("DB_URL", "http://"+dbHost+":5432").
+		WithExec([]string{"sh", "-c",
+			`python -c 'import os, urllib.request; urllib.request.urlopen(os.environ["DB_URL"])' && python -m http.server 8000`}).
+		AsService()
+
+	_, err = c.Container().
+		From(alpineImage).
+		WithServiceBinding("db", db).
+		WithServiceBinding("api", api, dagger.ContainerWithServiceBindingOpts{
+			DependsOn: []string{"db"},
+		}).
+		WithExec([]string{"wget", "-O-", "http://api:8000"}).
+		Sync(ctx)
+	require.NoError(t, err)
+
+	_, err = c.Container().
+		From(alpineImage).
+		WithServiceBinding("api", api, dagger.ContainerWithServiceBindingOpts{
+			DependsOn: []string{"nope"},
+		}).
+		WithExec([]string{"true"}).
+		Sync(ctx)
+	require.ErrorContains(t, err, `depends on unknown service "nope"`)
+}
+
 // TestServiceStartStopKill tests that we send SIGTERM by default, instead of SIGKILL.
 // Additionally, we check that we can attempt to SIGKILL a process that is not
 // responding to SIGTERM.
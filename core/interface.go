@@ -202,7 +202,10 @@ func (iface *InterfaceType) Install(ctx context.Context, dag *dagql.Server) erro
 			Func: func(ctx context.Context, self dagql.Instance[*InterfaceAnnotatedValue], args map[string]dagql.Input) (dagql.Typed, error) {
 				runtimeVal := self.Self
 
-				// TODO: support core types too
+				if runtimeVal.UnderlyingObject != nil {
+					return iface.callCoreObject(ctx, dag, fnTypeDef, runtimeVal, args)
+				}
+
 				userModObj, ok := runtimeVal.UnderlyingType.(*ModuleObjectType)
 				if !ok {
 					return nil, fmt.Errorf("unexpected underlying type %T for interface resolver %s.%s", runtimeVal.UnderlyingType, ifaceName, fieldDef.Name)
@@ -286,6 +289,120 @@ func (iface *InterfaceType) Install(ctx context.Context, dag *dagql.Server) erro
 	return nil
 }
 
+// callCoreObject resolves an interface function against a core object (e.g. a
+// Container) by selecting the field of the same name on it.
+func (iface *InterfaceType) callCoreObject(
+	ctx context.Context,
+	dag *dagql.Server,
+	fnTypeDef *Function,
+	runtimeVal *InterfaceAnnotatedValue,
+	args map[string]dagql.Input,
+) (dagql.Typed, error) {
+	obj := runtimeVal.UnderlyingObject
+	fieldName := gqlFieldName(fnTypeDef.Name)
+
+	// re-parse the args against the core field so that they're decoded to the
+	// types it expects (e.g. FooID rather than the interface's dynamic ID) and
+	// any of its extra optional args get their defaults
+	astField := &ast.Field{
+		Name: fieldName,
+	}
+	vars := map[string]any{}
+	for name, arg := range args {
+		vars[name] = arg.ToLiteral().ToInput()
+		astField.Arguments = append(astField.Arguments, &ast.Argument{
+			Name: name,
+			Value: &ast.Value{
+				Kind: ast.Variable,
+				Raw:  name,
+			},
+		})
+	}
+	sel, _, err := obj.ObjectType().ParseField(ctx, astField, vars)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s.%s: %w", obj.Type().Name(), fieldName, err)
+	}
+	res, err := obj.Select(ctx, sel)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call interface function %s.%s: %w", iface.typeDef.Name, fieldName, err)
+	}
+
+	if fnTypeDef.ReturnType.Underlying().Kind != TypeDefKindInterface {
+		return res, nil
+	}
+
+	// the interface returns an interface, so hydrate the core object(s) into
+	// Objects that can be wrapped
+	id, err := obj.IDFor(ctx, sel)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get ID for %s.%s: %w", obj.Type().Name(), fieldName, err)
+	}
+	res, err = hydrateObjects(dag, id, res)
+	if err != nil {
+		return nil, fmt.Errorf("failed to hydrate return value of %s.%s: %w", obj.Type().Name(), fieldName, err)
+	}
+
+	underlyingReturnType, ok, err := iface.mod.ModTypeFor(ctx, fnTypeDef.ReturnType.Underlying(), true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get return mod type: %w", err)
+	}
+	if !ok {
+		return nil, fmt.Errorf("failed to find return mod type")
+	}
+	ifaceReturnType, ok := underlyingReturnType.(*InterfaceType)
+	if !ok {
+		return nil, fmt.Errorf("expected return interface type, got %T", underlyingReturnType)
+	}
+	objFn, ok := runtimeVal.UnderlyingType.TypeDef().AsObject.Value.FunctionByName(fnTypeDef.Name)
+	if !ok {
+		return nil, fmt.Errorf("failed to find function %s.%s", obj.Type().Name(), fieldName)
+	}
+	objReturnType, ok, err := iface.mod.Deps.ModTypeFor(ctx, objFn.ReturnType)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get object return type for %s.%s: %w", obj.Type().Name(), fieldName, err)
+	}
+	if !ok {
+		return nil, fmt.Errorf("failed to find object return type for %s.%s", obj.Type().Name(), fieldName)
+	}
+	return wrapIface(ctx, dag, ifaceReturnType, objReturnType, res)
+}
+
+// hydrateObjects instantiates a raw return value, or each element of a list of
+// them, as an Object with the given ID.
+func hydrateObjects(dag *dagql.Server, id *idproto.ID, res dagql.Typed) (dagql.Typed, error) {
+	if res == nil {
+		return nil, nil
+	}
+	if _, ok := res.(dagql.Object); ok {
+		return res, nil
+	}
+	if enum, ok := res.(dagql.Enumerable); ok {
+		ret := dagql.DynamicArrayOutput{}
+		for i := 1; i <= enum.Len(); i++ {
+			item, err := enum.Nth(i)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get item %d: %w", i, err)
+			}
+			if ret.Elem == nil {
+				ret.Elem = item
+			}
+			nthID := id.Clone()
+			nthID.SelectNth(i)
+			val, err := hydrateObjects(dag, nthID, item)
+			if err != nil {
+				return nil, err
+			}
+			ret.Values = append(ret.Values, val)
+		}
+		return ret, nil
+	}
+	class, ok := dag.ObjectType(res.Type().Name())
+	if !ok {
+		return res, nil
+	}
+	return class.New(id, res)
+}
+
 func wrapIface(ctx context.Context, dag *dagql.Server, ifaceType *InterfaceType, underlyingType ModType, res dagql.Typed) (dagql.Typed, error) {
 	switch underlyingType := underlyingType.(type) {
 	case *InterfaceType, *ModuleObjectType:
@@ -331,6 +448,15 @@ func wrapIface(ctx context.Context, dag *dagql.Server, ifaceType *InterfaceType,
 		}
 		return ret, nil
 	default:
+		// core objects (e.g. Container) are kept as-is underneath the interface
+		if obj, ok := res.(dagql.Object); ok {
+			return &InterfaceAnnotatedValue{
+				TypeDef:          ifaceType.typeDef,
+				IfaceType:        ifaceType,
+				UnderlyingType:   underlyingType,
+				UnderlyingObject: obj,
+			}, nil
+		}
 		return res, nil
 	}
 }
@@ -340,6 +466,10 @@ type InterfaceAnnotatedValue struct {
 	IfaceType      *InterfaceType
 	Fields         map[string]any
 	UnderlyingType ModType
+
+	// UnderlyingObject is set instead of Fields when the underlying type is a
+	// core type rather than a module object
+	UnderlyingObject dagql.Object
 }
 
 var _ dagql.Typed = (*InterfaceAnnotatedValue)(nil)
@@ -358,6 +488,9 @@ func (iface *InterfaceAnnotatedValue) TypeDescription() string {
 var _ HasPBDefinitions = (*InterfaceAnnotatedValue)(nil)
 
 func (iface *InterfaceAnnotatedValue) PBDefinitions(ctx context.Context) ([]*pb.Definition, error) {
+	if iface.UnderlyingObject != nil {
+		return collectPBDefinitions(ctx, iface.UnderlyingObject)
+	}
 	defs := []*pb.Definition{}
 	objDef := iface.UnderlyingType.TypeDef().AsObject.Value
 	for name, val := range iface.Fields {
@@ -154,7 +154,7 @@ func (d *ModDeps) lazilyLoadSchema(ctx context.Context) (loadedSchema *dagql.Ser
 			return nil, "", fmt.Errorf("failed to get schema for module %q: %w", mod.Name(), err)
 		}
 
-		// TODO support core interfaces types
+		// TODO support core interfaces types (core doesn't define any yet)
 		if userMod, ok := mod.(*Module); ok {
 			defs, err := mod.TypeDefs(ctx)
 			if err != nil {
@@ -215,6 +215,60 @@ func (d *ModDeps) lazilyLoadSchema(ctx context.Context) (loadedSchema *dagql.Ser
 		}
 	}
 
+	// core objects can implement interfaces too
+	if len(ifaces) > 0 {
+		for _, mod := range d.Mods {
+			if _, ok := mod.(*Module); ok {
+				continue
+			}
+			defs, err := mod.TypeDefs(ctx)
+			if err != nil {
+				return nil, "", fmt.Errorf("failed to get type defs for module %q: %w", mod.Name(), err)
+			}
+			for _, def := range defs {
+				if def.Kind != TypeDefKindObject {
+					continue
+				}
+				objType, ok, err := mod.ModTypeFor(ctx, def, false)
+				if err != nil {
+					return nil, "", fmt.Errorf("failed to get mod type for %q: %w", def.AsObject.Value.Name, err)
+				}
+				if !ok {
+					continue
+				}
+				obj := objType.TypeDef().AsObject.Value
+				class, found := dag.ObjectType(obj.Name)
+				if !found {
+					return nil, "", fmt.Errorf("failed to find object %q in schema", obj.Name)
+				}
+				for _, ifaceType := range ifaces {
+					iface := ifaceType.typeDef
+					if !obj.IsSubtypeOf(iface) || !obj.ExtraArgsOptional(iface) {
+						continue
+					}
+					ifaceType := ifaceType
+					asIfaceFieldName := gqlFieldName(fmt.Sprintf("as%s", iface.Name))
+					class.Extend(
+						dagql.FieldSpec{
+							Name:        asIfaceFieldName,
+							Description: fmt.Sprintf("Converts this %s to a %s.", obj.Name, iface.Name),
+							Type:        &InterfaceAnnotatedValue{TypeDef: iface},
+							Module:      ifaceType.mod.IDModule(),
+						},
+						func(ctx context.Context, self dagql.Object, args map[string]dagql.Input) (dagql.Typed, error) {
+							return &InterfaceAnnotatedValue{
+								TypeDef:          iface,
+								UnderlyingType:   objType,
+								UnderlyingObject: self,
+								IfaceType:        ifaceType,
+							}, nil
+						},
+					)
+				}
+			}
+		}
+	}
+
 	introspectionJSON, err := schemaIntrospectionJSON(ctx, dag)
 	if err != nil {
 		return nil, "", fmt.Errorf("failed to get schema introspection JSON: %w", err)
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/dagger/dagger/analytics"
 	"github.com/dagger/dagger/core/pipeline"
@@ -111,6 +112,44 @@ func (fn *ModuleFunction) recordCall(ctx context.Context) {
 	analytics.Ctx(ctx).Capture(ctx, "module_call", props)
 }
 
+// cacheKey returns a value to mix into the digest of a call to the function
+// that scopes how long its result is reused for, according to the function's
+// cache policy, or "" if it's reused indefinitely.
+//
+// Calls with tainted inputs, like host directories, are only reused within the
+// session, whatever the policy, since the inputs can change between sessions
+// without their IDs changing.
+func (fn *ModuleFunction) cacheKey(ctx context.Context, caller *idproto.ID, inputs []CallInput) (string, error) {
+	policy := fn.metadata.CachePolicy
+	if fn.root.NoCache || caller.IsTainted() {
+		policy = FunctionCachePolicySession
+	}
+	for _, input := range inputs {
+		if in, ok := input.Value.(dagql.Input); ok && in.ToLiteral().Tainted() {
+			policy = FunctionCachePolicySession
+		}
+	}
+	switch policy {
+	case FunctionCachePolicyPure:
+		return "", nil
+	case FunctionCachePolicyTTL:
+		// results are reused until the end of the current TTL-sized window, so
+		// they are never reused for longer than the TTL
+		ttl := int64(fn.metadata.CacheTimeToLive)
+		if ttl <= 0 {
+			return "", fmt.Errorf("function %q has cache policy %s without a time to live", fn.metadata.Name, policy)
+		}
+		return fmt.Sprintf("ttl:%d", time.Now().Unix()/ttl), nil
+	default:
+		// use the ServerID so that we bust cache once-per-session
+		clientMetadata, err := engine.ClientMetadataFromContext(ctx)
+		if err != nil {
+			return "", fmt.Errorf("failed to get client metadata: %w", err)
+		}
+		return clientMetadata.ServerID, nil
+	}
+}
+
 func (fn *ModuleFunction) Call(ctx context.Context, caller *idproto.ID, opts *CallOpts) (t dagql.Typed, rerr error) {
 	mod := fn.mod
 
@@ -173,12 +212,13 @@ func (fn *ModuleFunction) Call(ctx context.Context, caller *idproto.ID, opts *Ca
 		callerDigestInputs = append(callerDigestInputs, callerIDDigest.String())
 	}
 	if !opts.Cache {
-		// use the ServerID so that we bust cache once-per-session
-		clientMetadata, err := engine.ClientMetadataFromContext(ctx)
+		cacheKey, err := fn.cacheKey(ctx, caller, opts.Inputs)
 		if err != nil {
-			return nil, fmt.Errorf("failed to get client metadata: %w", err)
+			return nil, err
+		}
+		if cacheKey != "" {
+			callerDigestInputs = append(callerDigestInputs, cacheKey)
 		}
-		callerDigestInputs = append(callerDigestInputs, clientMetadata.ServerID)
 	}
 
 	callerDigest := digest.FromString(strings.Join(callerDigestInputs, " "))
@@ -72,6 +72,23 @@ type ModuleConfigDependency struct {
 
 	// The source ref of the module dependency.
 	Source string `json:"source"`
+
+	// The resolved commit of a git dependency's source ref, which is used
+	// instead of re-resolving the ref's version when the dependency is loaded.
+	Pin string `json:"pin,omitempty"`
+
+	// The version of the source ref that Pin was resolved from. If the source
+	// ref's version changes, the pin is stale and is not used.
+	PinVersion string `json:"pinVersion,omitempty"`
+}
+
+// PinFor returns the dependency's pin if it was resolved from the given
+// version of its source ref, or "" if it is missing or stale.
+func (depCfg *ModuleConfigDependency) PinFor(version string) string {
+	if depCfg.Pin == "" || depCfg.PinVersion == "" || depCfg.PinVersion != version {
+		return ""
+	}
+	return depCfg.Pin
 }
 
 func (depCfg *ModuleConfigDependency) UnmarshalJSON(data []byte) error {
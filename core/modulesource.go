@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"net/url"
 	"path/filepath"
 	"strings"
 
@@ -56,6 +57,20 @@ type ModuleSource struct {
 	WithDependencies  []dagql.Instance[*ModuleDependency]
 	WithSDK           string
 	WithSourceSubpath string
+
+	// Names of configured dependencies whose pins should be ignored, so that
+	// they are re-resolved from their source ref's version.
+	WithUpdateDependencies []string
+
+	// Names of dependencies to remove from the source's dependency list.
+	WithoutDependencies []string
+
+	// Tags that git modules required with version constraints anywhere in the
+	// dependency tree were resolved to, keyed by their symbolic ref. It is nil
+	// unless the source is loaded as a dependency of another module, in which
+	// case the module loaded directly resolved the constraints for its whole
+	// dependency tree.
+	ResolvedDependencyVersions map[string]string
 }
 
 func (src *ModuleSource) Type() *ast.Type {
@@ -89,6 +104,23 @@ func (src ModuleSource) Clone() *ModuleSource {
 		copy(cp.WithDependencies, src.WithDependencies)
 	}
 
+	if src.WithUpdateDependencies != nil {
+		cp.WithUpdateDependencies = make([]string, len(src.WithUpdateDependencies))
+		copy(cp.WithUpdateDependencies, src.WithUpdateDependencies)
+	}
+
+	if src.WithoutDependencies != nil {
+		cp.WithoutDependencies = make([]string, len(src.WithoutDependencies))
+		copy(cp.WithoutDependencies, src.WithoutDependencies)
+	}
+
+	if src.ResolvedDependencyVersions != nil {
+		cp.ResolvedDependencyVersions = make(map[string]string, len(src.ResolvedDependencyVersions))
+		for symbolic, tag := range src.ResolvedDependencyVersions {
+			cp.ResolvedDependencyVersions[symbolic] = tag
+		}
+	}
+
 	return &cp
 }
 
@@ -324,7 +356,19 @@ type GitModuleSource struct {
 
 	Commit string `field:"true" doc:"The resolved commit of the git repo this source points to."`
 
-	URLParent string
+	// RepoRootRef is the root of the git repo as it appears in ref strings,
+	// e.g. github.com/org/repo or ssh://git@example.com/org/repo.git.
+	RepoRootRef string
+
+	// CloneRef is the URL the git repo is cloned from.
+	CloneRef string
+
+	// HTMLRepoURL is the base URL of the git repo's web UI.
+	HTMLRepoURL string
+
+	// Pinned is set if the source was loaded at a pinned commit rather than
+	// by resolving its version.
+	Pinned bool
 
 	RootSubpath string `field:"true" doc:"The path to the root of the module source under the context directory. This directory contains its configuration file. It also contains its source code (possibly as a subdirectory)."`
 
@@ -355,12 +399,22 @@ func (src *GitModuleSource) PBDefinitions(ctx context.Context) ([]*pb.Definition
 }
 
 func (src *GitModuleSource) RefString() string {
-	refPath := src.URLParent
+	return fmt.Sprintf("%s@%s", src.refPath(), src.Commit)
+}
+
+// VersionRefString is the ref string of the source at its specified version
+// rather than its resolved commit, e.g. github.com/org/repo/sub@main.
+func (src *GitModuleSource) VersionRefString() string {
+	return fmt.Sprintf("%s@%s", src.refPath(), src.Version)
+}
+
+func (src *GitModuleSource) refPath() string {
+	refPath := src.RepoRootRef
 	subPath := filepath.Join("/", src.RootSubpath)
 	if subPath != "/" {
 		refPath += subPath
 	}
-	return fmt.Sprintf("%s@%s", refPath, src.Commit)
+	return refPath
 }
 
 func (src *GitModuleSource) Symbolic() string {
@@ -368,13 +422,36 @@ func (src *GitModuleSource) Symbolic() string {
 }
 
 func (src *GitModuleSource) CloneURL() string {
-	return "https://" + src.URLParent
+	return src.CloneRef
 }
 
 func (src *GitModuleSource) HTMLURL() string {
-	u := "https://" + src.URLParent + "/tree/" + src.Commit
+	u := src.HTMLRepoURL + gitForgeTreePath(src.HTMLRepoURL) + src.Commit
 	if subPath := src.RootSubpath; subPath != "" {
 		u += "/" + subPath
 	}
 	return u
 }
+
+// gitForgeTreePath returns the path segment that forges place between a repo
+// URL and a commit to browse the tree at that commit, based on the repo's
+// host. Unrecognized hosts are assumed to follow GitHub's layout.
+func gitForgeTreePath(repoURL string) string {
+	host := repoURL
+	if u, err := url.Parse(repoURL); err == nil {
+		host = u.Hostname()
+	}
+	switch {
+	case host == "bitbucket.org":
+		return "/src/"
+	case host == "codeberg.org",
+		strings.HasPrefix(host, "gitea."),
+		strings.HasPrefix(host, "forgejo."):
+		return "/src/commit/"
+	case host == "gitlab.com",
+		strings.HasPrefix(host, "gitlab."):
+		return "/-/tree/"
+	default:
+		return "/tree/"
+	}
+}
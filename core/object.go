@@ -241,7 +241,6 @@ func (obj *ModuleObject) installConstructor(ctx context.Context, dag *dagql.Serv
 	}
 
 	spec.Name = gqlFieldName(mod.Name())
-	spec.ImpurityReason = "Module functions are currently always impure."
 	spec.Module = obj.Module.IDModule()
 
 	dag.Root().ObjectType().Extend(
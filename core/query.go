@@ -46,6 +46,10 @@ type Query struct {
 	// The DagQL query cache.
 	Cache dagql.Cache
 
+	// Whether to ignore the cache policies of module functions, running each
+	// call at most once per session like an impure function.
+	NoCache bool
+
 	// The metadata of client calls.
 	// For the special case of the main client caller, the key is just empty string.
 	// This is never explicitly deleted from; instead it will just be garbage collected
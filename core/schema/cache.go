@@ -2,9 +2,11 @@ package schema
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/dagger/dagger/core"
 	"github.com/dagger/dagger/dagql"
+	"github.com/opencontainers/go-digest"
 )
 
 type cacheSchema struct {
@@ -20,11 +22,51 @@ func (s *cacheSchema) Name() string {
 func (s *cacheSchema) Install() {
 	dagql.Fields[*core.Query]{
 		dagql.Func("cacheVolume", s.cacheVolume).
-			Doc("Constructs a cache volume for a given cache key.").
-			ArgDoc("key", `A string identifier to target this cache volume (e.g., "modules-cache").`),
+			CacheKey(s.cacheVolumeCacheKey).
+			Doc("Constructs a cache volume for a given cache key.",
+				`By default, cache volumes constructed by a module are scoped to that
+				module, so that modules using the same key don't share a volume. Cache
+				volumes constructed by the main client are shared.`,
+				`Namespaces keep modules from sharing cache volumes by accident, but are
+				not access control: any module can construct a cache volume in another
+				module's namespace, and use the cache volumes other modules give it.`).
+			ArgDoc("key", `A string identifier to target this cache volume (e.g., "modules-cache").`).
+			ArgDoc("namespace", `A namespace to scope the cache volume to instead of the calling module.`,
+				`Cache volumes with the same key and namespace are shared.`).
+			ArgDoc("shared", `Share the cache volume with every module and client that uses the same key.`),
+		dagql.Func("cacheVolumes", s.cacheVolumes).
+			Impure("Lists the current state of the engine's cache volumes.").
+			Doc("Lists the cache volumes used since the engine started, sorted by namespace and key.",
+				`Modules only see their own cache volumes and shared ones.`),
 	}.Install(s.srv)
 
-	dagql.Fields[*core.CacheVolume]{}.Install(s.srv)
+	dagql.Fields[*core.CacheVolume]{
+		dagql.Func("key", s.key).
+			Doc("The key the cache volume was constructed with."),
+		dagql.Func("namespace", s.namespace).
+			Doc("The namespace the cache volume is scoped to, or an empty string if it's shared."),
+		dagql.Func("snapshot", s.snapshot).
+			Impure("Reads the current contents of the cache volume.").
+			Doc("Returns a copy of the current contents of the cache volume."),
+		dagql.Func("seed", s.seed).
+			Impure("Mutates the cache volume.").
+			Doc("Copies the contents of a directory into the cache volume.",
+				`Files already in the cache volume are kept unless the directory has a
+				file at the same path.`).
+			ArgDoc("source", "The directory to copy into the cache volume."),
+		dagql.Func("size", s.size).
+			Impure("Reads the current state of the cache volume.").
+			Doc("The disk space used by the cache volume, in bytes."),
+		dagql.Func("mostRecentUseTime", s.mostRecentUseTime).
+			Impure("Reads the current state of the cache volume.").
+			Doc("The time the cache volume was last used, in seconds following Unix epoch, or 0 if it was never used."),
+		dagql.Func("clear", s.clear).
+			Impure("Mutates the cache volume.").
+			Doc(
+				"Empties the cache volume.",
+				"Subsequent uses of the cache volume start from an empty directory, while pipelines using it right now keep their contents.",
+			),
+	}.Install(s.srv)
 }
 
 func (s *cacheSchema) Dependencies() []SchemaResolvers {
@@ -32,13 +74,126 @@ func (s *cacheSchema) Dependencies() []SchemaResolvers {
 }
 
 type cacheArgs struct {
-	Key string
+	Key       string
+	Namespace dagql.Optional[dagql.String]
+	Shared    bool `default:"false"`
+}
+
+func (s *cacheSchema) cacheVolume(ctx context.Context, parent *core.Query, args cacheArgs) (inst dagql.Instance[*core.CacheVolume], err error) {
+	if args.Shared && args.Namespace.Valid {
+		return inst, fmt.Errorf("cache volume %q cannot both be shared and have a namespace", args.Key)
+	}
+
+	if !args.Shared && !args.Namespace.Valid {
+		// NB: select the default scope explicitly so that the ID refers to the same
+		// cache volume wherever it's loaded, e.g. by a module it's passed to
+		namespace, err := core.CacheVolumeNamespace(ctx, parent)
+		if err != nil {
+			return inst, err
+		}
+		scope := dagql.NamedInput{
+			Name:  "shared",
+			Value: dagql.Boolean(true),
+		}
+		if namespace != "" {
+			scope = dagql.NamedInput{
+				Name:  "namespace",
+				Value: dagql.Opt(dagql.NewString(namespace)),
+			}
+		}
+		if err := s.srv.Select(ctx, s.srv.Root(), &inst, dagql.Selector{
+			Field: "cacheVolume",
+			Args: []dagql.NamedInput{
+				{
+					Name:  "key",
+					Value: dagql.NewString(args.Key),
+				},
+				scope,
+			},
+		}); err != nil {
+			return inst, err
+		}
+		return inst, nil
+	}
+
+	cache := core.NewCache(args.Key)
+	cache.Query = parent
+	if args.Namespace.Valid {
+		cache.Namespace = args.Namespace.Value.String()
+	}
+	class, ok := s.srv.ObjectType(cache.Type().Name())
+	if !ok {
+		return inst, fmt.Errorf("unknown type %q", cache.Type().Name())
+	}
+	obj, err := class.New(dagql.CurrentID(ctx), cache)
+	if err != nil {
+		return inst, err
+	}
+	return obj.(dagql.Instance[*core.CacheVolume]), nil
+}
+
+// cacheVolumeCacheKey scopes the cached results of cacheVolume to the calling
+// module, since the namespace of a cache volume defaults to it.
+func (s *cacheSchema) cacheVolumeCacheKey(ctx context.Context, dig digest.Digest) (digest.Digest, error) {
+	query := s.srv.Root().(dagql.Instance[*core.Query]).Self
+	namespace, err := core.CacheVolumeNamespace(ctx, query)
+	if err != nil {
+		return "", err
+	}
+	if namespace == "" {
+		return dig, nil
+	}
+	return digest.FromString(dig.String() + "\x00" + namespace), nil
+}
+
+func (s *cacheSchema) cacheVolumes(ctx context.Context, parent *core.Query, args struct{}) (dagql.Array[*core.CacheVolume], error) {
+	return core.KnownCacheVolumes(ctx, parent)
+}
+
+func (s *cacheSchema) key(ctx context.Context, parent *core.CacheVolume, args struct{}) (dagql.String, error) {
+	return dagql.NewString(parent.Key()), nil
+}
+
+func (s *cacheSchema) namespace(ctx context.Context, parent *core.CacheVolume, args struct{}) (dagql.String, error) {
+	return dagql.NewString(parent.Namespace), nil
+}
+
+func (s *cacheSchema) snapshot(ctx context.Context, parent *core.CacheVolume, args struct{}) (*core.Directory, error) {
+	return parent.Snapshot(ctx)
+}
+
+type cacheSeedArgs struct {
+	Source core.DirectoryID
+}
+
+func (s *cacheSchema) seed(ctx context.Context, parent *core.CacheVolume, args cacheSeedArgs) (dagql.Nullable[core.Void], error) {
+	void := dagql.Null[core.Void]()
+	dir, err := args.Source.Load(ctx, s.srv)
+	if err != nil {
+		return void, err
+	}
+	return void, parent.Seed(ctx, dir.Self)
+}
+
+func (s *cacheSchema) size(ctx context.Context, parent *core.CacheVolume, args struct{}) (dagql.Int, error) {
+	usage, err := parent.Usage(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return dagql.NewInt(int(usage.Size)), nil
+}
+
+func (s *cacheSchema) mostRecentUseTime(ctx context.Context, parent *core.CacheVolume, args struct{}) (dagql.Int, error) {
+	usage, err := parent.Usage(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if usage.LastUsedAt == nil {
+		return 0, nil
+	}
+	return dagql.NewInt(int(usage.LastUsedAt.Unix())), nil
 }
 
-func (s *cacheSchema) cacheVolume(ctx context.Context, parent *core.Query, args cacheArgs) (*core.CacheVolume, error) {
-	// TODO(vito): inject some sort of scope/session/project/user derived value
-	// here instead of a static value
-	//
-	// we have to inject something so we can tell it's a valid ID
-	return core.NewCache(args.Key), nil
+func (s *cacheSchema) clear(ctx context.Context, parent *core.CacheVolume, args struct{}) (dagql.Nullable[core.Void], error) {
+	return dagql.Null[core.Void](), parent.Clear(ctx)
 }
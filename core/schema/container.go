@@ -51,7 +51,8 @@ func (s *containerSchema) Install() {
 			Doc(`Initializes this container from a pulled base image.`).
 			ArgDoc("address",
 				`Image's address from its registry.`,
-				`Formatted as [host]/[user]/[repo]:[tag] (e.g., "docker.io/dagger/dagger:main").`),
+				`Formatted as [host]/[user]/[repo]:[tag] (e.g., "docker.io/dagger/dagger:main").`).
+			Retry(remoteRetryPolicy),
 
 		dagql.Func("build", s.build).
 			Doc(`Initializes this container from a Dockerfile build.`).
@@ -302,7 +303,37 @@ func (s *containerSchema) Install() {
 				running a command with "sudo" or executing "docker run" with the
 				"--privileged" flag. Containerization does not provide any security
 				guarantees when using this option. It should only be used when
-				absolutely necessary and only with trusted commands.`),
+				absolutely necessary and only with trusted commands.`).
+			ArgDoc("expect",
+				`Exit codes this command is allowed to exit with without error.`,
+				`Use FAILURE or ANY to keep the container's files when the command
+				fails, and read the exit code with exitCode.`).
+			ArgDoc("timeout",
+				`Maximum duration of the command in seconds, after which it is killed
+				and fails with a timeout error. Overrides the container's default
+				set by withResourceLimits.`).
+			ArgDoc("memoryLimit",
+				`Maximum amount of memory the command may use (e.g., "512M", "2G").
+				Overrides the container's default set by withResourceLimits.`).
+			ArgDoc("cpuQuota",
+				`CPU time the command may use per 100ms period, in microseconds (e.g.,
+				150000 for 1.5 CPUs). Overrides the container's default set by
+				withResourceLimits.`).
+			ArgDoc("pidsLimit",
+				`Maximum number of processes the command may run. Overrides the
+				container's default set by withResourceLimits.`),
+
+		dagql.Func("withResourceLimits", s.withResourceLimits).
+			Doc(`Retrieves this container with default limits for subsequently executed commands.`,
+				`A zero value means no limit. Limits passed to withExec take precedence.`).
+			ArgDoc("timeout", `Maximum duration of each command in seconds.`).
+			ArgDoc("memoryLimit", `Maximum amount of memory each command may use (e.g., "512M", "2G").`).
+			ArgDoc("cpuQuota", `CPU time each command may use per 100ms period, in microseconds (e.g., 150000 for 1.5 CPUs).`).
+			ArgDoc("pidsLimit", `Maximum number of processes each command may run.`),
+
+		dagql.Func("exitCode", s.exitCode).
+			Doc(`The exit code of the last executed command.`,
+				`Will execute default command if none is set, or error if there's no default.`),
 
 		dagql.Func("stdout", s.stdout).
 			Doc(`The output stream of the last executed command.`,
@@ -412,6 +443,24 @@ func (s *containerSchema) Install() {
 			ArgDoc("description", `Optional port description`).
 			ArgDoc("experimentalSkipHealthcheck", `Skip the health check when run as a service.`),
 
+		dagql.Func("withHealthcheck", s.withHealthcheck).
+			Doc(`Configures how to check that the container is ready when run as a service.`,
+				`Exactly one of command, httpGet or tcpPort must be set. The service is
+				probed from inside its container until a probe succeeds, replacing the
+				default check that all exposed ports accept connections.`,
+				`Images that configure a HEALTHCHECK use it by default.`).
+			ArgDoc("command", `Command to run in the service's container, healthy if it exits 0.`).
+			ArgDoc("httpGet", `HTTP request to send to the service, healthy if it responds with the expected status.`).
+			ArgDoc("tcpPort", `Port the service must accept TCP connections on.`).
+			ArgDoc("interval", `Number of seconds to wait between probes.`).
+			ArgDoc("timeout", `Number of seconds after which a probe fails.`).
+			ArgDoc("retries", `Number of consecutive failed probes after which the service fails to start.`).
+			ArgDoc("startPeriod", `Number of seconds to give the service to start, during which failed probes are not counted.`),
+
+		dagql.Func("withoutHealthcheck", s.withoutHealthcheck).
+			Doc(`Retrieves this container without a healthcheck, including one configured by its image.`,
+				`Its exposed ports will be checked instead when run as a service.`),
+
 		dagql.Func("withoutExposedPort", s.withoutExposedPort).
 			Doc(`Unexpose a previously exposed port.`).
 			ArgDoc("port", `Port number to unexpose`).
@@ -429,7 +478,9 @@ func (s *containerSchema) Install() {
 				`The service will be reachable from the container via the provided hostname alias.`,
 				`The service dependency will also convey to any files or directories produced by the container.`).
 			ArgDoc("alias", `A name that can be used to reach the service from the container`).
-			ArgDoc("service", `Identifier of the service container`),
+			ArgDoc("service", `Identifier of the service container`).
+			ArgDoc("dependsOn", `Aliases of other services bound to the container that must be started
+				and healthy before this service is started.`),
 
 		dagql.Func("withFocus", s.withFocus).
 			Doc(`Indicate that subsequent operations should be featured more prominently in the UI.`),
@@ -557,6 +608,14 @@ func (s *containerSchema) withExec(ctx context.Context, parent *core.Container,
 	return parent.WithExec(ctx, args.ContainerExecOpts)
 }
 
+func (s *containerSchema) withResourceLimits(ctx context.Context, parent *core.Container, args core.ContainerResourceLimits) (*core.Container, error) {
+	return parent.WithResourceLimits(ctx, args)
+}
+
+func (s *containerSchema) exitCode(ctx context.Context, parent *core.Container, _ struct{}) (int, error) {
+	return parent.ExitCode(ctx)
+}
+
 func (s *containerSchema) stdout(ctx context.Context, parent *core.Container, _ struct{}) (string, error) {
 	return parent.MetaFileContents(ctx, "stdout")
 }
@@ -1229,8 +1288,9 @@ func (s *containerSchema) imageRef(ctx context.Context, parent *core.Container,
 }
 
 type containerWithServiceBindingArgs struct {
-	Alias   string
-	Service core.ServiceID
+	Alias     string
+	Service   core.ServiceID
+	DependsOn []string `default:"[]"`
 }
 
 func (s *containerSchema) withServiceBinding(ctx context.Context, parent *core.Container, args containerWithServiceBindingArgs) (*core.Container, error) {
@@ -1239,7 +1299,7 @@ func (s *containerSchema) withServiceBinding(ctx context.Context, parent *core.C
 		return nil, err
 	}
 
-	return parent.WithServiceBinding(ctx, svc.ID(), svc.Self, args.Alias)
+	return parent.WithServiceBinding(ctx, svc.ID(), svc.Self, args.Alias, args.DependsOn...)
 }
 
 type containerWithExposedPortArgs struct {
@@ -1258,6 +1318,38 @@ func (s *containerSchema) withExposedPort(ctx context.Context, parent *core.Cont
 	})
 }
 
+type containerWithHealthcheckArgs struct {
+	Command     []string                                                   `default:"[]"`
+	HTTPGet     dagql.Optional[dagql.InputObject[core.HealthcheckHTTPGet]] `name:"httpGet"`
+	TCPPort     *int                                                       `name:"tcpPort"`
+	Interval    int                                                        `default:"1"`
+	Timeout     int                                                        `default:"30"`
+	Retries     int                                                        `default:"3"`
+	StartPeriod int                                                        `default:"0"`
+}
+
+func (s *containerSchema) withHealthcheck(ctx context.Context, parent *core.Container, args containerWithHealthcheckArgs) (*core.Container, error) {
+	hc := core.ContainerHealthcheck{
+		Command:     args.Command,
+		Interval:    args.Interval,
+		Timeout:     args.Timeout,
+		Retries:     args.Retries,
+		StartPeriod: args.StartPeriod,
+	}
+	if args.HTTPGet.Valid {
+		httpGet := args.HTTPGet.Value.Value
+		hc.HTTPGet = &httpGet
+	}
+	if args.TCPPort != nil {
+		hc.TCPPort = *args.TCPPort
+	}
+	return parent.WithHealthcheck(ctx, hc)
+}
+
+func (s *containerSchema) withoutHealthcheck(ctx context.Context, parent *core.Container, args struct{}) (*core.Container, error) {
+	return parent.WithoutHealthcheck(ctx)
+}
+
 type containerWithoutExposedPortArgs struct {
 	Port     int
 	Protocol core.NetworkProtocol `default:"TCP"`
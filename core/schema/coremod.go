@@ -11,6 +11,7 @@ import (
 	"github.com/dagger/dagger/core"
 	"github.com/dagger/dagger/dagql"
 	"github.com/dagger/dagger/dagql/idproto"
+	"github.com/vektah/gqlparser/v2/ast"
 )
 
 // CoreMod is a special implementation of Mod for our core API, which is not *technically* a true module yet
@@ -41,10 +42,11 @@ func (m *CoreMod) Install(ctx context.Context, dag *dagql.Server) error {
 		&secretSchema{dag},
 		&serviceSchema{dag},
 		&hostSchema{dag},
+		&engineSchema{dag},
 		&httpSchema{dag},
 		&platformSchema{dag},
 		&socketSchema{dag},
-		&moduleSchema{dag},
+		&moduleSchema{dag: dag},
 	} {
 		schema.Install()
 	}
@@ -76,7 +78,7 @@ func (m *CoreMod) ModTypeFor(ctx context.Context, typeDef *core.TypeDef, checkDi
 		if !ok {
 			return nil, false, nil
 		}
-		modType = &CoreModObject{coreMod: m}
+		modType = &CoreModObject{coreMod: m, name: typeDef.AsObject.Value.Name}
 
 	case core.TypeDefKindInterface:
 		// core does not yet defined any interfaces
@@ -126,6 +128,7 @@ func (m *CoreMod) TypeDefs(ctx context.Context) ([]*core.TypeDef, error) {
 				fn := &core.Function{
 					Name:        introspectionField.Name,
 					Description: introspectionField.Description,
+					CachePolicy: core.FunctionCachePolicySession,
 				}
 
 				rtType, ok, err := introspectionRefToTypeDef(introspectionField.TypeRef, false, false)
@@ -252,14 +255,94 @@ func (obj *CoreModObject) SourceMod() core.Mod {
 }
 
 func (obj *CoreModObject) TypeDef() *core.TypeDef {
-	// TODO: to support matching core types against interfaces, we will need to actually fill
-	// this out with the functions rather than just name
+	typeDef := &core.ObjectTypeDef{
+		Name: obj.name,
+	}
+
+	// fill in the functions from the core schema so that core types can be
+	// checked against interfaces
+	class, ok := obj.coreMod.dag.ObjectType(obj.name)
+	if ok {
+		if definitive, ok := class.(dagql.Definitive); ok {
+			def := definitive.TypeDefinition()
+			typeDef.Description = def.Description
+			for _, field := range def.Fields {
+				if field.Name == "id" {
+					continue
+				}
+				fn := &core.Function{
+					Name:        field.Name,
+					Description: field.Description,
+					CachePolicy: core.FunctionCachePolicySession,
+					ReturnType:  obj.coreMod.astTypeToTypeDef(field.Type, false),
+				}
+				for _, arg := range field.Arguments {
+					fnArg := &core.FunctionArg{
+						Name:        arg.Name,
+						Description: arg.Description,
+						TypeDef:     obj.coreMod.astTypeToTypeDef(arg.Type, true),
+					}
+					if arg.DefaultValue != nil {
+						fnArg.DefaultValue = core.JSON(arg.DefaultValue.String())
+					}
+					fn.Args = append(fn.Args, fnArg)
+				}
+				typeDef.Functions = append(typeDef.Functions, fn)
+			}
+		}
+	}
+
 	return &core.TypeDef{
-		Kind: core.TypeDefKindObject,
-		AsObject: dagql.NonNull(&core.ObjectTypeDef{
-			Name: obj.name,
-		}),
+		Kind:     core.TypeDefKindObject,
+		AsObject: dagql.NonNull(typeDef),
+	}
+}
+
+// astTypeToTypeDef converts a type from the core schema to a TypeDef, following
+// the same conventions as introspectionRefToTypeDef. Unlike that function it
+// never drops a type, since args are matched positionally against interfaces.
+func (m *CoreMod) astTypeToTypeDef(t *ast.Type, isInput bool) *core.TypeDef {
+	if t.Elem != nil {
+		return &core.TypeDef{
+			Kind:     core.TypeDefKindList,
+			Optional: !t.NonNull,
+			AsList: dagql.NonNull(&core.ListTypeDef{
+				ElementTypeDef: m.astTypeToTypeDef(t.Elem, isInput),
+			}),
+		}
+	}
+
+	typeDef := &core.TypeDef{
+		Optional: !t.NonNull,
+	}
+	switch name := t.NamedType; {
+	case name == string(introspection.ScalarString):
+		typeDef.Kind = core.TypeDefKindString
+	case name == string(introspection.ScalarInt):
+		typeDef.Kind = core.TypeDefKindInteger
+	case name == string(introspection.ScalarBoolean):
+		typeDef.Kind = core.TypeDefKindBoolean
+	case name == "Void":
+		typeDef.Kind = core.TypeDefKindVoid
+	case isInput && strings.HasSuffix(name, "ID"):
+		// convert ID inputs to the actual object
+		typeDef.Kind = core.TypeDefKindObject
+		typeDef.AsObject = dagql.NonNull(&core.ObjectTypeDef{
+			Name: strings.TrimSuffix(name, "ID"),
+		})
+	default:
+		if _, ok := m.dag.ObjectType(name); ok && !isInput {
+			typeDef.Kind = core.TypeDefKindObject
+			typeDef.AsObject = dagql.NonNull(&core.ObjectTypeDef{
+				Name: name,
+			})
+		} else {
+			// default to saying it's a string for now, as with enums and
+			// other scalars in introspectionRefToTypeDef
+			typeDef.Kind = core.TypeDefKindString
+		}
 	}
+	return typeDef
 }
 
 func introspectionRefToTypeDef(introspectionType *introspection.TypeRef, nonNull, isInput bool) (*core.TypeDef, bool, error) {
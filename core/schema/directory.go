@@ -34,9 +34,19 @@ func (s *directorySchema) Install() {
 		dagql.Func("entries", s.entries).
 			Doc(`Returns a list of files and directories at the given path.`).
 			ArgDoc("path", `Location of the directory to look at (e.g., "/src").`),
+		dagql.Func("entriesWithStat", s.entriesWithStat).
+			Doc(`Returns the metadata of each file and directory at the given path.`).
+			ArgDoc("path", `Location of the directory to look at (e.g., "/src").`),
+		dagql.Func("stat", s.stat).
+			Doc(`Retrieves the metadata of the file, directory or symlink at the given path.`,
+				`Symlinks are not followed.`).
+			ArgDoc("path", `Location of the entry to look at (e.g., "bin/run.sh").`),
 		dagql.Func("glob", s.glob).
 			Doc(`Returns a list of files and directories that matche the given pattern.`).
 			ArgDoc("pattern", `Pattern to match (e.g., "*.md").`),
+		dagql.Func("digest", s.digest).
+			Doc(`Returns a digest of the directory's contents (e.g., "sha256:...").`).
+			ArgDoc("excludeMetadata", `Only hash file names and contents, ignoring permissions, ownership and timestamps.`),
 		dagql.Func("file", s.file).
 			Doc(`Retrieves a file at the given path.`).
 			ArgDoc("path", `Location of the file to retrieve (e.g., "README.md").`),
@@ -77,10 +87,26 @@ func (s *directorySchema) Install() {
 		dagql.Func("diff", s.diff).
 			Doc(`Gets the difference between this directory and an another directory.`).
 			ArgDoc("other", `Identifier of the directory to compare.`),
+		dagql.Func("asPatch", s.asPatch).
+			Doc(`Returns a unified diff of the changes from this directory to another directory, in the format of git diff.`).
+			ArgDoc("other", `Identifier of the directory to compare.`),
+		dagql.Func("withPatch", s.withPatch).
+			Doc(`Retrieves this directory with the given unified diff applied, as written by diff -u, git diff or git format-patch.`,
+				`Fails without applying anything if any part of the patch does not apply.`).
+			ArgDoc("patch", `Identifier of the file containing the patch.`).
+			ArgDoc("strip", `Number of leading path components to strip from file names in the patch, as with patch -p.`),
 		dagql.Func("export", s.export).
 			Impure("Writes to the local host.").
 			Doc(`Writes the contents of the directory to a path on the host.`).
-			ArgDoc("path", `Location of the copied directory (e.g., "logs/").`),
+			ArgDoc("path", `Location of the copied directory (e.g., "logs/").`).
+			ArgDoc("wipe", `Remove anything under the path that isn't in this directory, making the path an exact copy of it.`,
+				`The filesystem root, the home directory and the working directory, and any directory containing them, cannot be wiped.`),
+		dagql.Func("exportDryRun", s.exportDryRun).
+			Impure("Reads from the local host.").
+			Doc(`Reports the changes that exporting the directory to a path on the host would make, without writing anything.`,
+				`Each change is formatted as "A path" for added, "M path" for modified and "D path" for deleted entries, with directories ending in a slash.`).
+			ArgDoc("path", `Location of the copied directory (e.g., "logs/").`).
+			ArgDoc("wipe", `Report anything under the path that isn't in this directory as deleted.`),
 		dagql.Func("dockerBuild", s.dockerBuild).
 			Doc(`Builds a new Docker container from this directory.`).
 			ArgDoc("dockerfile", `Path to the Dockerfile to use (e.g., "frontend.Dockerfile").`).
@@ -89,6 +115,41 @@ func (s *directorySchema) Install() {
 			ArgDoc("target", `Target build stage to build.`).
 			ArgDoc("secrets", `Secrets to pass to the build.`,
 				`They will be mounted at /run/secrets/[secret-name].`),
+		dagql.Func("withSymlink", s.withSymlink).
+			Doc(`Retrieves this directory plus a symlink at the given path.`,
+				`Any existing file or directory at linkName is replaced.`).
+			ArgDoc("target", `Location the symlink points to (e.g., "../lib/foo.so").`).
+			ArgDoc("linkName", `Location of the created symlink (e.g., "/lib/bar.so").`),
+		dagql.Func("withPermissions", s.withPermissions).
+			Doc(`Retrieves this directory with the permissions of the given path changed.`).
+			ArgDoc("path", `Location of the file or directory to change (e.g., "/bin/run.sh").`).
+			ArgDoc("mode", `Permissions to set (e.g., 0755).`).
+			ArgDoc("recursive", `If the path is a directory, also change the permissions of its contents.`),
+		dagql.Func("withOwner", s.withOwner).
+			Doc(`Retrieves this directory with the ownership of the given path changed.`,
+				`Directories are changed recursively.`).
+			ArgDoc("path", `Location of the file or directory to change (e.g., "/src").`).
+			ArgDoc("owner", `A user:group to set for the path.`,
+				`The user and group can either be an ID (1000:1000) or a name (foo:bar).`,
+				`If the group is omitted, it defaults to the same as the user.`,
+				`Names are resolved using /etc/passwd and /etc/group in this directory.`),
+		dagql.Func("withMove", s.withMove).
+			Doc(`Retrieves this directory with the file or directory at src moved to dest.`,
+				`If dest is an existing directory, src is moved into it.`).
+			ArgDoc("src", `Location of the file or directory to move (e.g., "/build/out").`).
+			ArgDoc("dest", `Location to move it to (e.g., "/dist").`),
+		dagql.Func("asArchive", s.asArchive).
+			Doc(`Packs this directory's contents into an archive file.`).
+			ArgDoc("format", `File format of the archive.`).
+			ArgDoc("compression", `Compression to apply to the archive.`,
+				`Zip archives compress each entry with DEFLATE when set to Gzip, and don't support Zstd.`,
+				`EStarGZ is not supported.`).
+			ArgDoc("reproducible", `Set every timestamp to a fixed time and omit user and group names, so that the same contents always produce the same archive.`),
+		dagql.Func("withArchive", s.withArchive).
+			Doc(`Retrieves this directory plus the contents of the given archive extracted at the given path.`,
+				`The archive's format and compression are detected automatically.`).
+			ArgDoc("path", `Location to extract the archive to (e.g., "/vendor").`).
+			ArgDoc("file", `Identifier of the archive to extract.`),
 		dagql.Func("withTimestamps", s.withTimestamps).
 			Doc(`Retrieves this directory with all file/dir timestamps set to the given time.`).
 			ArgDoc("timestamp", `Timestamp to set dir/files in.`,
@@ -162,6 +223,66 @@ func (s *directorySchema) withTimestamps(ctx context.Context, parent *core.Direc
 	return parent.WithTimestamps(ctx, args.Timestamp)
 }
 
+type asArchiveArgs struct {
+	Format       core.ArchiveFormat         `default:"TAR"`
+	Compression  core.ImageLayerCompression `default:"Uncompressed"`
+	Reproducible bool                       `default:"true"`
+}
+
+func (s *directorySchema) asArchive(ctx context.Context, parent *core.Directory, args asArchiveArgs) (*core.File, error) {
+	return parent.AsArchive(ctx, args.Format, args.Compression, args.Reproducible)
+}
+
+type withArchiveArgs struct {
+	Path string
+	File core.FileID
+}
+
+func (s *directorySchema) withArchive(ctx context.Context, parent *core.Directory, args withArchiveArgs) (*core.Directory, error) {
+	file, err := args.File.Load(ctx, s.srv)
+	if err != nil {
+		return nil, err
+	}
+	return parent.WithArchive(ctx, args.Path, file.Self)
+}
+
+type withSymlinkArgs struct {
+	Target   string
+	LinkName string
+}
+
+func (s *directorySchema) withSymlink(ctx context.Context, parent *core.Directory, args withSymlinkArgs) (*core.Directory, error) {
+	return parent.WithSymlink(ctx, args.Target, args.LinkName)
+}
+
+type withPermissionsArgs struct {
+	Path      string
+	Mode      int
+	Recursive bool `default:"false"`
+}
+
+func (s *directorySchema) withPermissions(ctx context.Context, parent *core.Directory, args withPermissionsArgs) (*core.Directory, error) {
+	return parent.WithPermissions(ctx, args.Path, fs.FileMode(args.Mode), args.Recursive)
+}
+
+type withOwnerArgs struct {
+	Path  string
+	Owner string
+}
+
+func (s *directorySchema) withOwner(ctx context.Context, parent *core.Directory, args withOwnerArgs) (*core.Directory, error) {
+	return parent.WithOwner(ctx, args.Path, args.Owner)
+}
+
+type withMoveArgs struct {
+	Src  string
+	Dest string
+}
+
+func (s *directorySchema) withMove(ctx context.Context, parent *core.Directory, args withMoveArgs) (*core.Directory, error) {
+	return parent.WithMove(ctx, args.Src, args.Dest)
+}
+
 type entriesArgs struct {
 	Path dagql.Optional[dagql.String]
 }
@@ -174,6 +295,34 @@ func (s *directorySchema) entries(ctx context.Context, parent *core.Directory, a
 	return dagql.NewStringArray(ents...), nil
 }
 
+func (s *directorySchema) entriesWithStat(ctx context.Context, parent *core.Directory, args entriesArgs) (dagql.Array[core.Stat], error) {
+	return parent.EntriesWithStat(ctx, args.Path.Value.String())
+}
+
+type statArgs struct {
+	Path string
+}
+
+func (s *directorySchema) stat(ctx context.Context, parent *core.Directory, args statArgs) (core.Stat, error) {
+	info, err := parent.Stat(ctx, parent.Query.Buildkit, parent.Query.Services, args.Path)
+	if err != nil {
+		return core.Stat{}, err
+	}
+	return core.NewStat(info), nil
+}
+
+type digestArgs struct {
+	ExcludeMetadata bool `default:"false"`
+}
+
+func (s *directorySchema) digest(ctx context.Context, parent *core.Directory, args digestArgs) (dagql.String, error) {
+	dgst, err := parent.Digest(ctx, args.ExcludeMetadata)
+	if err != nil {
+		return "", err
+	}
+	return dagql.NewString(dgst.String()), nil
+}
+
 type globArgs struct {
 	Pattern string
 }
@@ -260,12 +409,38 @@ func (s *directorySchema) diff(ctx context.Context, parent *core.Directory, args
 	return parent.Diff(ctx, dir.Self)
 }
 
+type asPatchArgs struct {
+	Other core.DirectoryID
+}
+
+func (s *directorySchema) asPatch(ctx context.Context, parent *core.Directory, args asPatchArgs) (*core.File, error) {
+	dir, err := args.Other.Load(ctx, s.srv)
+	if err != nil {
+		return nil, err
+	}
+	return parent.AsPatch(ctx, dir.Self)
+}
+
+type withPatchArgs struct {
+	Patch core.FileID
+	Strip int `default:"1"`
+}
+
+func (s *directorySchema) withPatch(ctx context.Context, parent *core.Directory, args withPatchArgs) (*core.Directory, error) {
+	patch, err := args.Patch.Load(ctx, s.srv)
+	if err != nil {
+		return nil, err
+	}
+	return parent.WithPatch(ctx, patch.Self, args.Strip)
+}
+
 type dirExportArgs struct {
 	Path string
+	Wipe bool `default:"false"`
 }
 
 func (s *directorySchema) export(ctx context.Context, parent *core.Directory, args dirExportArgs) (dagql.Boolean, error) {
-	err := parent.Export(ctx, args.Path)
+	err := parent.Export(ctx, args.Path, args.Wipe)
 	if err != nil {
 		return false, err
 	}
@@ -273,6 +448,14 @@ func (s *directorySchema) export(ctx context.Context, parent *core.Directory, ar
 	return true, nil
 }
 
+func (s *directorySchema) exportDryRun(ctx context.Context, parent *core.Directory, args dirExportArgs) (dagql.Array[dagql.String], error) {
+	changes, err := parent.ExportDryRun(ctx, args.Path, args.Wipe)
+	if err != nil {
+		return nil, err
+	}
+	return dagql.NewStringArray(changes...), nil
+}
+
 type dirDockerBuildArgs struct {
 	Platform   dagql.Optional[core.Platform]
 	Dockerfile string                             `default:"Dockerfile"`
@@ -0,0 +1,130 @@
+package schema
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/dagger/dagger/core"
+	"github.com/dagger/dagger/dagql"
+	bkclient "github.com/moby/buildkit/client"
+)
+
+type engineSchema struct {
+	srv *dagql.Server
+}
+
+var _ SchemaResolvers = &engineSchema{}
+
+func (s *engineSchema) Install() {
+	dagql.Fields[*core.Query]{
+		dagql.Func("engine", s.engine).
+			Doc(`Queries the Dagger engine the client is connected to.`),
+	}.Install(s.srv)
+
+	dagql.Fields[*core.Engine]{
+		dagql.Func("localCache", s.localCache).
+			Doc(`The local cache of the engine.`),
+	}.Install(s.srv)
+
+	dagql.Fields[*core.EngineCache]{
+		dagql.Func("entries", s.entries).
+			Impure("Lists the current state of the engine's cache.").
+			Doc(`The entries in the cache, largest first.`).
+			ArgDoc("filters", `Only list entries matching all of the filters (e.g., "type==exec.cachemount").`),
+		dagql.Func("keepBytes", s.keepBytes).
+			Doc(`The most space the engine's garbage collection policy lets the cache use, in bytes, or 0 if it's unbounded.`),
+		dagql.Func("prune", s.prune).
+			Impure("Mutates the engine's cache.").
+			Doc(`Removes entries that aren't in use from the cache, returning the entries that were removed.`,
+				`Unless all is set, the entries to remove must be narrowed down with at least one of the other arguments. This can't be called from within a module.`).
+			ArgDoc("all", `Remove every entry that isn't in use.`).
+			ArgDoc("filters", `Only remove entries matching all of the filters (e.g., "type==exec.cachemount").`).
+			ArgDoc("keepBytes", `Keep entries until the cache uses at most this many bytes.`).
+			ArgDoc("olderThan", `Only remove entries that were not used in this many seconds.`).
+			ArgDoc("useDefaultPolicy", `Apply the engine's garbage collection policy instead of the other arguments.`),
+	}.Install(s.srv)
+
+	dagql.Fields[core.EngineCacheEntry]{}.Install(s.srv)
+}
+
+func (s *engineSchema) engine(ctx context.Context, parent *core.Query, args struct{}) (*core.Engine, error) {
+	return &core.Engine{Query: parent}, nil
+}
+
+func (s *engineSchema) localCache(ctx context.Context, parent *core.Engine, args struct{}) (*core.EngineCache, error) {
+	return &core.EngineCache{Query: parent.Query}, nil
+}
+
+type engineCacheEntriesArgs struct {
+	Filters []string `default:"[]"`
+}
+
+func (s *engineSchema) entries(ctx context.Context, parent *core.EngineCache, args engineCacheEntriesArgs) (dagql.Array[core.EngineCacheEntry], error) {
+	infos, err := parent.Query.Buildkit.EngineCacheEntries(ctx, buildkitFilter(args.Filters))
+	if err != nil {
+		return nil, err
+	}
+	entries := make(dagql.Array[core.EngineCacheEntry], 0, len(infos))
+	for _, info := range infos {
+		entries = append(entries, core.NewEngineCacheEntry(info))
+	}
+	return entries, nil
+}
+
+func (s *engineSchema) keepBytes(ctx context.Context, parent *core.EngineCache, args struct{}) (dagql.Int, error) {
+	return dagql.NewInt(int(parent.Query.Buildkit.EngineCacheKeepBytes())), nil
+}
+
+type engineCachePruneArgs struct {
+	All              bool     `default:"false"`
+	Filters          []string `default:"[]"`
+	KeepBytes        int      `default:"0"`
+	OlderThan        int      `default:"0"`
+	UseDefaultPolicy bool     `default:"false"`
+}
+
+func (s *engineSchema) prune(ctx context.Context, parent *core.EngineCache, args engineCachePruneArgs) (dagql.Array[core.EngineCacheEntry], error) {
+	if _, err := parent.Query.CurrentModule(ctx); err == nil {
+		// the cache is shared by every client of the engine, so only the main
+		// client may remove entries from it
+		return nil, errors.New("the engine's cache cannot be pruned from within a module")
+	} else if !errors.Is(err, core.ErrNoCurrentModule) {
+		return nil, err
+	}
+
+	narrowed := len(args.Filters) > 0 || args.KeepBytes > 0 || args.OlderThan > 0 || args.UseDefaultPolicy
+	if !narrowed && !args.All {
+		return nil, errors.New("refusing to remove every unused cache entry: set all, or narrow it down with filters, keepBytes, olderThan or useDefaultPolicy")
+	}
+
+	var policies []bkclient.PruneInfo
+	if !args.UseDefaultPolicy {
+		policies = append(policies, bkclient.PruneInfo{
+			Filter:       buildkitFilter(args.Filters),
+			All:          true,
+			KeepBytes:    int64(args.KeepBytes),
+			KeepDuration: time.Duration(args.OlderThan) * time.Second,
+		})
+	}
+	pruned, err := parent.Query.Buildkit.PruneEngineCache(ctx, policies...)
+	if err != nil {
+		return nil, err
+	}
+	entries := make(dagql.Array[core.EngineCacheEntry], 0, len(pruned))
+	for i := range pruned {
+		entries = append(entries, core.NewEngineCacheEntry(&pruned[i]))
+	}
+	return entries, nil
+}
+
+// buildkitFilter joins filters into the single filter buildkit needs to only
+// match entries matching all of them, since it matches entries against any
+// one of separate filters.
+func buildkitFilter(filters []string) []string {
+	if len(filters) == 0 {
+		return nil
+	}
+	return []string{strings.Join(filters, ",")}
+}
@@ -27,6 +27,10 @@ func (s *fileSchema) Install() {
 			Doc(`Retrieves the contents of the file.`),
 		dagql.Func("size", s.size).
 			Doc(`Retrieves the size of the file, in bytes.`),
+		dagql.Func("stat", s.stat).
+			Doc(`Retrieves the metadata of the file.`),
+		dagql.Func("digest", s.digest).
+			Doc(`Returns the SHA-256 digest of the file's contents (e.g., "sha256:...").`),
 		dagql.Func("name", s.name).
 			Doc(`Retrieves the name of the file.`),
 		dagql.Func("export", s.export).
@@ -36,6 +40,13 @@ func (s *fileSchema) Install() {
 			ArgDoc("allowParentDirPath",
 				`If allowParentDirPath is true, the path argument can be a directory
 				path, in which case the file will be created in that directory.`),
+		dagql.Func("extract", s.extract).
+			Doc(`Extracts this file as an archive.`).
+			ArgDoc("format", `File format of the archive.`,
+				`If not set, it's detected from the file. Compressed tarballs are detected automatically.`),
+		dagql.Func("withName", s.withName).
+			Doc(`Retrieves this file with the given name.`).
+			ArgDoc("name", `Name to give the file (e.g., "main.go").`),
 		dagql.Func("withTimestamps", s.withTimestamps).
 			Doc(`Retrieves this file with its created/modified timestamps set to the given time.`).
 			ArgDoc("timestamp", `Timestamp to set dir/files in.`,
@@ -64,6 +75,15 @@ func (s *fileSchema) contents(ctx context.Context, file *core.File, args struct{
 	return dagql.NewString(string(content)), nil
 }
 
+func (s *fileSchema) stat(ctx context.Context, file *core.File, args struct{}) (core.Stat, error) {
+	info, err := file.Stat(ctx)
+	if err != nil {
+		return core.Stat{}, err
+	}
+
+	return core.NewStat(info), nil
+}
+
 func (s *fileSchema) size(ctx context.Context, file *core.File, args struct{}) (dagql.Int, error) {
 	info, err := file.Stat(ctx)
 	if err != nil {
@@ -73,6 +93,15 @@ func (s *fileSchema) size(ctx context.Context, file *core.File, args struct{}) (
 	return dagql.NewInt(int(info.Size_)), nil
 }
 
+func (s *fileSchema) digest(ctx context.Context, file *core.File, args struct{}) (dagql.String, error) {
+	dgst, err := file.Digest(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	return dagql.NewString(dgst.String()), nil
+}
+
 func (s *fileSchema) name(ctx context.Context, file *core.File, args struct{}) (dagql.String, error) {
 	return dagql.NewString(filepath.Base(file.File)), nil
 }
@@ -98,3 +127,19 @@ type fileWithTimestampsArgs struct {
 func (s *fileSchema) withTimestamps(ctx context.Context, parent *core.File, args fileWithTimestampsArgs) (*core.File, error) {
 	return parent.WithTimestamps(ctx, args.Timestamp)
 }
+
+type fileWithNameArgs struct {
+	Name string
+}
+
+func (s *fileSchema) withName(ctx context.Context, parent *core.File, args fileWithNameArgs) (*core.File, error) {
+	return parent.WithName(ctx, args.Name)
+}
+
+type fileExtractArgs struct {
+	Format dagql.Optional[core.ArchiveFormat]
+}
+
+func (s *fileSchema) extract(ctx context.Context, parent *core.File, args fileExtractArgs) (*core.Directory, error) {
+	return parent.Extract(ctx, args.Format.Value)
+}
@@ -5,9 +5,11 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"net/url"
 	"os/exec"
 	"regexp"
 	"strings"
+	"sync"
 
 	"github.com/dagger/dagger/core"
 	"github.com/dagger/dagger/dagql"
@@ -230,6 +232,45 @@ func gitTags(ctx context.Context, repoURL string) ([]string, error) {
 	return tags, nil
 }
 
+// gitTagsCache memoizes the tags of git repos by their URL. Failed lookups
+// are not cached.
+type gitTagsCache struct {
+	mu    sync.Mutex
+	repos map[string]*cachedGitTags
+}
+
+type cachedGitTags struct {
+	once sync.Once
+	tags []string
+	err  error
+}
+
+func (cache *gitTagsCache) get(ctx context.Context, repoURL string) ([]string, error) {
+	cache.mu.Lock()
+	if cache.repos == nil {
+		cache.repos = map[string]*cachedGitTags{}
+	}
+	repo, ok := cache.repos[repoURL]
+	if !ok {
+		repo = &cachedGitTags{}
+		cache.repos[repoURL] = repo
+	}
+	cache.mu.Unlock()
+
+	repo.once.Do(func() {
+		repo.tags, repo.err = gitTags(ctx, repoURL)
+	})
+	if repo.err != nil {
+		cache.mu.Lock()
+		if cache.repos[repoURL] == repo {
+			delete(cache.repos, repoURL)
+		}
+		cache.mu.Unlock()
+		return nil, repo.err
+	}
+	return repo.tags, nil
+}
+
 func isSemver(ver string) bool {
 	re := regexp.MustCompile(`^v[0-9]+\.[0-9]+\.[0-9]+$`)
 	return re.MatchString(ver)
@@ -257,3 +298,145 @@ func matchVersion(versions []string, match, subPath string) (string, error) {
 	}
 	return "", fmt.Errorf("unable to find version %s", match)
 }
+
+// gitRepoRef is a git module ref split into the repo it lives in and the
+// subpath of the module within that repo.
+type gitRepoRef struct {
+	// refRoot is the repo root as it should appear in ref strings, e.g.
+	// github.com/org/repo or git@example.com:org/repo.git
+	refRoot string
+	// cloneURL is the URL to clone the repo from
+	cloneURL string
+	// htmlURL is the base URL of the repo's web UI
+	htmlURL string
+	// host is the hostname of the repo's server, without any port
+	host string
+	// subPath is the path of the module within the repo, or / for the root
+	subPath string
+}
+
+// forges whose repos always live exactly two path segments deep
+var fixedDepthGitHosts = map[string]bool{
+	"github.com":    true,
+	"bitbucket.org": true,
+	"codeberg.org":  true,
+}
+
+var scpLikeGitURL = regexp.MustCompile(`^([a-zA-Z0-9._-]+)@([a-zA-Z0-9._-]+):(.*)$`)
+
+// gitHostLike matches a first path segment that looks like a git server's
+// hostname, e.g. gitlab.com or git.example.com:8443.
+var gitHostLike = regexp.MustCompile(`^([a-zA-Z0-9]([a-zA-Z0-9-]*[a-zA-Z0-9])?\.)+[a-zA-Z]{2,}(:[0-9]+)?$`)
+
+// isHostLikePath reports whether the path starts with a hostname followed by
+// at least one more path segment, like gitlab.com/org/repo.
+func isHostLikePath(path string) bool {
+	host, rest, ok := strings.Cut(path, "/")
+	return ok && rest != "" && gitHostLike.MatchString(host)
+}
+
+// parseGitRepoRef splits a git module path (without version) into its repo
+// and subpath. It accepts https://, http://, ssh://, git:// URLs, scp-like
+// user@host:path refs, and scheme-less host/path refs (which are cloned over
+// https).
+//
+// The repo boundary is the first path segment ending in ".git"; otherwise
+// the repo is assumed to be at owner/repo, as on most forges. Repos nested
+// deeper, like GitLab subgroups, must have their boundary marked with ".git".
+func parseGitRepoRef(modPath string) (*gitRepoRef, error) {
+	var (
+		scheme string
+		user   string
+		host   string
+		port   string
+		p      string
+	)
+	switch {
+	case strings.Contains(modPath, "://"):
+		u, err := url.Parse(modPath)
+		if err != nil {
+			return nil, fmt.Errorf("invalid git url %q: %w", modPath, err)
+		}
+		switch u.Scheme {
+		case "https", "http", "ssh", "git":
+		default:
+			return nil, fmt.Errorf("unsupported git url scheme %q: %s", u.Scheme, modPath)
+		}
+		scheme = u.Scheme
+		if u.User != nil {
+			user = u.User.Username()
+		}
+		host, port = u.Hostname(), u.Port()
+		p = u.Path
+	case scpLikeGitURL.MatchString(modPath):
+		m := scpLikeGitURL.FindStringSubmatch(modPath)
+		user, host, p = m[1], m[2], m[3]
+	default:
+		host, p, _ = strings.Cut(modPath, "/")
+	}
+	if host == "" {
+		return nil, fmt.Errorf("git ref has no host: %s", modPath)
+	}
+
+	segments := strings.FieldsFunc(p, func(r rune) bool { return r == '/' })
+	if len(segments) == 0 {
+		return nil, fmt.Errorf("git ref has no repo path: %s", modPath)
+	}
+
+	ref := &gitRepoRef{host: host}
+	hostPort := host
+	if port != "" {
+		hostPort += ":" + port
+	}
+	withRepoPath := func(repoPath string) {
+		switch {
+		case scheme != "":
+			userPrefix := ""
+			if user != "" {
+				userPrefix = user + "@"
+			}
+			ref.cloneURL = scheme + "://" + userPrefix + hostPort + "/" + repoPath
+			ref.refRoot = ref.cloneURL
+		case user != "":
+			ref.cloneURL = user + "@" + host + ":" + repoPath
+			ref.refRoot = ref.cloneURL
+		default:
+			ref.cloneURL = "https://" + host + "/" + repoPath
+			ref.refRoot = host + "/" + repoPath
+		}
+		// the web UI is assumed to be served over https on the default port,
+		// unless the repo is already being cloned over plain http
+		htmlBase := "https://" + host
+		if scheme == "http" {
+			htmlBase = "http://" + hostPort
+		}
+		ref.htmlURL = htmlBase + "/" + strings.TrimSuffix(repoPath, ".git")
+	}
+	split := func(n int) {
+		withRepoPath(strings.Join(segments[:n], "/"))
+		ref.subPath = "/"
+		if n < len(segments) {
+			ref.subPath = strings.Join(segments[n:], "/")
+		}
+	}
+
+	for i, segment := range segments {
+		if strings.HasSuffix(segment, ".git") {
+			split(i + 1)
+			return ref, nil
+		}
+	}
+
+	if fixedDepthGitHosts[host] {
+		if len(segments) < 2 {
+			return nil, fmt.Errorf("invalid %s path: %s", host, modPath)
+		}
+		split(2)
+		return ref, nil
+	}
+
+	// repos served from the root of a host, e.g. git://host/repo, have a
+	// single segment
+	split(min(2, len(segments)))
+	return ref, nil
+}
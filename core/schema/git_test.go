@@ -3,6 +3,7 @@ package schema
 import (
 	"testing"
 
+	"github.com/dagger/dagger/core"
 	"github.com/stretchr/testify/require"
 )
 
@@ -25,6 +26,88 @@ func TestMatchVersion(t *testing.T) {
 	require.Error(t, err)
 }
 
+func TestIsVersionConstraint(t *testing.T) {
+	require.True(t, isVersionConstraint("^1.2"))
+	require.True(t, isVersionConstraint("~0.3.1"))
+	require.True(t, isVersionConstraint(">=1.0 <2.0"))
+	require.True(t, isVersionConstraint(">= 1.0, < 2.0"))
+	require.True(t, isVersionConstraint("^1 || ^2"))
+	require.False(t, isVersionConstraint("v1.0.0"))
+	require.False(t, isVersionConstraint("main"))
+	require.False(t, isVersionConstraint("^main"))
+	require.False(t, isVersionConstraint(""))
+}
+
+func TestVersionConstraintCheck(t *testing.T) {
+	for _, tc := range []struct {
+		constraint string
+		matches    []string
+		mismatches []string
+	}{
+		{"^1.2", []string{"v1.2.0", "v1.9.3"}, []string{"v1.1.9", "v2.0.0", "v2.0.0-rc.1"}},
+		{"^0.3.1", []string{"v0.3.1", "v0.3.9"}, []string{"v0.3.0", "v0.4.0"}},
+		{"^0.0.3", []string{"v0.0.3"}, []string{"v0.0.4"}},
+		{"~0.3.1", []string{"v0.3.1", "v0.3.5"}, []string{"v0.4.0"}},
+		{"~1", []string{"v1.0.0", "v1.5.0"}, []string{"v2.0.0"}},
+		{">=1.0 <2.0", []string{"v1.0.0", "v1.99.0"}, []string{"v0.9.0", "v2.0.0"}},
+		{">1.2", []string{"v1.3.0"}, []string{"v1.2.9"}},
+		{"<=1.2", []string{"v1.2.9"}, []string{"v1.3.0"}},
+		{"=v1.2.3", []string{"v1.2.3"}, []string{"v1.2.4"}},
+		{"^1.0.0-rc.1", []string{"v1.0.0-rc.2", "v1.0.0", "v1.1.0"}, []string{"v1.1.0-rc.1"}},
+		{"^1 || ^3", []string{"v1.1.0", "v3.0.0"}, []string{"v2.0.0"}},
+	} {
+		c, err := parseVersionConstraint(tc.constraint)
+		require.NoError(t, err, tc.constraint)
+		for _, v := range tc.matches {
+			require.True(t, c.Check(v), "%s should match %s", tc.constraint, v)
+		}
+		for _, v := range tc.mismatches {
+			require.False(t, c.Check(v), "%s should not match %s", tc.constraint, v)
+		}
+	}
+
+	_, err := parseVersionConstraint("^1.x")
+	require.Error(t, err)
+	_, err = parseVersionConstraint("^1.2-rc.1")
+	require.Error(t, err)
+}
+
+func TestMatchVersionConstraint(t *testing.T) {
+	tags := []string{"v1.0.0", "v1.2.0", "v1.10.1", "v2.0.0", "mymod/v0.3.1", "mymod/v0.3.4", "mymod/v0.4.0", "other/v9.0.0"}
+
+	parse := func(s string) *versionConstraint {
+		c, err := parseVersionConstraint(s)
+		require.NoError(t, err)
+		return c
+	}
+
+	matched, err := matchVersionConstraint(tags, "/", parse("^1.2"))
+	require.NoError(t, err)
+	require.Equal(t, "v1.10.1", matched)
+
+	matched, err = matchVersionConstraint(tags, "mymod", parse("~0.3.1"))
+	require.NoError(t, err)
+	require.Equal(t, "mymod/v0.3.4", matched)
+
+	// subpaths without their own tags use the repo's tags
+	matched, err = matchVersionConstraint(tags, "nested/mod", parse(">=1.0 <2.0"))
+	require.NoError(t, err)
+	require.Equal(t, "v1.10.1", matched)
+
+	_, err = matchVersionConstraint(tags, "mymod", parse("^1"))
+	require.ErrorContains(t, err, `no version matches "^1" (available: mymod/v0.4.0, mymod/v0.3.4, mymod/v0.3.1)`)
+
+	_, err = matchVersionConstraint(tags, "/", parse("^1.2"), parse("^2"))
+	require.ErrorContains(t, err, `no version matches "^1.2" and "^2"`)
+
+	matched, err = matchVersionConstraint(tags, "/", parse("^1"), parse("<1.5"))
+	require.NoError(t, err)
+	require.Equal(t, "v1.2.0", matched)
+
+	_, err = matchVersionConstraint([]string{"main"}, "/", parse("^1"))
+	require.ErrorContains(t, err, "no semver tags found")
+}
+
 func TestIsSemver(t *testing.T) {
 	require.True(t, isSemver("v1.0.0"))
 	require.True(t, isSemver("v2.0.1"))
@@ -33,3 +116,125 @@ func TestIsSemver(t *testing.T) {
 	require.False(t, isSemver("v1"))
 	require.False(t, isSemver("foo"))
 }
+
+func TestParseRefString(t *testing.T) {
+	for _, tc := range []struct {
+		ref        string
+		modPath    string
+		modVersion string
+		kind       core.ModuleSourceKind
+	}{
+		{"./foo/bar", "./foo/bar", "", core.ModuleSourceKindLocal},
+		{"gitlab.com/foo/bar", "gitlab.com/foo/bar", "", core.ModuleSourceKindGit},
+		{"gitlab.example.com/org/mod", "gitlab.example.com/org/mod", "", core.ModuleSourceKindGit},
+		{"git.example.com:8443/org/mod", "git.example.com:8443/org/mod", "", core.ModuleSourceKindGit},
+		{"./gitlab.com/foo/bar", "./gitlab.com/foo/bar", "", core.ModuleSourceKindLocal},
+		{"foo/bar", "foo/bar", "", core.ModuleSourceKindLocal},
+		{"foo.d/bar", "foo.d/bar", "", core.ModuleSourceKindLocal},
+		{"mod.go", "mod.go", "", core.ModuleSourceKindLocal},
+		{".", ".", "", core.ModuleSourceKindLocal},
+		{"github.com/foo/bar", "github.com/foo/bar", "", core.ModuleSourceKindGit},
+		{"github.com/foo/bar/baz@v1.0.0", "github.com/foo/bar/baz", "v1.0.0", core.ModuleSourceKindGit},
+		{"gitlab.com/foo/bar@main", "gitlab.com/foo/bar", "main", core.ModuleSourceKindGit},
+		{"https://gitlab.example.com/group/sub/repo", "https://gitlab.example.com/group/sub/repo", "", core.ModuleSourceKindGit},
+		{"ssh://git@example.com:2222/org/repo.git/mod@v1.2.3", "ssh://git@example.com:2222/org/repo.git/mod", "v1.2.3", core.ModuleSourceKindGit},
+		{"git@example.com:org/repo.git", "git@example.com:org/repo.git", "", core.ModuleSourceKindGit},
+		{"git@example.com:org/repo.git/mod@main", "git@example.com:org/repo.git/mod", "main", core.ModuleSourceKindGit},
+		{"github.com/foo/bar/mymod@^1.2", "github.com/foo/bar/mymod", "^1.2", core.ModuleSourceKindGit},
+		{"gitlab.com/foo/bar@>=1.0 <2.0", "gitlab.com/foo/bar", ">=1.0 <2.0", core.ModuleSourceKindGit},
+	} {
+		parsed := parseRefString(tc.ref)
+		require.Equal(t, tc.modPath, parsed.modPath, tc.ref)
+		require.Equal(t, tc.modVersion, parsed.modVersion, tc.ref)
+		require.Equal(t, tc.modVersion != "", parsed.hasVersion, tc.ref)
+		require.Equal(t, tc.kind, parsed.kind, tc.ref)
+	}
+
+	// version-less refs starting with a hostname may turn out to be local
+	require.True(t, parseRefString("my.app/mod").hostLike)
+	require.True(t, parseRefString("gitlab.com/foo/bar").hostLike)
+	require.False(t, parseRefString("gitlab.com/foo/bar@main").hostLike)
+	require.False(t, parseRefString("https://gitlab.com/foo/bar").hostLike)
+	require.False(t, parseRefString("git@example.com:org/repo.git").hostLike)
+	require.False(t, parseRefString("./my.app/mod").hostLike)
+}
+
+func TestParseGitRepoRef(t *testing.T) {
+	for _, tc := range []struct {
+		modPath  string
+		refRoot  string
+		cloneURL string
+		htmlURL  string
+		subPath  string
+	}{
+		{
+			modPath:  "github.com/foo/bar",
+			refRoot:  "github.com/foo/bar",
+			cloneURL: "https://github.com/foo/bar",
+			htmlURL:  "https://github.com/foo/bar",
+			subPath:  "/",
+		},
+		{
+			modPath:  "github.com/foo/bar/sub/dir",
+			refRoot:  "github.com/foo/bar",
+			cloneURL: "https://github.com/foo/bar",
+			htmlURL:  "https://github.com/foo/bar",
+			subPath:  "sub/dir",
+		},
+		{
+			modPath:  "gitlab.example.com/org/repo/mod",
+			refRoot:  "gitlab.example.com/org/repo",
+			cloneURL: "https://gitlab.example.com/org/repo",
+			htmlURL:  "https://gitlab.example.com/org/repo",
+			subPath:  "mod",
+		},
+		{
+			modPath:  "git://localhost/repo",
+			refRoot:  "git://localhost/repo",
+			cloneURL: "git://localhost/repo",
+			htmlURL:  "https://localhost/repo",
+			subPath:  "/",
+		},
+		{
+			modPath:  "gitlab.example.com/group/sub/repo.git/mod",
+			refRoot:  "gitlab.example.com/group/sub/repo.git",
+			cloneURL: "https://gitlab.example.com/group/sub/repo.git",
+			htmlURL:  "https://gitlab.example.com/group/sub/repo",
+			subPath:  "mod",
+		},
+		{
+			modPath:  "ssh://git@gitea.example.com:2222/org/repo.git",
+			refRoot:  "ssh://git@gitea.example.com:2222/org/repo.git",
+			cloneURL: "ssh://git@gitea.example.com:2222/org/repo.git",
+			htmlURL:  "https://gitea.example.com/org/repo",
+			subPath:  "/",
+		},
+		{
+			modPath:  "git@example.com:org/repo.git/a/b",
+			refRoot:  "git@example.com:org/repo.git",
+			cloneURL: "git@example.com:org/repo.git",
+			htmlURL:  "https://example.com/org/repo",
+			subPath:  "a/b",
+		},
+		{
+			modPath:  "http://localhost:8080/repo.git/mod",
+			refRoot:  "http://localhost:8080/repo.git",
+			cloneURL: "http://localhost:8080/repo.git",
+			htmlURL:  "http://localhost:8080/repo",
+			subPath:  "mod",
+		},
+	} {
+		ref, err := parseGitRepoRef(tc.modPath)
+		require.NoError(t, err, tc.modPath)
+		require.Equal(t, tc.refRoot, ref.refRoot, tc.modPath)
+		require.Equal(t, tc.cloneURL, ref.cloneURL, tc.modPath)
+		require.Equal(t, tc.htmlURL, ref.htmlURL, tc.modPath)
+		require.Equal(t, tc.subPath, ref.subPath, tc.modPath)
+	}
+
+	_, err := parseGitRepoRef("ftp://example.com/repo.git")
+	require.ErrorContains(t, err, "unsupported git url scheme")
+
+	_, err = parseGitRepoRef("github.com/foo")
+	require.ErrorContains(t, err, "invalid github.com path")
+}
@@ -2,6 +2,8 @@ package schema
 
 import (
 	"context"
+	"fmt"
+	"io/fs"
 
 	"github.com/dagger/dagger/core"
 	"github.com/dagger/dagger/dagql"
@@ -22,13 +24,24 @@ func (s *httpSchema) Install() {
 		dagql.Func("http", s.http).
 			Doc(`Returns a file containing an http remote url content.`).
 			ArgDoc("url", `HTTP url to get the content from (e.g., "https://docs.dagger.io").`).
-			ArgDoc("experimentalServiceHost", `A service which must be started before the URL is fetched.`),
+			ArgDoc("experimentalServiceHost", `A service which must be started before the URL is fetched.`).
+			ArgDoc("checksum", `Expected digest of the content (e.g., "sha256:...").`,
+				`The download fails if the content does not match.`).
+			ArgDoc("headers", `Additional headers to send with the request.`).
+			ArgDoc("authHeader", `Secret used as the value of the Authorization header (e.g., "Bearer ...").`).
+			ArgDoc("name", `Name of the downloaded file (defaults to a digest of the URL).`).
+			ArgDoc("permissions", `Permission given to the downloaded file (e.g., 0600).`),
 	}.Install(s.srv)
 }
 
 type httpArgs struct {
 	URL                     string
 	ExperimentalServiceHost dagql.Optional[core.ServiceID]
+	Checksum                string                               `default:""`
+	Headers                 []dagql.InputObject[core.HTTPHeader] `default:"[]"`
+	AuthHeader              dagql.Optional[core.SecretID]
+	Name                    string `default:""`
+	Permissions             *int
 }
 
 func (s *httpSchema) http(ctx context.Context, parent *core.Query, args httpArgs) (*core.File, error) {
@@ -37,6 +50,9 @@ func (s *httpSchema) http(ctx context.Context, parent *core.Query, args httpArgs
 	// of following more optimized cache codepaths.
 	// Do a hash encode to prevent conflicts with use of `/` in the URL while also not hitting max filename limits
 	filename := digest.FromString(args.URL).Encoded()
+	if args.Name != "" {
+		filename = args.Name
+	}
 
 	svcs := core.ServiceBindings{}
 	if args.ExperimentalServiceHost.Valid {
@@ -58,6 +74,34 @@ func (s *httpSchema) http(ctx context.Context, parent *core.Query, args httpArgs
 	opts := []llb.HTTPOption{
 		llb.Filename(filename),
 	}
+	if args.Checksum != "" {
+		dgst, err := digest.Parse(args.Checksum)
+		if err != nil {
+			return nil, fmt.Errorf("invalid checksum %q: %w", args.Checksum, err)
+		}
+		if dgst.Algorithm() != digest.SHA256 {
+			return nil, fmt.Errorf("invalid checksum %q: only sha256 is supported", args.Checksum)
+		}
+		opts = append(opts, llb.Checksum(dgst))
+	}
+	if args.Permissions != nil {
+		opts = append(opts, llb.Chmod(fs.FileMode(*args.Permissions)))
+	}
+
+	info := httpdns.HTTPInfo{}
+	for _, header := range collectInputsSlice(args.Headers) {
+		info.Headers = append(info.Headers, httpdns.Header{
+			Name:  header.Name,
+			Value: header.Value,
+		})
+	}
+	if args.AuthHeader.Valid {
+		secret, err := args.AuthHeader.Value.Load(ctx, s.srv)
+		if err != nil {
+			return nil, err
+		}
+		info.AuthHeaderSecret = secret.Self.Accessor
+	}
 
 	useDNS := len(svcs) > 0
 
@@ -65,8 +109,6 @@ func (s *httpSchema) http(ctx context.Context, parent *core.Query, args httpArgs
 	if err == nil && !useDNS {
 		useDNS = len(clientMetadata.ParentClientIDs) > 0
 	}
-
-	var st llb.State
 	if useDNS {
 		// NB: only configure search domains if we're directly using a service, or
 		// if we're nested.
@@ -75,7 +117,12 @@ func (s *httpSchema) http(ctx context.Context, parent *core.Query, args httpArgs
 		// that use a Buildkit frontend (# syntax = ...).
 		//
 		// TODO: add API cap
-		st = httpdns.HTTP(args.URL, clientMetadata.ClientIDs(), opts...)
+		info.ClientIDs = clientMetadata.ClientIDs()
+	}
+
+	var st llb.State
+	if len(info.ClientIDs) > 0 || len(info.Headers) > 0 || info.AuthHeaderSecret != "" {
+		st = httpdns.HTTP(args.URL, info, opts...)
 	} else {
 		st = llb.HTTP(args.URL, opts...)
 	}
@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"path/filepath"
+	"time"
 
 	"github.com/dagger/dagger/core"
 	"github.com/dagger/dagger/core/modules"
@@ -16,6 +17,10 @@ import (
 
 type moduleSchema struct {
 	dag *dagql.Server
+
+	// tags of git module repos, so that resolving version constraints across
+	// a dependency tree lists each repo's tags once
+	tags gitTagsCache
 }
 
 var _ SchemaResolvers = &moduleSchema{}
@@ -34,6 +39,7 @@ func (s *moduleSchema) Install() {
 		dagql.Func("moduleSource", s.moduleSource).
 			Doc(`Create a new module source instance from a source ref string.`).
 			ArgDoc("refString", `The string ref representation of the module source`).
+			ArgDoc("refPin", `The pinned version of the module source, e.g. the resolved commit of a git source. If set, the ref's version is not resolved again.`).
 			ArgDoc("stable", `If true, enforce that the source is a stable version for source kinds that support versioning.`),
 
 		dagql.Func("moduleDependency", s.moduleDependency).
@@ -119,6 +125,18 @@ func (s *moduleSchema) Install() {
 			Doc(`Append the provided dependencies to the module source's dependency list.`).
 			ArgDoc("dependencies", `The dependencies to append.`),
 
+		dagql.Func("withoutDependencies", s.moduleSourceWithoutDependencies).
+			Doc(`Remove the named dependencies from the module source's dependency list.`).
+			ArgDoc("dependencies", `The names of the dependencies to remove.`),
+
+		dagql.Func("withUpdateDependencies", s.moduleSourceWithUpdateDependencies).
+			Doc(`Re-resolve the named configured dependencies from their source ref's version, ignoring their pins.`).
+			ArgDoc("dependencies", `The names of the dependencies to update.`),
+
+		dagql.Func("withResolvedDependencyVersions", s.moduleSourceWithResolvedDependencyVersions).
+			Doc(`Load the module source as a dependency of another module, resolving git modules required with version constraints anywhere in its dependency tree to the given versions.`).
+			ArgDoc("versions", `The resolved versions, each formatted as a git module's symbolic ref and tag separated by @, e.g. https://github.com/org/repo/mod@mod/v1.2.3.`),
+
 		dagql.Func("withSDK", s.moduleSourceWithSDK).
 			Doc(`Update the module source with a new SDK.`).
 			ArgDoc("sdk", `The SDK to set.`),
@@ -215,6 +233,11 @@ func (s *moduleSchema) Install() {
 			ArgDoc("typeDef", `The type of the argument`).
 			ArgDoc("description", `A doc string for the argument, if any`).
 			ArgDoc("defaultValue", `A default value to use for this argument if not explicitly set by the caller, if any`),
+
+		dagql.Func("withCachePolicy", s.functionWithCachePolicy).
+			Doc(`Returns the function with the given cache policy.`).
+			ArgDoc("policy", `How the results of calls to the function are cached.`).
+			ArgDoc("timeToLive", `How long the results are reused for, as a duration (e.g., "10m"). Required for TTL_CACHE.`),
 	}.Install(s.dag)
 
 	dagql.Fields[*core.FunctionArg]{}.Install(s.dag)
@@ -403,6 +426,21 @@ func (s *moduleSchema) functionWithArg(ctx context.Context, fn *core.Function, a
 	return fn.WithArg(args.Name, argType.Self, args.Description, args.DefaultValue), nil
 }
 
+func (s *moduleSchema) functionWithCachePolicy(ctx context.Context, fn *core.Function, args struct {
+	Policy     core.FunctionCachePolicy
+	TimeToLive string `default:""`
+}) (*core.Function, error) {
+	var ttl time.Duration
+	if args.TimeToLive != "" {
+		var err error
+		ttl, err = time.ParseDuration(args.TimeToLive)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse time to live: %w", err)
+		}
+	}
+	return fn.WithCachePolicy(args.Policy, ttl)
+}
+
 func (s *moduleSchema) moduleDependency(
 	ctx context.Context,
 	query *core.Query,
@@ -920,7 +958,7 @@ func (s *moduleSchema) updateDaggerConfig(
 
 	modCfg.Dependencies = make([]*modules.ModuleConfigDependency, len(mod.DependencyConfig))
 	for i, dep := range mod.DependencyConfig {
-		var srcStr string
+		var srcStr, pin, pinVersion string
 		switch dep.Source.Self.Kind {
 		case core.ModuleSourceKindLocal:
 			// make it relative to this module's source root
@@ -935,7 +973,9 @@ func (s *moduleSchema) updateDaggerConfig(
 			srcStr = depRelPath
 
 		case core.ModuleSourceKindGit:
-			srcStr = dep.Source.Self.AsGitSource.Value.RefString()
+			srcStr = dep.Source.Self.AsGitSource.Value.VersionRefString()
+			pin = dep.Source.Self.AsGitSource.Value.Commit
+			pinVersion = dep.Source.Self.AsGitSource.Value.Version
 
 		default:
 			return fmt.Errorf("unsupported dependency source kind: %s", dep.Source.Self.Kind)
@@ -948,8 +988,10 @@ func (s *moduleSchema) updateDaggerConfig(
 		}
 
 		modCfg.Dependencies[i] = &modules.ModuleConfigDependency{
-			Name:   depName,
-			Source: srcStr,
+			Name:       depName,
+			Source:     srcStr,
+			Pin:        pin,
+			PinVersion: pinVersion,
 		}
 	}
 
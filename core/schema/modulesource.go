@@ -13,6 +13,7 @@ import (
 	"github.com/dagger/dagger/core/modules"
 	"github.com/dagger/dagger/dagql"
 	"github.com/dagger/dagger/engine/buildkit"
+	"github.com/opencontainers/go-digest"
 	"github.com/vito/progrock"
 	"golang.org/x/sync/errgroup"
 )
@@ -20,15 +21,19 @@ import (
 type moduleSourceArgs struct {
 	// avoiding name "ref" due to that being a reserved word in some SDKs (e.g. Rust)
 	RefString string
+	RefPin    string `default:""`
 
 	Stable bool `default:"false"`
 }
 
 func (s *moduleSchema) moduleSource(ctx context.Context, query *core.Query, args moduleSourceArgs) (*core.ModuleSource, error) {
 	parsed := parseRefString(args.RefString)
-	modPath, modVersion, hasVersion, isGitHub := parsed.modPath, parsed.modVersion, parsed.hasVersion, parsed.isGitHub
+	modPath, modVersion, hasVersion := parsed.modPath, parsed.modVersion, parsed.hasVersion
 
-	if !hasVersion && isGitHub && args.Stable {
+	if args.RefPin != "" && parsed.kind != core.ModuleSourceKindGit {
+		return nil, fmt.Errorf("pin is only supported for git module sources: %s", args.RefString)
+	}
+	if !hasVersion && args.RefPin == "" && parsed.kind == core.ModuleSourceKindGit && args.Stable {
 		return nil, fmt.Errorf("no version provided for stable remote ref: %s", args.RefString)
 	}
 
@@ -47,23 +52,21 @@ func (s *moduleSchema) moduleSource(ctx context.Context, query *core.Query, args
 		})
 
 	case core.ModuleSourceKindGit:
-		if !isGitHub {
-			return nil, fmt.Errorf("for now, only github.com/ paths are supported: %q", args.RefString)
-		}
-
-		src.AsGitSource = dagql.NonNull(&core.GitModuleSource{})
-
-		segments := strings.SplitN(modPath, "/", 4)
-		if len(segments) < 3 {
-			return nil, fmt.Errorf("invalid github.com path: %s", modPath)
+		repoRef, err := parseGitRepoRef(modPath)
+		if err != nil {
+			return nil, err
 		}
 
-		src.AsGitSource.Value.URLParent = segments[0] + "/" + segments[1] + "/" + segments[2]
+		src.AsGitSource = dagql.NonNull(&core.GitModuleSource{
+			RepoRootRef: repoRef.refRoot,
+			CloneRef:    repoRef.cloneURL,
+			HTMLRepoURL: repoRef.htmlURL,
+		})
 
 		cloneURL := src.AsGitSource.Value.CloneURL()
 
 		if !hasVersion {
-			if args.Stable {
+			if args.Stable && args.RefPin == "" {
 				return nil, fmt.Errorf("no version provided for stable remote ref: %s", args.RefString)
 			}
 			var err error
@@ -74,16 +77,15 @@ func (s *moduleSchema) moduleSource(ctx context.Context, query *core.Query, args
 		}
 		src.AsGitSource.Value.Version = modVersion
 
-		var subPath string
-		if len(segments) == 4 {
-			subPath = segments[3]
-		} else {
-			subPath = "/"
-		}
+		subPath := repoRef.subPath
 
 		commitRef := modVersion
-		if hasVersion && isSemver(modVersion) {
-			allTags, err := gitTags(ctx, cloneURL)
+		if args.RefPin != "" {
+			// the pin was resolved from the version previously, don't resolve it again
+			commitRef = args.RefPin
+			src.AsGitSource.Value.Pinned = true
+		} else if hasVersion && isSemver(modVersion) {
+			allTags, err := s.tags.get(ctx, cloneURL)
 			if err != nil {
 				return nil, fmt.Errorf("get git tags: %w", err)
 			}
@@ -93,10 +95,24 @@ func (s *moduleSchema) moduleSource(ctx context.Context, query *core.Query, args
 			}
 			// reassign modVersion to matched tag which could be subPath/tag
 			commitRef = matched
+		} else if hasVersion && isVersionConstraint(modVersion) {
+			constraint, err := parseVersionConstraint(modVersion)
+			if err != nil {
+				return nil, err
+			}
+			allTags, err := s.tags.get(ctx, cloneURL)
+			if err != nil {
+				return nil, fmt.Errorf("get git tags: %w", err)
+			}
+			matched, err := matchVersionConstraint(allTags, subPath, constraint)
+			if err != nil {
+				return nil, fmt.Errorf("resolve version of %s: %w", modPath, err)
+			}
+			commitRef = matched
 		}
 
 		var gitRef dagql.Instance[*core.GitRef]
-		err := s.dag.Select(ctx, s.dag.Root(), &gitRef,
+		err = s.dag.Select(ctx, s.dag.Root(), &gitRef,
 			dagql.Selector{
 				Field: "git",
 				Args: []dagql.NamedInput{
@@ -147,23 +163,93 @@ type parsedRefString struct {
 	modPath    string
 	modVersion string
 	hasVersion bool
-	isGitHub   bool
 	kind       core.ModuleSourceKind
+	// hostLike is set for refs without a version, scheme or user@ that start
+	// with a hostname, which are git refs unless a local path by that name
+	// exists
+	hostLike bool
 }
 
+// parseRefString splits a module ref into its path and optional @version.
+//
+// A ref is a git ref if it has a version, is a URL (https://, ssh://, etc.),
+// or is an scp-like user@host:path ref. A ref that starts with a hostname
+// such as github.com/ or gitlab.example.com/ is a git ref too, unless it's
+// found to be a local path where it's loaded from (see hostLike). Anything
+// else is a local path.
 func parseRefString(refString string) parsedRefString {
 	var parsed parsedRefString
+
+	// keep any user@ in URLs and scp-like refs out of the version split
+	var prefix string
+	if scheme, rest, ok := strings.Cut(refString, "://"); ok {
+		prefix = scheme + "://"
+		refString = rest
+		if userInfo, rest, ok := strings.Cut(refString, "@"); ok && !strings.Contains(userInfo, "/") {
+			prefix += userInfo + "@"
+			refString = rest
+		}
+	} else if m := scpLikeGitURL.FindStringSubmatchIndex(refString); m != nil {
+		prefix = refString[:m[3]+1]
+		refString = refString[m[3]+1:]
+	}
+
 	parsed.modPath, parsed.modVersion, parsed.hasVersion = strings.Cut(refString, "@")
-	parsed.isGitHub = strings.HasPrefix(parsed.modPath, "github.com/")
+	parsed.modPath = prefix + parsed.modPath
 
-	if !parsed.hasVersion && !parsed.isGitHub {
-		parsed.kind = core.ModuleSourceKindLocal
-		return parsed
+	if !parsed.hasVersion && prefix == "" {
+		if !isHostLikePath(parsed.modPath) {
+			parsed.kind = core.ModuleSourceKindLocal
+			return parsed
+		}
+		parsed.hostLike = true
 	}
 	parsed.kind = core.ModuleSourceKindGit
 	return parsed
 }
 
+// contextRefString returns the ref to load a dependency or SDK of src by. A
+// host-like ref (see parsedRefString) that exists in src's context directory
+// is returned as an explicitly local ./ path.
+func contextRefString(ctx context.Context, src *core.ModuleSource, refString string) (string, error) {
+	parsed := parseRefString(refString)
+	if !parsed.hostLike {
+		return refString, nil
+	}
+	contextDir, err := src.ContextDirectory()
+	if err != nil {
+		return "", fmt.Errorf("failed to get context directory: %w", err)
+	}
+	if contextDir.Self == nil {
+		return refString, nil
+	}
+	srcRootSubpath, err := src.SourceRootSubpath()
+	if err != nil {
+		return "", fmt.Errorf("failed to get source root subpath: %w", err)
+	}
+	depSubpath := filepath.Join(srcRootSubpath, parsed.modPath)
+	if !filepath.IsLocal(depSubpath) {
+		return refString, nil
+	}
+	if _, err := contextDir.Self.Stat(ctx, src.Query.Buildkit, src.Query.Services, depSubpath); err != nil {
+		return refString, nil
+	}
+	return "./" + refString, nil
+}
+
+// callerRefString returns the ref to load a module from the caller's host by.
+// A host-like ref (see parsedRefString) that exists at the given path on the
+// caller's host is returned as an explicitly local ./ path.
+func callerRefString(ctx context.Context, bk *buildkit.Client, refString, path string) string {
+	if !parseRefString(refString).hostLike {
+		return refString
+	}
+	if _, err := bk.StatCallerHostPath(ctx, path, false); err != nil {
+		return refString
+	}
+	return "./" + refString
+}
+
 func (s *moduleSchema) moduleSourceAsModule(
 	ctx context.Context,
 	src dagql.Instance[*core.ModuleSource],
@@ -279,6 +365,17 @@ func (s *moduleSchema) moduleSourceDependencies(
 		return nil, fmt.Errorf("failed to get module config: %w", err)
 	}
 
+	updateDeps := make(map[string]struct{}, len(src.Self.WithUpdateDependencies))
+	for _, name := range src.Self.WithUpdateDependencies {
+		if !ok {
+			return nil, fmt.Errorf("dependency %q to update not found: module has no configuration", name)
+		}
+		if _, found := modCfg.DependencyByName(name); !found {
+			return nil, fmt.Errorf("dependency %q to update not found in module configuration", name)
+		}
+		updateDeps[name] = struct{}{}
+	}
+
 	var existingDeps []dagql.Instance[*core.ModuleDependency]
 	if ok && len(modCfg.Dependencies) > 0 {
 		existingDeps = make([]dagql.Instance[*core.ModuleDependency], len(modCfg.Dependencies))
@@ -286,18 +383,39 @@ func (s *moduleSchema) moduleSourceDependencies(
 		for i, depCfg := range modCfg.Dependencies {
 			i, depCfg := i, depCfg
 			eg.Go(func() error {
+				refString, err := contextRefString(ctx, src.Self, depCfg.Source)
+				if err != nil {
+					return err
+				}
+				depSrcArgs := []dagql.NamedInput{
+					{Name: "refString", Value: dagql.String(refString)},
+				}
+				var pin string
+				if _, update := updateDeps[depCfg.Name]; !update && depCfg.Pin != "" {
+					version, err := dependencyVersion(ctx, refString)
+					if err != nil {
+						return err
+					}
+					// a pin resolved from a different version than the source's current one is stale
+					pin = depCfg.PinFor(version)
+				}
+				if pin != "" {
+					depSrcArgs = append(depSrcArgs, dagql.NamedInput{Name: "refPin", Value: dagql.String(pin)})
+				}
 				var depSrc dagql.Instance[*core.ModuleSource]
-				err := s.dag.Select(ctx, s.dag.Root(), &depSrc,
+				err = s.dag.Select(ctx, s.dag.Root(), &depSrc,
 					dagql.Selector{
 						Field: "moduleSource",
-						Args: []dagql.NamedInput{
-							{Name: "refString", Value: dagql.String(depCfg.Source)},
-						},
+						Args:  depSrcArgs,
 					},
 				)
 				if err != nil {
 					return fmt.Errorf("failed to create module source from dependency: %w", err)
 				}
+				depSrc, err = s.atResolvedVersion(ctx, depSrc, src.Self.ResolvedDependencyVersions)
+				if err != nil {
+					return err
+				}
 
 				var resolvedDepSrc dagql.Instance[*core.ModuleSource]
 				err = s.dag.Select(ctx, src, &resolvedDepSrc,
@@ -388,15 +506,319 @@ func (s *moduleSchema) moduleSourceDependencies(
 		depSet[symbolic] = dep
 	}
 
+	removeDeps := make(map[string]bool, len(src.Self.WithoutDependencies))
+	for _, name := range src.Self.WithoutDependencies {
+		removeDeps[name] = false
+	}
+
 	finalDeps := make([]dagql.Instance[*core.ModuleDependency], 0, len(depSet))
 	for _, dep := range depSet {
+		name := dep.Self.Name
+		if name == "" {
+			// deps from older configs may be nameless, in which case they are
+			// named after their module
+			name, err = dep.Self.Source.Self.ModuleOriginalName(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get dependency module name: %w", err)
+			}
+		}
+		if _, remove := removeDeps[name]; remove {
+			removeDeps[name] = true
+			continue
+		}
 		finalDeps = append(finalDeps, dep)
 	}
+	for _, name := range src.Self.WithoutDependencies {
+		if !removeDeps[name] {
+			return nil, fmt.Errorf("dependency %q to remove not found", name)
+		}
+	}
 	sort.Slice(finalDeps, func(i, j int) bool {
 		return finalDeps[i].Self.Name < finalDeps[j].Self.Name
 	})
 
-	return finalDeps, nil
+	if len(src.Self.ResolvedDependencyVersions) > 0 {
+		return s.asResolvedDependencies(ctx, finalDeps, src.Self.ResolvedDependencyVersions)
+	}
+	// the source is loaded directly rather than as a dependency, so resolve
+	// version constraints across its whole dependency tree
+	return s.resolveDependencyVersions(ctx, src, finalDeps)
+}
+
+// dependencyVersion returns the version of a dependency's source ref that its
+// pin was resolved from, which for git refs without a version is their repo's
+// default branch, as in moduleSource.
+func dependencyVersion(ctx context.Context, refString string) (string, error) {
+	parsed := parseRefString(refString)
+	if parsed.kind != core.ModuleSourceKindGit || parsed.hasVersion {
+		return parsed.modVersion, nil
+	}
+	repoRef, err := parseGitRepoRef(parsed.modPath)
+	if err != nil {
+		return "", err
+	}
+	branch, err := defaultBranch(ctx, repoRef.cloneURL)
+	if err != nil {
+		return "", fmt.Errorf("determine default branch: %w", err)
+	}
+	return branch, nil
+}
+
+// atResolvedVersion returns the git dependency source loaded at the tag its
+// version constraint was resolved to across the dependency tree of the module
+// loaded directly, if any. Sources loaded at a pinned commit are kept as is.
+func (s *moduleSchema) atResolvedVersion(
+	ctx context.Context,
+	depSrc dagql.Instance[*core.ModuleSource],
+	versions map[string]string,
+) (dagql.Instance[*core.ModuleSource], error) {
+	if depSrc.Self.Kind != core.ModuleSourceKindGit {
+		return depSrc, nil
+	}
+	git := depSrc.Self.AsGitSource.Value
+	if git.Pinned || !isVersionConstraint(git.Version) {
+		return depSrc, nil
+	}
+	tag, ok := versions[git.Symbolic()]
+	if !ok {
+		return depSrc, nil
+	}
+
+	var commit dagql.String
+	err := s.dag.Select(ctx, s.dag.Root(), &commit,
+		dagql.Selector{
+			Field: "git",
+			Args: []dagql.NamedInput{
+				{Name: "url", Value: dagql.String(git.CloneURL())},
+			},
+		},
+		dagql.Selector{
+			Field: "tag",
+			Args: []dagql.NamedInput{
+				{Name: "name", Value: dagql.String(tag)},
+			},
+		},
+		dagql.Selector{Field: "commit"},
+	)
+	if err != nil {
+		return depSrc, fmt.Errorf("failed to resolve tag %s of %s: %w", tag, git.CloneURL(), err)
+	}
+	if commit.String() == git.Commit {
+		return depSrc, nil
+	}
+
+	var resolved dagql.Instance[*core.ModuleSource]
+	err = s.dag.Select(ctx, s.dag.Root(), &resolved,
+		dagql.Selector{
+			Field: "moduleSource",
+			Args: []dagql.NamedInput{
+				{Name: "refString", Value: dagql.String(git.VersionRefString())},
+				{Name: "refPin", Value: commit},
+			},
+		},
+	)
+	if err != nil {
+		return depSrc, fmt.Errorf("failed to load dependency at resolved version %s: %w", tag, err)
+	}
+	return resolved, nil
+}
+
+// asResolvedDependencies returns the dependencies with their sources loaded
+// as dependencies whose own version constraints resolve to the given versions.
+func (s *moduleSchema) asResolvedDependencies(
+	ctx context.Context,
+	deps []dagql.Instance[*core.ModuleDependency],
+	versions map[string]string,
+) ([]dagql.Instance[*core.ModuleDependency], error) {
+	versionArgs := make(dagql.ArrayInput[dagql.String], 0, len(versions))
+	for symbolic, tag := range versions {
+		versionArgs = append(versionArgs, dagql.String(symbolic+"@"+tag))
+	}
+	sort.Slice(versionArgs, func(i, j int) bool {
+		return versionArgs[i] < versionArgs[j]
+	})
+
+	resolved := make([]dagql.Instance[*core.ModuleDependency], len(deps))
+	var eg errgroup.Group
+	for i, dep := range deps {
+		i, dep := i, dep
+		eg.Go(func() error {
+			var depSrc dagql.Instance[*core.ModuleSource]
+			err := s.dag.Select(ctx, dep.Self.Source, &depSrc,
+				dagql.Selector{
+					Field: "withResolvedDependencyVersions",
+					Args: []dagql.NamedInput{
+						{Name: "versions", Value: versionArgs},
+					},
+				},
+			)
+			if err != nil {
+				return fmt.Errorf("failed to resolve dependency versions: %w", err)
+			}
+			err = s.dag.Select(ctx, s.dag.Root(), &resolved[i],
+				dagql.Selector{
+					Field: "moduleDependency",
+					Args: []dagql.NamedInput{
+						{Name: "source", Value: dagql.NewID[*core.ModuleSource](depSrc.ID())},
+						{Name: "name", Value: dagql.String(dep.Self.Name)},
+					},
+				},
+			)
+			if err != nil {
+				return fmt.Errorf("failed to create module dependency: %w", err)
+			}
+			return nil
+		})
+	}
+	if err := eg.Wait(); err != nil {
+		return nil, err
+	}
+	return resolved, nil
+}
+
+// resolveDependencyVersions resolves each git module that is required with
+// more than one version constraint in the module source's transitive
+// dependencies to the highest version satisfying all of them, and returns
+// the dependencies loaded at those versions.
+//
+// Modules required with a single constraint keep resolving it on their own,
+// and pinned dependencies, exact versions, branches and commits are used as
+// is, even if the same module is required at other versions elsewhere in the
+// tree. If there's nothing to resolve, the dependencies are returned as is.
+func (s *moduleSchema) resolveDependencyVersions(
+	ctx context.Context,
+	src dagql.Instance[*core.ModuleSource],
+	deps []dagql.Instance[*core.ModuleDependency],
+) ([]dagql.Instance[*core.ModuleDependency], error) {
+	type requirement struct {
+		constraint *versionConstraint
+		requiredBy string
+	}
+	type constrainedModule struct {
+		git          *core.GitModuleSource
+		requirements []requirement
+	}
+	constrained := map[string]*constrainedModule{}
+
+	type queued struct {
+		dep        dagql.Instance[*core.ModuleDependency]
+		requiredBy string
+	}
+	rootName, err := src.Self.ModuleOriginalName(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get module name: %w", err)
+	}
+	queue := make([]queued, 0, len(deps))
+	for _, dep := range deps {
+		queue = append(queue, queued{dep, rootName})
+	}
+	seen := map[digest.Digest]struct{}{}
+	for len(queue) > 0 {
+		cur := queue[0]
+		queue = queue[1:]
+		depSrc := cur.dep.Self.Source
+		dgst, err := depSrc.ID().Digest()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get dependency source digest: %w", err)
+		}
+		if _, ok := seen[dgst]; ok {
+			continue
+		}
+		seen[dgst] = struct{}{}
+
+		if depSrc.Self.Kind == core.ModuleSourceKindGit &&
+			!depSrc.Self.AsGitSource.Value.Pinned &&
+			isVersionConstraint(depSrc.Self.AsGitSource.Value.Version) {
+			git := depSrc.Self.AsGitSource.Value
+			constraint, err := parseVersionConstraint(git.Version)
+			if err != nil {
+				return nil, err
+			}
+			symbolic := git.Symbolic()
+			mod, ok := constrained[symbolic]
+			if !ok {
+				mod = &constrainedModule{git: git}
+				constrained[symbolic] = mod
+			}
+			mod.requirements = append(mod.requirements, requirement{constraint, cur.requiredBy})
+		}
+
+		depName, err := depSrc.Self.ModuleOriginalName(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get dependency module name: %w", err)
+		}
+		var subDeps []dagql.Instance[*core.ModuleDependency]
+		err = s.dag.Select(ctx, depSrc, &subDeps, dagql.Selector{Field: "dependencies"})
+		if err != nil {
+			return nil, fmt.Errorf("failed to load dependencies of %s: %w", depName, err)
+		}
+		for _, subDep := range subDeps {
+			queue = append(queue, queued{subDep, depName})
+		}
+	}
+
+	symbolics := make([]string, 0, len(constrained))
+	for symbolic := range constrained {
+		symbolics = append(symbolics, symbolic)
+	}
+	sort.Strings(symbolics)
+	versions := map[string]string{}
+	for _, symbolic := range symbolics {
+		mod := constrained[symbolic]
+		distinct := map[string]struct{}{}
+		constraints := make([]*versionConstraint, 0, len(mod.requirements))
+		for _, req := range mod.requirements {
+			if _, ok := distinct[req.constraint.raw]; ok {
+				continue
+			}
+			distinct[req.constraint.raw] = struct{}{}
+			constraints = append(constraints, req.constraint)
+		}
+		if len(constraints) < 2 {
+			continue
+		}
+		tags, err := s.tags.get(ctx, mod.git.CloneURL())
+		if err != nil {
+			return nil, fmt.Errorf("get git tags: %w", err)
+		}
+		tag, err := matchVersionConstraint(tags, mod.git.RootSubpath, constraints...)
+		if err != nil {
+			reqs := make([]string, len(mod.requirements))
+			for i, req := range mod.requirements {
+				reqs[i] = fmt.Sprintf("%q required by %s", req.constraint.raw, req.requiredBy)
+			}
+			return nil, fmt.Errorf("incompatible version requirements for module %s: %s: %w", symbolic, strings.Join(reqs, ", "), err)
+		}
+		versions[symbolic] = tag
+	}
+	if len(versions) == 0 {
+		return deps, nil
+	}
+
+	// reload the direct dependencies that are among the unified modules at
+	// their resolved version
+	for i, dep := range deps {
+		depSrc, err := s.atResolvedVersion(ctx, dep.Self.Source, versions)
+		if err != nil {
+			return nil, err
+		}
+		if depSrc.ID() == dep.Self.Source.ID() {
+			continue
+		}
+		err = s.dag.Select(ctx, s.dag.Root(), &deps[i],
+			dagql.Selector{
+				Field: "moduleDependency",
+				Args: []dagql.NamedInput{
+					{Name: "source", Value: dagql.NewID[*core.ModuleSource](depSrc.ID())},
+					{Name: "name", Value: dagql.String(dep.Self.Name)},
+				},
+			},
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create module dependency: %w", err)
+		}
+	}
+	return s.asResolvedDependencies(ctx, deps, versions)
 }
 
 func (s *moduleSchema) moduleSourceWithDependencies(
@@ -415,6 +837,50 @@ func (s *moduleSchema) moduleSourceWithDependencies(
 	return src, nil
 }
 
+func (s *moduleSchema) moduleSourceWithoutDependencies(
+	ctx context.Context,
+	src *core.ModuleSource,
+	args struct {
+		Dependencies []string
+	},
+) (*core.ModuleSource, error) {
+	src = src.Clone()
+	src.WithoutDependencies = append(src.WithoutDependencies, args.Dependencies...)
+	return src, nil
+}
+
+func (s *moduleSchema) moduleSourceWithResolvedDependencyVersions(
+	ctx context.Context,
+	src *core.ModuleSource,
+	args struct {
+		Versions []string
+	},
+) (*core.ModuleSource, error) {
+	src = src.Clone()
+	src.ResolvedDependencyVersions = make(map[string]string, len(args.Versions))
+	for _, version := range args.Versions {
+		// tags can't contain @, but symbolic refs of ssh remotes can
+		i := strings.LastIndex(version, "@")
+		if i < 0 {
+			return nil, fmt.Errorf("invalid resolved version %q: expected <symbolic ref>@<tag>", version)
+		}
+		src.ResolvedDependencyVersions[version[:i]] = version[i+1:]
+	}
+	return src, nil
+}
+
+func (s *moduleSchema) moduleSourceWithUpdateDependencies(
+	ctx context.Context,
+	src *core.ModuleSource,
+	args struct {
+		Dependencies []string
+	},
+) (*core.ModuleSource, error) {
+	src = src.Clone()
+	src.WithUpdateDependencies = append(src.WithUpdateDependencies, args.Dependencies...)
+	return src, nil
+}
+
 func (s *moduleSchema) moduleSourceWithSDK(
 	ctx context.Context,
 	src *core.ModuleSource,
@@ -941,7 +1407,8 @@ func (s *moduleSchema) collectCallerLocalDeps(
 		}
 
 		for _, depCfg := range modCfg.Dependencies {
-			parsed := parseRefString(depCfg.Source)
+			depPath := filepath.Join(sourceRootAbsPath, depCfg.Source)
+			parsed := parseRefString(callerRefString(ctx, query.Buildkit, depCfg.Source, depPath))
 			if parsed.kind != core.ModuleSourceKindLocal {
 				continue
 			}
@@ -962,11 +1429,11 @@ func (s *moduleSchema) collectCallerLocalDeps(
 		switch {
 		case err == nil:
 		case errors.Is(err, errUnknownBuiltinSDK):
-			parsed := parseRefString(modCfg.SDK)
+			sdkPath := filepath.Join(sourceRootAbsPath, modCfg.SDK)
+			parsed := parseRefString(callerRefString(ctx, query.Buildkit, modCfg.SDK, sdkPath))
 			switch parsed.kind {
 			case core.ModuleSourceKindLocal:
 				// SDK is a local custom one, it needs to be included
-				sdkPath := filepath.Join(sourceRootAbsPath, parsed.modPath)
 
 				err = s.collectCallerLocalDeps(ctx, query, contextAbsPath, sdkPath, false, src, collectedDeps)
 				if err != nil {
@@ -989,7 +1456,7 @@ func (s *moduleSchema) collectCallerLocalDeps(
 					dagql.Selector{
 						Field: "moduleSource",
 						Args: []dagql.NamedInput{
-							{Name: "refString", Value: dagql.String(sdkCallerRelPath)},
+							{Name: "refString", Value: dagql.String(callerRefString(ctx, query.Buildkit, sdkCallerRelPath, sdkPath))},
 						},
 					},
 					dagql.Selector{
@@ -31,17 +31,27 @@ func (s *querySchema) Install() {
 	core.ImageLayerCompressions.Install(s.srv)
 	core.ImageMediaTypesEnum.Install(s.srv)
 	core.CacheSharingModes.Install(s.srv)
+	core.ReturnTypes.Install(s.srv)
+	core.ServiceStatuses.Install(s.srv)
+	core.ServiceRestartPolicies.Install(s.srv)
+	core.ArchiveFormats.Install(s.srv)
+	core.FileTypes.Install(s.srv)
 	core.TypeDefKinds.Install(s.srv)
+	core.FunctionCachePolicies.Install(s.srv)
 	core.ModuleSourceKindEnum.Install(s.srv)
 
 	dagql.MustInputSpec(pipeline.Label{}).Install(s.srv)
 	dagql.MustInputSpec(core.PortForward{}).Install(s.srv)
 	dagql.MustInputSpec(core.BuildArg{}).Install(s.srv)
+	dagql.MustInputSpec(core.HTTPHeader{}).Install(s.srv)
+	dagql.MustInputSpec(core.HealthcheckHTTPGet{}).Install(s.srv)
 
 	dagql.Fields[EnvVariable]{}.Install(s.srv)
 
 	dagql.Fields[core.Port]{}.Install(s.srv)
 
+	dagql.Fields[core.Stat]{}.Install(s.srv)
+
 	dagql.Fields[Label]{}.Install(s.srv)
 
 	dagql.Fields[*core.Query]{
@@ -35,6 +35,14 @@ type InitializeArgs struct {
 	LeaseManager   *leaseutil.Manager
 	Auth           *auth.RegistryAuthProvider
 	Secrets        *core.SecretStore
+
+	// Cache is the dagql result cache to use; if nil, an unbounded cache is
+	// created.
+	Cache dagql.Cache
+
+	// NoCache disables reusing the results of module function calls from
+	// previous sessions.
+	NoCache bool
 }
 
 type APIServer struct {
@@ -55,8 +63,12 @@ func New(ctx context.Context, params InitializeArgs) (*APIServer, error) {
 	root.OCIStore = params.OCIStore
 	root.LeaseManager = params.LeaseManager
 	root.Auth = params.Auth
+	root.NoCache = params.NoCache
 
 	dag := dagql.NewServer(root)
+	if params.Cache != nil {
+		dag.Cache = params.Cache
+	}
 
 	// stash away the cache so we can share it between other servers
 	root.Cache = dag.Cache
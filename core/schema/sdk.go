@@ -36,6 +36,13 @@ func (s *moduleSchema) sdkForModule(
 		return nil, err
 	}
 
+	if parentSrc.Self != nil {
+		sdk, err = contextRefString(ctx, parentSrc.Self, sdk)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	var sdkSource dagql.Instance[*core.ModuleSource]
 	err = s.dag.Select(ctx, s.dag.Root(), &sdkSource,
 		dagql.Selector{
@@ -534,6 +541,10 @@ func (sdk *goSDK) base(ctx context.Context) (dagql.Instance[*core.Container], er
 				Name:  "key",
 				Value: dagql.String("modgomodcache"),
 			},
+			{
+				Name:  "shared",
+				Value: dagql.Boolean(true),
+			},
 		},
 	}); err != nil {
 		return inst, fmt.Errorf("failed to get mod cache from go module sdk tarball: %w", err)
@@ -546,6 +557,10 @@ func (sdk *goSDK) base(ctx context.Context) (dagql.Instance[*core.Container], er
 				Name:  "key",
 				Value: dagql.String("modgobuildcache"),
 			},
+			{
+				Name:  "shared",
+				Value: dagql.Boolean(true),
+			},
 		},
 	}); err != nil {
 		return inst, fmt.Errorf("failed to get build cache from go module sdk tarball: %w", err)
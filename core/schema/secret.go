@@ -2,6 +2,7 @@ package schema
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/dagger/dagger/core"
 	"github.com/dagger/dagger/dagql"
@@ -27,7 +28,14 @@ func (s *secretSchema) Install() {
 
 		dagql.Func("secret", s.secret).
 			Impure("A secret is scoped to the client that created it.").
-			Doc(`Reference a secret by name.`),
+			Doc(`Reference a secret by name.`,
+				`If a URI is given, the secret's plaintext is fetched by the engine
+				from the matching secret provider each time the secret is used.
+				Secret providers run in the engine, so they must be enabled in its
+				configuration first.`).
+			ArgDoc("uri", `The URI of the secret in a secret provider, e.g. "vault://secret/data/ci#token",
+				"env://TOKEN", "file:///run/secrets/token", "cmd://gcloud auth print-access-token",
+				"op://vault/item/field" or "sops:///secrets.yaml#db.password".`),
 	}.Install(s.srv)
 
 	dagql.Fields[*core.Secret]{
@@ -42,6 +50,8 @@ type secretArgs struct {
 
 	// Accessor is the scoped per-module name, which should guarantee uniqueness.
 	Accessor dagql.Optional[dagql.String]
+
+	URI dagql.Optional[dagql.String] `name:"uri"`
 }
 
 func (s *secretSchema) secret(ctx context.Context, parent *core.Query, args secretArgs) (*core.Secret, error) {
@@ -54,6 +64,20 @@ func (s *secretSchema) secret(ctx context.Context, parent *core.Query, args secr
 		}
 	}
 
+	if args.URI.Valid {
+		uri := args.URI.Value
+		if _, err := parent.CurrentModule(ctx); err == nil {
+			// providers run in the engine with access to its environment, so only
+			// the main client may reference secrets through them
+			return nil, fmt.Errorf("secret %s: secret URIs cannot be resolved from within a module", args.Name)
+		} else if !errors.Is(err, core.ErrNoCurrentModule) {
+			return nil, err
+		}
+		if err := parent.Secrets.AddSecretURI(ctx, accessor, uri.String()); err != nil {
+			return nil, err
+		}
+	}
+
 	return parent.NewSecret(args.Name, accessor), nil
 }
 
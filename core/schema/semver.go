@@ -0,0 +1,283 @@
+package schema
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"golang.org/x/mod/semver"
+)
+
+// versionConstraint is a semver range a module dependency version may be
+// given as, e.g. ^1.2, ~0.3.1 or ">=1.0 <2.0".
+//
+// Comparators separated by spaces or commas must all match; alternatives
+// separated by || match if any of them does. Partial versions are filled in
+// the same way as npm and cargo, e.g. ^0.3 allows 0.3.x and >1.2 means >=1.3.0.
+type versionConstraint struct {
+	raw          string
+	alternatives [][]versionComparator
+}
+
+type versionComparator struct {
+	op string
+	// canonical semver with a v prefix, e.g. v1.2.0
+	version string
+}
+
+// isVersionConstraint reports whether the module ref version is a semver
+// range rather than a branch, tag or commit.
+func isVersionConstraint(ver string) bool {
+	if ver == "" {
+		return false
+	}
+	if !strings.ContainsRune("^~<>=", rune(ver[0])) && !strings.Contains(ver, "||") {
+		return false
+	}
+	_, err := parseVersionConstraint(ver)
+	return err == nil
+}
+
+func parseVersionConstraint(raw string) (*versionConstraint, error) {
+	c := &versionConstraint{raw: raw}
+	for _, alt := range strings.Split(raw, "||") {
+		fields := strings.FieldsFunc(alt, func(r rune) bool {
+			return r == ' ' || r == ','
+		})
+		var comparators []versionComparator
+		for i := 0; i < len(fields); i++ {
+			field := fields[i]
+			// allow whitespace between the operator and the version, e.g. ">= 1.0"
+			if strings.Trim(field, "^~<>=") == "" && i+1 < len(fields) {
+				i++
+				field += fields[i]
+			}
+			parsed, err := parseVersionComparator(field)
+			if err != nil {
+				return nil, fmt.Errorf("invalid version constraint %q: %w", raw, err)
+			}
+			comparators = append(comparators, parsed...)
+		}
+		if len(comparators) == 0 {
+			return nil, fmt.Errorf("invalid version constraint %q: empty range", raw)
+		}
+		c.alternatives = append(c.alternatives, comparators)
+	}
+	return c, nil
+}
+
+// parseVersionComparator expands a single comparator like ^1.2 into the
+// primitive comparators (=, <, <=, >, >=) it stands for.
+func parseVersionComparator(s string) ([]versionComparator, error) {
+	var op string
+	for _, candidate := range []string{">=", "<=", ">", "<", "=", "^", "~"} {
+		if strings.HasPrefix(s, candidate) {
+			op = candidate
+			break
+		}
+	}
+	ver := strings.TrimPrefix(strings.TrimPrefix(s, op), "v")
+
+	release, prerelease, hasPrerelease := strings.Cut(ver, "-")
+	parts := strings.Split(release, ".")
+	if len(parts) > 3 {
+		return nil, fmt.Errorf("invalid version %q", ver)
+	}
+	nums := make([]int, 3)
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return nil, fmt.Errorf("invalid version %q", ver)
+		}
+		nums[i] = n
+	}
+	if hasPrerelease && len(parts) != 3 {
+		return nil, fmt.Errorf("invalid version %q: prerelease requires a full version", ver)
+	}
+
+	full := len(parts) == 3
+	lower := fmt.Sprintf("v%d.%d.%d", nums[0], nums[1], nums[2])
+	if hasPrerelease {
+		lower += "-" + prerelease
+	}
+	if !semver.IsValid(lower) {
+		return nil, fmt.Errorf("invalid version %q", ver)
+	}
+	// the next version that's not covered by the partial version, e.g. 1.3.0 for 1.2
+	next := func(level int) string {
+		switch level {
+		case 1:
+			return fmt.Sprintf("v%d.0.0", nums[0]+1)
+		case 2:
+			return fmt.Sprintf("v%d.%d.0", nums[0], nums[1]+1)
+		default:
+			return fmt.Sprintf("v%d.%d.%d", nums[0], nums[1], nums[2]+1)
+		}
+	}
+
+	switch op {
+	case "", "=":
+		if full {
+			return []versionComparator{{"=", lower}}, nil
+		}
+		return []versionComparator{{">=", lower}, {"<", next(len(parts))}}, nil
+	case ">=", "<":
+		return []versionComparator{{op, lower}}, nil
+	case ">":
+		if full {
+			return []versionComparator{{">", lower}}, nil
+		}
+		return []versionComparator{{">=", next(len(parts))}}, nil
+	case "<=":
+		if full {
+			return []versionComparator{{"<=", lower}}, nil
+		}
+		return []versionComparator{{"<", next(len(parts))}}, nil
+	case "^":
+		switch {
+		case nums[0] > 0 || len(parts) == 1:
+			return []versionComparator{{">=", lower}, {"<", next(1)}}, nil
+		case nums[1] > 0 || len(parts) == 2:
+			return []versionComparator{{">=", lower}, {"<", next(2)}}, nil
+		default:
+			return []versionComparator{{">=", lower}, {"<", next(3)}}, nil
+		}
+	case "~":
+		if len(parts) == 1 {
+			return []versionComparator{{">=", lower}, {"<", next(1)}}, nil
+		}
+		return []versionComparator{{">=", lower}, {"<", next(2)}}, nil
+	}
+	return nil, fmt.Errorf("invalid operator in %q", s)
+}
+
+// Check reports whether the canonical semver version satisfies the
+// constraint. Prereleases only match if a comparator in the same
+// alternative is a prerelease of the same major.minor.patch.
+func (c *versionConstraint) Check(ver string) bool {
+	for _, comparators := range c.alternatives {
+		if checkComparators(comparators, ver) {
+			return true
+		}
+	}
+	return false
+}
+
+func checkComparators(comparators []versionComparator, ver string) bool {
+	if semver.Prerelease(ver) != "" {
+		allowed := false
+		release := strings.TrimSuffix(ver, semver.Prerelease(ver)+semver.Build(ver))
+		for _, cmp := range comparators {
+			if semver.Prerelease(cmp.version) != "" &&
+				strings.TrimSuffix(cmp.version, semver.Prerelease(cmp.version)) == release {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			return false
+		}
+	}
+	for _, cmp := range comparators {
+		res := semver.Compare(ver, cmp.version)
+		var ok bool
+		switch cmp.op {
+		case "=":
+			ok = res == 0
+		case ">":
+			ok = res > 0
+		case ">=":
+			ok = res >= 0
+		case "<":
+			ok = res < 0
+		case "<=":
+			ok = res <= 0
+		}
+		if !ok {
+			return false
+		}
+	}
+	return true
+}
+
+func (c *versionConstraint) String() string {
+	return c.raw
+}
+
+// taggedVersion is a git tag that's a semver version, possibly prefixed with
+// a subpath for repos containing multiple modules, e.g. mymod/v1.2.3
+type taggedVersion struct {
+	tag     string
+	version string
+}
+
+// semverTags returns the semver tags of the module at the given subpath of a
+// repo, highest version first. Tags prefixed with the subpath are used if
+// there are any, otherwise unprefixed tags are used.
+func semverTags(tags []string, subPath string) []taggedVersion {
+	collect := func(prefix string) []taggedVersion {
+		var versions []taggedVersion
+		for _, tag := range tags {
+			ver, ok := strings.CutPrefix(tag, prefix)
+			if !ok || strings.Contains(ver, "/") {
+				continue
+			}
+			if !strings.HasPrefix(ver, "v") {
+				ver = "v" + ver
+			}
+			if !semver.IsValid(ver) || semver.Canonical(ver) != strings.TrimSuffix(ver, semver.Build(ver)) {
+				// skip partial versions like v1 or v1.2
+				continue
+			}
+			versions = append(versions, taggedVersion{tag: tag, version: ver})
+		}
+		sort.SliceStable(versions, func(i, j int) bool {
+			return semver.Compare(versions[i].version, versions[j].version) > 0
+		})
+		return versions
+	}
+
+	if subPath = strings.Trim(subPath, "/"); subPath != "" && subPath != "." {
+		if versions := collect(subPath + "/"); len(versions) > 0 {
+			return versions
+		}
+	}
+	return collect("")
+}
+
+// matchVersionConstraint returns the tag of the highest version satisfying
+// all of the given constraints.
+func matchVersionConstraint(tags []string, subPath string, constraints ...*versionConstraint) (string, error) {
+	versions := semverTags(tags, subPath)
+	for _, v := range versions {
+		matches := true
+		for _, c := range constraints {
+			if !c.Check(v.version) {
+				matches = false
+				break
+			}
+		}
+		if matches {
+			return v.tag, nil
+		}
+	}
+
+	raws := make([]string, len(constraints))
+	for i, c := range constraints {
+		raws[i] = fmt.Sprintf("%q", c.raw)
+	}
+	if len(versions) == 0 {
+		return "", fmt.Errorf("no version matches %s: no semver tags found", strings.Join(raws, " and "))
+	}
+	const maxAvailable = 10
+	available := make([]string, 0, maxAvailable)
+	for i, v := range versions {
+		if i == maxAvailable {
+			available = append(available, "...")
+			break
+		}
+		available = append(available, v.tag)
+	}
+	return "", fmt.Errorf("no version matches %s (available: %s)", strings.Join(raws, " and "), strings.Join(available, ", "))
+}
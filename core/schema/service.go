@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"runtime/debug"
+	"time"
 
 	"github.com/dagger/dagger/core"
 	"github.com/dagger/dagger/dagql"
@@ -20,7 +21,10 @@ func (s *serviceSchema) Install() {
 	dagql.Fields[*core.Container]{
 		dagql.Func("asService", s.containerAsService).
 			Doc(`Turn the container into a Service.`,
-				`Be sure to set any exposed ports before this conversion.`),
+				`Be sure to set any exposed ports before this conversion.`).
+			ArgDoc("restartPolicy", `Whether to restart the service when its process exits.`,
+				`A service is never restarted after it has been stopped.`).
+			ArgDoc("maxRestarts", `Maximum number of times to restart the service. Zero means no limit.`),
 	}.Install(s.srv)
 
 	dagql.Fields[*core.Service]{
@@ -44,6 +48,24 @@ func (s *serviceSchema) Install() {
 			Doc(`Start the service and wait for its health checks to succeed.`,
 				`Services bound to a Container do not need to be manually started.`),
 
+		dagql.NodeFunc("status", s.status).
+			Impure("A service's status changes as it runs.").
+			Doc(`The status of the current or last run of the service.`),
+
+		dagql.NodeFunc("exitCode", s.exitCode).
+			Impure("A service's exit code is only known once it exits.").
+			Doc(`The exit code of the last run of the service.`,
+				`Returns an error if the service has not exited yet.`),
+
+		dagql.NodeFunc("logs", s.logs).
+			Impure("A service's output grows as it runs.").
+			Doc(`The combined stdout and stderr of the current or last run of the service.`).
+			ArgDoc("since", `Only return output written after this time.`,
+				`Either an RFC 3339 timestamp (e.g., "2024-01-02T15:04:05Z") or a
+				duration relative to now (e.g., "10m").`).
+			ArgDoc("tail", `Only return this many of the most recent lines.`).
+			ArgDoc("follow", `Wait for the service to exit before returning its output.`),
+
 		dagql.NodeFunc("up", s.up).
 			Impure("Starts a host tunnel, possibly with ports that change each time it's started.").
 			Doc(`Creates a tunnel that forwards traffic from the caller's network to this service.`).
@@ -58,8 +80,17 @@ func (s *serviceSchema) Install() {
 	}.Install(s.srv)
 }
 
-func (s *serviceSchema) containerAsService(ctx context.Context, parent *core.Container, args struct{}) (*core.Service, error) {
-	return parent.Service(ctx)
+type containerAsServiceArgs struct {
+	RestartPolicy core.ServiceRestartPolicy `default:"NEVER"`
+	MaxRestarts   int                       `default:"0"`
+}
+
+func (s *serviceSchema) containerAsService(ctx context.Context, parent *core.Container, args containerAsServiceArgs) (*core.Service, error) {
+	svc, err := parent.Service(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return svc.WithRestartPolicy(args.RestartPolicy, args.MaxRestarts)
 }
 
 func (s *serviceSchema) hostname(ctx context.Context, parent dagql.Instance[*core.Service], args struct{}) (dagql.String, error) {
@@ -102,6 +133,47 @@ func (s *serviceSchema) start(ctx context.Context, parent dagql.Instance[*core.S
 	return dagql.NewID[*core.Service](parent.ID()), nil
 }
 
+func (s *serviceSchema) status(ctx context.Context, parent dagql.Instance[*core.Service], args struct{}) (core.ServiceStatus, error) {
+	return parent.Self.Status(ctx, parent.ID())
+}
+
+func (s *serviceSchema) exitCode(ctx context.Context, parent dagql.Instance[*core.Service], args struct{}) (dagql.Int, error) {
+	code, err := parent.Self.ExitCode(ctx, parent.ID())
+	if err != nil {
+		return 0, err
+	}
+	return dagql.NewInt(code), nil
+}
+
+type serviceLogsArgs struct {
+	Since  string `default:""`
+	Tail   *int
+	Follow bool `default:"false"`
+}
+
+func (s *serviceSchema) logs(ctx context.Context, parent dagql.Instance[*core.Service], args serviceLogsArgs) (dagql.String, error) {
+	var since time.Time
+	if args.Since != "" {
+		if d, err := time.ParseDuration(args.Since); err == nil {
+			since = time.Now().Add(-d)
+		} else if since, err = time.Parse(time.RFC3339, args.Since); err != nil {
+			return "", fmt.Errorf("invalid since %q: expected a duration or RFC 3339 timestamp", args.Since)
+		}
+	}
+	tail := -1
+	if args.Tail != nil {
+		if *args.Tail < 0 {
+			return "", fmt.Errorf("invalid tail %d: must not be negative", *args.Tail)
+		}
+		tail = *args.Tail
+	}
+	logs, err := parent.Self.Logs(ctx, parent.ID(), since, tail, args.Follow)
+	if err != nil {
+		return "", err
+	}
+	return dagql.NewString(logs), nil
+}
+
 type serviceStopArgs struct {
 	Kill bool `default:"false"`
 }
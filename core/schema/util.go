@@ -2,7 +2,13 @@ package schema
 
 import (
 	"context"
+	"errors"
+	"net/http"
+	"regexp"
+	"strconv"
+	"time"
 
+	remoteserrors "github.com/containerd/containerd/remotes/errors"
 	"github.com/dagger/dagger/dagql"
 	"github.com/dagger/dagger/engine/buildkit"
 )
@@ -61,3 +67,36 @@ func asArrayInput[T any, I dagql.Input](ts []T, conv func(T) I) dagql.ArrayInput
 	}
 	return ins
 }
+
+// remoteRetryPolicy retries fields that talk to remote registries and
+// servers, absorbing network hiccups and server-side failures.
+var remoteRetryPolicy = dagql.RetryPolicy{
+	Attempts:   3,
+	Backoff:    time.Second,
+	MaxBackoff: 10 * time.Second,
+	RetryOn:    isTransientRemoteError,
+}
+
+func isTransientRemoteError(err error) bool {
+	if dagql.RetryTransient(err) {
+		return true
+	}
+	var statusErr remoteserrors.ErrUnexpectedStatus
+	if errors.As(err, &statusErr) {
+		return isTransientStatus(statusErr.StatusCode)
+	}
+	// errors returned by buildkit may have crossed a gRPC boundary, which
+	// only preserves their message
+	if m := unexpectedStatusRe.FindStringSubmatch(err.Error()); m != nil {
+		code, _ := strconv.Atoi(m[1])
+		return isTransientStatus(code)
+	}
+	return false
+}
+
+// unexpectedStatusRe matches the message of remoteserrors.ErrUnexpectedStatus.
+var unexpectedStatusRe = regexp.MustCompile(`unexpected status from [A-Z]+ request to \S+: (\d{3})\b`)
+
+func isTransientStatus(code int) bool {
+	return code == http.StatusTooManyRequests || code >= http.StatusInternalServerError
+}
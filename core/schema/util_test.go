@@ -0,0 +1,49 @@
+package schema
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	remoteserrors "github.com/containerd/containerd/remotes/errors"
+	"github.com/moby/buildkit/util/grpcerrors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsTransientRemoteError(t *testing.T) {
+	statusErr := func(code int) error {
+		return remoteserrors.ErrUnexpectedStatus{
+			Status:        fmt.Sprintf("%d %s", code, http.StatusText(code)),
+			StatusCode:    code,
+			RequestURL:    "https://registry.example.com/v2/library/alpine/manifests/latest",
+			RequestMethod: http.MethodGet,
+		}
+	}
+	// simulate the error being returned from buildkit over gRPC
+	overGRPC := func(err error) error {
+		return grpcerrors.FromGRPC(grpcerrors.ToGRPC(context.Background(), fmt.Errorf("resolve image config: %w", err)))
+	}
+
+	for _, tc := range []struct {
+		code      int
+		transient bool
+	}{
+		{http.StatusServiceUnavailable, true},
+		{http.StatusTooManyRequests, true},
+		{http.StatusNotFound, false},
+		{http.StatusUnauthorized, false},
+	} {
+		tc := tc
+		t.Run(http.StatusText(tc.code), func(t *testing.T) {
+			require.Equal(t, tc.transient, isTransientRemoteError(statusErr(tc.code)))
+			require.Equal(t, tc.transient, isTransientRemoteError(fmt.Errorf("pull: %w", statusErr(tc.code))))
+
+			err := overGRPC(statusErr(tc.code))
+			var target remoteserrors.ErrUnexpectedStatus
+			require.False(t, errors.As(err, &target), "typed error should not survive gRPC")
+			require.Equal(t, tc.transient, isTransientRemoteError(err))
+		})
+	}
+}
@@ -4,8 +4,10 @@ import (
 	"context"
 	"crypto/hmac"
 	"encoding/hex"
+	"fmt"
 	"sync"
 
+	"github.com/dagger/dagger/core/secretprovider"
 	"github.com/moby/buildkit/session/secrets"
 	"github.com/opencontainers/go-digest"
 	"github.com/pkg/errors"
@@ -65,7 +67,9 @@ func (secret *Secret) Clone() *Secret {
 
 func NewSecretStore() *SecretStore {
 	return &SecretStore{
-		secrets: map[string][]byte{},
+		secrets:   map[string][]byte{},
+		uris:      map[string]string{},
+		providers: map[string]secretprovider.Provider{},
 	}
 }
 
@@ -74,6 +78,10 @@ var _ secrets.SecretStore = &SecretStore{}
 type SecretStore struct {
 	mu      sync.Mutex
 	secrets map[string][]byte
+	// uris maps secret names to the URIs of secrets that are fetched from a
+	// provider each time they are used
+	uris      map[string]string
+	providers map[string]secretprovider.Provider
 }
 
 // AddSecret adds the secret identified by user defined name with its plaintext
@@ -82,16 +90,66 @@ func (store *SecretStore) AddSecret(ctx context.Context, name string, plaintext
 	store.mu.Lock()
 	defer store.mu.Unlock()
 	store.secrets[name] = plaintext
+	delete(store.uris, name)
 	return nil
 }
 
+// AddSecretURI adds the secret identified by user defined name to the secret
+// store as a reference to a secret provider, e.g. vault://secret/data/ci#token.
+// The plaintext is fetched from the provider whenever the secret is used.
+func (store *SecretStore) AddSecretURI(ctx context.Context, name string, uri string) error {
+	scheme, err := secretprovider.Scheme(uri)
+	if err != nil {
+		return err
+	}
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	if _, ok := store.providers[scheme]; !ok {
+		if _, ok := secretprovider.Builtin()[scheme]; ok {
+			return fmt.Errorf("secret provider %q is not enabled in the engine", scheme)
+		}
+		return fmt.Errorf("unsupported secret provider %q", scheme)
+	}
+	store.uris[name] = uri
+	delete(store.secrets, name)
+	return nil
+}
+
+// RegisterSecretProvider sets the provider used for secret URIs with the
+// given scheme, replacing any existing one.
+func (store *SecretStore) RegisterSecretProvider(scheme string, provider secretprovider.Provider) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	store.providers[scheme] = provider
+}
+
 // GetSecret returns the plaintext secret value for a user defined secret name.
 func (store *SecretStore) GetSecret(ctx context.Context, name string) ([]byte, error) {
 	store.mu.Lock()
-	defer store.mu.Unlock()
 	plaintext, ok := store.secrets[name]
+	if ok {
+		store.mu.Unlock()
+		return plaintext, nil
+	}
+	uri, ok := store.uris[name]
 	if !ok {
+		store.mu.Unlock()
 		return nil, errors.Wrapf(secrets.ErrNotFound, "secret %s", name)
 	}
+	scheme, err := secretprovider.Scheme(uri)
+	if err != nil {
+		store.mu.Unlock()
+		return nil, err
+	}
+	provider := store.providers[scheme]
+	store.mu.Unlock()
+
+	plaintext, err = provider.GetSecret(ctx, uri)
+	if err != nil {
+		if errors.Is(err, secretprovider.ErrNotFound) {
+			return nil, errors.Wrapf(secrets.ErrNotFound, "secret %s: %s", name, err)
+		}
+		return nil, errors.Wrapf(err, "secret %s", name)
+	}
 	return plaintext, nil
 }
@@ -4,6 +4,7 @@ import (
 	"context"
 	"testing"
 
+	"github.com/dagger/dagger/core/secretprovider"
 	"github.com/moby/buildkit/session/secrets"
 	"github.com/stretchr/testify/require"
 )
@@ -21,3 +22,39 @@ func TestSecretStoreNotFound(t *testing.T) {
 	_, err := store.GetSecret(context.Background(), "foo")
 	require.ErrorIs(t, err, secrets.ErrNotFound)
 }
+
+type fakeSecretProvider map[string]string
+
+func (p fakeSecretProvider) GetSecret(ctx context.Context, uri string) ([]byte, error) {
+	val, ok := p[uri]
+	if !ok {
+		return nil, secretprovider.ErrNotFound
+	}
+	return []byte(val), nil
+}
+
+func TestSecretStoreURI(t *testing.T) {
+	ctx := context.Background()
+	store := NewSecretStore()
+	provider := fakeSecretProvider{"fake://token": "bar"}
+	store.RegisterSecretProvider("fake", provider)
+
+	require.NoError(t, store.AddSecretURI(ctx, "foo", "fake://token"))
+	result, err := store.GetSecret(ctx, "foo")
+	require.NoError(t, err)
+	require.Equal(t, []byte("bar"), result)
+
+	// fetched each time it's used
+	provider["fake://token"] = "baz"
+	result, err = store.GetSecret(ctx, "foo")
+	require.NoError(t, err)
+	require.Equal(t, []byte("baz"), result)
+
+	require.NoError(t, store.AddSecretURI(ctx, "missing", "fake://missing"))
+	_, err = store.GetSecret(ctx, "missing")
+	require.ErrorIs(t, err, secrets.ErrNotFound)
+
+	require.ErrorContains(t, store.AddSecretURI(ctx, "foo", "cmd://echo token"), `secret provider "cmd" is not enabled in the engine`)
+	require.ErrorContains(t, store.AddSecretURI(ctx, "foo", "nope://token"), `unsupported secret provider "nope"`)
+	require.Error(t, store.AddSecretURI(ctx, "foo", "token"))
+}
@@ -0,0 +1,167 @@
+// Package secretprovider resolves the plaintext of secrets referenced by URI,
+// e.g. vault://secret/data/ci#token, at the time they are used rather than
+// when they are created.
+package secretprovider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// Provider fetches the plaintext of secrets whose URI has a particular scheme.
+type Provider interface {
+	GetSecret(ctx context.Context, uri string) ([]byte, error)
+}
+
+// ErrNotFound is returned by providers when the referenced secret does not
+// exist.
+var ErrNotFound = errors.New("secret not found")
+
+// Builtin returns the built-in providers keyed by the URI scheme they handle.
+//
+// Providers run in the engine with access to its environment, filesystem and
+// network, so none of them are used unless the engine is configured to enable
+// them.
+func Builtin() map[string]Provider {
+	return map[string]Provider{
+		"env":   Env{},
+		"file":  File{},
+		"cmd":   Command{},
+		"vault": NewVaultFromEnv(),
+		"op":    OnePassword{},
+		"sops":  SOPS{},
+	}
+}
+
+// Enabled returns the built-in providers for the given schemes, e.g. as
+// configured with the engine's --secret-provider flag.
+func Enabled(schemes []string) (map[string]Provider, error) {
+	builtin := Builtin()
+	providers := make(map[string]Provider, len(schemes))
+	for _, scheme := range schemes {
+		provider, ok := builtin[scheme]
+		if !ok {
+			return nil, fmt.Errorf("unknown secret provider %q", scheme)
+		}
+		providers[scheme] = provider
+	}
+	return providers, nil
+}
+
+// Scheme returns the scheme of a secret URI, e.g. vault for
+// vault://secret/data/ci#token.
+func Scheme(uri string) (string, error) {
+	scheme, rest, ok := strings.Cut(uri, "://")
+	if !ok || scheme == "" || rest == "" {
+		return "", fmt.Errorf("invalid secret URI %q: expected scheme://reference", uri)
+	}
+	return scheme, nil
+}
+
+// reference returns everything after the scheme:// of a URI.
+func reference(uri string) string {
+	_, ref, _ := strings.Cut(uri, "://")
+	return ref
+}
+
+// parseURI parses a URI whose reference is a path, treating its host as the
+// first path segment so that both scheme://rel/path and scheme:///abs/path
+// work.
+func parseURI(uri string) (path string, u *url.URL, err error) {
+	u, err = url.Parse(uri)
+	if err != nil {
+		return "", nil, fmt.Errorf("invalid secret URI: %w", err)
+	}
+	return u.Host + u.Path, u, nil
+}
+
+// Env reads secrets from the engine's environment, e.g. env://GITHUB_TOKEN.
+type Env struct{}
+
+func (Env) GetSecret(_ context.Context, uri string) ([]byte, error) {
+	name := reference(uri)
+	val, ok := os.LookupEnv(name)
+	if !ok {
+		return nil, fmt.Errorf("env var %q: %w", name, ErrNotFound)
+	}
+	return []byte(val), nil
+}
+
+// File reads secrets from files on the engine's filesystem, e.g.
+// file:///run/secrets/token.
+type File struct{}
+
+func (File) GetSecret(_ context.Context, uri string) ([]byte, error) {
+	path := reference(uri)
+	dt, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("file %q: %w", path, ErrNotFound)
+		}
+		return nil, fmt.Errorf("read secret file %q: %w", path, err)
+	}
+	return dt, nil
+}
+
+// Command runs a shell command in the engine and uses its stdout as the
+// secret, e.g. cmd://gcloud auth print-access-token.
+type Command struct{}
+
+func (Command) GetSecret(ctx context.Context, uri string) ([]byte, error) {
+	// #nosec G204
+	return runSecretCommand(exec.CommandContext(ctx, "sh", "-c", reference(uri)))
+}
+
+// OnePassword reads secrets with the 1Password CLI, which must be installed
+// and signed in, e.g. op://vault/item/field.
+type OnePassword struct{}
+
+func (OnePassword) GetSecret(ctx context.Context, uri string) ([]byte, error) {
+	if _, err := exec.LookPath("op"); err != nil {
+		return nil, fmt.Errorf("1Password CLI (op) is not available: %w", err)
+	}
+	// #nosec G204
+	return runSecretCommand(exec.CommandContext(ctx, "op", "read", "--no-newline", uri))
+}
+
+// SOPS decrypts a SOPS-encrypted file, optionally extracting a single value
+// from it by a dot-separated key path, e.g. sops:///secrets.yaml#db.password.
+type SOPS struct{}
+
+func (SOPS) GetSecret(ctx context.Context, uri string) ([]byte, error) {
+	if _, err := exec.LookPath("sops"); err != nil {
+		return nil, fmt.Errorf("sops is not available: %w", err)
+	}
+	path, u, err := parseURI(uri)
+	if err != nil {
+		return nil, err
+	}
+	args := []string{"--decrypt"}
+	if u.Fragment != "" {
+		var extract strings.Builder
+		for _, key := range strings.Split(u.Fragment, ".") {
+			fmt.Fprintf(&extract, "[%q]", key)
+		}
+		args = append(args, "--extract", extract.String())
+	}
+	args = append(args, path)
+	// #nosec G204
+	return runSecretCommand(exec.CommandContext(ctx, "sops", args...))
+}
+
+func runSecretCommand(cmd *exec.Cmd) ([]byte, error) {
+	stdout, err := cmd.Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && len(exitErr.Stderr) > 0 {
+			return nil, fmt.Errorf("%s: %w: %s", cmd.Args[0], err, strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return nil, fmt.Errorf("%s: %w", cmd.Args[0], err)
+	}
+	return stdout, nil
+}
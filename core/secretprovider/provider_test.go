@@ -0,0 +1,72 @@
+package secretprovider
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestScheme(t *testing.T) {
+	scheme, err := Scheme("vault://secret/data/ci#token")
+	require.NoError(t, err)
+	require.Equal(t, "vault", scheme)
+
+	_, err = Scheme("secret/data/ci")
+	require.Error(t, err)
+	_, err = Scheme("env://")
+	require.Error(t, err)
+}
+
+func TestEnv(t *testing.T) {
+	ctx := context.Background()
+	t.Setenv("DAGGER_TEST_SECRET", "hunter2")
+
+	dt, err := Env{}.GetSecret(ctx, "env://DAGGER_TEST_SECRET")
+	require.NoError(t, err)
+	require.Equal(t, "hunter2", string(dt))
+
+	_, err = Env{}.GetSecret(ctx, "env://DAGGER_TEST_SECRET_MISSING")
+	require.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestFile(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "token")
+	require.NoError(t, os.WriteFile(path, []byte("hunter2"), 0o600))
+
+	dt, err := File{}.GetSecret(ctx, "file://"+path)
+	require.NoError(t, err)
+	require.Equal(t, "hunter2", string(dt))
+
+	_, err = File{}.GetSecret(ctx, "file://"+path+"-missing")
+	require.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestCommand(t *testing.T) {
+	ctx := context.Background()
+
+	dt, err := Command{}.GetSecret(ctx, "cmd://printf '%s' hunter2")
+	require.NoError(t, err)
+	require.Equal(t, "hunter2", string(dt))
+
+	_, err = Command{}.GetSecret(ctx, "cmd://echo oops >&2; exit 1")
+	require.ErrorContains(t, err, "oops")
+}
+
+func TestEnabled(t *testing.T) {
+	providers, err := Enabled(nil)
+	require.NoError(t, err)
+	require.Empty(t, providers)
+
+	providers, err = Enabled([]string{"vault", "env"})
+	require.NoError(t, err)
+	require.Len(t, providers, 2)
+	require.IsType(t, Env{}, providers["env"])
+	require.IsType(t, &Vault{}, providers["vault"])
+
+	_, err = Enabled([]string{"bogus"})
+	require.ErrorContains(t, err, `unknown secret provider "bogus"`)
+}
@@ -0,0 +1,130 @@
+package secretprovider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+)
+
+// Vault reads secrets from a HashiCorp Vault server's HTTP API, e.g.
+// vault://secret/data/ci#token reads the token key of the ci secret in a KV
+// version 2 engine mounted at secret/.
+//
+// The path is requested as-is, so KV version 2 paths must include data/. The
+// fragment selects a key of the secret; it may be omitted if the secret has
+// a single key. Query parameters such as ?version=2 are passed through.
+type Vault struct {
+	// Address is the Vault server's address, e.g. https://vault:8200.
+	Address string
+	// Token is the Vault token used to authenticate.
+	Token string
+	// Namespace is the Vault Enterprise namespace, if any.
+	Namespace string
+	// Client is the HTTP client used to talk to the server. If nil,
+	// http.DefaultClient is used.
+	Client *http.Client
+}
+
+// NewVaultFromEnv configures a Vault provider from the standard VAULT_ADDR,
+// VAULT_TOKEN and VAULT_NAMESPACE environment variables.
+func NewVaultFromEnv() *Vault {
+	return &Vault{
+		Address:   os.Getenv("VAULT_ADDR"),
+		Token:     os.Getenv("VAULT_TOKEN"),
+		Namespace: os.Getenv("VAULT_NAMESPACE"),
+	}
+}
+
+func (v *Vault) GetSecret(ctx context.Context, uri string) ([]byte, error) {
+	if v.Address == "" {
+		return nil, fmt.Errorf("vault address is not configured (VAULT_ADDR)")
+	}
+	path, u, err := parseURI(uri)
+	if err != nil {
+		return nil, err
+	}
+
+	reqURL := strings.TrimSuffix(v.Address, "/") + "/v1/" + strings.TrimPrefix(path, "/")
+	if u.RawQuery != "" {
+		reqURL += "?" + u.RawQuery
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("vault request: %w", err)
+	}
+	if v.Token != "" {
+		req.Header.Set("X-Vault-Token", v.Token)
+	}
+	if v.Namespace != "" {
+		req.Header.Set("X-Vault-Namespace", v.Namespace)
+	}
+
+	client := v.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("vault request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("vault response: %w", err)
+	}
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return nil, fmt.Errorf("vault path %q: %w", path, ErrNotFound)
+	case resp.StatusCode != http.StatusOK:
+		var errResp struct {
+			Errors []string `json:"errors"`
+		}
+		_ = json.Unmarshal(body, &errResp)
+		return nil, fmt.Errorf("vault path %q: %s: %s", path, resp.Status, strings.Join(errResp.Errors, "; "))
+	}
+
+	var secretResp struct {
+		Data map[string]any `json:"data"`
+	}
+	if err := json.Unmarshal(body, &secretResp); err != nil {
+		return nil, fmt.Errorf("vault response: %w", err)
+	}
+	data := secretResp.Data
+	// KV version 2 nests the secret's keys under data.data
+	if nested, ok := data["data"].(map[string]any); ok {
+		if _, isKV2 := data["metadata"]; isKV2 {
+			data = nested
+		}
+	}
+
+	key := u.Fragment
+	if key == "" {
+		if len(data) != 1 {
+			keys := make([]string, 0, len(data))
+			for k := range data {
+				keys = append(keys, k)
+			}
+			sort.Strings(keys)
+			return nil, fmt.Errorf("vault path %q has keys %s, select one with #key", path, strings.Join(keys, ", "))
+		}
+		for k := range data {
+			key = k
+		}
+	}
+	val, ok := data[key]
+	if !ok {
+		return nil, fmt.Errorf("vault path %q key %q: %w", path, key, ErrNotFound)
+	}
+	switch val := val.(type) {
+	case string:
+		return []byte(val), nil
+	default:
+		return json.Marshal(val)
+	}
+}
@@ -0,0 +1,68 @@
+package secretprovider
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// fakeVault serves responses in the format of a dev-mode Vault server with
+// the default KV version 2 engine mounted at secret/.
+func fakeVault(t *testing.T, token string) *httptest.Server {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != token {
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(`{"errors":["permission denied"]}`))
+			return
+		}
+		switch r.URL.Path {
+		case "/v1/secret/data/ci":
+			w.Write([]byte(`{"data":{"data":{"token":"hunter2","user":"ci"},"metadata":{"version":1}}}`))
+		case "/v1/secret/data/single":
+			w.Write([]byte(`{"data":{"data":{"password":"s3cret"},"metadata":{"version":3}}}`))
+		case "/v1/kv1/ci":
+			w.Write([]byte(`{"data":{"token":"legacy"}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"errors":[]}`))
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestVault(t *testing.T) {
+	ctx := context.Background()
+	srv := fakeVault(t, "root")
+	vault := &Vault{Address: srv.URL, Token: "root"}
+
+	dt, err := vault.GetSecret(ctx, "vault://secret/data/ci#token")
+	require.NoError(t, err)
+	require.Equal(t, "hunter2", string(dt))
+
+	dt, err = vault.GetSecret(ctx, "vault://secret/data/single")
+	require.NoError(t, err)
+	require.Equal(t, "s3cret", string(dt))
+
+	dt, err = vault.GetSecret(ctx, "vault://kv1/ci#token")
+	require.NoError(t, err)
+	require.Equal(t, "legacy", string(dt))
+
+	_, err = vault.GetSecret(ctx, "vault://secret/data/ci")
+	require.ErrorContains(t, err, "has keys token, user, select one with #key")
+
+	_, err = vault.GetSecret(ctx, "vault://secret/data/ci#nope")
+	require.ErrorIs(t, err, ErrNotFound)
+
+	_, err = vault.GetSecret(ctx, "vault://secret/data/nope#token")
+	require.ErrorIs(t, err, ErrNotFound)
+
+	_, err = (&Vault{Address: srv.URL, Token: "wrong"}).GetSecret(ctx, "vault://secret/data/ci#token")
+	require.ErrorContains(t, err, "permission denied")
+
+	_, err = (&Vault{}).GetSecret(ctx, "vault://secret/data/ci#token")
+	require.ErrorContains(t, err, "VAULT_ADDR")
+}
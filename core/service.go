@@ -1,7 +1,6 @@
 package core
 
 import (
-	"bytes"
 	"context"
 	"errors"
 	"fmt"
@@ -10,6 +9,7 @@ import (
 	"strconv"
 	"strings"
 	"syscall"
+	"time"
 
 	"github.com/dagger/dagger/core/pipeline"
 	"github.com/dagger/dagger/dagql"
@@ -18,14 +18,20 @@ import (
 	"github.com/dagger/dagger/engine/buildkit"
 	"github.com/dagger/dagger/network"
 	bkgw "github.com/moby/buildkit/frontend/gateway/client"
+	bkgwpb "github.com/moby/buildkit/frontend/gateway/pb"
 	"github.com/moby/buildkit/identity"
 	"github.com/moby/buildkit/solver/pb"
+	"github.com/moby/buildkit/util/bklog"
 	"github.com/vektah/gqlparser/v2/ast"
 	"github.com/vito/progrock"
 )
 
 const (
 	ShimEnableTTYEnvVar = "_DAGGER_ENABLE_TTY"
+
+	// ShimPath is where the shim is mounted in every container that runs a
+	// withExec, see cmd/shim.
+	ShimPath = "/_shim"
 )
 
 type Service struct {
@@ -44,6 +50,13 @@ type Service struct {
 	HostUpstream string `json:"reverse_tunnel_upstream_addr,omitempty"`
 	// HostPorts configures the port forwarding rules for the host.
 	HostPorts []PortForward `json:"host_ports,omitempty"`
+
+	// RestartPolicy configures whether a container service is restarted when
+	// its process exits.
+	RestartPolicy ServiceRestartPolicy `json:"restart_policy,omitempty"`
+	// MaxRestarts limits how many times the service is restarted. Zero means
+	// no limit.
+	MaxRestarts int `json:"max_restarts,omitempty"`
 }
 
 func (*Service) Type() *ast.Type {
@@ -183,6 +196,117 @@ func (svc *Service) Stop(ctx context.Context, id *idproto.ID, kill bool) error {
 	return svc.Query.Services.Stop(ctx, id, kill)
 }
 
+// WithRestartPolicy configures whether the service is restarted when its
+// process exits.
+func (svc *Service) WithRestartPolicy(policy ServiceRestartPolicy, maxRestarts int) (*Service, error) {
+	if maxRestarts < 0 {
+		return nil, fmt.Errorf("invalid max restarts %d: must not be negative", maxRestarts)
+	}
+	if svc.Container == nil && policy != ServiceRestartNever {
+		return nil, fmt.Errorf("only container services can be restarted")
+	}
+	svc = svc.Clone()
+	svc.RestartPolicy = policy
+	svc.MaxRestarts = maxRestarts
+	return svc, nil
+}
+
+// Restart returns whether the service should be restarted after exiting with
+// the given error, having already been restarted the given number of times.
+func (svc *Service) Restart(exitErr error, restarts int) bool {
+	if svc.MaxRestarts > 0 && restarts >= svc.MaxRestarts {
+		return false
+	}
+	switch svc.RestartPolicy {
+	case ServiceRestartAlways:
+		return true
+	case ServiceRestartOnFailure:
+		return exitErr != nil
+	default:
+		return false
+	}
+}
+
+// Status returns the status of the current or last run of the service.
+func (svc *Service) Status(ctx context.Context, id *idproto.ID) (ServiceStatus, error) {
+	running, starting, err := svc.Query.Services.Lookup(ctx, id)
+	if err != nil {
+		return "", err
+	}
+	switch {
+	case starting:
+		return ServiceStarting, nil
+	case running == nil:
+		return ServiceNotStarted, nil
+	case running.Exited != nil:
+		if exited, _ := running.Exited(); exited {
+			return ServiceExited, nil
+		}
+	}
+	return ServiceHealthy, nil
+}
+
+// ExitCode returns the exit code of the service's process once it has exited.
+func (svc *Service) ExitCode(ctx context.Context, id *idproto.ID) (int, error) {
+	running, err := svc.lastRun(ctx, id)
+	if err != nil {
+		return 0, err
+	}
+	if running.Exited == nil {
+		return 0, fmt.Errorf("service %s does not run a process", running.Host)
+	}
+	exited, exitErr := running.Exited()
+	if !exited {
+		return 0, fmt.Errorf("service %s has not exited", running.Host)
+	}
+	if exitErr == nil {
+		return 0, nil
+	}
+	var procErr *bkgwpb.ExitError
+	if errors.As(exitErr, &procErr) && procErr.ExitCode != bkgwpb.UnknownExitStatus {
+		return int(procErr.ExitCode), nil
+	}
+	return 0, fmt.Errorf("service %s exited without an exit code: %w", running.Host, exitErr)
+}
+
+// Logs returns the output of the current or last run of the service. Lines
+// written before since are skipped unless it's zero, and only the last tail
+// lines are returned unless tail is negative. If follow is true, it waits for
+// the service to exit first.
+func (svc *Service) Logs(ctx context.Context, id *idproto.ID, since time.Time, tail int, follow bool) (string, error) {
+	running, err := svc.lastRun(ctx, id)
+	if err != nil {
+		return "", err
+	}
+	if running.Logs == nil {
+		return "", fmt.Errorf("service %s does not run a process", running.Host)
+	}
+	if follow {
+		// the exit status doesn't matter, only that there's no more output
+		_ = running.Wait(ctx)
+		if err := ctx.Err(); err != nil {
+			return "", err
+		}
+	}
+	return running.Logs.Lines(since, tail), nil
+}
+
+// lastRun returns the current or last run of the service, waiting for it if
+// it's starting.
+func (svc *Service) lastRun(ctx context.Context, id *idproto.ID) (*RunningService, error) {
+	running, starting, err := svc.Query.Services.Lookup(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if starting {
+		return svc.Query.Services.Get(ctx, id)
+	}
+	if running == nil {
+		return nil, fmt.Errorf("service has not been started")
+	}
+	return running, nil
+}
+
 func (svc *Service) Start(
 	ctx context.Context,
 	id *idproto.ID,
@@ -277,8 +401,6 @@ func (svc *Service) startContainer(
 
 	bk := svc.Query.Buildkit
 
-	health := newHealth(bk, fullHost, ctr.Ports)
-
 	pbPlatform := pb.PlatformFromSpec(ctr.Platform.Spec())
 
 	mounts := make([]bkgw.Mount, len(execOp.Mounts))
@@ -331,11 +453,6 @@ func (svc *Service) startContainer(
 		}
 	}()
 
-	checked := make(chan error, 1)
-	go func() {
-		checked <- health.Check(ctx)
-	}()
-
 	if execOp.Meta.ProxyEnv == nil {
 		execOp.Meta.ProxyEnv = &pb.ProxyEnv{}
 	}
@@ -357,7 +474,8 @@ func (svc *Service) startContainer(
 		env = append(env, ShimEnableTTYEnvVar+"=1")
 	}
 
-	outBuf := new(bytes.Buffer)
+	logs := NewServiceLogs()
+	stdoutLog, stderrLog := logs.Writer(), logs.Writer()
 	var stdinCtr, stdoutClient, stderrClient io.ReadCloser
 	var stdinClient, stdoutCtr, stderrCtr io.WriteCloser
 	if forwardStdin != nil {
@@ -367,16 +485,16 @@ func (svc *Service) startContainer(
 	if forwardStdout != nil {
 		stdoutClient, stdoutCtr = io.Pipe()
 	} else {
-		stdoutCtr = nopCloser{io.MultiWriter(vtx.Stdout(), outBuf)}
+		stdoutCtr = nopCloser{io.MultiWriter(vtx.Stdout(), stdoutLog)}
 	}
 
 	if forwardStderr != nil {
 		stderrClient, stderrCtr = io.Pipe()
 	} else {
-		stderrCtr = nopCloser{io.MultiWriter(vtx.Stderr(), outBuf)}
+		stderrCtr = nopCloser{io.MultiWriter(vtx.Stderr(), stderrLog)}
 	}
 
-	svcProc, err := gc.Start(ctx, bkgw.StartRequest{
+	svcReq := bkgw.StartRequest{
 		Args:         execOp.Meta.Args,
 		Env:          env,
 		Cwd:          execOp.Meta.Cwd,
@@ -387,11 +505,25 @@ func (svc *Service) startContainer(
 		Stdout:       stdoutCtr,
 		Stderr:       stderrCtr,
 		SecurityMode: execOp.Security,
-	})
+	}
+	svcProc, err := gc.Start(ctx, svcReq)
 	if err != nil {
 		return nil, fmt.Errorf("start container: %w", err)
 	}
 
+	var health healthChecker
+	if ctr.Healthcheck != nil {
+		// probes are run in the service's container, so it must be started first
+		health = newExecHealth(ctr.Healthcheck, gc, svcReq)
+	} else {
+		health = newHealth(bk, fullHost, ctr.Ports)
+	}
+
+	checked := make(chan error, 1)
+	go func() {
+		checked <- health.Check(ctx)
+	}()
+
 	if forwardStdin != nil {
 		forwardStdin(stdinClient, svcProc)
 	}
@@ -415,6 +547,8 @@ func (svc *Service) startContainer(
 			if stderrClient != nil {
 				stderrClient.Close()
 			}
+			stdoutLog.Close()
+			stderrLog.Close()
 			close(exited)
 		}()
 
@@ -434,6 +568,12 @@ func (svc *Service) startContainer(
 	}()
 
 	stopSvc := func(ctx context.Context, force bool) error {
+		select {
+		case <-exited:
+			// already exited on its own; nothing to signal
+			return nil
+		default:
+		}
 		sig := syscall.SIGTERM
 		if force {
 			sig = syscall.SIGKILL
@@ -458,28 +598,45 @@ func (svc *Service) startContainer(
 		}
 	}
 
+	exitedSvc := func() (bool, error) {
+		select {
+		case <-exited:
+			return true, exitErr
+		default:
+			return false, nil
+		}
+	}
+
+	running = &RunningService{
+		Service: svc,
+		Host:    fullHost,
+		Ports:   ctr.Ports,
+		Key: ServiceKey{
+			Digest:   dig,
+			ServerID: clientMetadata.ServerID,
+		},
+		Stop:   stopSvc,
+		Wait:   waitSvc,
+		Exited: exitedSvc,
+		Logs:   logs,
+	}
+
+	// NB: the failed run is returned along with any error from here on, so
+	// that its logs and exit code can still be inspected
 	select {
 	case err := <-checked:
 		if err != nil {
-			return nil, fmt.Errorf("health check errored: %w", err)
+			if stopErr := stopSvc(ctx, true); stopErr != nil {
+				bklog.G(ctx).WithError(stopErr).Warnf("failed to stop unhealthy service %s", host)
+			}
+			return running, fmt.Errorf("health check errored: %w", err)
 		}
-
-		return &RunningService{
-			Service: svc,
-			Host:    fullHost,
-			Ports:   ctr.Ports,
-			Key: ServiceKey{
-				Digest:   dig,
-				ServerID: clientMetadata.ServerID,
-			},
-			Stop: stopSvc,
-			Wait: waitSvc,
-		}, nil
+		return running, nil
 	case <-exited:
 		if exitErr != nil {
-			return nil, fmt.Errorf("exited: %w\noutput: %s", exitErr, outBuf.String())
+			return running, fmt.Errorf("exited: %w\noutput: %s", exitErr, logs.String())
 		}
-		return nil, fmt.Errorf("service exited before healthcheck")
+		return running, fmt.Errorf("service exited before healthcheck")
 	}
 }
 
@@ -681,6 +838,74 @@ func (svc *Service) startReverseTunnel(ctx context.Context, id *idproto.ID) (run
 	}
 }
 
+// ServiceRestartPolicy is a GraphQL enum type.
+type ServiceRestartPolicy string
+
+var ServiceRestartPolicies = dagql.NewEnum[ServiceRestartPolicy]()
+
+var (
+	ServiceRestartNever = ServiceRestartPolicies.Register("NEVER",
+		"Never restart the service")
+	ServiceRestartOnFailure = ServiceRestartPolicies.Register("ON_FAILURE",
+		"Restart the service if it exits with a non-zero exit code")
+	ServiceRestartAlways = ServiceRestartPolicies.Register("ALWAYS",
+		"Restart the service whenever it exits, unless it was stopped")
+)
+
+func (policy ServiceRestartPolicy) Type() *ast.Type {
+	return &ast.Type{
+		NamedType: "ServiceRestartPolicy",
+		NonNull:   true,
+	}
+}
+
+func (policy ServiceRestartPolicy) TypeDescription() string {
+	return "When to restart a service whose process exits."
+}
+
+func (policy ServiceRestartPolicy) Decoder() dagql.InputDecoder {
+	return ServiceRestartPolicies
+}
+
+func (policy ServiceRestartPolicy) ToLiteral() *idproto.Literal {
+	return ServiceRestartPolicies.Literal(policy)
+}
+
+// ServiceStatus is a GraphQL enum type.
+type ServiceStatus string
+
+var ServiceStatuses = dagql.NewEnum[ServiceStatus]()
+
+var (
+	ServiceNotStarted = ServiceStatuses.Register("NOT_STARTED",
+		"The service has not been started")
+	ServiceStarting = ServiceStatuses.Register("STARTING",
+		"The service is starting and waiting for its health checks to pass")
+	ServiceHealthy = ServiceStatuses.Register("HEALTHY",
+		"The service is running and its health checks have passed")
+	ServiceExited = ServiceStatuses.Register("EXITED",
+		"The service has exited or been stopped")
+)
+
+func (status ServiceStatus) Type() *ast.Type {
+	return &ast.Type{
+		NamedType: "ServiceStatus",
+		NonNull:   true,
+	}
+}
+
+func (status ServiceStatus) TypeDescription() string {
+	return "The status of a service."
+}
+
+func (status ServiceStatus) Decoder() dagql.InputDecoder {
+	return ServiceStatuses
+}
+
+func (status ServiceStatus) ToLiteral() *idproto.Literal {
+	return ServiceStatuses.Literal(status)
+}
+
 type ServiceBindings []ServiceBinding
 
 type ServiceBinding struct {
@@ -688,6 +913,68 @@ type ServiceBinding struct {
 	Service  *Service `json:"service"`
 	Hostname string   `json:"hostname"`
 	Aliases  AliasSet `json:"aliases"`
+
+	// DependsOn lists the hostnames or aliases of other bindings that must be
+	// started before this one.
+	DependsOn AliasSet `json:"dependsOn,omitempty"`
+}
+
+// dependencies returns the indices of the bindings each binding depends on.
+func (bnds ServiceBindings) dependencies() ([][]int, error) {
+	names := map[string]int{}
+	for i, bnd := range bnds {
+		names[bnd.Hostname] = i
+		for _, alias := range bnd.Aliases {
+			names[alias] = i
+		}
+	}
+
+	deps := make([][]int, len(bnds))
+	for i, bnd := range bnds {
+		for _, name := range bnd.DependsOn {
+			dep, found := names[name]
+			if !found {
+				return nil, fmt.Errorf("service %s depends on unknown service %q", bnd.Hostname, name)
+			}
+			if dep == i {
+				return nil, fmt.Errorf("service %s depends on itself", bnd.Hostname)
+			}
+			deps[i] = append(deps[i], dep)
+		}
+	}
+
+	// detect cycles, which would otherwise deadlock
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := make([]int, len(bnds))
+	var visit func(i int, path []string) error
+	visit = func(i int, path []string) error {
+		path = append(path, bnds[i].Hostname)
+		switch state[i] {
+		case visiting:
+			return fmt.Errorf("service dependency cycle: %s", strings.Join(path, " -> "))
+		case visited:
+			return nil
+		}
+		state[i] = visiting
+		for _, dep := range deps[i] {
+			if err := visit(dep, path); err != nil {
+				return err
+			}
+		}
+		state[i] = visited
+		return nil
+	}
+	for i := range bnds {
+		if err := visit(i, nil); err != nil {
+			return nil, err
+		}
+	}
+
+	return deps, nil
 }
 
 type AliasSet []string
@@ -736,6 +1023,7 @@ func (bndp *ServiceBindings) Merge(other ServiceBindings) {
 		}
 
 		merged[i].Aliases = merged[i].Aliases.Union(bnd.Aliases)
+		merged[i].DependsOn = merged[i].DependsOn.Union(bnd.DependsOn)
 	}
 
 	*bndp = merged
@@ -0,0 +1,56 @@
+package core
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestServiceBindingsDependencies(t *testing.T) {
+	bnds := ServiceBindings{
+		{Hostname: "abc", Aliases: AliasSet{"api"}, DependsOn: AliasSet{"db", "cache"}},
+		{Hostname: "def", Aliases: AliasSet{"db"}},
+		{Hostname: "ghi", Aliases: AliasSet{"cache"}, DependsOn: AliasSet{"def"}},
+	}
+	deps, err := bnds.dependencies()
+	require.NoError(t, err)
+	require.Equal(t, [][]int{{1, 2}, nil, {1}}, deps)
+
+	bnds[1].DependsOn = AliasSet{"api"}
+	_, err = bnds.dependencies()
+	require.ErrorContains(t, err, "service dependency cycle: abc -> def -> abc")
+
+	bnds[1].DependsOn = AliasSet{"db"}
+	_, err = bnds.dependencies()
+	require.ErrorContains(t, err, "service def depends on itself")
+
+	bnds[1].DependsOn = AliasSet{"queue"}
+	_, err = bnds.dependencies()
+	require.ErrorContains(t, err, `service def depends on unknown service "queue"`)
+}
+
+func TestServiceRestart(t *testing.T) {
+	exitErr := errors.New("exit code: 1")
+
+	svc := &Service{Container: &Container{}}
+	require.False(t, svc.Restart(exitErr, 0))
+
+	svc, err := svc.WithRestartPolicy(ServiceRestartOnFailure, 2)
+	require.NoError(t, err)
+	require.True(t, svc.Restart(exitErr, 0))
+	require.True(t, svc.Restart(exitErr, 1))
+	require.False(t, svc.Restart(exitErr, 2))
+	require.False(t, svc.Restart(nil, 0))
+
+	svc, err = svc.WithRestartPolicy(ServiceRestartAlways, 0)
+	require.NoError(t, err)
+	require.True(t, svc.Restart(nil, 100))
+	require.True(t, svc.Restart(exitErr, 100))
+
+	_, err = svc.WithRestartPolicy(ServiceRestartAlways, -1)
+	require.Error(t, err)
+
+	_, err = (&Service{HostUpstream: "localhost"}).WithRestartPolicy(ServiceRestartAlways, 0)
+	require.ErrorContains(t, err, "only container services can be restarted")
+}
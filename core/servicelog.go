@@ -0,0 +1,110 @@
+package core
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"sync"
+	"time"
+)
+
+// maxServiceLogBytes bounds the output kept for each run of a service; the
+// oldest lines are dropped once it's exceeded.
+const maxServiceLogBytes = 10 * 1024 * 1024
+
+// ServiceLogs records the timestamped output of a running service.
+type ServiceLogs struct {
+	mu    sync.Mutex
+	lines []serviceLogLine
+	size  int
+}
+
+type serviceLogLine struct {
+	time time.Time
+	data string
+}
+
+func NewServiceLogs() *ServiceLogs {
+	return &ServiceLogs{}
+}
+
+// Writer returns a writer that records each complete line written to it.
+// Every stream should use its own writer so that partial lines from different
+// streams aren't mixed up. Closing the writer records any trailing partial
+// line.
+func (logs *ServiceLogs) Writer() io.WriteCloser {
+	return &serviceLogWriter{logs: logs}
+}
+
+func (logs *ServiceLogs) add(data string) {
+	logs.mu.Lock()
+	defer logs.mu.Unlock()
+	logs.lines = append(logs.lines, serviceLogLine{
+		time: time.Now(),
+		data: data,
+	})
+	logs.size += len(data)
+	for logs.size > maxServiceLogBytes && len(logs.lines) > 1 {
+		// dropped lines are freed once append reallocates
+		logs.size -= len(logs.lines[0].data)
+		logs.lines = logs.lines[1:]
+	}
+}
+
+// Lines returns the recorded output, excluding lines written before since
+// unless it's zero, and limited to the last tail lines unless tail is
+// negative.
+func (logs *ServiceLogs) Lines(since time.Time, tail int) string {
+	logs.mu.Lock()
+	defer logs.mu.Unlock()
+	lines := logs.lines
+	if !since.IsZero() {
+		for len(lines) > 0 && lines[0].time.Before(since) {
+			lines = lines[1:]
+		}
+	}
+	if tail >= 0 && len(lines) > tail {
+		lines = lines[len(lines)-tail:]
+	}
+	var out strings.Builder
+	for _, line := range lines {
+		out.WriteString(line.data)
+	}
+	return out.String()
+}
+
+// String returns all of the recorded output.
+func (logs *ServiceLogs) String() string {
+	return logs.Lines(time.Time{}, -1)
+}
+
+type serviceLogWriter struct {
+	logs    *ServiceLogs
+	mu      sync.Mutex
+	partial []byte
+}
+
+func (w *serviceLogWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.partial = append(w.partial, p...)
+	for {
+		i := bytes.IndexByte(w.partial, '\n')
+		if i < 0 {
+			break
+		}
+		w.logs.add(string(w.partial[:i+1]))
+		w.partial = w.partial[i+1:]
+	}
+	return len(p), nil
+}
+
+func (w *serviceLogWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if len(w.partial) > 0 {
+		w.logs.add(string(w.partial))
+		w.partial = nil
+	}
+	return nil
+}
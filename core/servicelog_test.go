@@ -0,0 +1,52 @@
+package core
+
+import (
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestServiceLogs(t *testing.T) {
+	logs := NewServiceLogs()
+	stdout, stderr := logs.Writer(), logs.Writer()
+
+	_, err := io.WriteString(stdout, "one\ntw")
+	require.NoError(t, err)
+	_, err = io.WriteString(stderr, "err one\n")
+	require.NoError(t, err)
+	_, err = io.WriteString(stdout, "o\nthree")
+	require.NoError(t, err)
+	require.Equal(t, "one\nerr one\ntwo\n", logs.String())
+
+	require.NoError(t, stdout.Close())
+	require.Equal(t, "one\nerr one\ntwo\nthree", logs.String())
+
+	require.Equal(t, "two\nthree", logs.Lines(time.Time{}, 2))
+	require.Equal(t, "", logs.Lines(time.Time{}, 0))
+	require.Equal(t, "", logs.Lines(time.Now().Add(time.Minute), -1))
+
+	since := time.Now()
+	_, err = io.WriteString(stderr, "four\n")
+	require.NoError(t, err)
+	require.Equal(t, "four\n", logs.Lines(since, -1))
+}
+
+func TestServiceLogsLimit(t *testing.T) {
+	logs := NewServiceLogs()
+	w := logs.Writer()
+
+	line := strings.Repeat("x", 1023) + "\n"
+	for i := 0; i < maxServiceLogBytes/len(line)+10; i++ {
+		_, err := io.WriteString(w, line)
+		require.NoError(t, err)
+	}
+	_, err := io.WriteString(w, "last\n")
+	require.NoError(t, err)
+
+	out := logs.String()
+	require.LessOrEqual(t, len(out), maxServiceLogBytes)
+	require.True(t, strings.HasSuffix(out, "last\n"))
+}
@@ -28,6 +28,11 @@ const (
 	// TerminateGracePeriod is an arbitrary amount of time between when a service is
 	// sent a graceful stop (SIGTERM) and when it is sent an immediate stop (SIGKILL).
 	TerminateGracePeriod = 10 * time.Second
+
+	// MaxRestartDelay is the longest amount of time to wait before restarting
+	// a service that has exited. The delay starts at 100ms and doubles with
+	// each restart.
+	MaxRestartDelay = 10 * time.Second
 )
 
 // Services manages the lifecycle of services, ensuring the same service only
@@ -37,7 +42,10 @@ type Services struct {
 	starting map[ServiceKey]*sync.WaitGroup
 	running  map[ServiceKey]*RunningService
 	bindings map[ServiceKey]int
-	l        sync.Mutex
+	// stopped holds the last run of each service that has been stopped, so
+	// that its logs and exit code can still be inspected.
+	stopped map[ServiceKey]*RunningService
+	l       sync.Mutex
 }
 
 // RunningService represents a service that is actively running.
@@ -68,6 +76,19 @@ type RunningService struct {
 
 	// Block until the service has exited or the provided context is canceled.
 	Wait func(ctx context.Context) error
+
+	// Exited returns whether the service has exited and the error it exited
+	// with, if any, without blocking. It is nil for services that don't run a
+	// process, i.e. tunnels.
+	Exited func() (bool, error)
+
+	// Logs records the output of the service's process. It is nil for
+	// services that don't run a process.
+	Logs *ServiceLogs
+
+	// stopping is set once the service has been asked to stop, so that it's
+	// not restarted. It is guarded by Services.l.
+	stopping bool
 }
 
 // ServiceKey is a unique identifier for a service.
@@ -83,6 +104,7 @@ func NewServices(bk *buildkit.Client) *Services {
 		starting: map[ServiceKey]*sync.WaitGroup{},
 		running:  map[ServiceKey]*RunningService{},
 		bindings: map[ServiceKey]int{},
+		stopped:  map[ServiceKey]*RunningService{},
 	}
 }
 
@@ -125,6 +147,47 @@ func (ss *Services) Get(ctx context.Context, id *idproto.ID) (*RunningService, e
 	}
 }
 
+// Lookup returns the current or last run of the given service without
+// waiting for it to start. If the service is starting, starting is true. If
+// it has never been started, running is nil.
+func (ss *Services) Lookup(ctx context.Context, id *idproto.ID) (running *RunningService, starting bool, err error) {
+	clientMetadata, err := engine.ClientMetadataFromContext(ctx)
+	if err != nil {
+		return nil, false, err
+	}
+
+	dig, err := id.Digest()
+	if err != nil {
+		return nil, false, err
+	}
+
+	key := ServiceKey{
+		Digest:   dig,
+		ServerID: clientMetadata.ServerID,
+	}
+
+	ss.l.Lock()
+	defer ss.l.Unlock()
+	if _, isStarting := ss.starting[key]; isStarting {
+		return nil, true, nil
+	}
+	if running, isRunning := ss.running[key]; isRunning {
+		return running, false, nil
+	}
+	return ss.stopped[key], false, nil
+}
+
+// Restartable is implemented by services that may be restarted after their
+// process exits.
+type Restartable interface {
+	// Restart returns whether the service should be restarted after exiting
+	// with the given error, having been restarted the given number of times.
+	Restart(exitErr error, restarts int) bool
+}
+
+// Startable is implemented by services that can be started. If a service's
+// process runs but fails before it's ready, the failed run is returned along
+// with the error.
 type Startable interface {
 	Start(
 		ctx context.Context,
@@ -193,6 +256,9 @@ dance:
 		stop()
 		ss.l.Lock()
 		delete(ss.starting, key)
+		if running != nil {
+			ss.stopped[key] = running
+		}
 		ss.l.Unlock()
 		return nil, err
 	}
@@ -205,11 +271,75 @@ dance:
 
 	_ = stop // leave it running
 
+	if restartable, ok := svc.(Restartable); ok && running.Wait != nil {
+		go ss.supervise(svcCtx, id, key, svc, restartable, running)
+	}
+
 	return running, nil
 }
 
+// supervise restarts a service each time it exits for as long as its restart
+// policy allows, until it's stopped.
+func (ss *Services) supervise(ctx context.Context, id *idproto.ID, key ServiceKey, svc Startable, restartable Restartable, running *RunningService) {
+	for restarts := 0; ; restarts++ {
+		exitErr := running.Wait(ctx)
+
+		ss.l.Lock()
+		if running.stopping || ss.running[key] != running || !restartable.Restart(exitErr, restarts) {
+			ss.l.Unlock()
+			return
+		}
+		// mark it as starting so that Get and Start wait for the restart
+		starting := new(sync.WaitGroup)
+		starting.Add(1)
+		ss.starting[key] = starting
+		delete(ss.running, key)
+		ss.stopped[key] = running
+		ss.l.Unlock()
+
+		bklog.G(ctx).Debugf("restarting service %s (restart %d): %v", running.Host, restarts+1, exitErr)
+		time.Sleep(restartDelay(restarts))
+
+		restarted, err := svc.Start(ctx, id, false, nil, nil, nil)
+
+		ss.l.Lock()
+		delete(ss.starting, key)
+		if err != nil {
+			if restarted != nil {
+				ss.stopped[key] = restarted
+			}
+			delete(ss.bindings, key)
+			ss.l.Unlock()
+			starting.Done()
+			bklog.G(ctx).WithError(err).Warnf("failed to restart service %s", running.Host)
+			return
+		}
+		ss.running[key] = restarted
+		detached := ss.bindings[key] <= 0
+		ss.l.Unlock()
+		starting.Done()
+
+		if detached {
+			// everything detached from the service while it was restarting
+			ss.stopGraceful(ctx, restarted, TerminateGracePeriod)
+			return
+		}
+
+		running = restarted
+	}
+}
+
+func restartDelay(restarts int) time.Duration {
+	delay := 100 * time.Millisecond
+	for i := 0; i < restarts && delay < MaxRestartDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, MaxRestartDelay)
+}
+
 // StartBindings starts each of the bound services in parallel and returns a
-// function that will detach from all of them after 10 seconds.
+// function that will detach from all of them after 10 seconds. Services that
+// depend on other bindings are started once their dependencies are running.
 func (ss *Services) StartBindings(ctx context.Context, bindings ServiceBindings) (_ func(), _ []*RunningService, err error) {
 	running := make([]*RunningService, len(bindings))
 	detachOnce := sync.Once{}
@@ -226,11 +356,30 @@ func (ss *Services) StartBindings(ctx context.Context, bindings ServiceBindings)
 		})
 	}
 
+	deps, err := bindings.dependencies()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// closed once each binding has either started or failed to start
+	done := make([]chan struct{}, len(bindings))
+	for i := range done {
+		done[i] = make(chan struct{})
+	}
+
 	// NB: don't use errgroup.WithCancel; we don't want to cancel on Wait
 	eg := new(errgroup.Group)
 	for i, bnd := range bindings {
 		i, bnd := i, bnd
 		eg.Go(func() error {
+			defer close(done[i])
+			for _, dep := range deps[i] {
+				<-done[dep]
+				if running[dep] == nil {
+					// the dependency's own error is returned instead
+					return nil
+				}
+			}
 			runningSvc, err := ss.Start(ctx, bnd.ID, bnd.Service)
 			if err != nil {
 				return fmt.Errorf("start %s (%s): %w", bnd.Hostname, bnd.Aliases, err)
@@ -322,7 +471,18 @@ func (ss *Services) StopClientServices(ctx context.Context, client *engine.Clien
 		})
 	}
 
-	return eg.Wait()
+	err := eg.Wait()
+
+	// the client is gone, so nothing can inspect its stopped services anymore
+	ss.l.Lock()
+	for key := range ss.stopped {
+		if key.ServerID == client.ServerID {
+			delete(ss.stopped, key)
+		}
+	}
+	ss.l.Unlock()
+
+	return err
 }
 
 // Detach detaches from the given service. If the service is not running, it is
@@ -331,6 +491,12 @@ func (ss *Services) StopClientServices(ctx context.Context, client *engine.Clien
 func (ss *Services) Detach(ctx context.Context, svc *RunningService) {
 	ss.l.Lock()
 
+	if ss.bindings[svc.Key] > 0 {
+		// NB: the service may be restarting, in which case it's stopped once
+		// it's back up if this was the last binding
+		ss.bindings[svc.Key]--
+	}
+
 	running, found := ss.running[svc.Key]
 	if !found {
 		ss.l.Unlock()
@@ -338,8 +504,6 @@ func (ss *Services) Detach(ctx context.Context, svc *RunningService) {
 		return
 	}
 
-	ss.bindings[svc.Key]--
-
 	if ss.bindings[svc.Key] > 0 {
 		ss.l.Unlock()
 		// detached, but other instances still active
@@ -353,6 +517,7 @@ func (ss *Services) Detach(ctx context.Context, svc *RunningService) {
 }
 
 func (ss *Services) stop(ctx context.Context, running *RunningService, force bool) error {
+	ss.markStopping(running)
 	err := running.Stop(ctx, force)
 	if err != nil {
 		return fmt.Errorf("stop: %w", err)
@@ -361,15 +526,19 @@ func (ss *Services) stop(ctx context.Context, running *RunningService, force boo
 	ss.l.Lock()
 	delete(ss.bindings, running.Key)
 	delete(ss.running, running.Key)
+	ss.stopped[running.Key] = running
 	ss.l.Unlock()
 
 	return nil
 }
 
 func (ss *Services) stopGraceful(ctx context.Context, running *RunningService, timeout time.Duration) error {
+	ss.markStopping(running)
+
 	// attempt to gentle stop within a timeout
 	cause := errors.New("service did not terminate")
-	ctx2, _ := context.WithTimeoutCause(ctx, timeout, cause)
+	ctx2, cancel := context.WithTimeoutCause(ctx, timeout, cause)
+	defer cancel()
 	err := running.Stop(ctx2, false)
 	if context.Cause(ctx2) == cause {
 		// service didn't terminate within timeout, so force it to stop
@@ -382,6 +551,13 @@ func (ss *Services) stopGraceful(ctx context.Context, running *RunningService, t
 	ss.l.Lock()
 	delete(ss.bindings, running.Key)
 	delete(ss.running, running.Key)
+	ss.stopped[running.Key] = running
 	ss.l.Unlock()
 	return nil
 }
+
+func (ss *Services) markStopping(running *RunningService) {
+	ss.l.Lock()
+	running.stopping = true
+	ss.l.Unlock()
+}
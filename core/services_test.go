@@ -280,6 +280,80 @@ func TestServicesStartConcurrentSadThenHappy(t *testing.T) {
 	require.Equal(t, 2, stub.Starts())
 }
 
+func TestServicesStartSadInspectable(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	ctx = engine.ContextWithClientMetadata(ctx, &engine.ClientMetadata{
+		ClientID: "fake-client",
+	})
+
+	stubClient := new(buildkit.Client)
+	services := core.NewServices(stubClient)
+
+	stub := newStartable("fake")
+
+	expected, expectedErr := stub.FailRunning()
+
+	_, err := services.Start(ctx, stub.ID(), stub)
+	require.Equal(t, expectedErr, err)
+
+	_, err = services.Get(ctx, stub.ID())
+	require.Error(t, err)
+
+	// the failed run can still be inspected for its logs and exit code
+	running, starting, err := services.Lookup(ctx, stub.ID())
+	require.NoError(t, err)
+	require.False(t, starting)
+	require.Equal(t, expected, running)
+}
+
+func TestServicesLookup(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	ctx = engine.ContextWithClientMetadata(ctx, &engine.ClientMetadata{
+		ClientID: "fake-client",
+	})
+
+	stubClient := new(buildkit.Client)
+	services := core.NewServices(stubClient)
+
+	stub := newStartable("fake")
+
+	running, starting, err := services.Lookup(ctx, stub.ID())
+	require.NoError(t, err)
+	require.False(t, starting)
+	require.Nil(t, running)
+
+	dig, err := stub.ID().Digest()
+	require.NoError(t, err)
+
+	expected := stub.Succeed()
+	expected.Key = core.ServiceKey{Digest: dig}
+	expected.Stop = func(context.Context, bool) error { return nil }
+
+	_, err = services.Start(ctx, stub.ID(), stub)
+	require.NoError(t, err)
+
+	running, starting, err = services.Lookup(ctx, stub.ID())
+	require.NoError(t, err)
+	require.False(t, starting)
+	require.Equal(t, expected, running)
+
+	err = services.Stop(ctx, stub.ID(), false)
+	require.NoError(t, err)
+
+	_, err = services.Get(ctx, stub.ID())
+	require.Error(t, err)
+
+	// the last run can still be inspected after it has stopped
+	running, starting, err = services.Lookup(ctx, stub.ID())
+	require.NoError(t, err)
+	require.False(t, starting)
+	require.Equal(t, expected, running)
+}
+
 type fakeStartable struct {
 	name   string
 	digest digest.Digest
@@ -344,3 +418,19 @@ func (f *fakeStartable) Fail() error {
 	}
 	return err
 }
+
+func (f *fakeStartable) FailRunning() (*core.RunningService, error) {
+	running := &core.RunningService{
+		Key: core.ServiceKey{
+			Digest:   f.digest,
+			ServerID: "doesnt-matter",
+		},
+		Host: f.name + "-host",
+	}
+	err := errors.New("oh no")
+	f.startResults <- startResult{
+		Started: running,
+		Failed:  err,
+	}
+	return running, err
+}
@@ -0,0 +1,107 @@
+package core
+
+import (
+	"os"
+	"path"
+
+	fstypes "github.com/tonistiigi/fsutil/types"
+	"github.com/vektah/gqlparser/v2/ast"
+
+	"github.com/dagger/dagger/dagql"
+	"github.com/dagger/dagger/dagql/idproto"
+)
+
+// Stat is the metadata of a file, directory or symlink.
+type Stat struct {
+	Name       string   `field:"true" doc:"The base name of the entry."`
+	FileType   FileType `field:"true" doc:"The type of the entry."`
+	Mode       int      `field:"true" doc:"The permission bits of the entry, including setuid, setgid and sticky bits (e.g., 0755)."`
+	UID        int      `field:"true" name:"uid" doc:"The ID of the user that owns the entry."`
+	GID        int      `field:"true" name:"gid" doc:"The ID of the group that owns the entry."`
+	Size       int      `field:"true" doc:"The size of the entry, in bytes."`
+	Mtime      int      `field:"true" doc:"The last modification time of the entry, in seconds following Unix epoch."`
+	LinkTarget string   `field:"true" doc:"The path a symlink points to, or an empty string for other types of entries."`
+}
+
+func (Stat) Type() *ast.Type {
+	return &ast.Type{
+		NamedType: "Stat",
+		NonNull:   true,
+	}
+}
+
+func (Stat) TypeDescription() string {
+	return "The metadata of a file, directory or symlink."
+}
+
+// NewStat converts a stat returned by buildkit.
+func NewStat(st *fstypes.Stat) Stat {
+	mode := os.FileMode(st.Mode)
+
+	perm := int(mode.Perm())
+	if mode&os.ModeSetuid != 0 {
+		perm |= 0o4000
+	}
+	if mode&os.ModeSetgid != 0 {
+		perm |= 0o2000
+	}
+	if mode&os.ModeSticky != 0 {
+		perm |= 0o1000
+	}
+
+	fileType := FileTypeOther
+	switch {
+	case mode.IsRegular():
+		fileType = FileTypeRegular
+	case mode.IsDir():
+		fileType = FileTypeDir
+	case mode&os.ModeSymlink != 0:
+		fileType = FileTypeSymlink
+	}
+
+	return Stat{
+		Name:       path.Base(st.Path),
+		FileType:   fileType,
+		Mode:       perm,
+		UID:        int(st.Uid),
+		GID:        int(st.Gid),
+		Size:       int(st.Size_),
+		Mtime:      int(st.ModTime / 1e9),
+		LinkTarget: st.Linkname,
+	}
+}
+
+// FileType is a GraphQL enum type.
+type FileType string
+
+var FileTypes = dagql.NewEnum[FileType]()
+
+var (
+	FileTypeRegular = FileTypes.Register("REGULAR",
+		"A regular file")
+	FileTypeDir = FileTypes.Register("DIR",
+		"A directory")
+	FileTypeSymlink = FileTypes.Register("SYMLINK",
+		"A symbolic link")
+	FileTypeOther = FileTypes.Register("OTHER",
+		"Any other type of entry, like a device, pipe or socket")
+)
+
+func (typ FileType) Type() *ast.Type {
+	return &ast.Type{
+		NamedType: "FileType",
+		NonNull:   true,
+	}
+}
+
+func (typ FileType) TypeDescription() string {
+	return "The type of a filesystem entry."
+}
+
+func (typ FileType) Decoder() dagql.InputDecoder {
+	return FileTypes
+}
+
+func (typ FileType) ToLiteral() *idproto.Literal {
+	return FileTypes.Literal(typ)
+}
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/dagger/dagger/dagql"
 	"github.com/dagger/dagger/dagql/idproto"
@@ -21,6 +22,9 @@ type Function struct {
 	Args        []*FunctionArg `field:"true" doc:"Arguments accepted by the function, if any."`
 	ReturnType  *TypeDef       `field:"true" doc:"The type returned by the function."`
 
+	CachePolicy     FunctionCachePolicy `field:"true" doc:"How the results of calls to the function are cached."`
+	CacheTimeToLive int                 `field:"true" doc:"How long the results of calls to the function are cached for, in seconds, if its cache policy is TTL_CACHE."`
+
 	// Below are not in public API
 
 	// OriginalName of the parent object
@@ -35,6 +39,7 @@ func NewFunction(name string, returnType *TypeDef) *Function {
 	return &Function{
 		Name:         strcase.ToLowerCamel(name),
 		ReturnType:   returnType,
+		CachePolicy:  FunctionCachePolicySession,
 		OriginalName: name,
 	}
 }
@@ -67,10 +72,14 @@ func (fn Function) Clone() *Function {
 
 func (fn *Function) FieldSpec() (dagql.FieldSpec, error) {
 	spec := dagql.FieldSpec{
-		Name:           fn.Name,
-		Description:    formatGqlDescription(fn.Description),
-		Type:           fn.ReturnType.ToTyped(),
-		ImpurityReason: "Module functions are currently always impure.", // TODO
+		Name:        fn.Name,
+		Description: formatGqlDescription(fn.Description),
+		Type:        fn.ReturnType.ToTyped(),
+	}
+	if fn.CachePolicy != FunctionCachePolicyPure {
+		// results that are only reused for a while (or a session) can't be
+		// cached by their ID
+		spec.ImpurityReason = "Module functions are impure unless their cache policy is PURE_CACHE."
 	}
 	for _, arg := range fn.Args {
 		input := arg.TypeDef.ToInput()
@@ -104,6 +113,23 @@ func (fn *Function) WithDescription(desc string) *Function {
 	return fn
 }
 
+func (fn *Function) WithCachePolicy(policy FunctionCachePolicy, ttl time.Duration) (*Function, error) {
+	switch policy {
+	case FunctionCachePolicyTTL:
+		if ttl < time.Second {
+			return nil, fmt.Errorf("cache policy %s requires a time to live of at least 1s, got %s", policy, ttl)
+		}
+	default:
+		if ttl != 0 {
+			return nil, fmt.Errorf("cache policy %s does not take a time to live", policy)
+		}
+	}
+	fn = fn.Clone()
+	fn.CachePolicy = policy
+	fn.CacheTimeToLive = int(ttl.Seconds())
+	return fn, nil
+}
+
 func (fn *Function) WithArg(name string, typeDef *TypeDef, desc string, defaultValue JSON) *Function {
 	fn = fn.Clone()
 	fn.Args = append(fn.Args, &FunctionArg{
@@ -131,7 +157,6 @@ func (fn *Function) IsSubtypeOf(otherFn *Function) bool {
 		/* TODO: with more effort could probably relax and allow:
 		* arg names to not match (only types really matter in theory)
 		* mismatches in optional (provided defaults exist, etc.)
-		* fewer args in interface fn than object fn (as long as the ones that exist match)
 		 */
 
 		if i >= len(fn.Args) {
@@ -160,6 +185,20 @@ func (fn *Function) IsSubtypeOf(otherFn *Function) bool {
 	return true
 }
 
+// ExtraArgsOptional reports whether every arg of fn beyond those of otherFn can
+// be omitted, since callers of otherFn have no way to set them.
+func (fn *Function) ExtraArgsOptional(otherFn *Function) bool {
+	if len(fn.Args) <= len(otherFn.Args) {
+		return true
+	}
+	for _, fnArg := range fn.Args[len(otherFn.Args):] {
+		if !fnArg.TypeDef.Optional && fnArg.DefaultValue == nil {
+			return false
+		}
+	}
+	return true
+}
+
 func (fn *Function) LookupArg(name string) (*FunctionArg, bool) {
 	for _, arg := range fn.Args {
 		if arg.Name == name {
@@ -613,6 +652,23 @@ func (obj *ObjectTypeDef) IsSubtypeOf(iface *InterfaceTypeDef) bool {
 	return true
 }
 
+// ExtraArgsOptional reports whether each function of obj implementing one of
+// iface can be called with only the args iface declares. Module objects aren't
+// held to this, but core objects (e.g. Container.export) are called directly
+// with the interface's args, so anything else they require can't be set.
+func (obj *ObjectTypeDef) ExtraArgsOptional(iface *InterfaceTypeDef) bool {
+	for _, ifaceFn := range iface.Functions {
+		objFn, ok := obj.FunctionByName(ifaceFn.Name)
+		if !ok {
+			continue
+		}
+		if !objFn.ExtraArgsOptional(ifaceFn) {
+			return false
+		}
+	}
+	return true
+}
+
 type FieldTypeDef struct {
 	Name        string   `field:"true" doc:"The name of the field in lowerCamelCase format."`
 	Description string   `field:"true" doc:"A doc string for the field, if any."`
@@ -839,6 +895,43 @@ func (k TypeDefKind) ToLiteral() *idproto.Literal {
 	return TypeDefKinds.Literal(k)
 }
 
+type FunctionCachePolicy string
+
+func (p FunctionCachePolicy) String() string {
+	return string(p)
+}
+
+var FunctionCachePolicies = dagql.NewEnum[FunctionCachePolicy]()
+
+var (
+	FunctionCachePolicyPure = FunctionCachePolicies.Register("PURE_CACHE",
+		"The function always returns the same result for the same inputs, so its results are reused across sessions.")
+	FunctionCachePolicySession = FunctionCachePolicies.Register("SESSION_CACHE",
+		"The function is called at most once per session for the same inputs.",
+		"This is the default.")
+	FunctionCachePolicyTTL = FunctionCachePolicies.Register("TTL_CACHE",
+		"The results of the function are reused across sessions until they are older than its time to live.")
+)
+
+func (p FunctionCachePolicy) Type() *ast.Type {
+	return &ast.Type{
+		NamedType: "FunctionCachePolicy",
+		NonNull:   true,
+	}
+}
+
+func (p FunctionCachePolicy) TypeDescription() string {
+	return `How the results of calls to a function are cached.`
+}
+
+func (p FunctionCachePolicy) Decoder() dagql.InputDecoder {
+	return FunctionCachePolicies
+}
+
+func (p FunctionCachePolicy) ToLiteral() *idproto.Literal {
+	return FunctionCachePolicies.Literal(p)
+}
+
 type FunctionCall struct {
 	Query *Query
 
@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/dagger/dagger/dagql"
+	"github.com/stretchr/testify/require"
 )
 
 // Samples contains a valid type definition for each kind. If you add a new
@@ -67,3 +68,46 @@ func TestTypeDefConversions(t *testing.T) {
 		})
 	}
 }
+
+func TestFunctionExtraArgsOptional(t *testing.T) {
+	ifaceFn := &Function{
+		Name:       "export",
+		ReturnType: &TypeDef{Kind: TypeDefKindBoolean},
+		Args: []*FunctionArg{
+			{Name: "path", TypeDef: &TypeDef{Kind: TypeDefKindString}},
+		},
+	}
+	objFn := func(extraArgs ...*FunctionArg) *Function {
+		return &Function{
+			Name:       "export",
+			ReturnType: &TypeDef{Kind: TypeDefKindBoolean},
+			Args: append([]*FunctionArg{
+				{Name: "path", TypeDef: &TypeDef{Kind: TypeDefKindString}},
+			}, extraArgs...),
+		}
+	}
+	required := objFn(&FunctionArg{
+		Name:    "required",
+		TypeDef: &TypeDef{Kind: TypeDefKindString},
+	})
+
+	require.True(t, objFn().ExtraArgsOptional(ifaceFn))
+	require.True(t, objFn(&FunctionArg{
+		Name:    "platformVariants",
+		TypeDef: &TypeDef{Kind: TypeDefKindString, Optional: true},
+	}).ExtraArgsOptional(ifaceFn))
+	require.True(t, objFn(&FunctionArg{
+		Name:         "forcedCompression",
+		TypeDef:      &TypeDef{Kind: TypeDefKindString},
+		DefaultValue: JSON(`"Gzip"`),
+	}).ExtraArgsOptional(ifaceFn))
+	require.False(t, required.ExtraArgsOptional(ifaceFn))
+
+	// module objects may still have required extra args
+	require.True(t, required.IsSubtypeOf(ifaceFn))
+
+	iface := &InterfaceTypeDef{Name: "Exporter", Functions: []*Function{ifaceFn}}
+	obj := &ObjectTypeDef{Name: "Container", Functions: []*Function{required}}
+	require.True(t, obj.IsSubtypeOf(iface))
+	require.False(t, obj.ExtraArgsOptional(iface))
+}
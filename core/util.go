@@ -27,6 +27,20 @@ type HasPBDefinitions interface {
 	PBDefinitions(context.Context) ([]*pb.Definition, error)
 }
 
+// definitionsCacheSize estimates the memory retained by a value for the dagql
+// cache, which is dominated by its marshaled LLB definitions.
+func definitionsCacheSize(value HasPBDefinitions) int64 {
+	size := dagql.DefaultCacheEntrySize
+	defs, err := value.PBDefinitions(context.Background())
+	if err != nil {
+		return size
+	}
+	for _, def := range defs {
+		size += int64(def.Size())
+	}
+	return size
+}
+
 func collectPBDefinitions(ctx context.Context, value dagql.Typed) ([]*pb.Definition, error) {
 	switch x := value.(type) {
 	case dagql.String, dagql.Int, dagql.Boolean, dagql.Float:
@@ -94,7 +108,9 @@ func defToState(def *pb.Definition) (llb.State, error) {
 	return llb.NewState(defop), nil
 }
 
-func resolveUIDGID(ctx context.Context, fsSt llb.State, bk *buildkit.Client, platform Platform, owner string) (*Ownership, error) {
+// resolveUIDGID resolves an owner of the form user[:group] to a uid and gid,
+// looking up names in the etc/passwd and etc/group files under root in fsSt.
+func resolveUIDGID(ctx context.Context, fsSt llb.State, root string, bk *buildkit.Client, platform Platform, owner string) (*Ownership, error) {
 	uidOrName, gidOrName, hasGroup := strings.Cut(owner, ":")
 
 	var uid, gid int
@@ -121,14 +137,14 @@ func resolveUIDGID(ctx context.Context, fsSt llb.State, bk *buildkit.Client, pla
 	}
 
 	if uname != "" {
-		uid, err = findUID(fs, uname)
+		uid, err = findUID(fs, root, uname)
 		if err != nil {
 			return nil, fmt.Errorf("find uid: %w", err)
 		}
 	}
 
 	if gname != "" {
-		gid, err = findGID(fs, gname)
+		gid, err = findGID(fs, root, gname)
 		if err != nil {
 			return nil, fmt.Errorf("find gid: %w", err)
 		}
@@ -141,17 +157,18 @@ func resolveUIDGID(ctx context.Context, fsSt llb.State, bk *buildkit.Client, pla
 	return &Ownership{uid, gid}, nil
 }
 
-func findUID(fs fs.FS, uname string) (int, error) {
-	f, err := fs.Open("/etc/passwd")
+func findUID(fs fs.FS, root string, uname string) (int, error) {
+	filePath := path.Join("/", root, "etc/passwd")
+	f, err := fs.Open(filePath)
 	if err != nil {
-		return -1, fmt.Errorf("open /etc/passwd: %w", err)
+		return -1, fmt.Errorf("open %s: %w", filePath, err)
 	}
 
 	users, err := user.ParsePasswdFilter(f, func(u user.User) bool {
 		return u.Name == uname
 	})
 	if err != nil {
-		return -1, fmt.Errorf("parse /etc/passwd: %w", err)
+		return -1, fmt.Errorf("parse %s: %w", filePath, err)
 	}
 
 	if len(users) == 0 {
@@ -161,17 +178,18 @@ func findUID(fs fs.FS, uname string) (int, error) {
 	return users[0].Uid, nil
 }
 
-func findGID(fs fs.FS, gname string) (int, error) {
-	f, err := fs.Open("/etc/group")
+func findGID(fs fs.FS, root string, gname string) (int, error) {
+	filePath := path.Join("/", root, "etc/group")
+	f, err := fs.Open(filePath)
 	if err != nil {
-		return -1, fmt.Errorf("open /etc/passwd: %w", err)
+		return -1, fmt.Errorf("open %s: %w", filePath, err)
 	}
 
 	groups, err := user.ParseGroupFilter(f, func(g user.Group) bool {
 		return g.Name == gname
 	})
 	if err != nil {
-		return -1, fmt.Errorf("parse /etc/group: %w", err)
+		return -1, fmt.Errorf("parse %s: %w", filePath, err)
 	}
 
 	if len(groups) == 0 {
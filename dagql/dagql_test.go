@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"io"
 	"log/slog"
+	"net/http"
 	"os"
 	"testing"
 	"time"
@@ -18,6 +19,7 @@ import (
 	"github.com/dagger/dagger/dagql/internal/pipes"
 	"github.com/dagger/dagger/dagql/internal/points"
 	"github.com/dagger/dagger/dagql/introspection"
+	"github.com/opencontainers/go-digest"
 	"github.com/vektah/gqlparser/v2/ast"
 	"gotest.tools/v3/assert"
 	"gotest.tools/v3/assert/cmp"
@@ -845,6 +847,50 @@ func TestImpureIDsReEvaluate(t *testing.T) {
 	assert.Equal(t, called, 2)
 }
 
+func TestCacheKeyScopesCache(t *testing.T) {
+	srv := dagql.NewServer(Query{})
+	points.Install[Query](srv)
+
+	type scopeKey struct{}
+	gql := client.New(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := context.WithValue(r.Context(), scopeKey{}, r.Header.Get("X-Scope"))
+		handler.NewDefaultServer(srv).ServeHTTP(w, r.WithContext(ctx))
+	}))
+
+	called := 0
+	dagql.Fields[*points.Point]{
+		dagql.Func("snitch", func(ctx context.Context, self *points.Point, _ struct{}) (*points.Point, error) {
+			called++
+			return self, nil
+		}).CacheKey(func(ctx context.Context, dig digest.Digest) (digest.Digest, error) {
+			return digest.FromString(dig.String() + ctx.Value(scopeKey{}).(string)), nil
+		}),
+	}.Install(srv)
+
+	snitch := func(scope string) {
+		var res struct {
+			Point struct {
+				Snitch struct {
+					X int
+				}
+			}
+		}
+		err := gql.Post(`query { point(x: 6, y: 7) { snitch { x } } }`, &res, client.AddHeader("X-Scope", scope))
+		assert.NilError(t, err)
+		assert.Equal(t, res.Point.Snitch.X, 6)
+	}
+
+	snitch("a")
+	snitch("a")
+	assert.Equal(t, called, 1)
+
+	snitch("b")
+	assert.Equal(t, called, 2)
+
+	snitch("a")
+	assert.Equal(t, called, 2)
+}
+
 func TestPassingObjectsAround(t *testing.T) {
 	srv := dagql.NewServer(Query{})
 	points.Install[Query](srv)
@@ -0,0 +1,233 @@
+package dagql
+
+import (
+	"container/list"
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/opencontainers/go-digest"
+)
+
+// LRUCacheOpts configures the limits of a cache created with NewLRUCache.
+//
+// A zero value for either limit means that dimension is unbounded.
+type LRUCacheOpts struct {
+	// MaxEntries is the maximum number of completed results to retain.
+	MaxEntries int
+
+	// MaxBytes is the maximum estimated size of all retained results.
+	MaxBytes int64
+}
+
+// CacheStats reports counters and current usage of a Cache.
+type CacheStats struct {
+	Hits      int64
+	Misses    int64
+	Evictions int64
+	Entries   int
+	Bytes     int64
+}
+
+// StatsCache is implemented by caches that track usage metrics.
+type StatsCache interface {
+	Cache
+	Stats() CacheStats
+}
+
+// CacheSizer may be implemented by values stored in the cache to report an
+// estimate of the memory they retain. Values that don't implement it are
+// counted as DefaultCacheEntrySize.
+type CacheSizer interface {
+	CacheSize() int64
+}
+
+// DefaultCacheEntrySize is the estimated size of a cached value that does not
+// implement CacheSizer.
+const DefaultCacheEntrySize int64 = 1024
+
+// LRUCache is a Cache that evicts the least recently used results once its
+// configured limits are exceeded.
+//
+// Results that are still being computed, or that have callers waiting on
+// them, are never evicted.
+type LRUCache struct {
+	opts LRUCacheOpts
+
+	l       sync.Mutex
+	entries map[digest.Digest]*lruEntry
+	// lru holds completed entries, most recently used at the front
+	lru   *list.List
+	bytes int64
+
+	hits      int64
+	misses    int64
+	evictions int64
+}
+
+type lruEntry struct {
+	key  digest.Digest
+	wg   sync.WaitGroup
+	val  Typed
+	err  error
+	size int64
+	refs int
+	elem *list.Element
+}
+
+var _ StatsCache = (*LRUCache)(nil)
+
+// NewLRUCache creates a new bounded cache suitable for assigning on a Server
+// or multiple Servers.
+func NewLRUCache(opts LRUCacheOpts) *LRUCache {
+	return &LRUCache{
+		opts:    opts,
+		entries: map[digest.Digest]*lruEntry{},
+		lru:     list.New(),
+	}
+}
+
+type lruCacheContextKey struct {
+	key digest.Digest
+	c   *LRUCache
+}
+
+func (c *LRUCache) GetOrInitialize(ctx context.Context, key digest.Digest, fn func(context.Context) (Typed, error)) (Typed, error) {
+	if v := ctx.Value(lruCacheContextKey{key: key, c: c}); v != nil {
+		return nil, ErrCacheMapRecursiveCall
+	}
+
+	c.l.Lock()
+	if e, ok := c.entries[key]; ok {
+		c.hits++
+		e.refs++
+		if e.elem != nil {
+			c.lru.MoveToFront(e.elem)
+		}
+		c.l.Unlock()
+
+		e.wg.Wait()
+
+		c.l.Lock()
+		e.refs--
+		c.evictLocked()
+		c.l.Unlock()
+		return e.val, e.err
+	}
+
+	c.misses++
+	e := &lruEntry{key: key, refs: 1}
+	e.wg.Add(1)
+	c.entries[key] = e
+	c.l.Unlock()
+
+	ctx = context.WithValue(ctx, lruCacheContextKey{key: key, c: c}, struct{}{})
+	e.val, e.err = fn(ctx)
+	e.wg.Done()
+
+	c.l.Lock()
+	e.refs--
+	if e.err != nil {
+		if c.entries[key] == e {
+			delete(c.entries, key)
+		}
+	} else {
+		e.size = cacheSizeOf(e.val)
+		c.bytes += e.size
+		e.elem = c.lru.PushFront(e)
+		c.evictLocked()
+	}
+	c.l.Unlock()
+
+	return e.val, e.err
+}
+
+// Get returns a completed result for the given key without initializing it.
+func (c *LRUCache) Get(ctx context.Context, key digest.Digest) (Typed, error) {
+	if v := ctx.Value(lruCacheContextKey{key: key, c: c}); v != nil {
+		return nil, ErrCacheMapRecursiveCall
+	}
+
+	c.l.Lock()
+	e, ok := c.entries[key]
+	if !ok {
+		c.l.Unlock()
+		return nil, fmt.Errorf("key not found")
+	}
+	e.refs++
+	if e.elem != nil {
+		c.lru.MoveToFront(e.elem)
+	}
+	c.l.Unlock()
+
+	e.wg.Wait()
+
+	c.l.Lock()
+	e.refs--
+	c.evictLocked()
+	c.l.Unlock()
+	return e.val, e.err
+}
+
+// Keys returns the keys of all entries currently held by the cache.
+func (c *LRUCache) Keys() []digest.Digest {
+	c.l.Lock()
+	keys := make([]digest.Digest, 0, len(c.entries))
+	for k := range c.entries {
+		keys = append(keys, k)
+	}
+	c.l.Unlock()
+	return keys
+}
+
+// Stats returns a snapshot of the cache's counters.
+func (c *LRUCache) Stats() CacheStats {
+	c.l.Lock()
+	defer c.l.Unlock()
+	return CacheStats{
+		Hits:      c.hits,
+		Misses:    c.misses,
+		Evictions: c.evictions,
+		Entries:   c.lru.Len(),
+		Bytes:     c.bytes,
+	}
+}
+
+func (c *LRUCache) overLimitLocked() bool {
+	if c.opts.MaxEntries > 0 && c.lru.Len() > c.opts.MaxEntries {
+		return true
+	}
+	if c.opts.MaxBytes > 0 && c.bytes > c.opts.MaxBytes {
+		return true
+	}
+	return false
+}
+
+func (c *LRUCache) evictLocked() {
+	for elem := c.lru.Back(); elem != nil && c.overLimitLocked(); {
+		prev := elem.Prev()
+		e := elem.Value.(*lruEntry)
+		if e.refs == 0 {
+			c.lru.Remove(elem)
+			e.elem = nil
+			c.bytes -= e.size
+			if c.entries[e.key] == e {
+				delete(c.entries, e.key)
+			}
+			c.evictions++
+		}
+		elem = prev
+	}
+}
+
+func cacheSizeOf(val Typed) int64 {
+	if sizer, ok := val.(CacheSizer); ok {
+		return sizer.CacheSize()
+	}
+	if wrapper, ok := val.(Wrapper); ok {
+		if sizer, ok := wrapper.Unwrap().(CacheSizer); ok {
+			return sizer.CacheSize()
+		}
+	}
+	return DefaultCacheEntrySize
+}
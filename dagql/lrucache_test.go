@@ -0,0 +1,197 @@
+package dagql
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/opencontainers/go-digest"
+	"github.com/pkg/errors"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func intKey(i int) digest.Digest {
+	return digest.FromString(strconv.Itoa(i))
+}
+
+type sizedInt struct {
+	Int
+	size int64
+}
+
+func (s sizedInt) CacheSize() int64 {
+	return s.size
+}
+
+func TestLRUCacheConcurrent(t *testing.T) {
+	t.Parallel()
+	c := NewLRUCache(LRUCacheOpts{MaxEntries: 10})
+	ctx := context.Background()
+
+	commonKey := digest.FromString("42")
+	initialized := map[int]bool{}
+
+	wg := new(sync.WaitGroup)
+	for i := 0; i < 100; i++ {
+		i := i
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			val, err := c.GetOrInitialize(ctx, commonKey, func(_ context.Context) (Typed, error) {
+				initialized[i] = true
+				return Int(i), nil
+			})
+			assert.NilError(t, err)
+			assert.Assert(t, initialized[int(val.(Int))])
+		}()
+	}
+
+	wg.Wait()
+
+	// only one of them should have initialized
+	assert.Assert(t, is.Len(initialized, 1))
+
+	stats := c.Stats()
+	assert.Equal(t, int64(1), stats.Misses)
+	assert.Equal(t, int64(99), stats.Hits)
+	assert.Equal(t, 1, stats.Entries)
+}
+
+func TestLRUCacheErrors(t *testing.T) {
+	t.Parallel()
+	c := NewLRUCache(LRUCacheOpts{})
+	ctx := context.Background()
+
+	key := digest.FromString("42")
+
+	myErr := errors.New("nope")
+	_, err := c.GetOrInitialize(ctx, key, func(_ context.Context) (Typed, error) {
+		return nil, myErr
+	})
+	assert.Assert(t, is.ErrorIs(err, myErr))
+	assert.Equal(t, 0, c.Stats().Entries)
+
+	res, err := c.GetOrInitialize(ctx, key, func(_ context.Context) (Typed, error) {
+		return Int(1), nil
+	})
+	assert.NilError(t, err)
+	assert.Equal(t, Int(1), res)
+}
+
+func TestLRUCacheMaxEntries(t *testing.T) {
+	t.Parallel()
+	c := NewLRUCache(LRUCacheOpts{MaxEntries: 2})
+	ctx := context.Background()
+
+	get := func(i int) {
+		_, err := c.GetOrInitialize(ctx, intKey(i), func(_ context.Context) (Typed, error) {
+			return Int(i), nil
+		})
+		assert.NilError(t, err)
+	}
+
+	get(1)
+	get(2)
+	get(1) // touch 1 so 2 is least recently used
+	get(3)
+
+	stats := c.Stats()
+	assert.Equal(t, 2, stats.Entries)
+	assert.Equal(t, int64(1), stats.Evictions)
+
+	_, err := c.Get(ctx, intKey(2))
+	assert.ErrorContains(t, err, "key not found")
+
+	val, err := c.Get(ctx, intKey(1))
+	assert.NilError(t, err)
+	assert.Equal(t, Int(1), val)
+}
+
+func TestLRUCacheMaxBytes(t *testing.T) {
+	t.Parallel()
+	c := NewLRUCache(LRUCacheOpts{MaxBytes: 100})
+	ctx := context.Background()
+
+	for i := 0; i < 5; i++ {
+		i := i
+		_, err := c.GetOrInitialize(ctx, intKey(i), func(_ context.Context) (Typed, error) {
+			return sizedInt{Int: Int(i), size: 40}, nil
+		})
+		assert.NilError(t, err)
+	}
+
+	stats := c.Stats()
+	assert.Equal(t, 2, stats.Entries)
+	assert.Equal(t, int64(80), stats.Bytes)
+	assert.Equal(t, int64(3), stats.Evictions)
+}
+
+func TestLRUCacheReferencedNotEvicted(t *testing.T) {
+	t.Parallel()
+	c := NewLRUCache(LRUCacheOpts{MaxEntries: 1})
+	ctx := context.Background()
+
+	outer := digest.FromString("outer")
+	inner := digest.FromString("inner")
+
+	_, err := c.GetOrInitialize(ctx, outer, func(ctx context.Context) (Typed, error) {
+		// the outer entry is still being computed, so filling the cache with
+		// other entries must not drop it
+		for i := 0; i < 3; i++ {
+			i := i
+			_, err := c.GetOrInitialize(ctx, intKey(i), func(_ context.Context) (Typed, error) {
+				return Int(i), nil
+			})
+			assert.NilError(t, err)
+		}
+		return c.GetOrInitialize(ctx, inner, func(_ context.Context) (Typed, error) {
+			return Int(100), nil
+		})
+	})
+	assert.NilError(t, err)
+
+	val, err := c.Get(ctx, outer)
+	assert.NilError(t, err)
+	assert.Equal(t, Int(100), val)
+	assert.Equal(t, 1, c.Stats().Entries)
+}
+
+func TestLRUCacheRecursiveCall(t *testing.T) {
+	t.Parallel()
+	c := NewLRUCache(LRUCacheOpts{})
+	ctx := context.Background()
+
+	key := digest.FromString("1")
+	_, err := c.GetOrInitialize(ctx, key, func(ctx context.Context) (Typed, error) {
+		return c.GetOrInitialize(ctx, key, func(ctx context.Context) (Typed, error) {
+			return Int(2), nil
+		})
+	})
+	assert.Assert(t, is.ErrorIs(err, ErrCacheMapRecursiveCall))
+}
+
+func TestLRUCacheMaxBytesStrings(t *testing.T) {
+	t.Parallel()
+	c := NewLRUCache(LRUCacheOpts{MaxBytes: 100})
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		i := i
+		_, err := c.GetOrInitialize(ctx, intKey(i), func(_ context.Context) (Typed, error) {
+			return NewString(strings.Repeat("x", 40)), nil
+		})
+		assert.NilError(t, err)
+	}
+	_, err := c.GetOrInitialize(ctx, intKey(3), func(_ context.Context) (Typed, error) {
+		return NewStringArray("a", "b"), nil
+	})
+	assert.NilError(t, err)
+
+	stats := c.Stats()
+	assert.Equal(t, 3, stats.Entries)
+	assert.Equal(t, int64(82), stats.Bytes)
+	assert.Equal(t, int64(1), stats.Evictions)
+}
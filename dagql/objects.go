@@ -11,6 +11,7 @@ import (
 
 	"github.com/dagger/dagger/dagql/idproto"
 	"github.com/iancoleman/strcase"
+	"github.com/opencontainers/go-digest"
 	"github.com/vektah/gqlparser/v2/ast"
 )
 
@@ -199,7 +200,9 @@ func (cls Class[T]) Call(ctx context.Context, node Instance[T], fieldName string
 	if !ok {
 		return nil, fmt.Errorf("Call: %s has no such field: %q", cls.inner.Type().Name(), fieldName)
 	}
-	return field.Func(ctx, node, args)
+	return field.Spec.Retry.Do(ctx, func(ctx context.Context) (Typed, error) {
+		return field.Func(ctx, node, args)
+	})
 }
 
 // Instance is an instance of an Object type.
@@ -258,6 +261,19 @@ func (r Instance[T]) IDFor(ctx context.Context, sel Selector) (*idproto.ID, erro
 	return sel.AppendTo(r.ID(), field.Spec), nil
 }
 
+// CacheKeyFor returns the key that the result of the given field is cached
+// under, given the digest of its ID.
+func (r Instance[T]) CacheKeyFor(ctx context.Context, sel Selector, dig digest.Digest) (digest.Digest, error) {
+	field, ok := r.Class.Field(sel.Field)
+	if !ok {
+		return "", fmt.Errorf("CacheKeyFor: %s has no such field: %q", r.Class.TypeName(), sel.Field)
+	}
+	if field.Spec.CacheKey == nil {
+		return dig, nil
+	}
+	return field.Spec.CacheKey(ctx, dig)
+}
+
 // Select calls a field on the instance.
 func (r Instance[T]) Select(ctx context.Context, sel Selector) (val Typed, err error) {
 	field, ok := r.Class.Field(sel.Field)
@@ -379,8 +395,18 @@ type FieldSpec struct {
 	DeprecatedReason string
 	// Module is the module that provides the field's implementation.
 	Module *idproto.Module
+	// Retry is the policy for retrying the field's resolver when it fails.
+	Retry *RetryPolicy
+	// CacheKey derives the key that the field's result is cached under from
+	// the digest of its ID. By default the digest itself is used.
+	CacheKey CacheKeyFunc
 }
 
+// CacheKeyFunc derives the key that a field's result is cached under from the
+// digest of its ID, for fields whose result depends on the context they're
+// called in and not just their ID.
+type CacheKeyFunc func(ctx context.Context, dig digest.Digest) (digest.Digest, error)
+
 func (spec FieldSpec) FieldDefinition() *ast.FieldDefinition {
 	def := &ast.FieldDefinition{
 		Name:        spec.Name,
@@ -605,12 +631,26 @@ func (field Field[T]) Impure(reason string, paras ...string) Field[T] {
 	return field
 }
 
+// CacheKey sets the function that derives the key the field's result is
+// cached under, e.g. to keep callers in different contexts from sharing it.
+func (field Field[T]) CacheKey(fn CacheKeyFunc) Field[T] {
+	field.Spec.CacheKey = fn
+	return field
+}
+
 // Meta indicates that the field has no impact on the field's result.
 func (field Field[T]) Meta() Field[T] {
 	field.Spec.Meta = true
 	return field
 }
 
+// Retry sets a policy for retrying the field's resolver when it fails with a
+// retryable error.
+func (field Field[T]) Retry(policy RetryPolicy) Field[T] {
+	field.Spec.Retry = &policy
+	return field
+}
+
 // Definition returns the schema definition of the field.
 func (field Field[T]) FieldDefinition() *ast.FieldDefinition {
 	spec := field.Spec
@@ -0,0 +1,130 @@
+package dagql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"syscall"
+	"time"
+)
+
+// RetryPolicy configures how a field's resolver is retried when it fails.
+//
+// Failed results are never cached, so without a policy a transient failure is
+// only returned to callers that were already waiting on it; the policy allows
+// absorbing such failures before they reach any caller.
+type RetryPolicy struct {
+	// Attempts is the total number of times the resolver may be called,
+	// including the first call.
+	Attempts int
+
+	// Backoff is the delay before the second attempt. It doubles after each
+	// subsequent attempt, up to MaxBackoff.
+	Backoff time.Duration
+
+	// MaxBackoff caps the delay between attempts. Zero means no cap.
+	MaxBackoff time.Duration
+
+	// RetryOn decides whether a failed attempt should be retried. If nil,
+	// RetryTransient is used.
+	RetryOn func(error) bool
+}
+
+// RetryTransient reports whether the error looks transient: network errors,
+// timeouts, connection resets, unexpected EOFs, and errors marked with
+// Transient.
+//
+// Context cancellation and DNS lookups of hosts that don't exist are never
+// considered transient.
+func RetryTransient(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	var transient *TransientError
+	if errors.As(err, &transient) {
+		return true
+	}
+	if errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, syscall.ECONNREFUSED) {
+		return true
+	}
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		return dnsErr.IsTemporary || dnsErr.IsTimeout
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return netErr.Timeout()
+	}
+	return false
+}
+
+// RetryAny retries on any error other than context cancellation.
+func RetryAny(err error) bool {
+	return !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded)
+}
+
+// TransientError marks an error as safe to retry under RetryTransient.
+type TransientError struct {
+	Err error
+}
+
+// Transient wraps the error so that RetryTransient considers it retryable.
+func Transient(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &TransientError{Err: err}
+}
+
+func (err *TransientError) Error() string {
+	return err.Err.Error()
+}
+
+func (err *TransientError) Unwrap() error {
+	return err.Err
+}
+
+// Do calls fn until it succeeds, the policy's attempts are exhausted, the
+// error is not retryable, or the context is canceled. A nil policy calls fn
+// exactly once.
+func (policy *RetryPolicy) Do(ctx context.Context, fn func(context.Context) (Typed, error)) (Typed, error) {
+	if policy == nil || policy.Attempts <= 1 {
+		return fn(ctx)
+	}
+	retryOn := policy.RetryOn
+	if retryOn == nil {
+		retryOn = RetryTransient
+	}
+	delay := policy.Backoff
+	for attempt := 1; ; attempt++ {
+		val, err := fn(ctx)
+		if err == nil {
+			return val, nil
+		}
+		if !retryOn(err) {
+			return nil, err
+		}
+		if attempt >= policy.Attempts {
+			return nil, fmt.Errorf("failed after %d attempts: %w", attempt, err)
+		}
+		if delay > 0 {
+			timer := time.NewTimer(delay)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return nil, errors.Join(err, ctx.Err())
+			case <-timer.C:
+			}
+			delay *= 2
+			if policy.MaxBackoff > 0 && delay > policy.MaxBackoff {
+				delay = policy.MaxBackoff
+			}
+		} else if ctx.Err() != nil {
+			return nil, errors.Join(err, ctx.Err())
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package dagql
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func TestRetryPolicyTransient(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	policy := &RetryPolicy{Attempts: 3}
+
+	calls := 0
+	val, err := policy.Do(ctx, func(context.Context) (Typed, error) {
+		calls++
+		if calls < 3 {
+			return nil, Transient(errors.New("registry 503"))
+		}
+		return Int(42), nil
+	})
+	assert.NilError(t, err)
+	assert.Equal(t, Int(42), val)
+	assert.Equal(t, 3, calls)
+}
+
+func TestRetryPolicyExhausted(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	policy := &RetryPolicy{Attempts: 2}
+
+	calls := 0
+	myErr := Transient(errors.New("nope"))
+	_, err := policy.Do(ctx, func(context.Context) (Typed, error) {
+		calls++
+		return nil, myErr
+	})
+	assert.Assert(t, is.ErrorIs(err, myErr))
+	assert.ErrorContains(t, err, "failed after 2 attempts")
+	assert.Equal(t, 2, calls)
+}
+
+func TestRetryPolicyNotRetryable(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	policy := &RetryPolicy{Attempts: 5}
+
+	calls := 0
+	myErr := errors.New("invalid reference format")
+	_, err := policy.Do(ctx, func(context.Context) (Typed, error) {
+		calls++
+		return nil, myErr
+	})
+	assert.Assert(t, is.ErrorIs(err, myErr))
+	assert.Equal(t, 1, calls)
+}
+
+func TestRetryPolicyCanceled(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithCancel(context.Background())
+
+	policy := &RetryPolicy{Attempts: 5, Backoff: time.Hour, RetryOn: RetryAny}
+
+	calls := 0
+	myErr := errors.New("nope")
+	_, err := policy.Do(ctx, func(context.Context) (Typed, error) {
+		calls++
+		cancel()
+		return nil, myErr
+	})
+	assert.Assert(t, is.ErrorIs(err, myErr))
+	assert.Assert(t, is.ErrorIs(err, context.Canceled))
+	assert.Equal(t, 1, calls)
+}
+
+func TestRetryPolicyNil(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	var policy *RetryPolicy
+
+	calls := 0
+	_, err := policy.Do(ctx, func(context.Context) (Typed, error) {
+		calls++
+		return nil, Transient(errors.New("nope"))
+	})
+	assert.ErrorContains(t, err, "nope")
+	assert.Equal(t, 1, calls)
+}
+
+func TestRetryTransientDNS(t *testing.T) {
+	t.Parallel()
+
+	notFound := &net.DNSError{Err: "no such host", Name: "regsitry.example.com", IsNotFound: true}
+	assert.Assert(t, !RetryTransient(errors.Wrap(notFound, "resolve")))
+
+	timeout := &net.DNSError{Err: "i/o timeout", Name: "registry.example.com", IsTimeout: true}
+	assert.Assert(t, RetryTransient(errors.Wrap(timeout, "resolve")))
+
+	temporary := &net.DNSError{Err: "server misbehaving", Name: "registry.example.com", IsTemporary: true}
+	assert.Assert(t, RetryTransient(errors.Wrap(temporary, "resolve")))
+}
@@ -589,7 +589,12 @@ func (s *Server) cachedSelect(ctx context.Context, self Object, sel Selector) (r
 	if chainedID.IsTainted() {
 		val, err = doSelect(ctx)
 	} else {
-		val, err = s.Cache.GetOrInitialize(ctx, dig, doSelect)
+		var key digest.Digest
+		key, err = self.CacheKeyFor(ctx, sel, dig)
+		if err != nil {
+			return nil, nil, err
+		}
+		val, err = s.Cache.GetOrInitialize(ctx, key, doSelect)
 	}
 	if err != nil {
 		return nil, nil, err
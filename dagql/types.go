@@ -9,6 +9,7 @@ import (
 	"strings"
 
 	"github.com/dagger/dagger/dagql/idproto"
+	"github.com/opencontainers/go-digest"
 	"github.com/vektah/gqlparser/v2/ast"
 	"golang.org/x/exp/constraints"
 )
@@ -73,6 +74,9 @@ type Object interface {
 	ObjectType() ObjectType
 	// IDFor returns the ID representing the return value of the given field.
 	IDFor(context.Context, Selector) (*idproto.ID, error)
+	// CacheKeyFor returns the key that the result of the given field is cached
+	// under, given the digest of its ID.
+	CacheKeyFor(context.Context, Selector, digest.Digest) (digest.Digest, error)
 	// Select evaluates the selected field and returns the result.
 	//
 	// The returned value is the raw Typed value returned from the field; it must
@@ -474,6 +478,14 @@ func (s String) String() string {
 	return string(s)
 }
 
+var _ CacheSizer = String("")
+
+// CacheSize returns the length of the string, so that large results such as
+// file contents count against the cache's byte limit.
+func (s String) CacheSize() int64 {
+	return int64(len(s))
+}
+
 var _ Setter = String("")
 
 func (s String) SetField(v reflect.Value) error {
@@ -796,6 +808,17 @@ func NewFloatArray(elems ...float64) Array[Float] {
 
 var _ Typed = Array[Typed]{}
 
+var _ CacheSizer = Array[Typed]{}
+
+// CacheSize returns the sum of the estimated sizes of the array's elements.
+func (i Array[T]) CacheSize() int64 {
+	var size int64
+	for _, elem := range i {
+		size += cacheSizeOf(elem)
+	}
+	return size
+}
+
 func (i Array[T]) Type() *ast.Type {
 	var t T
 	return &ast.Type{
@@ -0,0 +1,442 @@
+package buildkit
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/containerd/continuity/fs"
+	"github.com/docker/docker/pkg/archive"
+	"github.com/klauspost/compress/zstd"
+	bksolverpb "github.com/moby/buildkit/solver/pb"
+	specs "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/vito/progrock"
+)
+
+const (
+	ArchiveTar = "tar"
+	ArchiveZip = "zip"
+)
+
+var (
+	// reproducibleTime is the timestamp given to every entry of a
+	// reproducible tarball.
+	reproducibleTime = time.Unix(0, 0).UTC()
+
+	// reproducibleZipTime is the timestamp given to every entry of a
+	// reproducible zip archive, which can't represent times before 1980.
+	reproducibleZipTime = time.Date(1980, 1, 1, 0, 0, 0, 0, time.UTC)
+)
+
+type ArchiveOpts struct {
+	// Format is either ArchiveTar or ArchiveZip.
+	Format string
+
+	// Compression is "", "gzip" or "zstd". Zip archives only support "gzip",
+	// which compresses each entry with DEFLATE.
+	Compression string
+
+	// Reproducible sets every timestamp to a fixed time and leaves out user
+	// and group names so that the same contents always produce the same
+	// archive.
+	Reproducible bool
+}
+
+// DirectoryToArchive writes the directory at dirPath in the result of the
+// definition to an archive named fileName, returning a definition containing
+// just that file.
+func (c *Client) DirectoryToArchive(
+	ctx context.Context,
+	engineHostPlatform specs.Platform,
+	def *bksolverpb.Definition,
+	dirPath string,
+	fileName string,
+	opts ArchiveOpts,
+) (*bksolverpb.Definition, error) {
+	ctx, cancel, err := c.withClientCloseCancel(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer cancel()
+
+	tmpDir, err := os.MkdirTemp("", "dagger-archive")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temp dir for archive: %w", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	err = c.withMountedDir(ctx, def, dirPath, func(dir string) error {
+		f, err := os.Create(filepath.Join(tmpDir, fileName))
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		if err := writeArchive(f, dir, opts); err != nil {
+			return err
+		}
+		return f.Close()
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to write archive: %w", err)
+	}
+
+	ctx, recorder := progrock.WithGroup(ctx, "directory to archive")
+	pbDef, _, err := c.EngineContainerLocalImport(ctx, recorder, engineHostPlatform, tmpDir, nil, []string{fileName})
+	if err != nil {
+		return nil, fmt.Errorf("failed to import archive from engine container filesystem: %w", err)
+	}
+	return pbDef, nil
+}
+
+// ExtractArchive unpacks the archive at filePath in the result of the
+// definition, returning a definition containing its contents. If format is
+// empty, it's detected from the archive itself. Compressed tarballs are
+// detected automatically.
+func (c *Client) ExtractArchive(
+	ctx context.Context,
+	engineHostPlatform specs.Platform,
+	def *bksolverpb.Definition,
+	filePath string,
+	format string,
+) (*bksolverpb.Definition, error) {
+	ctx, cancel, err := c.withClientCloseCancel(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer cancel()
+
+	tmpDir, err := os.MkdirTemp("", "dagger-extract")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temp dir for extraction: %w", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	err = c.withMountedResult(ctx, def, func(root string) error {
+		mntFilePath, err := fs.RootPath(root, filePath)
+		if err != nil {
+			return fmt.Errorf("failed to get root path: %w", err)
+		}
+		f, err := os.Open(mntFilePath)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		return extractArchive(f, tmpDir, format)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to extract archive: %w", err)
+	}
+
+	ctx, recorder := progrock.WithGroup(ctx, "extract archive")
+	pbDef, _, err := c.EngineContainerLocalImport(ctx, recorder, engineHostPlatform, tmpDir, nil, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to import extracted archive from engine container filesystem: %w", err)
+	}
+	return pbDef, nil
+}
+
+func writeArchive(w io.Writer, dir string, opts ArchiveOpts) error {
+	switch opts.Format {
+	case ArchiveTar, "":
+		var out io.WriteCloser
+		switch opts.Compression {
+		case "":
+			out = nopWriteCloser{w}
+		case "gzip":
+			out = gzip.NewWriter(w)
+		case "zstd":
+			zw, err := zstd.NewWriter(w)
+			if err != nil {
+				return err
+			}
+			out = zw
+		default:
+			return fmt.Errorf("unsupported compression %q", opts.Compression)
+		}
+		if err := writeTar(out, dir, opts.Reproducible); err != nil {
+			out.Close()
+			return err
+		}
+		return out.Close()
+	case ArchiveZip:
+		switch opts.Compression {
+		case "", "gzip":
+		default:
+			return fmt.Errorf("unsupported compression %q for zip archives", opts.Compression)
+		}
+		return writeZip(w, dir, opts.Compression != "", opts.Reproducible)
+	default:
+		return fmt.Errorf("unsupported archive format %q", opts.Format)
+	}
+}
+
+// writeTar writes everything under dir to w as a tarball, in lexical order.
+func writeTar(w io.Writer, dir string, reproducible bool) error {
+	tw := tar.NewWriter(w)
+	err := filepath.WalkDir(dir, func(p string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if p == dir {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		if info.Mode()&os.ModeSocket != 0 {
+			// sockets can't be archived
+			return nil
+		}
+
+		var link string
+		if info.Mode()&os.ModeSymlink != 0 {
+			link, err = os.Readlink(p)
+			if err != nil {
+				return err
+			}
+		}
+		hdr, err := tar.FileInfoHeader(info, link)
+		if err != nil {
+			return err
+		}
+		hdr.Name = filepath.ToSlash(rel)
+		if d.IsDir() {
+			hdr.Name += "/"
+		}
+		// names depend on the engine's /etc/passwd, not the directory
+		hdr.Uname, hdr.Gname = "", ""
+		hdr.AccessTime, hdr.ChangeTime = time.Time{}, time.Time{}
+		if reproducible {
+			hdr.ModTime = reproducibleTime
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		if info.Mode().IsRegular() {
+			return copyFileTo(tw, p)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	return tw.Close()
+}
+
+// writeZip writes everything under dir to w as a zip archive, in lexical
+// order. Symlinks are stored as entries containing their target, as Info-ZIP
+// does.
+func writeZip(w io.Writer, dir string, deflate bool, reproducible bool) error {
+	zw := zip.NewWriter(w)
+	err := filepath.WalkDir(dir, func(p string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if p == dir {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		mode := info.Mode()
+		if !mode.IsDir() && !mode.IsRegular() && mode&os.ModeSymlink == 0 {
+			// devices, pipes and sockets can't be represented in a zip
+			return nil
+		}
+
+		hdr, err := zip.FileInfoHeader(info)
+		if err != nil {
+			return err
+		}
+		hdr.Name = filepath.ToSlash(rel)
+		if mode.IsDir() {
+			hdr.Name += "/"
+		}
+		hdr.Method = zip.Store
+		if deflate && mode.IsRegular() {
+			hdr.Method = zip.Deflate
+		}
+		if reproducible {
+			hdr.Modified = reproducibleZipTime
+		}
+		ew, err := zw.CreateHeader(hdr)
+		if err != nil {
+			return err
+		}
+		switch {
+		case mode&os.ModeSymlink != 0:
+			link, err := os.Readlink(p)
+			if err != nil {
+				return err
+			}
+			_, err = io.WriteString(ew, link)
+			return err
+		case mode.IsRegular():
+			return copyFileTo(ew, p)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	return zw.Close()
+}
+
+func extractArchive(f *os.File, dest string, format string) error {
+	if format == "" {
+		var err error
+		format, err = detectArchiveFormat(f)
+		if err != nil {
+			return err
+		}
+	}
+	switch format {
+	case ArchiveTar:
+		return archive.Untar(f, dest, nil)
+	case ArchiveZip:
+		fi, err := f.Stat()
+		if err != nil {
+			return err
+		}
+		return extractZip(f, fi.Size(), dest)
+	default:
+		return fmt.Errorf("unsupported archive format %q", format)
+	}
+}
+
+// detectArchiveFormat tells zip archives apart from (possibly compressed)
+// tarballs by their magic number.
+func detectArchiveFormat(r io.ReaderAt) (string, error) {
+	magic := make([]byte, 4)
+	n, err := r.ReadAt(magic, 0)
+	if err != nil && err != io.EOF {
+		return "", err
+	}
+	magic = magic[:n]
+	if bytes.Equal(magic, []byte("PK\x03\x04")) || bytes.Equal(magic, []byte("PK\x05\x06")) {
+		return ArchiveZip, nil
+	}
+	return ArchiveTar, nil
+}
+
+func extractZip(r io.ReaderAt, size int64, dest string) error {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return err
+	}
+
+	type dirMeta struct {
+		path string
+		zf   *zip.File
+	}
+	var dirs []dirMeta
+
+	for _, zf := range zr.File {
+		// RootPath keeps entries like "../foo" from escaping dest
+		target, err := fs.RootPath(dest, zf.Name)
+		if err != nil {
+			return err
+		}
+		mode := zf.Mode()
+		switch {
+		case mode.IsDir():
+			if err := os.MkdirAll(target, 0o755); err != nil {
+				return err
+			}
+			dirs = append(dirs, dirMeta{target, zf})
+		case mode&os.ModeSymlink != 0:
+			if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+				return err
+			}
+			link, err := readZipFile(zf)
+			if err != nil {
+				return err
+			}
+			if err := os.Symlink(string(link), target); err != nil {
+				return err
+			}
+		case mode.IsRegular():
+			if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+				return err
+			}
+			if err := writeZipFile(zf, target); err != nil {
+				return err
+			}
+		}
+	}
+
+	// set directory metadata last so that restrictive permissions and
+	// timestamps aren't clobbered by creating their contents
+	for i := len(dirs) - 1; i >= 0; i-- {
+		if err := os.Chmod(dirs[i].path, dirs[i].zf.Mode().Perm()); err != nil {
+			return err
+		}
+		if err := os.Chtimes(dirs[i].path, dirs[i].zf.Modified, dirs[i].zf.Modified); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func readZipFile(zf *zip.File) ([]byte, error) {
+	rc, err := zf.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+	return io.ReadAll(rc)
+}
+
+func writeZipFile(zf *zip.File, target string) error {
+	rc, err := zf.Open()
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	f, err := os.OpenFile(target, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, zf.Mode().Perm())
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if _, err := io.Copy(f, rc); err != nil {
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Chtimes(target, zf.Modified, zf.Modified)
+}
+
+func copyFileTo(w io.Writer, p string) error {
+	f, err := os.Open(p)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = io.Copy(w, f)
+	return err
+}
+
+type nopWriteCloser struct {
+	io.Writer
+}
+
+func (nopWriteCloser) Close() error { return nil }
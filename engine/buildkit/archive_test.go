@@ -0,0 +1,115 @@
+package buildkit
+
+import (
+	"archive/zip"
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestArchiveRoundTrip(t *testing.T) {
+	src := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(src, "sub", "empty"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(src, "foo"), []byte("hello"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(src, "sub", "run.sh"), []byte("echo hi"), 0o755))
+	require.NoError(t, os.Chmod(filepath.Join(src, "sub", "run.sh"), 0o755))
+	require.NoError(t, os.Symlink("../foo", filepath.Join(src, "sub", "link")))
+
+	for _, opts := range []ArchiveOpts{
+		{Format: ArchiveTar},
+		{Format: ArchiveTar, Compression: "gzip"},
+		{Format: ArchiveTar, Compression: "zstd"},
+		{Format: ArchiveZip},
+		{Format: ArchiveZip, Compression: "gzip"},
+	} {
+		opts := opts
+		t.Run(opts.Format+"+"+opts.Compression, func(t *testing.T) {
+			archivePath := filepath.Join(t.TempDir(), "archive")
+			f, err := os.Create(archivePath)
+			require.NoError(t, err)
+			require.NoError(t, writeArchive(f, src, opts))
+			require.NoError(t, f.Close())
+
+			f, err = os.Open(archivePath)
+			require.NoError(t, err)
+			defer f.Close()
+
+			format, err := detectArchiveFormat(f)
+			require.NoError(t, err)
+			require.Equal(t, opts.Format, format)
+
+			dest := t.TempDir()
+			require.NoError(t, extractArchive(f, dest, ""))
+
+			srcDigest, err := contentsDigest(src)
+			require.NoError(t, err)
+			destDigest, err := contentsDigest(dest)
+			require.NoError(t, err)
+			require.Equal(t, srcDigest, destDigest)
+
+			fi, err := os.Stat(filepath.Join(dest, "sub", "run.sh"))
+			require.NoError(t, err)
+			require.Equal(t, os.FileMode(0o755), fi.Mode().Perm())
+		})
+	}
+}
+
+func TestArchiveReproducible(t *testing.T) {
+	write := func(t *testing.T, mtime time.Time) string {
+		t.Helper()
+		dir := t.TempDir()
+		require.NoError(t, os.MkdirAll(filepath.Join(dir, "sub"), 0o755))
+		for _, name := range []string{"b", "a", "sub/c"} {
+			p := filepath.Join(dir, name)
+			require.NoError(t, os.WriteFile(p, []byte(name), 0o644))
+			require.NoError(t, os.Chtimes(p, mtime, mtime))
+		}
+		return dir
+	}
+
+	a := write(t, time.Unix(1000, 0))
+	b := write(t, time.Unix(2000, 0))
+
+	for _, opts := range []ArchiveOpts{
+		{Format: ArchiveTar, Compression: "gzip", Reproducible: true},
+		{Format: ArchiveZip, Compression: "gzip", Reproducible: true},
+	} {
+		var aBuf, bBuf bytes.Buffer
+		require.NoError(t, writeArchive(&aBuf, a, opts))
+		require.NoError(t, writeArchive(&bBuf, b, opts))
+		require.Equal(t, aBuf.Bytes(), bBuf.Bytes(), opts.Format)
+
+		opts.Reproducible = false
+		aBuf.Reset()
+		bBuf.Reset()
+		require.NoError(t, writeArchive(&aBuf, a, opts))
+		require.NoError(t, writeArchive(&bBuf, b, opts))
+		require.NotEqual(t, aBuf.Bytes(), bBuf.Bytes(), opts.Format)
+	}
+}
+
+func TestExtractZipOutsideDest(t *testing.T) {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	w, err := zw.Create("../../escaped")
+	require.NoError(t, err)
+	_, err = w.Write([]byte("nope"))
+	require.NoError(t, err)
+	require.NoError(t, zw.Close())
+
+	parent := t.TempDir()
+	dest := filepath.Join(parent, "dest")
+	require.NoError(t, os.Mkdir(dest, 0o755))
+
+	require.NoError(t, extractZip(bytes.NewReader(buf.Bytes()), int64(buf.Len()), dest))
+
+	_, err = os.Stat(filepath.Join(parent, "escaped"))
+	require.True(t, os.IsNotExist(err))
+	contents, err := os.ReadFile(filepath.Join(dest, "escaped"))
+	require.NoError(t, err)
+	require.Equal(t, "nope", string(contents))
+}
@@ -0,0 +1,76 @@
+package buildkit
+
+import (
+	"context"
+	"sort"
+
+	bkclient "github.com/moby/buildkit/client"
+	"github.com/moby/buildkit/util/bklog"
+	"golang.org/x/sync/errgroup"
+)
+
+// EngineCacheEntries returns the records in the engine's local cache that
+// match the filters, largest first.
+func (c *Client) EngineCacheEntries(ctx context.Context, filters []string) ([]*bkclient.UsageInfo, error) {
+	entries, err := c.Worker.DiskUsage(ctx, bkclient.DiskUsageInfo{
+		Filter: filters,
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].Size > entries[j].Size
+	})
+	return entries, nil
+}
+
+// EngineCacheKeepBytes returns the most space the engine's garbage collection
+// policy lets the local cache use, or 0 if it's unbounded.
+func (c *Client) EngineCacheKeepBytes() int64 {
+	var keepBytes int64
+	for _, policy := range c.Worker.GCPolicy() {
+		if policy.KeepBytes > keepBytes {
+			keepBytes = policy.KeepBytes
+		}
+	}
+	return keepBytes
+}
+
+// PruneEngineCache removes records from the engine's local cache that aren't
+// in use, returning the records that were removed. If no policies are given,
+// the engine's garbage collection policy is used.
+func (c *Client) PruneEngineCache(ctx context.Context, policies ...bkclient.PruneInfo) ([]bkclient.UsageInfo, error) {
+	if len(policies) == 0 {
+		policies = c.Worker.GCPolicy()
+	}
+
+	ch := make(chan bkclient.UsageInfo, 32)
+	var pruned []bkclient.UsageInfo
+
+	eg, egctx := errgroup.WithContext(ctx)
+	eg.Go(func() error {
+		defer close(ch)
+		return c.Worker.Prune(egctx, ch, policies...)
+	})
+	eg.Go(func() error {
+		for r := range ch {
+			pruned = append(pruned, r)
+		}
+		return nil
+	})
+	if err := eg.Wait(); err != nil {
+		return nil, err
+	}
+
+	if len(pruned) > 0 {
+		// same as the Prune RPC, release the metadata of the removed records
+		if cm, ok := c.Worker.CacheManager().(interface {
+			ReleaseUnreferenced(context.Context) error
+		}); ok {
+			if err := cm.ReleaseUnreferenced(ctx); err != nil {
+				bklog.G(ctx).Errorf("failed to release cache metadata: %+v", err)
+			}
+		}
+	}
+	return pruned, nil
+}
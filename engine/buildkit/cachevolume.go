@@ -0,0 +1,161 @@
+package buildkit
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/containerd/continuity/fs"
+	bkclient "github.com/moby/buildkit/client"
+	"github.com/moby/buildkit/snapshot"
+	"github.com/moby/buildkit/solver/llbsolver/mounts"
+	bksolverpb "github.com/moby/buildkit/solver/pb"
+	specs "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/vito/progrock"
+)
+
+// CacheVolumeUsage is the disk usage of the cache mount records that back a
+// cache volume.
+type CacheVolumeUsage struct {
+	// Size is the disk space used by the volume, in bytes.
+	Size int64
+
+	// LastUsedAt is the last time the volume was mounted, or nil if it never
+	// was.
+	LastUsedAt *time.Time
+}
+
+// CacheVolumeSnapshot copies the current contents of the cache volume with
+// the given ID into a definition. A volume that was never used is empty.
+func (c *Client) CacheVolumeSnapshot(
+	ctx context.Context,
+	engineHostPlatform specs.Platform,
+	cacheID string,
+) (*bksolverpb.Definition, error) {
+	ctx, cancel, err := c.withClientCloseCancel(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer cancel()
+
+	tmpDir, err := os.MkdirTemp("", "dagger-cache-snapshot")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temp dir for cache volume snapshot: %w", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	// copy the contents out first so that the volume is only held briefly
+	err = c.withMountedCacheVolume(ctx, cacheID, true, func(dir string) error {
+		return fs.CopyDir(tmpDir, dir)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to copy cache volume: %w", err)
+	}
+
+	ctx, recorder := progrock.WithGroup(ctx, "snapshot cache volume")
+	pbDef, _, err := c.EngineContainerLocalImport(ctx, recorder, engineHostPlatform, tmpDir, nil, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to import cache volume snapshot from engine container filesystem: %w", err)
+	}
+	return pbDef, nil
+}
+
+// CacheVolumeUsage returns the disk usage of the cache volume with the given
+// ID. A volume that was never used has no usage.
+func (c *Client) CacheVolumeUsage(ctx context.Context, cacheID string) (CacheVolumeUsage, error) {
+	var usage CacheVolumeUsage
+
+	mds, err := mounts.SearchCacheDir(ctx, c.Worker.CacheManager(), cacheID)
+	if err != nil {
+		return usage, fmt.Errorf("failed to search cache volume records: %w", err)
+	}
+	if len(mds) == 0 {
+		return usage, nil
+	}
+
+	filters := make([]string, 0, len(mds))
+	for _, md := range mds {
+		filters = append(filters, "id=="+md.ID())
+	}
+	infos, err := c.Worker.DiskUsage(ctx, bkclient.DiskUsageInfo{
+		Filter: filters,
+	})
+	if err != nil {
+		return usage, err
+	}
+	for _, info := range infos {
+		usage.Size += info.Size
+		lastUsedAt := info.LastUsedAt
+		if lastUsedAt == nil {
+			lastUsedAt = &info.CreatedAt
+		}
+		if usage.LastUsedAt == nil || lastUsedAt.After(*usage.LastUsedAt) {
+			usage.LastUsedAt = lastUsedAt
+		}
+	}
+	return usage, nil
+}
+
+// ClearCacheVolume detaches the records that back the cache volume with the
+// given ID, so that its next use starts from an empty directory. Pipelines
+// using the volume right now keep their contents, and the detached records
+// are removed by the engine's garbage collection once they're released.
+func (c *Client) ClearCacheVolume(ctx context.Context, cacheID string) error {
+	return c.Worker.PruneCacheMounts(ctx, []string{cacheID})
+}
+
+// SeedCacheVolume copies the contents of the directory at dirPath in def into
+// the cache volume with the given ID. Files already in the volume are kept
+// unless the directory has a file at the same path.
+func (c *Client) SeedCacheVolume(
+	ctx context.Context,
+	cacheID string,
+	def *bksolverpb.Definition,
+	dirPath string,
+) error {
+	ctx, cancel, err := c.withClientCloseCancel(ctx)
+	if err != nil {
+		return err
+	}
+	defer cancel()
+
+	err = c.withMountedDir(ctx, def, dirPath, func(src string) error {
+		return c.withMountedCacheVolume(ctx, cacheID, false, func(dir string) error {
+			return fs.CopyDir(dir, src)
+		})
+	})
+	if err != nil {
+		return fmt.Errorf("failed to copy directory into cache volume: %w", err)
+	}
+	return nil
+}
+
+// withMountedCacheVolume mounts the cache volume with the given ID and calls
+// fn with the path it's mounted at.
+func (c *Client) withMountedCacheVolume(ctx context.Context, cacheID string, readonly bool, fn func(dir string) error) error {
+	mm := mounts.NewMountManager("dagger cache volume", c.Worker.CacheManager(), c.SessionManager)
+	ref, err := mm.MountableCache(ctx, &bksolverpb.Mount{
+		Dest: "/",
+		CacheOpt: &bksolverpb.CacheOpt{
+			ID:      cacheID,
+			Sharing: bksolverpb.CacheSharingOpt_SHARED,
+		},
+	}, nil, nil)
+	if err != nil {
+		return fmt.Errorf("failed to get cache volume ref: %w", err)
+	}
+	defer ref.Release(context.WithoutCancel(ctx))
+
+	mountable, err := ref.Mount(ctx, readonly, nil)
+	if err != nil {
+		return fmt.Errorf("failed to get cache volume mount: %w", err)
+	}
+	mounter := snapshot.LocalMounter(mountable)
+	dir, err := mounter.Mount()
+	if err != nil {
+		return fmt.Errorf("failed to mount cache volume: %w", err)
+	}
+	defer mounter.Unmount()
+	return fn(dir)
+}
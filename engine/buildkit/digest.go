@@ -0,0 +1,245 @@
+package buildkit
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/containerd/continuity/fs"
+	"github.com/moby/buildkit/cache/contenthash"
+	bkgw "github.com/moby/buildkit/frontend/gateway/client"
+	bksession "github.com/moby/buildkit/session"
+	"github.com/moby/buildkit/snapshot"
+	bksolverpb "github.com/moby/buildkit/solver/pb"
+	"github.com/opencontainers/go-digest"
+)
+
+// FileDigest returns the SHA-256 digest of the contents of the file at the
+// given path in the result of the definition.
+func (c *Client) FileDigest(ctx context.Context, def *bksolverpb.Definition, filePath string) (digest.Digest, error) {
+	var dgst digest.Digest
+	err := c.withMountedResult(ctx, def, func(root string) error {
+		mntFilePath, err := fs.RootPath(root, filePath)
+		if err != nil {
+			return fmt.Errorf("failed to get root path: %w", err)
+		}
+		f, err := os.Open(mntFilePath)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		dgst, err = digest.SHA256.FromReader(f)
+		return err
+	})
+	return dgst, err
+}
+
+// DirectoryDigest returns a digest of the directory at the given path in the
+// result of the definition.
+//
+// By default it's buildkit's content hash of the directory, which covers the
+// names, contents, permissions and ownership of everything in it, but not
+// timestamps. If excludeMetadata is true, only the names, types and contents
+// of entries are covered, so the digest is stable across different
+// permissions and ownership.
+func (c *Client) DirectoryDigest(ctx context.Context, def *bksolverpb.Definition, dirPath string, excludeMetadata bool) (digest.Digest, error) {
+	res, err := c.Solve(ctx, bkgw.SolveRequest{Definition: def, Evaluate: true})
+	if err != nil {
+		return "", err
+	}
+	ref, err := res.SingleRef()
+	if err != nil {
+		return "", err
+	}
+	if ref == nil {
+		// empty directory, i.e. llb.Scratch(); digest it like any other
+		// empty directory
+		var dgst digest.Digest
+		err = withEmptyDir(func(dir string) error {
+			dgst, err = contentsDigest(dir)
+			return err
+		})
+		return dgst, err
+	}
+
+	if !excludeMetadata {
+		cacheRef, err := ref.CacheRef(ctx)
+		if err != nil {
+			return "", err
+		}
+		return contenthash.Checksum(ctx, cacheRef, dirPath, contenthash.ChecksumOpts{}, bksession.NewGroup(c.ID()))
+	}
+
+	var dgst digest.Digest
+	err = ref.withMountedDir(ctx, dirPath, func(dir string) error {
+		dgst, err = contentsDigest(dir)
+		return err
+	})
+	return dgst, err
+}
+
+// contentsDigest digests the names, types and contents of everything in a
+// local directory, in lexical order.
+func contentsDigest(dir string) (digest.Digest, error) {
+	digester := digest.SHA256.Digester()
+	err := filepath.WalkDir(dir, func(p string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if p == dir {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		switch {
+		case d.IsDir():
+			fmt.Fprintf(digester.Hash(), "dir %q\n", rel)
+		case d.Type()&os.ModeSymlink != 0:
+			target, err := os.Readlink(p)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(digester.Hash(), "symlink %q %q\n", rel, target)
+		case d.Type().IsRegular():
+			f, err := os.Open(p)
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			fileDigest := digest.SHA256.Digester()
+			if _, err := io.Copy(fileDigest.Hash(), f); err != nil {
+				return err
+			}
+			fmt.Fprintf(digester.Hash(), "file %q %s\n", rel, fileDigest.Digest())
+		default:
+			fmt.Fprintf(digester.Hash(), "%s %q\n", d.Type(), rel)
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return digester.Digest(), nil
+}
+
+func (c *Client) withMountedResult(ctx context.Context, def *bksolverpb.Definition, fn func(root string) error) error {
+	res, err := c.Solve(ctx, bkgw.SolveRequest{Definition: def, Evaluate: true})
+	if err != nil {
+		return err
+	}
+	ref, err := res.SingleRef()
+	if err != nil {
+		return err
+	}
+	if ref == nil {
+		return fmt.Errorf("empty reference")
+	}
+	return ref.withMount(ctx, fn)
+}
+
+// withMountedDir mounts the result of the definition and calls fn with the
+// local path of the directory at dirPath in it. An empty result is treated as
+// an empty directory.
+func (c *Client) withMountedDir(ctx context.Context, def *bksolverpb.Definition, dirPath string, fn func(dir string) error) error {
+	res, err := c.Solve(ctx, bkgw.SolveRequest{Definition: def, Evaluate: true})
+	if err != nil {
+		return err
+	}
+	ref, err := res.SingleRef()
+	if err != nil {
+		return err
+	}
+	if ref == nil {
+		return withEmptyDir(fn)
+	}
+	return ref.withMountedDir(ctx, dirPath, fn)
+}
+
+// withMountedChanges is like withMountedDir for the result of a diff, in which
+// dirPath is missing if nothing under it changed. If def is nil, fn is called
+// with an empty path.
+func (c *Client) withMountedChanges(ctx context.Context, def *bksolverpb.Definition, dirPath string, fn func(dir string) error) error {
+	if def == nil {
+		return fn("")
+	}
+	res, err := c.Solve(ctx, bkgw.SolveRequest{Definition: def, Evaluate: true})
+	if err != nil {
+		return err
+	}
+	ref, err := res.SingleRef()
+	if err != nil {
+		return err
+	}
+	if ref == nil {
+		return withEmptyDir(fn)
+	}
+	return ref.withMount(ctx, func(root string) error {
+		mntDirPath, err := fs.RootPath(root, dirPath)
+		if err != nil {
+			return fmt.Errorf("failed to get root path: %w", err)
+		}
+		fi, err := os.Stat(mntDirPath)
+		switch {
+		case os.IsNotExist(err):
+			return withEmptyDir(fn)
+		case err != nil:
+			return err
+		case !fi.IsDir():
+			return fmt.Errorf("%s: not a directory", dirPath)
+		}
+		return fn(mntDirPath)
+	})
+}
+
+// withEmptyDir calls fn with the path of a temporary empty directory.
+func withEmptyDir(fn func(dir string) error) error {
+	emptyDir, err := os.MkdirTemp("", "dagger-empty")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(emptyDir)
+	return fn(emptyDir)
+}
+
+func (r *ref) withMountedDir(ctx context.Context, dirPath string, fn func(dir string) error) error {
+	return r.withMount(ctx, func(root string) error {
+		mntDirPath, err := fs.RootPath(root, dirPath)
+		if err != nil {
+			return fmt.Errorf("failed to get root path: %w", err)
+		}
+		fi, err := os.Stat(mntDirPath)
+		switch {
+		case os.IsNotExist(err):
+			return fmt.Errorf("%s: no such file or directory", dirPath)
+		case err != nil:
+			return err
+		case !fi.IsDir():
+			return fmt.Errorf("%s: not a directory", dirPath)
+		}
+		return fn(mntDirPath)
+	})
+}
+
+// withMount mounts the ref read-only on the local filesystem and calls fn
+// with the path it's mounted at.
+func (r *ref) withMount(ctx context.Context, fn func(root string) error) error {
+	ctx = withOutgoingContext(ctx)
+	mountable, err := r.getMountable(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get mountable: %w", err)
+	}
+	if mountable == nil {
+		return fmt.Errorf("empty reference")
+	}
+	mounter := snapshot.LocalMounter(mountable)
+	mountPath, err := mounter.Mount()
+	if err != nil {
+		return fmt.Errorf("failed to mount: %w", err)
+	}
+	defer mounter.Unmount()
+	return fn(mountPath)
+}
@@ -0,0 +1,53 @@
+package buildkit
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestContentsDigest(t *testing.T) {
+	write := func(t *testing.T, dir, name, content string, perm os.FileMode) {
+		t.Helper()
+		p := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(p), 0o755))
+		require.NoError(t, os.WriteFile(p, []byte(content), perm))
+		require.NoError(t, os.Chmod(p, perm))
+	}
+
+	a := t.TempDir()
+	write(t, a, "foo", "hello", 0o644)
+	write(t, a, "sub/bar", "world", 0o644)
+	require.NoError(t, os.Symlink("foo", filepath.Join(a, "link")))
+
+	b := t.TempDir()
+	write(t, b, "foo", "hello", 0o600)
+	write(t, b, "sub/bar", "world", 0o755)
+	require.NoError(t, os.Symlink("foo", filepath.Join(b, "link")))
+
+	aDigest, err := contentsDigest(a)
+	require.NoError(t, err)
+	bDigest, err := contentsDigest(b)
+	require.NoError(t, err)
+	require.Equal(t, aDigest, bDigest, "permissions should not affect the digest")
+
+	write(t, b, "sub/bar", "world!", 0o755)
+	bDigest, err = contentsDigest(b)
+	require.NoError(t, err)
+	require.NotEqual(t, aDigest, bDigest)
+
+	c := t.TempDir()
+	write(t, c, "foo", "hello", 0o644)
+	write(t, c, "sub/baz", "world", 0o644)
+	require.NoError(t, os.Symlink("foo", filepath.Join(c, "link")))
+	cDigest, err := contentsDigest(c)
+	require.NoError(t, err)
+	require.NotEqual(t, aDigest, cDigest, "names should affect the digest")
+
+	require.NoError(t, os.Mkdir(filepath.Join(c, "empty"), 0o755))
+	emptyDigest, err := contentsDigest(c)
+	require.NoError(t, err)
+	require.NotEqual(t, cDigest, emptyDigest, "empty directories should affect the digest")
+}
@@ -1,5 +1,7 @@
 package buildkit
 
+import "time"
+
 // ExecError is an error that occurred while executing an `Op_Exec`.
 type ExecError struct {
 	original error
@@ -26,3 +28,21 @@ func (e *ExecError) Extensions() map[string]interface{} {
 		"stderr":   e.Stderr,
 	}
 }
+
+// ExecTimeoutError is an ExecError for an exec that was killed because it ran
+// longer than its timeout.
+type ExecTimeoutError struct {
+	*ExecError
+	Timeout time.Duration
+}
+
+func (e *ExecTimeoutError) Unwrap() error {
+	return e.ExecError
+}
+
+func (e *ExecTimeoutError) Extensions() map[string]interface{} {
+	ext := e.ExecError.Extensions()
+	ext["_type"] = "EXEC_TIMEOUT_ERROR"
+	ext["timeout"] = int(e.Timeout.Seconds())
+	return ext
+}
@@ -0,0 +1,184 @@
+package buildkit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"syscall"
+
+	"github.com/dagger/dagger/engine"
+	bksolverpb "github.com/moby/buildkit/solver/pb"
+	"github.com/opencontainers/go-digest"
+)
+
+const (
+	ExportAdded    = "A"
+	ExportModified = "M"
+	ExportDeleted  = "D"
+)
+
+// LocalDirExportDryRun reports the changes that LocalDirExport would make to
+// destPath on the caller's host, without writing anything. Each change is
+// formatted as "<A|M|D> <path>", with directories ending in a slash.
+func (c *Client) LocalDirExportDryRun(
+	ctx context.Context,
+	def *bksolverpb.Definition,
+	destPath string,
+	wipe bool,
+) ([]string, error) {
+	ctx, cancel, err := c.withClientCloseCancel(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer cancel()
+
+	destPath = path.Clean(destPath)
+	if destPath == ".." || strings.HasPrefix(destPath, "../") {
+		return nil, fmt.Errorf("path %q escapes workdir; use an absolute path instead", destPath)
+	}
+
+	var destEntries []engine.HostEntry
+	stat, err := c.StatCallerHostPath(ctx, destPath, false)
+	switch {
+	case err != nil && strings.Contains(err.Error(), "no such file or directory"):
+		// nothing there yet, so everything will be added
+	case err != nil:
+		return nil, fmt.Errorf("failed to stat export path: %w", err)
+	case !os.FileMode(stat.Mode).IsDir():
+		return nil, fmt.Errorf("destination %q is not a directory", destPath)
+	default:
+		destEntries, err = c.WalkCallerHostDir(ctx, destPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to walk export path: %w", err)
+		}
+	}
+
+	var changes []string
+	err = c.withMountedDir(ctx, def, "/", func(src string) error {
+		changes, err = exportChanges(src, destEntries, wipe)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to compare with export path: %w", err)
+	}
+	return changes, nil
+}
+
+// exportChanges compares the directory to export at src with the entries
+// already at the destination. Ownership and timestamps are ignored since
+// exports don't preserve them.
+func exportChanges(src string, destEntries []engine.HostEntry, wipe bool) ([]string, error) {
+	type change struct {
+		path string
+		op   string
+		dir  bool
+	}
+	var changes []change
+
+	dest := make(map[string]engine.HostEntry, len(destEntries))
+	for _, entry := range destEntries {
+		dest[entry.Path] = entry
+	}
+
+	err := filepath.WalkDir(src, func(p string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if p == src {
+			return nil
+		}
+		rel, err := filepath.Rel(src, p)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		srcInfo, err := d.Info()
+		if err != nil {
+			return err
+		}
+
+		op := ExportAdded
+		if destEntry, ok := dest[rel]; ok {
+			modified, err := entryModified(p, srcInfo, destEntry)
+			if err != nil {
+				return err
+			}
+			if !modified {
+				return nil
+			}
+			op = ExportModified
+		}
+		changes = append(changes, change{rel, op, d.IsDir()})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if wipe {
+		for _, entry := range destEntries {
+			_, err := os.Lstat(filepath.Join(src, filepath.FromSlash(entry.Path)))
+			switch {
+			case isNotExist(err):
+				changes = append(changes, change{entry.Path, ExportDeleted, entry.Mode.IsDir()})
+			case err != nil:
+				return nil, err
+			}
+		}
+	}
+
+	sort.SliceStable(changes, func(i, j int) bool {
+		return changes[i].path < changes[j].path
+	})
+
+	lines := make([]string, 0, len(changes))
+	for _, c := range changes {
+		name := c.path
+		if c.dir {
+			name += "/"
+		}
+		lines = append(lines, c.op+" "+name)
+	}
+	return lines, nil
+}
+
+// entryModified reports whether exporting the entry at srcPath over the one
+// at the destination would change it.
+func entryModified(srcPath string, srcInfo os.FileInfo, dest engine.HostEntry) (bool, error) {
+	if srcInfo.Mode().Type() != dest.Mode.Type() || srcInfo.Mode().Perm() != dest.Mode.Perm() {
+		return true, nil
+	}
+	switch {
+	case srcInfo.Mode()&os.ModeSymlink != 0:
+		srcLink, err := os.Readlink(srcPath)
+		if err != nil {
+			return false, err
+		}
+		return srcLink != dest.Target, nil
+	case srcInfo.Mode().IsRegular():
+		if srcInfo.Size() != dest.Size {
+			return true, nil
+		}
+		f, err := os.Open(srcPath)
+		if err != nil {
+			return false, err
+		}
+		defer f.Close()
+		dgst, err := digest.SHA256.FromReader(f)
+		if err != nil {
+			return false, err
+		}
+		return dgst != dest.Digest, nil
+	}
+	return false, nil
+}
+
+// isNotExist also treats a path under a non-directory as missing.
+func isNotExist(err error) bool {
+	return os.IsNotExist(err) || errors.Is(err, syscall.ENOTDIR)
+}
@@ -0,0 +1,73 @@
+package buildkit
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/dagger/dagger/engine"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExportChanges(t *testing.T) {
+	write := func(t *testing.T, dir, name, content string, perm os.FileMode) {
+		t.Helper()
+		p := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(p), 0o755))
+		require.NoError(t, os.WriteFile(p, []byte(content), perm))
+		require.NoError(t, os.Chmod(p, perm))
+	}
+
+	src := t.TempDir()
+	write(t, src, "same", "same", 0o644)
+	write(t, src, "modified", "after", 0o644)
+	write(t, src, "chmodded", "run", 0o755)
+	write(t, src, "new/file", "hi", 0o644)
+	require.NoError(t, os.Symlink("same", filepath.Join(src, "link")))
+
+	dest := t.TempDir()
+	write(t, dest, "same", "same", 0o644)
+	write(t, dest, "modified", "befor", 0o644)
+	write(t, dest, "chmodded", "run", 0o644)
+	write(t, dest, "stale/file", "bye", 0o644)
+	require.NoError(t, os.Symlink("modified", filepath.Join(dest, "link")))
+
+	var destEntries []engine.HostEntry
+	require.NoError(t, engine.WalkHostDir(dest, func(entry engine.HostEntry) error {
+		destEntries = append(destEntries, entry)
+		return nil
+	}))
+
+	changes, err := exportChanges(src, destEntries, false)
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		"M chmodded",
+		"M link",
+		"M modified",
+		"A new/",
+		"A new/file",
+	}, changes)
+
+	changes, err = exportChanges(src, destEntries, true)
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		"M chmodded",
+		"M link",
+		"M modified",
+		"A new/",
+		"A new/file",
+		"D stale/",
+		"D stale/file",
+	}, changes)
+
+	changes, err = exportChanges(src, nil, true)
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		"A chmodded",
+		"A link",
+		"A modified",
+		"A new/",
+		"A new/file",
+		"A same",
+	}, changes)
+}
@@ -3,6 +3,7 @@ package buildkit
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
@@ -175,12 +176,63 @@ func (c *Client) StatCallerHostPath(ctx context.Context, path string, returnAbsP
 	return &msg, nil
 }
 
+// WalkCallerHostDir returns a HostEntry for everything under the directory at
+// path on the caller's host, which is walked there so that none of its
+// contents need to be transferred.
+func (c *Client) WalkCallerHostDir(ctx context.Context, path string) ([]engine.HostEntry, error) {
+	ctx, cancel, err := c.withClientCloseCancel(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer cancel()
+
+	clientMetadata, err := engine.ClientMetadataFromContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get requester session ID: %s", err)
+	}
+
+	ctx = engine.LocalImportOpts{
+		Path:        path,
+		WalkDirOnly: true,
+	}.AppendToOutgoingContext(ctx)
+
+	clientCaller, err := c.SessionManager.Get(ctx, clientMetadata.ClientID, false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get requester session: %s", err)
+	}
+	diffCopyClient, err := filesync.NewFileSyncClient(clientCaller.Conn()).DiffCopy(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create diff copy client: %s", err)
+	}
+	defer diffCopyClient.CloseSend()
+
+	var entries []engine.HostEntry
+	for {
+		msg := filesync.BytesMessage{}
+		if err := diffCopyClient.RecvMsg(&msg); err != nil {
+			if errors.Is(err, io.EOF) {
+				return entries, nil
+			}
+			return nil, fmt.Errorf("failed to receive host entry: %w", err)
+		}
+		var entry engine.HostEntry
+		if err := json.Unmarshal(msg.Data, &entry); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal host entry: %w", err)
+		}
+		entries = append(entries, entry)
+	}
+}
+
 func (c *Client) LocalDirExport(
 	ctx context.Context,
 	def *bksolverpb.Definition,
 	destPath string,
+	wipe bool,
 ) (rerr error) {
-	ctx = bklog.WithLogger(ctx, bklog.G(ctx).WithField("export_path", destPath))
+	ctx = bklog.WithLogger(ctx, bklog.G(ctx).
+		WithField("export_path", destPath).
+		WithField("wipe", wipe),
+	)
 	bklog.G(ctx).Debug("exporting local dir")
 	defer func() {
 		lg := bklog.G(ctx)
@@ -227,6 +279,7 @@ func (c *Client) LocalDirExport(
 
 	ctx = engine.LocalExportOpts{
 		Path: destPath,
+		Wipe: wipe,
 	}.AppendToOutgoingContext(ctx)
 
 	_, descRef, err := expInstance.Export(ctx, cacheRes, nil, clientMetadata.ClientID)
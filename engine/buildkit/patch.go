@@ -0,0 +1,1125 @@
+package buildkit
+
+import (
+	"bufio"
+	"bytes"
+	"compress/zlib"
+	"context"
+	"crypto/sha1" //nolint:gosec // git's hash, not used for security
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"github.com/containerd/continuity/fs"
+	bksolverpb "github.com/moby/buildkit/solver/pb"
+	specs "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/pmezard/go-difflib/difflib"
+	"github.com/tonistiigi/fsutil"
+	"github.com/vito/progrock"
+)
+
+// patchContext is the number of lines of context around each hunk of a
+// generated patch, as with diff -u.
+const patchContext = 3
+
+// DirectoryPatch writes a unified diff from the directory at dirPath in def
+// to the directory at otherDirPath in otherDef to a file named fileName,
+// returning a definition containing just that file.
+//
+// If changesDef is not nil, it's the diff of def and otherDef, and only the
+// files in it at otherDirPath are compared, besides any that were removed.
+// Otherwise every file in both directories is compared.
+func (c *Client) DirectoryPatch(
+	ctx context.Context,
+	engineHostPlatform specs.Platform,
+	def *bksolverpb.Definition,
+	dirPath string,
+	otherDef *bksolverpb.Definition,
+	otherDirPath string,
+	changesDef *bksolverpb.Definition,
+	fileName string,
+) (*bksolverpb.Definition, error) {
+	ctx, cancel, err := c.withClientCloseCancel(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer cancel()
+
+	tmpDir, err := os.MkdirTemp("", "dagger-patch")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temp dir for patch: %w", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	err = c.withMountedDir(ctx, def, dirPath, func(dir string) error {
+		return c.withMountedDir(ctx, otherDef, otherDirPath, func(otherDir string) error {
+			return c.withMountedChanges(ctx, changesDef, otherDirPath, func(changesDir string) error {
+				f, err := os.Create(filepath.Join(tmpDir, fileName))
+				if err != nil {
+					return err
+				}
+				defer f.Close()
+				w := bufio.NewWriter(f)
+				if err := writePatch(w, dir, otherDir, changesDir); err != nil {
+					return err
+				}
+				if err := w.Flush(); err != nil {
+					return err
+				}
+				return f.Close()
+			})
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to write patch: %w", err)
+	}
+
+	ctx, recorder := progrock.WithGroup(ctx, "directory to patch")
+	pbDef, _, err := c.EngineContainerLocalImport(ctx, recorder, engineHostPlatform, tmpDir, nil, []string{fileName})
+	if err != nil {
+		return nil, fmt.Errorf("failed to import patch from engine container filesystem: %w", err)
+	}
+	return pbDef, nil
+}
+
+// ApplyPatch applies the patch at patchPath in patchDef to the directory at
+// dirPath in def, stripping strip leading components from the paths in the
+// patch.
+//
+// Every file created or modified by the patch is written to a new directory,
+// which is returned as a definition along with the paths of the files written
+// to it and of any files the patch removes. Nothing is returned if any hunk
+// fails to apply.
+func (c *Client) ApplyPatch(
+	ctx context.Context,
+	engineHostPlatform specs.Platform,
+	def *bksolverpb.Definition,
+	dirPath string,
+	patchDef *bksolverpb.Definition,
+	patchPath string,
+	strip int,
+) (*bksolverpb.Definition, []string, []string, error) {
+	ctx, cancel, err := c.withClientCloseCancel(ctx)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	defer cancel()
+
+	var patches []*filePatch
+	err = c.withMountedResult(ctx, patchDef, func(root string) error {
+		mntPatchPath, err := fs.RootPath(root, patchPath)
+		if err != nil {
+			return fmt.Errorf("failed to get root path: %w", err)
+		}
+		f, err := os.Open(mntPatchPath)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		patches, err = parsePatch(f, strip)
+		return err
+	})
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to parse patch: %w", err)
+	}
+
+	tmpDir, err := os.MkdirTemp("", "dagger-patched")
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to create temp dir for patched files: %w", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	var written, removed []string
+	err = c.withMountedDir(ctx, def, dirPath, func(dir string) error {
+		written, removed, err = applyPatches(dir, tmpDir, patches)
+		return err
+	})
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	ctx, recorder := progrock.WithGroup(ctx, "apply patch")
+	pbDef, _, err := c.EngineContainerLocalImport(ctx, recorder, engineHostPlatform, tmpDir, nil, nil)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to import patched files from engine container filesystem: %w", err)
+	}
+	return pbDef, written, removed, nil
+}
+
+// filePatch is the part of a patch that applies to a single file.
+type filePatch struct {
+	// oldPath and newPath are the paths of the file before and after the
+	// patch; oldPath is empty for new files and newPath is empty for removed
+	// files.
+	oldPath string
+	newPath string
+
+	// newMode is the mode of the file after the patch, if it changes.
+	newMode os.FileMode
+
+	hunks []*hunk
+
+	// binary is the contents of the file after the patch, for git binary
+	// patches, which replace the whole file rather than having hunks.
+	binary []byte
+}
+
+type hunk struct {
+	oldStart, oldLines int
+	newStart, newLines int
+
+	// lines are the lines of the hunk prefixed with ' ', '-' or '+'. Each
+	// ends in a newline, unless it's the last line of a file without one.
+	lines []string
+}
+
+func (h *hunk) header() string {
+	return fmt.Sprintf("@@ -%d,%d +%d,%d @@", h.oldStart, h.oldLines, h.newStart, h.newLines)
+}
+
+// split returns the lines the hunk expects to find and the lines it replaces
+// them with.
+func (h *hunk) split() (before, after []string) {
+	for _, line := range h.lines {
+		switch line[0] {
+		case ' ':
+			before = append(before, line[1:])
+			after = append(after, line[1:])
+		case '-':
+			before = append(before, line[1:])
+		case '+':
+			after = append(after, line[1:])
+		}
+	}
+	return before, after
+}
+
+var hunkHeader = regexp.MustCompile(`^@@ -(\d+)(?:,(\d+))? \+(\d+)(?:,(\d+))? @@`)
+
+// parsePatch parses a unified diff, as written by diff -u, git diff or git
+// format-patch. Anything outside of the diffs themselves, like commit
+// messages, is ignored.
+func parsePatch(r io.Reader, strip int) ([]*filePatch, error) {
+	var patches []*filePatch
+	var cur *filePatch
+	var curHunk *hunk
+	var oldLeft, newLeft int
+
+	// state of a git binary patch, which has a literal of the contents after
+	// the patch followed by one of the contents before it
+	var isBinary, inLiteral bool
+	var literalSize int
+	var literalLines []string
+	endLiteral := func() error {
+		inLiteral = false
+		dt, err := decodeBinaryLiteral(literalSize, literalLines)
+		if err != nil {
+			return err
+		}
+		if cur.binary == nil {
+			cur.binary = dt
+		}
+		return nil
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, 1024*1024)
+	scanner.Split(scanLinesWithNewline)
+	lineNo := 0
+	for scanner.Scan() {
+		line := scanner.Text()
+		lineNo++
+
+		if inLiteral {
+			if text := strings.TrimSuffix(line, "\n"); text != "" {
+				literalLines = append(literalLines, text)
+				continue
+			}
+			if err := endLiteral(); err != nil {
+				return nil, fmt.Errorf("line %d: %w", lineNo, err)
+			}
+			continue
+		}
+
+		if curHunk != nil && (oldLeft > 0 || newLeft > 0) {
+			body := line
+			if body == "\n" {
+				// some editors strip the trailing space of empty context lines
+				body = " \n"
+			}
+			switch body[0] {
+			case ' ':
+				oldLeft--
+				newLeft--
+			case '-':
+				oldLeft--
+			case '+':
+				newLeft--
+			case '\\':
+				curHunk.noNewline()
+				continue
+			default:
+				return nil, fmt.Errorf("line %d: unexpected line in hunk: %q", lineNo, strings.TrimSuffix(line, "\n"))
+			}
+			if oldLeft < 0 || newLeft < 0 {
+				return nil, fmt.Errorf("line %d: hunk is longer than its header says", lineNo)
+			}
+			curHunk.lines = append(curHunk.lines, body)
+			continue
+		}
+
+		text := strings.TrimSuffix(line, "\n")
+		switch {
+		case strings.HasPrefix(text, `\`) && curHunk != nil:
+			// "\ No newline at end of file" after the last line of a hunk
+			curHunk.noNewline()
+		case strings.HasPrefix(text, "diff --git "):
+			cur = &filePatch{}
+			curHunk = nil
+			isBinary = false
+			patches = append(patches, cur)
+			if p, ok := gitHeaderPath(strings.TrimPrefix(text, "diff --git "), strip); ok {
+				cur.oldPath, cur.newPath = p, p
+			}
+		case strings.HasPrefix(text, "--- ") && (cur == nil || len(cur.hunks) > 0):
+			// a plain unified diff without git headers
+			cur = &filePatch{}
+			curHunk = nil
+			patches = append(patches, cur)
+			fallthrough
+		case strings.HasPrefix(text, "--- ") && cur != nil:
+			p, err := patchPath(strings.TrimPrefix(text, "--- "), strip)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", lineNo, err)
+			}
+			cur.oldPath = p
+		case strings.HasPrefix(text, "+++ ") && cur != nil:
+			p, err := patchPath(strings.TrimPrefix(text, "+++ "), strip)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", lineNo, err)
+			}
+			cur.newPath = p
+		case strings.HasPrefix(text, "new file mode ") && cur != nil:
+			cur.oldPath = ""
+			mode, err := parseGitMode(strings.TrimPrefix(text, "new file mode "))
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", lineNo, err)
+			}
+			cur.newMode = mode
+		case strings.HasPrefix(text, "new mode ") && cur != nil:
+			mode, err := parseGitMode(strings.TrimPrefix(text, "new mode "))
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", lineNo, err)
+			}
+			cur.newMode = mode
+		case strings.HasPrefix(text, "deleted file mode ") && cur != nil:
+			cur.newPath = ""
+		case strings.HasPrefix(text, "rename from ") && cur != nil:
+			cur.oldPath = unquotePath(strings.TrimPrefix(text, "rename from "))
+		case strings.HasPrefix(text, "rename to ") && cur != nil:
+			cur.newPath = unquotePath(strings.TrimPrefix(text, "rename to "))
+		case strings.HasPrefix(text, "GIT binary patch") && cur != nil:
+			isBinary = true
+		case strings.HasPrefix(text, "literal ") && isBinary:
+			size, err := strconv.Atoi(strings.TrimPrefix(text, "literal "))
+			if err != nil || size < 0 {
+				return nil, fmt.Errorf("line %d: malformed binary literal: %q", lineNo, text)
+			}
+			inLiteral, literalSize, literalLines = true, size, nil
+		case strings.HasPrefix(text, "delta ") && isBinary:
+			return nil, fmt.Errorf("line %d: binary deltas are not supported", lineNo)
+		case strings.HasPrefix(text, "Binary files "):
+			return nil, fmt.Errorf("line %d: binary changes without their contents are not supported, as written by git diff without --binary", lineNo)
+		case strings.HasPrefix(text, "@@ ") && cur != nil:
+			m := hunkHeader.FindStringSubmatch(text)
+			if m == nil {
+				return nil, fmt.Errorf("line %d: malformed hunk header: %q", lineNo, text)
+			}
+			curHunk = &hunk{
+				oldStart: atoiDefault(m[1], 0),
+				oldLines: atoiDefault(m[2], 1),
+				newStart: atoiDefault(m[3], 0),
+				newLines: atoiDefault(m[4], 1),
+			}
+			oldLeft, newLeft = curHunk.oldLines, curHunk.newLines
+			cur.hunks = append(cur.hunks, curHunk)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if inLiteral {
+		if err := endLiteral(); err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNo, err)
+		}
+	}
+	if curHunk != nil && (oldLeft > 0 || newLeft > 0) {
+		return nil, fmt.Errorf("line %d: patch ends in the middle of a hunk", lineNo)
+	}
+	if len(patches) == 0 {
+		return nil, errors.New("no changes found in patch")
+	}
+	return patches, nil
+}
+
+// noNewline marks the last line of the hunk as not ending in a newline.
+func (h *hunk) noNewline() {
+	if len(h.lines) == 0 {
+		return
+	}
+	last := len(h.lines) - 1
+	h.lines[last] = strings.TrimSuffix(h.lines[last], "\n")
+}
+
+// patchedFile is a file as of some point partway through a series of patches.
+type patchedFile struct {
+	content  string
+	mode     os.FileMode
+	uid, gid uint32
+	// removed is set if the file no longer exists at this point.
+	removed bool
+}
+
+// applyPatches applies the patches to the files in dir in order, so that each
+// patch sees the changes made by the ones before it, e.g. a series of commits
+// that creates a file and then edits it.
+//
+// Every file the series leaves created or modified is written to outDir with
+// its final mode and the ownership of the file it replaces (or, for new files,
+// of the closest existing parent directory). The paths of those files are
+// returned along with the paths of the files in dir that the series removes.
+func applyPatches(dir, outDir string, patches []*filePatch) ([]string, []string, error) {
+	overlay := map[string]*patchedFile{}
+
+	// lookup returns the file at p as left by the patches applied so far, or
+	// nil if there is none.
+	lookup := func(p string) (*patchedFile, error) {
+		if f, ok := overlay[p]; ok {
+			if f.removed {
+				return nil, nil
+			}
+			return f, nil
+		}
+		src, err := rootPathNoFollow(dir, p)
+		if err != nil {
+			return nil, err
+		}
+		st, err := fsutil.Stat(src)
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				return nil, nil
+			}
+			return nil, err
+		}
+		mode := os.FileMode(st.Mode)
+		if !isPatchable(mode) {
+			return nil, fmt.Errorf("%s: not a regular file or symlink", p)
+		}
+		f, err := readPatchable(src, mode)
+		if err != nil {
+			return nil, err
+		}
+		return &patchedFile{
+			content: string(f.content),
+			mode:    f.mode,
+			uid:     st.Uid,
+			gid:     st.Gid,
+		}, nil
+	}
+
+	for _, fp := range patches {
+		var f patchedFile
+		if fp.oldPath != "" {
+			old, err := lookup(fp.oldPath)
+			if err != nil {
+				return nil, nil, err
+			}
+			if old == nil {
+				return nil, nil, fmt.Errorf("%s: no such file to patch", fp.oldPath)
+			}
+			f = *old
+		} else if fp.newPath != "" {
+			existing, err := lookup(fp.newPath)
+			if err != nil {
+				return nil, nil, err
+			}
+			if existing != nil {
+				return nil, nil, fmt.Errorf("%s: file to create already exists", fp.newPath)
+			}
+			uid, gid, err := parentOwner(dir, fp.newPath)
+			if err != nil {
+				return nil, nil, err
+			}
+			f = patchedFile{mode: 0o644, uid: uid, gid: gid}
+		}
+
+		name := fp.newPath
+		if name == "" {
+			name = fp.oldPath
+		}
+		patched := string(fp.binary)
+		if fp.binary == nil {
+			var err error
+			patched, err = applyHunks(name, f.content, fp.hunks)
+			if err != nil {
+				return nil, nil, err
+			}
+		}
+
+		if fp.oldPath != "" && fp.oldPath != fp.newPath {
+			overlay[fp.oldPath] = &patchedFile{removed: true}
+		}
+		if fp.newPath == "" {
+			if patched != "" {
+				return nil, nil, fmt.Errorf("%s: removed file still has contents after patching", fp.oldPath)
+			}
+			continue
+		}
+
+		f.content = patched
+		if fp.newMode != 0 {
+			f.mode = fp.newMode
+		}
+		if f.mode&os.ModeSymlink != 0 && (patched == "" || strings.Contains(patched, "\n")) {
+			return nil, nil, fmt.Errorf("%s: invalid symlink target %q", name, patched)
+		}
+		overlay[fp.newPath] = &f
+	}
+
+	paths := make([]string, 0, len(overlay))
+	for p := range overlay {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+
+	var written, removed []string
+	for _, p := range paths {
+		f := overlay[p]
+		if f.removed {
+			// files created and then removed by the series never existed in dir
+			src, err := fs.RootPath(dir, p)
+			if err != nil {
+				return nil, nil, err
+			}
+			if _, err := os.Lstat(src); err == nil {
+				removed = append(removed, p)
+			}
+			continue
+		}
+		dest, err := fs.RootPath(outDir, p)
+		if err != nil {
+			return nil, nil, err
+		}
+		if err := os.MkdirAll(filepath.Dir(dest), 0o755); err != nil {
+			return nil, nil, err
+		}
+		if f.mode&os.ModeSymlink != 0 {
+			if err := os.Symlink(f.content, dest); err != nil {
+				return nil, nil, err
+			}
+		} else {
+			if err := os.WriteFile(dest, []byte(f.content), f.mode); err != nil {
+				return nil, nil, err
+			}
+			if err := os.Chmod(dest, f.mode); err != nil {
+				return nil, nil, err
+			}
+		}
+		if err := os.Lchown(dest, int(f.uid), int(f.gid)); err != nil {
+			return nil, nil, err
+		}
+		written = append(written, p)
+	}
+	return written, removed, nil
+}
+
+// parentOwner returns the ownership of the closest existing parent directory
+// of p in dir, which is what a new file at p is given.
+func parentOwner(dir, p string) (uint32, uint32, error) {
+	for {
+		p = path.Dir(p)
+		src, err := fs.RootPath(dir, p)
+		if err != nil {
+			return 0, 0, err
+		}
+		st, err := fsutil.Stat(src)
+		if err == nil {
+			return st.Uid, st.Gid, nil
+		}
+		if !errors.Is(err, os.ErrNotExist) || p == "." || p == "/" {
+			return 0, 0, err
+		}
+	}
+}
+
+// applyHunks applies the hunks to the content of the named file. Like patch,
+// a hunk that doesn't match at the line it names is looked for at nearby
+// lines, so that earlier changes to the file don't stop it from applying.
+func applyHunks(name string, content string, hunks []*hunk) (string, error) {
+	lines := splitLines(content)
+	var out []string
+	pos := 0
+	offset := 0
+	for i, h := range hunks {
+		before, after := h.split()
+
+		want := h.oldStart - 1 + offset
+		if h.oldLines == 0 {
+			// pure insertions name the line they follow
+			want = h.oldStart + offset
+		}
+		at := findLines(lines, before, want, pos)
+		if at < 0 {
+			return "", hunkConflict(name, i+1, h, lines, before, want)
+		}
+
+		out = append(out, lines[pos:at]...)
+		out = append(out, after...)
+		pos = at + len(before)
+		offset += at - want
+	}
+	out = append(out, lines[pos:]...)
+	return strings.Join(out, ""), nil
+}
+
+// findLines returns the index of the occurrence of want in lines closest to
+// the given index, not before min, or -1 if there is none.
+func findLines(lines, want []string, at, min int) int {
+	matches := func(i int) bool {
+		if i < min || i+len(want) > len(lines) {
+			return false
+		}
+		for j, line := range want {
+			if lines[i+j] != line {
+				return false
+			}
+		}
+		return true
+	}
+	for delta := 0; at-delta >= min || at+delta <= len(lines); delta++ {
+		if matches(at - delta) {
+			return at - delta
+		}
+		if delta > 0 && matches(at+delta) {
+			return at + delta
+		}
+	}
+	return -1
+}
+
+func hunkConflict(name string, n int, h *hunk, lines, before []string, at int) error {
+	msg := fmt.Sprintf("%s: hunk #%d (%s) does not apply", name, n, h.header())
+	if at < 0 {
+		at = 0
+	}
+	for j, want := range before {
+		i := at + j
+		if i >= len(lines) {
+			return fmt.Errorf("%s: expected %q at line %d, found end of file", msg, strings.TrimSuffix(want, "\n"), i+1)
+		}
+		if lines[i] != want {
+			return fmt.Errorf("%s: expected %q at line %d, found %q", msg, strings.TrimSuffix(want, "\n"), i+1, strings.TrimSuffix(lines[i], "\n"))
+		}
+	}
+	return errors.New(msg)
+}
+
+// writePatch writes a git-style unified diff of the files under a and b to w.
+//
+// If changes is not empty, it's the directory of changes from a to b, as
+// computed by diffing them, and only the files in it are compared, besides
+// the files in a that are no longer in b.
+func writePatch(w io.Writer, a, b, changes string) error {
+	aFiles, err := patchableFiles(a)
+	if err != nil {
+		return err
+	}
+	bFiles, err := patchableFiles(b)
+	if changes != "" {
+		bFiles, err = patchableFiles(changes)
+	}
+	if err != nil {
+		return err
+	}
+
+	paths := make([]string, 0, len(aFiles)+len(bFiles))
+	for p := range bFiles {
+		paths = append(paths, p)
+	}
+	for p := range aFiles {
+		if _, ok := bFiles[p]; ok {
+			continue
+		}
+		if changes != "" {
+			// files left out of the changes are unchanged unless removed
+			unchanged, err := patchableAt(b, p)
+			if err != nil {
+				return err
+			}
+			if unchanged {
+				continue
+			}
+		}
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+
+	for _, p := range paths {
+		var aFile, bFile *patchableFile
+		if mode, ok := aFiles[p]; ok {
+			aFile, err = readPatchable(filepath.Join(a, p), mode)
+			if err != nil {
+				return err
+			}
+		}
+		if mode, ok := bFiles[p]; ok {
+			bFile, err = readPatchable(filepath.Join(b, p), mode)
+			if err != nil {
+				return err
+			}
+		}
+		if aFile != nil && bFile != nil &&
+			gitMode(aFile.mode) == gitMode(bFile.mode) &&
+			bytes.Equal(aFile.content, bFile.content) {
+			continue
+		}
+		if err := writeFilePatch(w, p, aFile, bFile); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeFilePatch writes the diff of a single file, where a nil file is one
+// that doesn't exist.
+func writeFilePatch(w io.Writer, p string, a, b *patchableFile) error {
+	if a != nil && b != nil && (a.mode&os.ModeSymlink) != (b.mode&os.ModeSymlink) {
+		// git diffs a file turning into a symlink or back as a removal and a
+		// creation
+		if err := writeFilePatch(w, p, a, nil); err != nil {
+			return err
+		}
+		return writeFilePatch(w, p, nil, b)
+	}
+
+	fmt.Fprintf(w, "diff --git a/%s b/%s\n", p, p)
+	fromFile, toFile := "a/"+p, "b/"+p
+	var aContent, bContent []byte
+	switch {
+	case a == nil:
+		fmt.Fprintf(w, "new file mode %s\n", gitMode(b.mode))
+		fromFile = "/dev/null"
+		bContent = b.content
+	case b == nil:
+		fmt.Fprintf(w, "deleted file mode %s\n", gitMode(a.mode))
+		toFile = "/dev/null"
+		aContent = a.content
+	default:
+		if gitMode(a.mode) != gitMode(b.mode) {
+			fmt.Fprintf(w, "old mode %s\nnew mode %s\n", gitMode(a.mode), gitMode(b.mode))
+		}
+		aContent, bContent = a.content, b.content
+	}
+	if bytes.Equal(aContent, bContent) {
+		return nil
+	}
+	if bytes.IndexByte(aContent, 0) != -1 || bytes.IndexByte(bContent, 0) != -1 {
+		return writeBinaryPatch(w, a, b)
+	}
+	fmt.Fprintf(w, "--- %s\n+++ %s\n", fromFile, toFile)
+	return writeHunks(w, splitLines(string(aContent)), splitLines(string(bContent)))
+}
+
+func writeHunks(w io.Writer, a, b []string) error {
+	m := difflib.NewMatcherWithJunk(a, b, false, nil)
+	for _, group := range m.GetGroupedOpCodes(patchContext) {
+		first, last := group[0], group[len(group)-1]
+		_, err := fmt.Fprintf(w, "@@ -%s +%s @@\n", unifiedRange(first.I1, last.I2), unifiedRange(first.J1, last.J2))
+		if err != nil {
+			return err
+		}
+		for _, op := range group {
+			if op.Tag == 'e' {
+				writePatchLines(w, ' ', a[op.I1:op.I2])
+				continue
+			}
+			if op.Tag == 'r' || op.Tag == 'd' {
+				writePatchLines(w, '-', a[op.I1:op.I2])
+			}
+			if op.Tag == 'r' || op.Tag == 'i' {
+				writePatchLines(w, '+', b[op.J1:op.J2])
+			}
+		}
+	}
+	return nil
+}
+
+func writePatchLines(w io.Writer, prefix byte, lines []string) {
+	for _, line := range lines {
+		fmt.Fprintf(w, "%c%s", prefix, line)
+		if !strings.HasSuffix(line, "\n") {
+			fmt.Fprint(w, "\n\\ No newline at end of file\n")
+		}
+	}
+}
+
+// unifiedRange formats a range of lines the way diff -u does.
+func unifiedRange(start, stop int) string {
+	beginning := start + 1
+	length := stop - start
+	switch length {
+	case 0:
+		return fmt.Sprintf("%d,0", beginning-1)
+	case 1:
+		return strconv.Itoa(beginning)
+	default:
+		return fmt.Sprintf("%d,%d", beginning, length)
+	}
+}
+
+// patchableFiles returns the modes of the regular files and symlinks under
+// dir, keyed by their slash-separated paths relative to dir.
+func patchableFiles(dir string) (map[string]os.FileMode, error) {
+	files := map[string]os.FileMode{}
+	err := filepath.WalkDir(dir, func(p string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !isPatchable(d.Type()) {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		files[filepath.ToSlash(rel)] = info.Mode()
+		return nil
+	})
+	return files, err
+}
+
+// patchableAt returns whether there's a regular file or symlink at p in dir.
+func patchableAt(dir, p string) (bool, error) {
+	src, err := rootPathNoFollow(dir, p)
+	if err != nil {
+		return false, err
+	}
+	fi, err := os.Lstat(src)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) || errors.Is(err, syscall.ENOTDIR) {
+			return false, nil
+		}
+		return false, err
+	}
+	return isPatchable(fi.Mode()), nil
+}
+
+// rootPathNoFollow is fs.RootPath, but without following p itself if it's a
+// symlink.
+func rootPathNoFollow(dir, p string) (string, error) {
+	parent, err := fs.RootPath(dir, path.Dir(p))
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(parent, path.Base(p)), nil
+}
+
+func isPatchable(mode os.FileMode) bool {
+	return mode.IsRegular() || mode&os.ModeSymlink != 0
+}
+
+// patchableFile is a regular file or a symlink, which is diffed as its
+// target, the way git does.
+type patchableFile struct {
+	mode    os.FileMode
+	content []byte
+}
+
+func readPatchable(p string, mode os.FileMode) (*patchableFile, error) {
+	if mode&os.ModeSymlink != 0 {
+		target, err := os.Readlink(p)
+		if err != nil {
+			return nil, err
+		}
+		return &patchableFile{mode: os.ModeSymlink | 0o777, content: []byte(target)}, nil
+	}
+	dt, err := os.ReadFile(p)
+	if err != nil {
+		return nil, err
+	}
+	return &patchableFile{mode: mode.Perm(), content: dt}, nil
+}
+
+// gitMode formats a file mode the way git diff does.
+func gitMode(mode os.FileMode) string {
+	switch {
+	case mode&os.ModeSymlink != 0:
+		return "120000"
+	case mode&0o111 != 0:
+		return "100755"
+	default:
+		return "100644"
+	}
+}
+
+func parseGitMode(s string) (os.FileMode, error) {
+	mode, err := strconv.ParseUint(strings.TrimSpace(s), 8, 32)
+	if err != nil {
+		return 0, fmt.Errorf("invalid file mode %q", s)
+	}
+	if mode&0o170000 == 0o120000 {
+		return os.ModeSymlink | 0o777, nil
+	}
+	return os.FileMode(mode & 0o777), nil
+}
+
+// writeBinaryPatch writes the change from a to b as a git binary patch, which
+// has the full contents of the file after the change and before it.
+func writeBinaryPatch(w io.Writer, a, b *patchableFile) error {
+	var aContent, bContent []byte
+	if a != nil {
+		aContent = a.content
+	}
+	if b != nil {
+		bContent = b.content
+	}
+	fmt.Fprintf(w, "index %s..%s\nGIT binary patch\n", gitBlobHash(a), gitBlobHash(b))
+	if err := writeBinaryLiteral(w, bContent); err != nil {
+		return err
+	}
+	return writeBinaryLiteral(w, aContent)
+}
+
+// gitBlobHash returns the hash git gives the file, or the null hash if there
+// is no file. git apply needs them to apply binary patches.
+func gitBlobHash(f *patchableFile) string {
+	if f == nil {
+		return strings.Repeat("0", sha1.Size*2)
+	}
+	h := sha1.New()
+	fmt.Fprintf(h, "blob %d\x00", len(f.content))
+	h.Write(f.content)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// binaryLineLen is the most bytes git encodes on one line of a binary patch.
+const binaryLineLen = 52
+
+// writeBinaryLiteral writes the content as a literal of a git binary patch:
+// compressed with zlib and encoded in base 85, with each line prefixed by a
+// letter giving how many bytes it holds.
+func writeBinaryLiteral(w io.Writer, content []byte) error {
+	var compressed bytes.Buffer
+	zw := zlib.NewWriter(&compressed)
+	if _, err := zw.Write(content); err != nil {
+		return err
+	}
+	if err := zw.Close(); err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "literal %d\n", len(content))
+	dt := compressed.Bytes()
+	for len(dt) > 0 {
+		n := min(len(dt), binaryLineLen)
+		var size byte
+		if n <= 26 {
+			size = 'A' + byte(n-1)
+		} else {
+			size = 'a' + byte(n-27)
+		}
+		fmt.Fprintf(w, "%c%s\n", size, encode85(dt[:n]))
+		dt = dt[n:]
+	}
+	_, err := fmt.Fprintln(w)
+	return err
+}
+
+// decodeBinaryLiteral decodes the lines of a literal of a git binary patch,
+// which decompress to size bytes.
+func decodeBinaryLiteral(size int, lines []string) ([]byte, error) {
+	var compressed []byte
+	for _, line := range lines {
+		var n int
+		switch c := line[0]; {
+		case c >= 'A' && c <= 'Z':
+			n = int(c-'A') + 1
+		case c >= 'a' && c <= 'z':
+			n = int(c-'a') + 27
+		default:
+			return nil, fmt.Errorf("malformed binary patch line: %q", line)
+		}
+		dt, err := decode85(line[1:], n)
+		if err != nil {
+			return nil, err
+		}
+		compressed = append(compressed, dt...)
+	}
+	zr, err := zlib.NewReader(bytes.NewReader(compressed))
+	if err != nil {
+		return nil, fmt.Errorf("corrupt binary patch: %w", err)
+	}
+	dt, err := io.ReadAll(zr)
+	if err != nil {
+		return nil, fmt.Errorf("corrupt binary patch: %w", err)
+	}
+	if len(dt) != size {
+		return nil, fmt.Errorf("corrupt binary patch: expected %d bytes, got %d", size, len(dt))
+	}
+	return dt, nil
+}
+
+// base85 is the alphabet of git's base 85 encoding, which differs from
+// Ascii85's.
+const base85 = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz!#$%&()*+-;<=>?@^_`{|}~"
+
+// encode85 encodes dt in groups of 4 bytes, padding the last group with
+// zeroes.
+func encode85(dt []byte) string {
+	var out []byte
+	for i := 0; i < len(dt); i += 4 {
+		var group [4]byte
+		copy(group[:], dt[i:])
+		acc := binary.BigEndian.Uint32(group[:])
+		var chars [5]byte
+		for j := 4; j >= 0; j-- {
+			chars[j] = base85[acc%85]
+			acc /= 85
+		}
+		out = append(out, chars[:]...)
+	}
+	return string(out)
+}
+
+// decode85 decodes n bytes from s, as encoded by encode85.
+func decode85(s string, n int) ([]byte, error) {
+	if len(s) != (n+3)/4*5 {
+		return nil, fmt.Errorf("malformed binary patch data: %q", s)
+	}
+	out := make([]byte, 0, len(s)/5*4)
+	for i := 0; i < len(s); i += 5 {
+		var acc uint64
+		for _, c := range []byte(s[i : i+5]) {
+			digit := strings.IndexByte(base85, c)
+			if digit < 0 {
+				return nil, fmt.Errorf("malformed binary patch data: %q", s)
+			}
+			acc = acc*85 + uint64(digit)
+		}
+		if acc > math.MaxUint32 {
+			return nil, fmt.Errorf("malformed binary patch data: %q", s)
+		}
+		out = binary.BigEndian.AppendUint32(out, uint32(acc))
+	}
+	return out[:n], nil
+}
+
+// patchPath parses the path from a ---/+++ line, returning an empty path for
+// /dev/null.
+func patchPath(s string, strip int) (string, error) {
+	if i := strings.IndexByte(s, '\t'); i != -1 {
+		// drop the timestamp diff -u adds
+		s = s[:i]
+	}
+	s = unquotePath(strings.TrimSpace(s))
+	if s == "/dev/null" {
+		return "", nil
+	}
+	return stripPath(s, strip)
+}
+
+// gitHeaderPath parses the path from a "diff --git a/foo b/foo" line, which
+// is only unambiguous when both paths are the same.
+func gitHeaderPath(s string, strip int) (string, bool) {
+	if len(s)%2 == 0 {
+		return "", false
+	}
+	half := len(s) / 2
+	if s[half] != ' ' {
+		return "", false
+	}
+	a, err := stripPath(s[:half], strip)
+	if err != nil {
+		return "", false
+	}
+	b, err := stripPath(s[half+1:], strip)
+	if err != nil || a != b {
+		return "", false
+	}
+	return a, true
+}
+
+// stripPath removes the given number of leading components from a path, like
+// patch -p.
+func stripPath(p string, strip int) (string, error) {
+	parts := strings.Split(p, "/")
+	if strip >= len(parts) {
+		return "", fmt.Errorf("cannot strip %d components from %q", strip, p)
+	}
+	cleaned := path.Clean(strings.Join(parts[strip:], "/"))
+	if cleaned == "." || cleaned == ".." || strings.HasPrefix(cleaned, "../") {
+		return "", fmt.Errorf("invalid path %q", p)
+	}
+	return strings.TrimPrefix(cleaned, "/"), nil
+}
+
+func unquotePath(s string) string {
+	if strings.HasPrefix(s, `"`) {
+		if unquoted, err := strconv.Unquote(s); err == nil {
+			return unquoted
+		}
+	}
+	return s
+}
+
+func atoiDefault(s string, def int) int {
+	if s == "" {
+		return def
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return def
+	}
+	return n
+}
+
+// splitLines splits s into lines, keeping their newlines.
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	lines := strings.SplitAfter(s, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// scanLinesWithNewline is bufio.ScanLines, but keeps the newlines so that a
+// missing one at the end of the patch can be told apart.
+func scanLinesWithNewline(data []byte, atEOF bool) (int, []byte, error) {
+	if atEOF && len(data) == 0 {
+		return 0, nil, nil
+	}
+	if i := bytes.IndexByte(data, '\n'); i >= 0 {
+		return i + 1, data[:i+1], nil
+	}
+	if atEOF {
+		return len(data), data, nil
+	}
+	return 0, nil, nil
+}
@@ -0,0 +1,316 @@
+package buildkit
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPatchRoundTrip(t *testing.T) {
+	write := func(t *testing.T, dir, name, content string, perm os.FileMode) {
+		t.Helper()
+		p := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(p), 0o755))
+		require.NoError(t, os.WriteFile(p, []byte(content), perm))
+		require.NoError(t, os.Chmod(p, perm))
+	}
+
+	a := t.TempDir()
+	write(t, a, "unchanged", "same\n", 0o644)
+	write(t, a, "modified", "one\ntwo\nthree\nfour\nfive\nsix\nseven\neight\nnine\nten\n", 0o644)
+	write(t, a, "removed", "bye\n", 0o644)
+	write(t, a, "chmodded", "run\n", 0o644)
+	write(t, a, "no-newline", "a\nb", 0o644)
+
+	b := t.TempDir()
+	write(t, b, "unchanged", "same\n", 0o644)
+	write(t, b, "modified", "one\ntwo\nTHREE\nfour\nfive\nsix\nseven\neight\nnine\nten\neleven\n", 0o644)
+	write(t, b, "chmodded", "run\n", 0o755)
+	write(t, b, "no-newline", "a\nc", 0o644)
+	write(t, b, "sub/added", "hi\n", 0o644)
+
+	var buf bytes.Buffer
+	require.NoError(t, writePatch(&buf, a, b, ""))
+	patch := buf.String()
+	require.NotContains(t, patch, "unchanged")
+	require.Contains(t, patch, "diff --git a/chmodded b/chmodded\nold mode 100644\nnew mode 100755\n")
+	require.Contains(t, patch, "--- /dev/null\n+++ b/sub/added\n@@ -0,0 +1 @@\n+hi\n")
+	require.Contains(t, patch, "-b\n\\ No newline at end of file\n+c\n\\ No newline at end of file\n")
+
+	patches, err := parsePatch(strings.NewReader(patch), 1)
+	require.NoError(t, err)
+
+	out := t.TempDir()
+	written, removed, err := applyPatches(a, out, patches)
+	require.NoError(t, err)
+	require.Equal(t, []string{"chmodded", "modified", "no-newline", "sub/added"}, written)
+	require.Equal(t, []string{"removed"}, removed)
+
+	// apply the result onto a copy of a and compare it with b
+	for _, fp := range patches {
+		if fp.newPath == "" {
+			require.NoError(t, os.Remove(filepath.Join(a, fp.oldPath)))
+			continue
+		}
+		dt, err := os.ReadFile(filepath.Join(out, fp.newPath))
+		require.NoError(t, err)
+		fi, err := os.Stat(filepath.Join(out, fp.newPath))
+		require.NoError(t, err)
+		write(t, a, fp.newPath, string(dt), fi.Mode().Perm())
+	}
+	aDigest, err := contentsDigest(a)
+	require.NoError(t, err)
+	bDigest, err := contentsDigest(b)
+	require.NoError(t, err)
+	require.Equal(t, bDigest, aDigest)
+
+	fi, err := os.Stat(filepath.Join(a, "chmodded"))
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0o755), fi.Mode().Perm())
+}
+
+func TestPatchRoundTripSymlinksAndBinaries(t *testing.T) {
+	write := func(t *testing.T, dir, name, content string) {
+		t.Helper()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644))
+	}
+	symlink := func(t *testing.T, dir, target, name string) {
+		t.Helper()
+		require.NoError(t, os.Symlink(target, filepath.Join(dir, name)))
+	}
+
+	a := t.TempDir()
+	write(t, a, "unchanged", "same\n")
+	symlink(t, a, "target-a", "link")
+	symlink(t, a, "unchanged", "removed-link")
+	write(t, a, "becomes-link", "x\n")
+	write(t, a, "bin", "\x00\x01\x02")
+	write(t, a, "removed-bin", "\x00gone")
+
+	b := t.TempDir()
+	write(t, b, "unchanged", "same\n")
+	symlink(t, b, "target-b", "link")
+	symlink(t, b, "unchanged", "becomes-link")
+	symlink(t, b, "nowhere", "new-link")
+	write(t, b, "bin", "\x00"+strings.Repeat("binary data ", 20))
+
+	var buf bytes.Buffer
+	require.NoError(t, writePatch(&buf, a, b, ""))
+	patch := buf.String()
+	require.NotContains(t, patch, "a/unchanged")
+	require.Contains(t, patch, "diff --git a/new-link b/new-link\nnew file mode 120000\n")
+	require.Contains(t, patch, "diff --git a/becomes-link b/becomes-link\ndeleted file mode 100644\n")
+	require.Contains(t, patch, "diff --git a/becomes-link b/becomes-link\nnew file mode 120000\n")
+	require.Contains(t, patch, "GIT binary patch\nliteral 241\n")
+	require.NotContains(t, patch, "Binary files")
+
+	// only comparing the changes from a to b gives the same patch
+	changes := t.TempDir()
+	symlink(t, changes, "target-b", "link")
+	symlink(t, changes, "unchanged", "becomes-link")
+	symlink(t, changes, "nowhere", "new-link")
+	write(t, changes, "bin", "\x00"+strings.Repeat("binary data ", 20))
+	var changesBuf bytes.Buffer
+	require.NoError(t, writePatch(&changesBuf, a, b, changes))
+	require.Equal(t, patch, changesBuf.String())
+
+	patches, err := parsePatch(strings.NewReader(patch), 1)
+	require.NoError(t, err)
+
+	out := t.TempDir()
+	written, removed, err := applyPatches(a, out, patches)
+	require.NoError(t, err)
+	require.Equal(t, []string{"becomes-link", "bin", "link", "new-link"}, written)
+	require.Equal(t, []string{"removed-bin", "removed-link"}, removed)
+
+	// apply the result onto a and compare it with b
+	for _, p := range removed {
+		require.NoError(t, os.Remove(filepath.Join(a, p)))
+	}
+	for _, p := range written {
+		if err := os.Remove(filepath.Join(a, p)); err != nil {
+			require.ErrorIs(t, err, os.ErrNotExist)
+		}
+		fi, err := os.Lstat(filepath.Join(out, p))
+		require.NoError(t, err)
+		if fi.Mode()&os.ModeSymlink != 0 {
+			target, err := os.Readlink(filepath.Join(out, p))
+			require.NoError(t, err)
+			symlink(t, a, target, p)
+			continue
+		}
+		dt, err := os.ReadFile(filepath.Join(out, p))
+		require.NoError(t, err)
+		write(t, a, p, string(dt))
+	}
+	aDigest, err := contentsDigest(a)
+	require.NoError(t, err)
+	bDigest, err := contentsDigest(b)
+	require.NoError(t, err)
+	require.Equal(t, bDigest, aDigest)
+}
+
+func TestParseBinaryPatch(t *testing.T) {
+	// as written by git diff --binary
+	patch := `diff --git a/data.bin b/data.bin
+new file mode 100644
+index 0000000000000000000000000000000000000000..eaf36c1daccfdf325514461cd1a2ffbc139b5464
+GIT binary patch
+literal 4
+LcmZQzWMT#Y01f~L
+
+literal 0
+HcmV?d00001
+
+`
+	patches, err := parsePatch(strings.NewReader(patch), 1)
+	require.NoError(t, err)
+	require.Len(t, patches, 1)
+	require.Equal(t, "data.bin", patches[0].newPath)
+	require.Equal(t, []byte{0, 1, 2, 3}, patches[0].binary)
+
+	_, err = parsePatch(strings.NewReader("diff --git a/x b/x\nBinary files a/x and b/x differ\n"), 1)
+	require.ErrorContains(t, err, "binary changes without their contents are not supported")
+}
+
+func TestApplyPatchSeries(t *testing.T) {
+	// two commits: the first creates a file and renames another, the second
+	// edits both of the files the first one left behind
+	patch := `From 1111 Mon Sep 17 00:00:00 2001
+Subject: [PATCH 1/2] add new
+
+---
+diff --git a/sub/new.txt b/sub/new.txt
+new file mode 100644
+--- /dev/null
++++ b/sub/new.txt
+@@ -0,0 +1,2 @@
++one
++two
+diff --git a/old.txt b/old.txt
+deleted file mode 100644
+--- a/old.txt
++++ /dev/null
+@@ -1 +0,0 @@
+-old
+diff --git a/moved.txt b/moved.txt
+new file mode 100644
+--- /dev/null
++++ b/moved.txt
+@@ -0,0 +1 @@
++old
+--
+2.42.0
+
+From 2222 Mon Sep 17 00:00:00 2001
+Subject: [PATCH 2/2] edit new
+
+---
+diff --git a/sub/new.txt b/sub/new.txt
+--- a/sub/new.txt
++++ b/sub/new.txt
+@@ -1,2 +1,2 @@
+ one
+-two
++TWO
+diff --git a/moved.txt b/moved.txt
+--- a/moved.txt
++++ b/moved.txt
+@@ -1 +1 @@
+-old
++new
+--
+2.42.0
+`
+	patches, err := parsePatch(strings.NewReader(patch), 1)
+	require.NoError(t, err)
+
+	dir := t.TempDir()
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "sub"), 0o700))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "old.txt"), []byte("old\n"), 0o600))
+
+	out := t.TempDir()
+	written, removed, err := applyPatches(dir, out, patches)
+	require.NoError(t, err)
+	require.Equal(t, []string{"moved.txt", "sub/new.txt"}, written)
+	require.Equal(t, []string{"old.txt"}, removed)
+
+	dt, err := os.ReadFile(filepath.Join(out, "sub/new.txt"))
+	require.NoError(t, err)
+	require.Equal(t, "one\nTWO\n", string(dt))
+	dt, err = os.ReadFile(filepath.Join(out, "moved.txt"))
+	require.NoError(t, err)
+	require.Equal(t, "new\n", string(dt))
+
+	// a file created twice in the same series conflicts with itself
+	_, _, err = applyPatches(dir, t.TempDir(), append(patches, patches[0]))
+	require.ErrorContains(t, err, "sub/new.txt: file to create already exists")
+}
+
+func TestApplyHunks(t *testing.T) {
+	patch := `From 1234 Mon Sep 17 00:00:00 2001
+From: Someone <someone@example.com>
+Subject: [PATCH] change things
+
+---
+ foo.txt | 2 +-
+ 1 file changed, 1 insertion(+), 1 deletion(-)
+
+diff --git a/src/foo.txt b/src/foo.txt
+index 1111111..2222222 100644
+--- a/src/foo.txt
++++ b/src/foo.txt
+@@ -2,3 +2,3 @@ header
+ two
+-three
++THREE
+ four
+--
+2.42.0
+`
+	patches, err := parsePatch(strings.NewReader(patch), 1)
+	require.NoError(t, err)
+	require.Len(t, patches, 1)
+	require.Equal(t, "src/foo.txt", patches[0].oldPath)
+	require.Equal(t, "src/foo.txt", patches[0].newPath)
+
+	t.Run("exact", func(t *testing.T) {
+		out, err := applyHunks("src/foo.txt", "one\ntwo\nthree\nfour\n", patches[0].hunks)
+		require.NoError(t, err)
+		require.Equal(t, "one\ntwo\nTHREE\nfour\n", out)
+	})
+
+	t.Run("offset", func(t *testing.T) {
+		out, err := applyHunks("src/foo.txt", "zero\nhalf\none\ntwo\nthree\nfour\n", patches[0].hunks)
+		require.NoError(t, err)
+		require.Equal(t, "zero\nhalf\none\ntwo\nTHREE\nfour\n", out)
+	})
+
+	t.Run("conflict", func(t *testing.T) {
+		_, err := applyHunks("src/foo.txt", "one\ntwo\n3\nfour\n", patches[0].hunks)
+		require.EqualError(t, err, `src/foo.txt: hunk #1 (@@ -2,3 +2,3 @@) does not apply: expected "three" at line 3, found "3"`)
+	})
+}
+
+func TestParsePatchStrip(t *testing.T) {
+	patch := "--- old/dir/foo.txt\t2024-01-01 00:00:00\n+++ new/dir/foo.txt\t2024-01-01 00:00:00\n@@ -1 +1 @@\n-a\n+b\n"
+
+	patches, err := parsePatch(strings.NewReader(patch), 0)
+	require.NoError(t, err)
+	require.Equal(t, "old/dir/foo.txt", patches[0].oldPath)
+
+	patches, err = parsePatch(strings.NewReader(patch), 2)
+	require.NoError(t, err)
+	require.Equal(t, "foo.txt", patches[0].oldPath)
+	require.Equal(t, "foo.txt", patches[0].newPath)
+
+	_, err = parsePatch(strings.NewReader(patch), 3)
+	require.ErrorContains(t, err, "cannot strip 3 components")
+
+	_, err = parsePatch(strings.NewReader("--- a/../x\n+++ b/../x\n@@ -1 +1 @@\n-a\n+b\n"), 1)
+	require.ErrorContains(t, err, "invalid path")
+}
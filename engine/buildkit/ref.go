@@ -7,6 +7,7 @@ import (
 	"path"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/containerd/containerd/leases"
 	bkcache "github.com/moby/buildkit/cache"
@@ -324,13 +325,31 @@ func wrapError(ctx context.Context, baseErr error, sessionID string) error {
 		}
 	}
 
-	return &ExecError{
+	daggerErr := &ExecError{
 		original: baseErr,
 		Cmd:      execOp.Exec.Meta.Args,
 		ExitCode: exitCode,
 		Stdout:   strings.TrimSpace(string(stdoutBytes)),
 		Stderr:   strings.TrimSpace(string(stderrBytes)),
 	}
+
+	// the shim records the timeout if it killed the exec for exceeding it
+	timeoutBytes, err := getExecMetaFile(ctx, mntable, "timeout")
+	if err != nil {
+		return errors.Join(err, daggerErr)
+	}
+	if len(timeoutBytes) > 0 {
+		timeout, err := strconv.Atoi(string(timeoutBytes))
+		if err != nil {
+			return errors.Join(err, daggerErr)
+		}
+		return &ExecTimeoutError{
+			ExecError: daggerErr,
+			Timeout:   time.Duration(timeout) * time.Second,
+		}
+	}
+
+	return daggerErr
 }
 
 func getExecMetaFile(ctx context.Context, mntable snapshot.Mountable, fileName string) ([]byte, error) {
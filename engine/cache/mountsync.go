@@ -90,7 +90,12 @@ func (m *manager) StartCacheMountSynchronization(ctx context.Context) error {
 
 		allCacheMounts := map[string]struct{}{}
 		core.SeenCacheKeys.Range(func(k any, v any) bool {
-			allCacheMounts[k.(string)] = struct{}{}
+			// the cache service identifies cache mounts by name only, which
+			// can't tell apart the same key in different namespaces, so only
+			// shared cache volumes are synced
+			if cache := v.(*core.CacheVolume); cache.Namespace == "" {
+				allCacheMounts[cache.Key()] = struct{}{}
+			}
 			return true
 		})
 
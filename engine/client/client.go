@@ -77,6 +77,10 @@ type Params struct {
 	// grpc context metadata for any api requests back to the engine. It's used by the API
 	// server to determine which schema to serve and other module context metadata.
 	ModuleCallerDigest digest.Digest
+
+	// If true, the results of module function calls from previous sessions
+	// aren't reused, regardless of the functions' cache policies.
+	NoCache bool
 }
 
 type Client struct {
@@ -306,6 +310,7 @@ func Connect(ctx context.Context, params Params) (_ *Client, _ context.Context,
 				ModuleCallerDigest:        c.ModuleCallerDigest,
 				CloudToken:                os.Getenv("DAGGER_CLOUD_TOKEN"),
 				DoNotTrack:                analytics.DoNotTrack(),
+				NoCache:                   c.NoCache,
 			}.AppendToMD(meta))
 		})
 	})
@@ -732,6 +737,16 @@ func (s AnyDirSource) DiffCopy(stream filesync.FileSync_DiffCopyServer) error {
 		return stream.SendMsg(stat)
 	}
 
+	if opts.WalkDirOnly {
+		return engine.WalkHostDir(opts.Path, func(entry engine.HostEntry) error {
+			dt, err := json.Marshal(entry)
+			if err != nil {
+				return fmt.Errorf("marshal entry: %w", err)
+			}
+			return stream.SendMsg(&filesync.BytesMessage{Data: dt})
+		})
+	}
+
 	// otherwise, do the whole directory sync back to the caller
 	fs, err := fsutil.NewFS(opts.Path)
 	if err != nil {
@@ -767,13 +782,21 @@ func (AnyDirTarget) DiffCopy(stream filesync.FileSend_DiffCopyServer) (rerr erro
 	}
 
 	if !opts.IsFileStream {
+		if opts.Wipe {
+			if err := checkWipeable(opts.Path); err != nil {
+				return err
+			}
+		}
+
 		// we're writing a full directory tree, normal fsutil.Receive is good
 		if err := os.MkdirAll(opts.Path, 0o700); err != nil {
 			return fmt.Errorf("failed to create synctarget dest dir %s: %w", opts.Path, err)
 		}
 
 		err := fsutil.Receive(stream.Context(), stream, opts.Path, fsutil.ReceiveOpt{
-			Merge: true,
+			// without merging, anything under the path that isn't in the
+			// exported directory is removed
+			Merge: !opts.Wipe,
 			Filter: func(path string, stat *fstypes.Stat) bool {
 				stat.Uid = uint32(os.Getuid())
 				stat.Gid = uint32(os.Getgid())
@@ -855,6 +878,37 @@ func (AnyDirTarget) DiffCopy(stream filesync.FileSend_DiffCopyServer) (rerr erro
 	}
 }
 
+// checkWipeable refuses to wipe the filesystem root, the home directory, the
+// directory the client runs in, or any directory containing them, since an
+// export would never be all that belongs in them.
+func checkWipeable(dest string) error {
+	dest, err := filepath.Abs(dest)
+	if err != nil {
+		return fmt.Errorf("get abs path: %w", err)
+	}
+	var protected []string
+	if home, err := os.UserHomeDir(); err == nil {
+		protected = append(protected, home)
+	}
+	if wd, err := os.Getwd(); err == nil {
+		protected = append(protected, wd)
+	}
+	if dest == filepath.VolumeName(dest)+string(filepath.Separator) {
+		return fmt.Errorf("refusing to wipe %s; export to a directory of its own instead", dest)
+	}
+	for _, p := range protected {
+		rel, err := filepath.Rel(dest, p)
+		switch {
+		case err != nil, rel == "..", strings.HasPrefix(rel, ".."+string(filepath.Separator)):
+		case rel == ".":
+			return fmt.Errorf("refusing to wipe %s; export to a directory of its own instead", dest)
+		default:
+			return fmt.Errorf("refusing to wipe %s, which contains %s; export to a directory of its own instead", dest, p)
+		}
+	}
+	return nil
+}
+
 type progRockAttachable struct {
 	writer progrock.Writer
 }
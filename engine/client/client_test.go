@@ -0,0 +1,25 @@
+package client
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckWipeable(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+
+	require.ErrorContains(t, checkWipeable("/"), "refusing to wipe /")
+	require.ErrorContains(t, checkWipeable(home), "refusing to wipe "+home)
+	require.ErrorContains(t, checkWipeable(filepath.Dir(home)), "which contains "+home)
+	require.ErrorContains(t, checkWipeable("."), "refusing to wipe "+wd)
+	require.ErrorContains(t, checkWipeable(".."), "which contains "+wd)
+
+	require.NoError(t, checkWipeable(filepath.Join(home, "gen")))
+	require.NoError(t, checkWipeable("gen"))
+}
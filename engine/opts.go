@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"path/filepath"
 
 	"github.com/dagger/dagger/core/pipeline"
 	controlapi "github.com/moby/buildkit/api/services/control"
@@ -74,6 +75,10 @@ type ClientMetadata struct {
 
 	// Disable analytics
 	DoNotTrack bool
+
+	// Don't reuse the results of module function calls from previous
+	// sessions, regardless of the functions' cache policies
+	NoCache bool
 }
 
 // ClientIDs returns the ClientID followed by ParentClientIDs.
@@ -117,6 +122,65 @@ type LocalImportOpts struct {
 	MaxFileSize        int64    `json:"max_file_size"`
 	StatPathOnly       bool     `json:"stat_path_only"`
 	StatReturnAbsPath  bool     `json:"stat_return_abs_path"`
+	// WalkDirOnly sends a HostEntry for everything under the directory rather
+	// than its contents.
+	WalkDirOnly bool `json:"walk_dir_only"`
+}
+
+// HostEntry describes an entry under a directory on the client's host.
+type HostEntry struct {
+	// Path is slash-separated and relative to the directory.
+	Path string      `json:"path"`
+	Mode os.FileMode `json:"mode"`
+	Size int64       `json:"size"`
+	// Target is the target of a symlink.
+	Target string `json:"target,omitempty"`
+	// Digest is the digest of the contents of a regular file.
+	Digest digest.Digest `json:"digest,omitempty"`
+}
+
+// WalkHostDir calls fn with a HostEntry for everything under dir, in lexical
+// order.
+func WalkHostDir(dir string, fn func(HostEntry) error) error {
+	return filepath.WalkDir(dir, func(p string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if p == dir {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		entry := HostEntry{
+			Path: filepath.ToSlash(rel),
+			Mode: info.Mode(),
+			Size: info.Size(),
+		}
+		switch {
+		case info.Mode()&os.ModeSymlink != 0:
+			entry.Target, err = os.Readlink(p)
+			if err != nil {
+				return err
+			}
+		case info.Mode().IsRegular():
+			f, err := os.Open(p)
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			entry.Digest, err = digest.SHA256.FromReader(f)
+			if err != nil {
+				return err
+			}
+		}
+		return fn(entry)
+	})
 }
 
 func (o LocalImportOpts) ToGRPCMD() metadata.MD {
@@ -175,6 +239,7 @@ type LocalExportOpts struct {
 	FileOriginalName   string      `json:"file_original_name"`
 	AllowParentDirPath bool        `json:"allow_parent_dir_path"`
 	FileMode           os.FileMode `json:"file_mode"`
+	Wipe               bool        `json:"wipe"`
 }
 
 func (o LocalExportOpts) ToGRPCMD() metadata.MD {
@@ -13,6 +13,8 @@ import (
 	"github.com/dagger/dagger/auth"
 	"github.com/dagger/dagger/core"
 	"github.com/dagger/dagger/core/pipeline"
+	"github.com/dagger/dagger/core/secretprovider"
+	"github.com/dagger/dagger/dagql"
 	"github.com/dagger/dagger/engine"
 	"github.com/dagger/dagger/engine/buildkit"
 	"github.com/dagger/dagger/engine/cache"
@@ -79,6 +81,14 @@ type BuildkitControllerOpts struct {
 	UpstreamCacheExporters map[string]remotecache.ResolveCacheExporterFunc
 	UpstreamCacheImporters map[string]remotecache.ResolveCacheImporterFunc
 	DNSConfig              *oci.DNSConfig
+
+	// DagqlCacheOpts configures a bounded LRU cache for each server's dagql
+	// results. If nil, results are cached for the lifetime of the server.
+	DagqlCacheOpts *dagql.LRUCacheOpts
+
+	// SecretProviders are the engine-side providers that clients may reference
+	// secrets from by URI, keyed by scheme. None are enabled by default.
+	SecretProviders map[string]secretprovider.Provider
 }
 
 func NewBuildkitController(opts BuildkitControllerOpts) (*BuildkitController, error) {
@@ -233,6 +243,9 @@ func (e *BuildkitController) Session(stream controlapi.Control_SessionServer) (r
 		bklog.G(ctx).Debugf("connected new server session")
 
 		secretStore := core.NewSecretStore()
+		for scheme, provider := range e.SecretProviders {
+			secretStore.RegisterSecretProvider(scheme, provider)
+		}
 		authProvider := auth.NewRegistryAuthProvider()
 
 		var cacheImporterCfgs []bkgw.CacheOptionsEntry
@@ -278,7 +291,7 @@ func (e *BuildkitController) Session(stream controlapi.Control_SessionServer) (r
 		labels = append(labels, pipeline.EngineLabel(e.EngineName))
 		labels = append(labels, pipeline.LoadServerLabels(engine.Version, runtime.GOOS, runtime.GOARCH, e.cacheManager.ID() != cache.LocalCacheID)...)
 
-		srv, err = NewDaggerServer(ctx, bkClient, e.worker, caller, opts.ServerID, secretStore, authProvider, labels, opts.CloudToken, opts.DoNotTrack)
+		srv, err = NewDaggerServer(ctx, bkClient, e.worker, caller, opts.ServerID, secretStore, authProvider, labels, opts.CloudToken, opts.DoNotTrack, opts.NoCache, e.DagqlCacheOpts)
 		if err != nil {
 			e.perServerMu.Unlock(opts.ServerID)
 			return fmt.Errorf("new Dagger server: %w", err)
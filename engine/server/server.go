@@ -17,6 +17,7 @@ import (
 	"github.com/dagger/dagger/core"
 	"github.com/dagger/dagger/core/pipeline"
 	"github.com/dagger/dagger/core/schema"
+	"github.com/dagger/dagger/dagql"
 	"github.com/dagger/dagger/engine"
 	"github.com/dagger/dagger/engine/buildkit"
 	bksession "github.com/moby/buildkit/session"
@@ -32,6 +33,7 @@ type DaggerServer struct {
 	worker   bkworker.Worker
 
 	schema      *schema.APIServer
+	dagqlCache  *dagql.LRUCache
 	recorder    *progrock.Recorder
 	analytics   analytics.Tracker
 	progCleanup func() error
@@ -54,6 +56,8 @@ func NewDaggerServer(
 	rootLabels []pipeline.Label,
 	cloudToken string,
 	doNotTrack bool,
+	noCache bool,
+	dagqlCacheOpts *dagql.LRUCacheOpts,
 ) (*DaggerServer, error) {
 	srv := &DaggerServer{
 		serverID: serverID,
@@ -96,6 +100,12 @@ func NewDaggerServer(
 	// leave internal progress contexts open and leak goroutines.
 	bkClient.WriteStatusesTo(context.Background(), srv.recorder)
 
+	var dagqlCache dagql.Cache
+	if dagqlCacheOpts != nil {
+		srv.dagqlCache = dagql.NewLRUCache(*dagqlCacheOpts)
+		dagqlCache = srv.dagqlCache
+	}
+
 	apiSchema, err := schema.New(ctx, schema.InitializeArgs{
 		BuildkitClient: srv.bkClient,
 		Platform:       srv.worker.Platforms(true)[0],
@@ -104,6 +114,8 @@ func NewDaggerServer(
 		LeaseManager:   srv.worker.LeaseManager(),
 		Secrets:        secretStore,
 		Auth:           authProvider,
+		Cache:          dagqlCache,
+		NoCache:        noCache,
 	})
 	if err != nil {
 		return nil, err
@@ -115,7 +127,16 @@ func NewDaggerServer(
 func (srv *DaggerServer) LogMetrics(l *logrus.Entry) *logrus.Entry {
 	srv.clientMu.RLock()
 	defer srv.clientMu.RUnlock()
-	return l.WithField(fmt.Sprintf("server-%s-client-count", srv.serverID), srv.connectedClients)
+	l = l.WithField(fmt.Sprintf("server-%s-client-count", srv.serverID), srv.connectedClients)
+	if srv.dagqlCache != nil {
+		stats := srv.dagqlCache.Stats()
+		l = l.WithField(fmt.Sprintf("server-%s-dagql-cache-entries", srv.serverID), stats.Entries)
+		l = l.WithField(fmt.Sprintf("server-%s-dagql-cache-bytes", srv.serverID), stats.Bytes)
+		l = l.WithField(fmt.Sprintf("server-%s-dagql-cache-hits", srv.serverID), stats.Hits)
+		l = l.WithField(fmt.Sprintf("server-%s-dagql-cache-misses", srv.serverID), stats.Misses)
+		l = l.WithField(fmt.Sprintf("server-%s-dagql-cache-evictions", srv.serverID), stats.Evictions)
+	}
+	return l
 }
 
 func (srv *DaggerServer) Close() {
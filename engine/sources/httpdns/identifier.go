@@ -4,10 +4,26 @@ import (
 	bkhttp "github.com/moby/buildkit/source/http"
 )
 
-const AttrHTTPClientIDs = "dagger.http.clientids"
+const (
+	AttrHTTPClientIDs        = "dagger.http.clientids"
+	AttrHTTPHeaders          = "dagger.http.headers"
+	AttrHTTPAuthHeaderSecret = "dagger.http.authheadersecret"
+)
 
 type HTTPIdentifier struct {
 	bkhttp.HTTPIdentifier
 
 	ClientIDs []string
+
+	// Headers are additional headers sent with each request.
+	Headers []Header
+	// AuthHeaderSecret is the name of a session secret whose plaintext is sent
+	// as the Authorization header.
+	AuthHeaderSecret string
+}
+
+// Header is an additional HTTP header to send when fetching a URL.
+type Header struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
 }
@@ -13,6 +13,7 @@ import (
 	"path"
 	"path/filepath"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/dagger/dagger/network"
@@ -20,6 +21,7 @@ import (
 	"github.com/moby/buildkit/cache"
 	"github.com/moby/buildkit/executor/oci"
 	"github.com/moby/buildkit/session"
+	"github.com/moby/buildkit/session/secrets"
 	"github.com/moby/buildkit/snapshot"
 	"github.com/moby/buildkit/solver"
 	"github.com/moby/buildkit/solver/pb"
@@ -78,6 +80,14 @@ func (hs *httpSource) Identifier(scheme, ref string, attrs map[string]string, pl
 	if v, ok := attrs[AttrHTTPClientIDs]; ok {
 		id.ClientIDs = strings.Split(v, ",")
 	}
+	if v, ok := attrs[AttrHTTPHeaders]; ok {
+		if err := json.Unmarshal([]byte(v), &id.Headers); err != nil {
+			return nil, errors.Wrapf(err, "invalid http headers %q", v)
+		}
+	}
+	if v, ok := attrs[AttrHTTPAuthHeaderSecret]; ok {
+		id.AuthHeaderSecret = v
+	}
 
 	return id, nil
 }
@@ -101,6 +111,9 @@ type httpSourceHandler struct {
 	refID    string
 	cacheKey digest.Digest
 	sm       *session.Manager
+
+	authMu sync.Mutex
+	auth   string
 }
 
 func (hs *httpSourceHandler) client(g session.Group) *http.Client {
@@ -115,17 +128,61 @@ func (hs *httpSourceHandler) client(g session.Group) *http.Client {
 	return &http.Client{Transport: newTransport(hs.transport, hs.sm, g, &dns)}
 }
 
+// newRequest creates a GET request for the source's URL, including its
+// additional headers and authorization.
+func (hs *httpSourceHandler) newRequest(ctx context.Context, g session.Group) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", hs.src.URL, nil)
+	if err != nil {
+		return nil, err
+	}
+	for _, h := range hs.src.Headers {
+		req.Header.Add(h.Name, h.Value)
+	}
+	if hs.src.AuthHeaderSecret != "" {
+		auth, err := hs.getAuthHeader(ctx, g)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Authorization", auth)
+	}
+	return req, nil
+}
+
+func (hs *httpSourceHandler) getAuthHeader(ctx context.Context, g session.Group) (string, error) {
+	hs.authMu.Lock()
+	defer hs.authMu.Unlock()
+	if hs.auth != "" {
+		return hs.auth, nil
+	}
+	err := hs.sm.Any(ctx, g, func(ctx context.Context, _ string, caller session.Caller) error {
+		dt, err := secrets.GetSecret(ctx, caller, hs.src.AuthHeaderSecret)
+		if err != nil {
+			return err
+		}
+		hs.auth = strings.TrimSpace(string(dt))
+		return nil
+	})
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to get auth header secret for %s", hs.src.URL)
+	}
+	return hs.auth, nil
+}
+
 // urlHash is internal hash the etag is stored by that doesn't leak outside
 // this package.
 func (hs *httpSourceHandler) urlHash() (digest.Digest, error) {
 	dt, err := json.Marshal(struct {
-		Filename       string
-		Perm, UID, GID int
+		Filename         string
+		Perm, UID, GID   int
+		Headers          []Header `json:",omitempty"`
+		AuthHeaderSecret string   `json:",omitempty"`
 	}{
-		Filename: getFileName(hs.src.URL, hs.src.Filename, nil),
-		Perm:     hs.src.Perm,
-		UID:      hs.src.UID,
-		GID:      hs.src.GID,
+		Filename:         getFileName(hs.src.URL, hs.src.Filename, nil),
+		Perm:             hs.src.Perm,
+		UID:              hs.src.UID,
+		GID:              hs.src.GID,
+		Headers:          hs.src.Headers,
+		AuthHeaderSecret: hs.src.AuthHeaderSecret,
 	})
 	if err != nil {
 		return "", err
@@ -135,17 +192,21 @@ func (hs *httpSourceHandler) urlHash() (digest.Digest, error) {
 
 func (hs *httpSourceHandler) formatCacheKey(filename string, dgst digest.Digest, lastModTime string) digest.Digest {
 	dt, err := json.Marshal(struct {
-		Filename       string
-		Perm, UID, GID int
-		Checksum       digest.Digest
-		LastModTime    string `json:",omitempty"`
+		Filename         string
+		Perm, UID, GID   int
+		Checksum         digest.Digest
+		LastModTime      string   `json:",omitempty"`
+		Headers          []Header `json:",omitempty"`
+		AuthHeaderSecret string   `json:",omitempty"`
 	}{
-		Filename:    filename,
-		Perm:        hs.src.Perm,
-		UID:         hs.src.UID,
-		GID:         hs.src.GID,
-		Checksum:    dgst,
-		LastModTime: lastModTime,
+		Filename:         filename,
+		Perm:             hs.src.Perm,
+		UID:              hs.src.UID,
+		GID:              hs.src.GID,
+		Checksum:         dgst,
+		LastModTime:      lastModTime,
+		Headers:          hs.src.Headers,
+		AuthHeaderSecret: hs.src.AuthHeaderSecret,
 	})
 	if err != nil {
 		return dgst
@@ -170,11 +231,10 @@ func (hs *httpSourceHandler) CacheKey(ctx context.Context, g session.Group, inde
 		return "", "", nil, false, errors.Wrapf(err, "failed to search metadata for %s", uh)
 	}
 
-	req, err := http.NewRequest("GET", hs.src.URL, nil)
+	req, err := hs.newRequest(ctx, g)
 	if err != nil {
 		return "", "", nil, false, err
 	}
-	req = req.WithContext(ctx)
 	m := map[string]cacheRefMetadata{}
 
 	// If we request a single ETag in 'If-None-Match', some servers omit the
@@ -421,11 +481,10 @@ func (hs *httpSourceHandler) Snapshot(ctx context.Context, g session.Group) (cac
 		}
 	}
 
-	req, err := http.NewRequest("GET", hs.src.URL, nil)
+	req, err := hs.newRequest(ctx, g)
 	if err != nil {
 		return nil, err
 	}
-	req = req.WithContext(ctx)
 
 	client := hs.client(g)
 
@@ -436,6 +495,9 @@ func (hs *httpSourceHandler) Snapshot(ctx context.Context, g session.Group) (cac
 	defer func() {
 		_ = resp.Body.Close()
 	}()
+	if resp.StatusCode < 200 || resp.StatusCode >= 400 {
+		return nil, errors.Errorf("invalid response status %d", resp.StatusCode)
+	}
 
 	ref, dgst, err := hs.save(ctx, resp, g)
 	if err != nil {
@@ -443,6 +505,9 @@ func (hs *httpSourceHandler) Snapshot(ctx context.Context, g session.Group) (cac
 	}
 	if dgst != hs.cacheKey {
 		ref.Release(context.TODO())
+		if hs.src.Checksum != "" {
+			return nil, errors.Errorf("checksum mismatch for %s: expected %s, got %s", hs.src.URL, hs.src.Checksum, dgst)
+		}
 		return nil, errors.Errorf("digest mismatch %s: %s", dgst, hs.cacheKey)
 	}
 
@@ -1,6 +1,7 @@
 package httpdns
 
 import (
+	"encoding/json"
 	"strconv"
 	"strings"
 
@@ -10,9 +11,21 @@ import (
 
 const AttrNetConfig = "httpdns.netconfig"
 
+// HTTPInfo holds the Dagger-specific attributes of an HTTP source.
+type HTTPInfo struct {
+	// ClientIDs are the clients whose network search domains are used to
+	// resolve the URL's host.
+	ClientIDs []string
+	// Headers are additional headers sent with each request.
+	Headers []Header
+	// AuthHeaderSecret is the name of a session secret whose plaintext is
+	// sent as the Authorization header.
+	AuthHeaderSecret string
+}
+
 // HTTP is a helper mimicking the llb.HTTP function, but with the ability to
 // set additional attributes.
-func HTTP(url string, clientIDs []string, opts ...llb.HTTPOption) llb.State {
+func HTTP(url string, info HTTPInfo, opts ...llb.HTTPOption) llb.State {
 	hi := &llb.HTTPInfo{}
 	for _, o := range opts {
 		o.SetHTTPOption(hi)
@@ -34,7 +47,17 @@ func HTTP(url string, clientIDs []string, opts ...llb.HTTPOption) llb.State {
 		attrs[pb.AttrHTTPGID] = strconv.Itoa(hi.GID)
 	}
 
-	attrs[AttrHTTPClientIDs] = strings.Join(clientIDs, ",")
+	if len(info.ClientIDs) > 0 {
+		attrs[AttrHTTPClientIDs] = strings.Join(info.ClientIDs, ",")
+	}
+	if len(info.Headers) > 0 {
+		// marshaling a slice of string pairs can't fail
+		dt, _ := json.Marshal(info.Headers)
+		attrs[AttrHTTPHeaders] = string(dt)
+	}
+	if info.AuthHeaderSecret != "" {
+		attrs[AttrHTTPAuthHeaderSecret] = info.AuthHeaderSecret
+	}
 
 	source := llb.NewSource(url, attrs, hi.Constraints)
 	return llb.NewState(source.Output())
@@ -53,9 +53,21 @@ func BuiltinContainer(digest string) *dagger.Container {
 }
 
 // Constructs a cache volume for a given cache key.
-func CacheVolume(key string) *dagger.CacheVolume {
+//
+// By default, cache volumes constructed by a module are scoped to that module, so that modules using the same key don't share a volume. Cache volumes constructed by the main client are shared.
+//
+// Namespaces keep modules from sharing cache volumes by accident, but are not access control: any module can construct a cache volume in another module's namespace, and use the cache volumes other modules give it.
+func CacheVolume(key string, opts ...dagger.CacheVolumeOpts) *dagger.CacheVolume {
+	client := initClient()
+	return client.CacheVolume(key, opts...)
+}
+
+// Lists the cache volumes used since the engine started, sorted by namespace and key.
+//
+// Modules only see their own cache volumes and shared ones.
+func CacheVolumes(ctx context.Context) ([]dagger.CacheVolume, error) {
 	client := initClient()
-	return client.CacheVolume(key)
+	return client.CacheVolumes(ctx)
 }
 
 // Checks if the current Dagger Engine is compatible with an SDK's required version.
@@ -104,6 +116,12 @@ func Directory(opts ...dagger.DirectoryOpts) *dagger.Directory {
 	return client.Directory(opts...)
 }
 
+// Queries the Dagger engine the client is connected to.
+func Engine() *dagger.Engine {
+	client := initClient()
+	return client.Engine()
+}
+
 // Deprecated: Use LoadFileFromID instead.
 func File(id dagger.FileID) *dagger.File {
 	client := initClient()
@@ -164,6 +182,24 @@ func LoadDirectoryFromID(id dagger.DirectoryID) *dagger.Directory {
 	return client.LoadDirectoryFromID(id)
 }
 
+// Load a EngineCacheEntry from its ID.
+func LoadEngineCacheEntryFromID(id dagger.EngineCacheEntryID) *dagger.EngineCacheEntry {
+	client := initClient()
+	return client.LoadEngineCacheEntryFromID(id)
+}
+
+// Load a EngineCache from its ID.
+func LoadEngineCacheFromID(id dagger.EngineCacheID) *dagger.EngineCache {
+	client := initClient()
+	return client.LoadEngineCacheFromID(id)
+}
+
+// Load a Engine from its ID.
+func LoadEngineFromID(id dagger.EngineID) *dagger.Engine {
+	client := initClient()
+	return client.LoadEngineFromID(id)
+}
+
 // Load a EnvVariable from its ID.
 func LoadEnvVariableFromID(id dagger.EnvVariableID) *dagger.EnvVariable {
 	client := initClient()
@@ -314,6 +350,12 @@ func LoadSocketFromID(id dagger.SocketID) *dagger.Socket {
 	return client.LoadSocketFromID(id)
 }
 
+// Load a Stat from its ID.
+func LoadStatFromID(id dagger.StatID) *dagger.Stat {
+	client := initClient()
+	return client.LoadStatFromID(id)
+}
+
 // Load a Terminal from its ID.
 func LoadTerminalFromID(id dagger.TerminalID) *dagger.Terminal {
 	client := initClient()
@@ -351,6 +393,8 @@ func Pipeline(name string, opts ...dagger.PipelineOpts) *dagger.Client {
 }
 
 // Reference a secret by name.
+//
+// If a URI is given, the secret's plaintext is fetched by the engine from the matching secret provider each time the secret is used. Secret providers run in the engine, so they must be enabled in its configuration first.
 func Secret(name string, opts ...dagger.SecretOpts) *dagger.Secret {
 	client := initClient()
 	return client.Secret(name, opts...)
@@ -9,6 +9,7 @@ import (
 	"fmt"
 	"reflect"
 	"strings"
+	"time"
 
 	"github.com/Khan/genqlient/graphql"
 	"github.com/vektah/gqlparser/v2/gqlerror"
@@ -45,25 +46,15 @@ func getCustomError(err error) error {
 		return nil
 	}
 
-	if typ == "EXEC_ERROR" {
-		e := &ExecError{
-			original: err,
+	switch typ {
+	case "EXEC_ERROR":
+		return getExecError(err, ext)
+	case "EXEC_TIMEOUT_ERROR":
+		e := &ExecTimeoutError{
+			ExecError: getExecError(err, ext),
 		}
-		if code, ok := ext["exitCode"].(float64); ok {
-			e.ExitCode = int(code)
-		}
-		if args, ok := ext["cmd"].([]interface{}); ok {
-			cmd := make([]string, len(args))
-			for i, v := range args {
-				cmd[i] = v.(string)
-			}
-			e.Cmd = cmd
-		}
-		if stdout, ok := ext["stdout"].(string); ok {
-			e.Stdout = stdout
-		}
-		if stderr, ok := ext["stderr"].(string); ok {
-			e.Stderr = stderr
+		if timeout, ok := ext["timeout"].(float64); ok {
+			e.Timeout = time.Duration(timeout) * time.Second
 		}
 		return e
 	}
@@ -71,6 +62,29 @@ func getCustomError(err error) error {
 	return nil
 }
 
+func getExecError(err error, ext map[string]interface{}) *ExecError {
+	e := &ExecError{
+		original: err,
+	}
+	if code, ok := ext["exitCode"].(float64); ok {
+		e.ExitCode = int(code)
+	}
+	if args, ok := ext["cmd"].([]interface{}); ok {
+		cmd := make([]string, len(args))
+		for i, v := range args {
+			cmd[i] = v.(string)
+		}
+		e.Cmd = cmd
+	}
+	if stdout, ok := ext["stdout"].(string); ok {
+		e.Stdout = stdout
+	}
+	if stderr, ok := ext["stderr"].(string); ok {
+		e.Stderr = stderr
+	}
+	return e
+}
+
 // ExecError is an API error from an exec operation.
 type ExecError struct {
 	original error
@@ -101,6 +115,17 @@ func (e *ExecError) Unwrap() error {
 	return e.original
 }
 
+// ExecTimeoutError is an API error from an exec operation that was killed
+// because it ran longer than its timeout.
+type ExecTimeoutError struct {
+	*ExecError
+	Timeout time.Duration
+}
+
+func (e *ExecTimeoutError) Unwrap() error {
+	return e.ExecError
+}
+
 // The `CacheVolumeID` scalar type represents an identifier for an object of type CacheVolume.
 type CacheVolumeID string
 
@@ -113,6 +138,15 @@ type CurrentModuleID string
 // The `DirectoryID` scalar type represents an identifier for an object of type Directory.
 type DirectoryID string
 
+// The `EngineCacheEntryID` scalar type represents an identifier for an object of type EngineCacheEntry.
+type EngineCacheEntryID string
+
+// The `EngineCacheID` scalar type represents an identifier for an object of type EngineCache.
+type EngineCacheID string
+
+// The `EngineID` scalar type represents an identifier for an object of type Engine.
+type EngineID string
+
 // The `EnvVariableID` scalar type represents an identifier for an object of type EnvVariable.
 type EnvVariableID string
 
@@ -196,6 +230,9 @@ type ServiceID string
 // The `SocketID` scalar type represents an identifier for an object of type Socket.
 type SocketID string
 
+// The `StatID` scalar type represents an identifier for an object of type Stat.
+type StatID string
+
 // The `TerminalID` scalar type represents an identifier for an object of type Terminal.
 type TerminalID string
 
@@ -216,6 +253,27 @@ type BuildArg struct {
 	Value string `json:"value"`
 }
 
+// Key value object that represents an HTTP header.
+type HTTPHeader struct {
+	// The header name.
+	Name string `json:"name"`
+
+	// The header value.
+	Value string `json:"value"`
+}
+
+// An HTTP request used to probe a service's health.
+type HealthcheckHTTPGet struct {
+	// The response status the service is healthy with.
+	ExpectStatus int `json:"expectStatus,omitempty"`
+
+	// The path to request.
+	Path string `json:"path,omitempty"`
+
+	// The port to send the request to.
+	Port int `json:"port"`
+}
+
 // Key value object that represents a pipeline label.
 type PipelineLabel struct {
 	// Label name.
@@ -242,7 +300,28 @@ type CacheVolume struct {
 	Query  *querybuilder.Selection
 	Client graphql.Client
 
-	id *CacheVolumeID
+	clear             *Void
+	id                *CacheVolumeID
+	key               *string
+	mostRecentUseTime *int
+	namespace         *string
+	seed              *Void
+	size              *int
+}
+
+// Empties the cache volume.
+//
+// Subsequent uses of the cache volume start from an empty directory, while pipelines using it right now keep their contents.
+func (r *CacheVolume) Clear(ctx context.Context) (Void, error) {
+	if r.clear != nil {
+		return *r.clear, nil
+	}
+	q := r.Query.Select("clear")
+
+	var response Void
+
+	q = q.Bind(&response)
+	return response, q.Execute(ctx, r.Client)
 }
 
 // A unique identifier for this CacheVolume.
@@ -285,12 +364,92 @@ func (r *CacheVolume) MarshalJSON() ([]byte, error) {
 	return json.Marshal(id)
 }
 
+// The key the cache volume was constructed with.
+func (r *CacheVolume) Key(ctx context.Context) (string, error) {
+	if r.key != nil {
+		return *r.key, nil
+	}
+	q := r.Query.Select("key")
+
+	var response string
+
+	q = q.Bind(&response)
+	return response, q.Execute(ctx, r.Client)
+}
+
+// The time the cache volume was last used, in seconds following Unix epoch, or 0 if it was never used.
+func (r *CacheVolume) MostRecentUseTime(ctx context.Context) (int, error) {
+	if r.mostRecentUseTime != nil {
+		return *r.mostRecentUseTime, nil
+	}
+	q := r.Query.Select("mostRecentUseTime")
+
+	var response int
+
+	q = q.Bind(&response)
+	return response, q.Execute(ctx, r.Client)
+}
+
+// The namespace the cache volume is scoped to, or an empty string if it's shared.
+func (r *CacheVolume) Namespace(ctx context.Context) (string, error) {
+	if r.namespace != nil {
+		return *r.namespace, nil
+	}
+	q := r.Query.Select("namespace")
+
+	var response string
+
+	q = q.Bind(&response)
+	return response, q.Execute(ctx, r.Client)
+}
+
+// Copies the contents of a directory into the cache volume.
+//
+// Files already in the cache volume are kept unless the directory has a file at the same path.
+func (r *CacheVolume) Seed(ctx context.Context, source *Directory) (Void, error) {
+	assertNotNil("source", source)
+	if r.seed != nil {
+		return *r.seed, nil
+	}
+	q := r.Query.Select("seed")
+	q = q.Arg("source", source)
+
+	var response Void
+
+	q = q.Bind(&response)
+	return response, q.Execute(ctx, r.Client)
+}
+
+// The disk space used by the cache volume, in bytes.
+func (r *CacheVolume) Size(ctx context.Context) (int, error) {
+	if r.size != nil {
+		return *r.size, nil
+	}
+	q := r.Query.Select("size")
+
+	var response int
+
+	q = q.Bind(&response)
+	return response, q.Execute(ctx, r.Client)
+}
+
+// Returns a copy of the current contents of the cache volume.
+func (r *CacheVolume) Snapshot() *Directory {
+	q := r.Query.Select("snapshot")
+
+	return &Directory{
+		Query:  q,
+		Client: r.Client,
+	}
+}
+
 // An OCI-compatible container, also known as a Docker container.
 type Container struct {
 	Query  *querybuilder.Selection
 	Client graphql.Client
 
 	envVariable *string
+	exitCode    *int
 	export      *bool
 	id          *ContainerID
 	imageRef    *string
@@ -312,11 +471,31 @@ func (r *Container) With(f WithContainerFunc) *Container {
 	return f(r)
 }
 
+// ContainerAsServiceOpts contains options for Container.AsService
+type ContainerAsServiceOpts struct {
+	// Whether to restart the service when its process exits.
+	//
+	// A service is never restarted after it has been stopped.
+	RestartPolicy ServiceRestartPolicy
+	// Maximum number of times to restart the service. Zero means no limit.
+	MaxRestarts int
+}
+
 // Turn the container into a Service.
 //
 // Be sure to set any exposed ports before this conversion.
-func (r *Container) AsService() *Service {
+func (r *Container) AsService(opts ...ContainerAsServiceOpts) *Service {
 	q := r.Query.Select("asService")
+	for i := len(opts) - 1; i >= 0; i-- {
+		// `restartPolicy` optional argument
+		if !querybuilder.IsZeroValue(opts[i].RestartPolicy) {
+			q = q.Arg("restartPolicy", opts[i].RestartPolicy)
+		}
+		// `maxRestarts` optional argument
+		if !querybuilder.IsZeroValue(opts[i].MaxRestarts) {
+			q = q.Arg("maxRestarts", opts[i].MaxRestarts)
+		}
+	}
 
 	return &Service{
 		Query:  q,
@@ -491,6 +670,21 @@ func (r *Container) EnvVariables(ctx context.Context) ([]EnvVariable, error) {
 	return convert(response), nil
 }
 
+// The exit code of the last executed command.
+//
+// Will execute default command if none is set, or error if there's no default.
+func (r *Container) ExitCode(ctx context.Context) (int, error) {
+	if r.exitCode != nil {
+		return *r.exitCode, nil
+	}
+	q := r.Query.Select("exitCode")
+
+	var response int
+
+	q = q.Bind(&response)
+	return response, q.Execute(ctx, r.Client)
+}
+
 // EXPERIMENTAL API! Subject to change/removal at any time.
 //
 // Configures all available GPUs on the host to be accessible to this container.
@@ -1063,6 +1257,18 @@ type ContainerWithExecOpts struct {
 	ExperimentalPrivilegedNesting bool
 	// Execute the command with all root capabilities. This is similar to running a command with "sudo" or executing "docker run" with the "--privileged" flag. Containerization does not provide any security guarantees when using this option. It should only be used when absolutely necessary and only with trusted commands.
 	InsecureRootCapabilities bool
+	// Exit codes this command is allowed to exit with without error.
+	//
+	// Use FAILURE or ANY to keep the container's files when the command fails, and read the exit code with exitCode.
+	Expect ReturnType
+	// Maximum duration of the command in seconds, after which it is killed and fails with a timeout error. Overrides the container's default set by withResourceLimits.
+	Timeout int
+	// Maximum amount of memory the command may use (e.g., "512M", "2G"). Overrides the container's default set by withResourceLimits.
+	MemoryLimit string
+	// CPU time the command may use per 100ms period, in microseconds (e.g., 150000 for 1.5 CPUs). Overrides the container's default set by withResourceLimits.
+	CPUQuota int
+	// Maximum number of processes the command may run. Overrides the container's default set by withResourceLimits.
+	PidsLimit int
 }
 
 // Retrieves this container after executing the specified command inside it.
@@ -1093,6 +1299,26 @@ func (r *Container) WithExec(args []string, opts ...ContainerWithExecOpts) *Cont
 		if !querybuilder.IsZeroValue(opts[i].InsecureRootCapabilities) {
 			q = q.Arg("insecureRootCapabilities", opts[i].InsecureRootCapabilities)
 		}
+		// `expect` optional argument
+		if !querybuilder.IsZeroValue(opts[i].Expect) {
+			q = q.Arg("expect", opts[i].Expect)
+		}
+		// `timeout` optional argument
+		if !querybuilder.IsZeroValue(opts[i].Timeout) {
+			q = q.Arg("timeout", opts[i].Timeout)
+		}
+		// `memoryLimit` optional argument
+		if !querybuilder.IsZeroValue(opts[i].MemoryLimit) {
+			q = q.Arg("memoryLimit", opts[i].MemoryLimit)
+		}
+		// `cpuQuota` optional argument
+		if !querybuilder.IsZeroValue(opts[i].CPUQuota) {
+			q = q.Arg("cpuQuota", opts[i].CPUQuota)
+		}
+		// `pidsLimit` optional argument
+		if !querybuilder.IsZeroValue(opts[i].PidsLimit) {
+			q = q.Arg("pidsLimit", opts[i].PidsLimit)
+		}
 	}
 	q = q.Arg("args", args)
 
@@ -1222,6 +1448,68 @@ func (r *Container) WithFocus() *Container {
 	}
 }
 
+// ContainerWithHealthcheckOpts contains options for Container.WithHealthcheck
+type ContainerWithHealthcheckOpts struct {
+	// Command to run in the service's container, healthy if it exits 0.
+	Command []string
+	// HTTP request to send to the service, healthy if it responds with the expected status.
+	HTTPGet HealthcheckHTTPGet
+	// Port the service must accept TCP connections on.
+	TCPPort int
+	// Number of seconds to wait between probes.
+	Interval int
+	// Number of seconds after which a probe fails.
+	Timeout int
+	// Number of consecutive failed probes after which the service fails to start.
+	Retries int
+	// Number of seconds to give the service to start, during which failed probes are not counted.
+	StartPeriod int
+}
+
+// Configures how to check that the container is ready when run as a service.
+//
+// Exactly one of command, httpGet or tcpPort must be set. The service is probed from inside its container until a probe succeeds, replacing the default check that all exposed ports accept connections.
+//
+// Images that configure a HEALTHCHECK use it by default.
+func (r *Container) WithHealthcheck(opts ...ContainerWithHealthcheckOpts) *Container {
+	q := r.Query.Select("withHealthcheck")
+	for i := len(opts) - 1; i >= 0; i-- {
+		// `command` optional argument
+		if !querybuilder.IsZeroValue(opts[i].Command) {
+			q = q.Arg("command", opts[i].Command)
+		}
+		// `httpGet` optional argument
+		if !querybuilder.IsZeroValue(opts[i].HTTPGet) {
+			q = q.Arg("httpGet", opts[i].HTTPGet)
+		}
+		// `tcpPort` optional argument
+		if !querybuilder.IsZeroValue(opts[i].TCPPort) {
+			q = q.Arg("tcpPort", opts[i].TCPPort)
+		}
+		// `interval` optional argument
+		if !querybuilder.IsZeroValue(opts[i].Interval) {
+			q = q.Arg("interval", opts[i].Interval)
+		}
+		// `timeout` optional argument
+		if !querybuilder.IsZeroValue(opts[i].Timeout) {
+			q = q.Arg("timeout", opts[i].Timeout)
+		}
+		// `retries` optional argument
+		if !querybuilder.IsZeroValue(opts[i].Retries) {
+			q = q.Arg("retries", opts[i].Retries)
+		}
+		// `startPeriod` optional argument
+		if !querybuilder.IsZeroValue(opts[i].StartPeriod) {
+			q = q.Arg("startPeriod", opts[i].StartPeriod)
+		}
+	}
+
+	return &Container{
+		Query:  q,
+		Client: r.Client,
+	}
+}
+
 // Retrieves this container plus the given label.
 func (r *Container) WithLabel(name string, value string) *Container {
 	q := r.Query.Select("withLabel")
@@ -1436,6 +1724,48 @@ func (r *Container) WithRegistryAuth(address string, username string, secret *Se
 	}
 }
 
+// ContainerWithResourceLimitsOpts contains options for Container.WithResourceLimits
+type ContainerWithResourceLimitsOpts struct {
+	// Maximum duration of each command in seconds.
+	Timeout int
+	// Maximum amount of memory each command may use (e.g., "512M", "2G").
+	MemoryLimit string
+	// CPU time each command may use per 100ms period, in microseconds (e.g., 150000 for 1.5 CPUs).
+	CPUQuota int
+	// Maximum number of processes each command may run.
+	PidsLimit int
+}
+
+// Retrieves this container with default limits for subsequently executed commands.
+//
+// A zero value means no limit. Limits passed to withExec take precedence.
+func (r *Container) WithResourceLimits(opts ...ContainerWithResourceLimitsOpts) *Container {
+	q := r.Query.Select("withResourceLimits")
+	for i := len(opts) - 1; i >= 0; i-- {
+		// `timeout` optional argument
+		if !querybuilder.IsZeroValue(opts[i].Timeout) {
+			q = q.Arg("timeout", opts[i].Timeout)
+		}
+		// `memoryLimit` optional argument
+		if !querybuilder.IsZeroValue(opts[i].MemoryLimit) {
+			q = q.Arg("memoryLimit", opts[i].MemoryLimit)
+		}
+		// `cpuQuota` optional argument
+		if !querybuilder.IsZeroValue(opts[i].CPUQuota) {
+			q = q.Arg("cpuQuota", opts[i].CPUQuota)
+		}
+		// `pidsLimit` optional argument
+		if !querybuilder.IsZeroValue(opts[i].PidsLimit) {
+			q = q.Arg("pidsLimit", opts[i].PidsLimit)
+		}
+	}
+
+	return &Container{
+		Query:  q,
+		Client: r.Client,
+	}
+}
+
 // Retrieves the container with the given directory mounted to /.
 func (r *Container) WithRootfs(directory *Directory) *Container {
 	assertNotNil("directory", directory)
@@ -1461,6 +1791,12 @@ func (r *Container) WithSecretVariable(name string, secret *Secret) *Container {
 	}
 }
 
+// ContainerWithServiceBindingOpts contains options for Container.WithServiceBinding
+type ContainerWithServiceBindingOpts struct {
+	// Aliases of other services bound to the container that must be started and healthy before this service is started.
+	DependsOn []string
+}
+
 // Establish a runtime dependency on a service.
 //
 // The service will be started automatically when needed and detached when it is no longer needed, executing the default command if none is set.
@@ -1468,9 +1804,15 @@ func (r *Container) WithSecretVariable(name string, secret *Secret) *Container {
 // The service will be reachable from the container via the provided hostname alias.
 //
 // The service dependency will also convey to any files or directories produced by the container.
-func (r *Container) WithServiceBinding(alias string, service *Service) *Container {
+func (r *Container) WithServiceBinding(alias string, service *Service, opts ...ContainerWithServiceBindingOpts) *Container {
 	assertNotNil("service", service)
 	q := r.Query.Select("withServiceBinding")
+	for i := len(opts) - 1; i >= 0; i-- {
+		// `dependsOn` optional argument
+		if !querybuilder.IsZeroValue(opts[i].DependsOn) {
+			q = q.Arg("dependsOn", opts[i].DependsOn)
+		}
+	}
 	q = q.Arg("alias", alias)
 	q = q.Arg("service", service)
 
@@ -1609,6 +1951,18 @@ func (r *Container) WithoutFocus() *Container {
 	}
 }
 
+// Retrieves this container without a healthcheck, including one configured by its image.
+//
+// Its exposed ports will be checked instead when run as a service.
+func (r *Container) WithoutHealthcheck() *Container {
+	q := r.Query.Select("withoutHealthcheck")
+
+	return &Container{
+		Query:  q,
+		Client: r.Client,
+	}
+}
+
 // Retrieves this container minus the given environment label.
 func (r *Container) WithoutLabel(name string) *Container {
 	q := r.Query.Select("withoutLabel")
@@ -1807,6 +2161,7 @@ type Directory struct {
 	Query  *querybuilder.Selection
 	Client graphql.Client
 
+	digest *string
 	export *bool
 	id     *DirectoryID
 	sync   *DirectoryID
@@ -1820,6 +2175,44 @@ func (r *Directory) With(f WithDirectoryFunc) *Directory {
 	return f(r)
 }
 
+// DirectoryAsArchiveOpts contains options for Directory.AsArchive
+type DirectoryAsArchiveOpts struct {
+	// File format of the archive.
+	Format ArchiveFormat
+	// Compression to apply to the archive.
+	//
+	// Zip archives compress each entry with DEFLATE when set to Gzip, and don't support Zstd.
+	//
+	// EStarGZ is not supported.
+	Compression ImageLayerCompression
+	// Set every timestamp to a fixed time and omit user and group names, so that the same contents always produce the same archive.
+	Reproducible bool
+}
+
+// Packs this directory's contents into an archive file.
+func (r *Directory) AsArchive(opts ...DirectoryAsArchiveOpts) *File {
+	q := r.Query.Select("asArchive")
+	for i := len(opts) - 1; i >= 0; i-- {
+		// `format` optional argument
+		if !querybuilder.IsZeroValue(opts[i].Format) {
+			q = q.Arg("format", opts[i].Format)
+		}
+		// `compression` optional argument
+		if !querybuilder.IsZeroValue(opts[i].Compression) {
+			q = q.Arg("compression", opts[i].Compression)
+		}
+		// `reproducible` optional argument
+		if !querybuilder.IsZeroValue(opts[i].Reproducible) {
+			q = q.Arg("reproducible", opts[i].Reproducible)
+		}
+	}
+
+	return &File{
+		Query:  q,
+		Client: r.Client,
+	}
+}
+
 // DirectoryAsModuleOpts contains options for Directory.AsModule
 type DirectoryAsModuleOpts struct {
 	// An optional subpath of the directory which contains the module's configuration file.
@@ -1846,6 +2239,18 @@ func (r *Directory) AsModule(opts ...DirectoryAsModuleOpts) *Module {
 	}
 }
 
+// Returns a unified diff of the changes from this directory to another directory, in the format of git diff.
+func (r *Directory) AsPatch(other *Directory) *File {
+	assertNotNil("other", other)
+	q := r.Query.Select("asPatch")
+	q = q.Arg("other", other)
+
+	return &File{
+		Query:  q,
+		Client: r.Client,
+	}
+}
+
 // Gets the difference between this directory and an another directory.
 func (r *Directory) Diff(other *Directory) *Directory {
 	assertNotNil("other", other)
@@ -1858,6 +2263,31 @@ func (r *Directory) Diff(other *Directory) *Directory {
 	}
 }
 
+// DirectoryDigestOpts contains options for Directory.Digest
+type DirectoryDigestOpts struct {
+	// Only hash file names and contents, ignoring permissions, ownership and timestamps.
+	ExcludeMetadata bool
+}
+
+// Returns a digest of the directory's contents (e.g., "sha256:...").
+func (r *Directory) Digest(ctx context.Context, opts ...DirectoryDigestOpts) (string, error) {
+	if r.digest != nil {
+		return *r.digest, nil
+	}
+	q := r.Query.Select("digest")
+	for i := len(opts) - 1; i >= 0; i-- {
+		// `excludeMetadata` optional argument
+		if !querybuilder.IsZeroValue(opts[i].ExcludeMetadata) {
+			q = q.Arg("excludeMetadata", opts[i].ExcludeMetadata)
+		}
+	}
+
+	var response string
+
+	q = q.Bind(&response)
+	return response, q.Execute(ctx, r.Client)
+}
+
 // Retrieves a directory at the given path.
 func (r *Directory) Directory(path string) *Directory {
 	q := r.Query.Select("directory")
@@ -1939,12 +2369,72 @@ func (r *Directory) Entries(ctx context.Context, opts ...DirectoryEntriesOpts) (
 	return response, q.Execute(ctx, r.Client)
 }
 
+// DirectoryEntriesWithStatOpts contains options for Directory.EntriesWithStat
+type DirectoryEntriesWithStatOpts struct {
+	// Location of the directory to look at (e.g., "/src").
+	Path string
+}
+
+// Returns the metadata of each file and directory at the given path.
+func (r *Directory) EntriesWithStat(ctx context.Context, opts ...DirectoryEntriesWithStatOpts) ([]Stat, error) {
+	q := r.Query.Select("entriesWithStat")
+	for i := len(opts) - 1; i >= 0; i-- {
+		// `path` optional argument
+		if !querybuilder.IsZeroValue(opts[i].Path) {
+			q = q.Arg("path", opts[i].Path)
+		}
+	}
+
+	q = q.Select("id")
+
+	type entriesWithStat struct {
+		Id StatID
+	}
+
+	convert := func(fields []entriesWithStat) []Stat {
+		out := []Stat{}
+
+		for i := range fields {
+			val := Stat{id: &fields[i].Id}
+			val.Query = querybuilder.Query().Select("loadStatFromID").Arg("id", fields[i].Id)
+			val.Client = r.Client
+			out = append(out, val)
+		}
+
+		return out
+	}
+	var response []entriesWithStat
+
+	q = q.Bind(&response)
+
+	err := q.Execute(ctx, r.Client)
+	if err != nil {
+		return nil, err
+	}
+
+	return convert(response), nil
+}
+
+// DirectoryExportOpts contains options for Directory.Export
+type DirectoryExportOpts struct {
+	// Remove anything under the path that isn't in this directory, making the path an exact copy of it.
+	//
+	// The filesystem root, the home directory and the working directory, and any directory containing them, cannot be wiped.
+	Wipe bool
+}
+
 // Writes the contents of the directory to a path on the host.
-func (r *Directory) Export(ctx context.Context, path string) (bool, error) {
+func (r *Directory) Export(ctx context.Context, path string, opts ...DirectoryExportOpts) (bool, error) {
 	if r.export != nil {
 		return *r.export, nil
 	}
 	q := r.Query.Select("export")
+	for i := len(opts) - 1; i >= 0; i-- {
+		// `wipe` optional argument
+		if !querybuilder.IsZeroValue(opts[i].Wipe) {
+			q = q.Arg("wipe", opts[i].Wipe)
+		}
+	}
 	q = q.Arg("path", path)
 
 	var response bool
@@ -1953,6 +2443,31 @@ func (r *Directory) Export(ctx context.Context, path string) (bool, error) {
 	return response, q.Execute(ctx, r.Client)
 }
 
+// DirectoryExportDryRunOpts contains options for Directory.ExportDryRun
+type DirectoryExportDryRunOpts struct {
+	// Report anything under the path that isn't in this directory as deleted.
+	Wipe bool
+}
+
+// Reports the changes that exporting the directory to a path on the host would make, without writing anything.
+//
+// Each change is formatted as "A path" for added, "M path" for modified and "D path" for deleted entries, with directories ending in a slash.
+func (r *Directory) ExportDryRun(ctx context.Context, path string, opts ...DirectoryExportDryRunOpts) ([]string, error) {
+	q := r.Query.Select("exportDryRun")
+	for i := len(opts) - 1; i >= 0; i-- {
+		// `wipe` optional argument
+		if !querybuilder.IsZeroValue(opts[i].Wipe) {
+			q = q.Arg("wipe", opts[i].Wipe)
+		}
+	}
+	q = q.Arg("path", path)
+
+	var response []string
+
+	q = q.Bind(&response)
+	return response, q.Execute(ctx, r.Client)
+}
+
 // Retrieves a file at the given path.
 func (r *Directory) File(path string) *File {
 	q := r.Query.Select("file")
@@ -2044,13 +2559,41 @@ func (r *Directory) Pipeline(name string, opts ...DirectoryPipelineOpts) *Direct
 	}
 }
 
-// Force evaluation in the engine.
-func (r *Directory) Sync(ctx context.Context) (*Directory, error) {
+// Retrieves the metadata of the file, directory or symlink at the given path.
+//
+// Symlinks are not followed.
+func (r *Directory) Stat(path string) *Stat {
+	q := r.Query.Select("stat")
+	q = q.Arg("path", path)
+
+	return &Stat{
+		Query:  q,
+		Client: r.Client,
+	}
+}
+
+// Force evaluation in the engine.
+func (r *Directory) Sync(ctx context.Context) (*Directory, error) {
 	q := r.Query.Select("sync")
 
 	return r, q.Execute(ctx, r.Client)
 }
 
+// Retrieves this directory plus the contents of the given archive extracted at the given path.
+//
+// The archive's format and compression are detected automatically.
+func (r *Directory) WithArchive(path string, file *File) *Directory {
+	assertNotNil("file", file)
+	q := r.Query.Select("withArchive")
+	q = q.Arg("path", path)
+	q = q.Arg("file", file)
+
+	return &Directory{
+		Query:  q,
+		Client: r.Client,
+	}
+}
+
 // DirectoryWithDirectoryOpts contains options for Directory.WithDirectory
 type DirectoryWithDirectoryOpts struct {
 	// Exclude artifacts that match the given pattern (e.g., ["node_modules/", ".git*"]).
@@ -2131,6 +2674,20 @@ func (r *Directory) WithFiles(path string, sources []*File, opts ...DirectoryWit
 	}
 }
 
+// Retrieves this directory with the file or directory at src moved to dest.
+//
+// If dest is an existing directory, src is moved into it.
+func (r *Directory) WithMove(src string, dest string) *Directory {
+	q := r.Query.Select("withMove")
+	q = q.Arg("src", src)
+	q = q.Arg("dest", dest)
+
+	return &Directory{
+		Query:  q,
+		Client: r.Client,
+	}
+}
+
 // DirectoryWithNewDirectoryOpts contains options for Directory.WithNewDirectory
 type DirectoryWithNewDirectoryOpts struct {
 	// Permission granted to the created directory (e.g., 0777).
@@ -2178,6 +2735,84 @@ func (r *Directory) WithNewFile(path string, contents string, opts ...DirectoryW
 	}
 }
 
+// Retrieves this directory with the ownership of the given path changed.
+//
+// Directories are changed recursively.
+func (r *Directory) WithOwner(path string, owner string) *Directory {
+	q := r.Query.Select("withOwner")
+	q = q.Arg("path", path)
+	q = q.Arg("owner", owner)
+
+	return &Directory{
+		Query:  q,
+		Client: r.Client,
+	}
+}
+
+// DirectoryWithPatchOpts contains options for Directory.WithPatch
+type DirectoryWithPatchOpts struct {
+	// Number of leading path components to strip from file names in the patch, as with patch -p.
+	Strip int
+}
+
+// Retrieves this directory with the given unified diff applied, as written by diff -u, git diff or git format-patch.
+//
+// Fails without applying anything if any part of the patch does not apply.
+func (r *Directory) WithPatch(patch *File, opts ...DirectoryWithPatchOpts) *Directory {
+	assertNotNil("patch", patch)
+	q := r.Query.Select("withPatch")
+	for i := len(opts) - 1; i >= 0; i-- {
+		// `strip` optional argument
+		if !querybuilder.IsZeroValue(opts[i].Strip) {
+			q = q.Arg("strip", opts[i].Strip)
+		}
+	}
+	q = q.Arg("patch", patch)
+
+	return &Directory{
+		Query:  q,
+		Client: r.Client,
+	}
+}
+
+// DirectoryWithPermissionsOpts contains options for Directory.WithPermissions
+type DirectoryWithPermissionsOpts struct {
+	// If the path is a directory, also change the permissions of its contents.
+	Recursive bool
+}
+
+// Retrieves this directory with the permissions of the given path changed.
+func (r *Directory) WithPermissions(path string, mode int, opts ...DirectoryWithPermissionsOpts) *Directory {
+	q := r.Query.Select("withPermissions")
+	for i := len(opts) - 1; i >= 0; i-- {
+		// `recursive` optional argument
+		if !querybuilder.IsZeroValue(opts[i].Recursive) {
+			q = q.Arg("recursive", opts[i].Recursive)
+		}
+	}
+	q = q.Arg("path", path)
+	q = q.Arg("mode", mode)
+
+	return &Directory{
+		Query:  q,
+		Client: r.Client,
+	}
+}
+
+// Retrieves this directory plus a symlink at the given path.
+//
+// Any existing file or directory at linkName is replaced.
+func (r *Directory) WithSymlink(target string, linkName string) *Directory {
+	q := r.Query.Select("withSymlink")
+	q = q.Arg("target", target)
+	q = q.Arg("linkName", linkName)
+
+	return &Directory{
+		Query:  q,
+		Client: r.Client,
+	}
+}
+
 // Retrieves this directory with all file/dir timestamps set to the given time.
 func (r *Directory) WithTimestamps(timestamp int) *Directory {
 	q := r.Query.Select("withTimestamps")
@@ -2211,6 +2846,390 @@ func (r *Directory) WithoutFile(path string) *Directory {
 	}
 }
 
+// The Dagger engine the client is connected to.
+type Engine struct {
+	Query  *querybuilder.Selection
+	Client graphql.Client
+
+	id *EngineID
+}
+
+// A unique identifier for this Engine.
+func (r *Engine) ID(ctx context.Context) (EngineID, error) {
+	if r.id != nil {
+		return *r.id, nil
+	}
+	q := r.Query.Select("id")
+
+	var response EngineID
+
+	q = q.Bind(&response)
+	return response, q.Execute(ctx, r.Client)
+}
+
+// XXX_GraphQLType is an internal function. It returns the native GraphQL type name
+func (r *Engine) XXX_GraphQLType() string {
+	return "Engine"
+}
+
+// XXX_GraphQLIDType is an internal function. It returns the native GraphQL type name for the ID of this object
+func (r *Engine) XXX_GraphQLIDType() string {
+	return "EngineID"
+}
+
+// XXX_GraphQLID is an internal function. It returns the underlying type ID
+func (r *Engine) XXX_GraphQLID(ctx context.Context) (string, error) {
+	id, err := r.ID(ctx)
+	if err != nil {
+		return "", err
+	}
+	return string(id), nil
+}
+
+func (r *Engine) MarshalJSON() ([]byte, error) {
+	id, err := r.ID(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(id)
+}
+
+// The local cache of the engine.
+func (r *Engine) LocalCache() *EngineCache {
+	q := r.Query.Select("localCache")
+
+	return &EngineCache{
+		Query:  q,
+		Client: r.Client,
+	}
+}
+
+// The local cache of the Dagger engine.
+type EngineCache struct {
+	Query  *querybuilder.Selection
+	Client graphql.Client
+
+	id        *EngineCacheID
+	keepBytes *int
+}
+
+// EngineCacheEntriesOpts contains options for EngineCache.Entries
+type EngineCacheEntriesOpts struct {
+	// Only list entries matching all of the filters (e.g., "type==exec.cachemount").
+	Filters []string
+}
+
+// The entries in the cache, largest first.
+func (r *EngineCache) Entries(ctx context.Context, opts ...EngineCacheEntriesOpts) ([]EngineCacheEntry, error) {
+	q := r.Query.Select("entries")
+	for i := len(opts) - 1; i >= 0; i-- {
+		// `filters` optional argument
+		if !querybuilder.IsZeroValue(opts[i].Filters) {
+			q = q.Arg("filters", opts[i].Filters)
+		}
+	}
+
+	q = q.Select("id")
+
+	type entries struct {
+		Id EngineCacheEntryID
+	}
+
+	convert := func(fields []entries) []EngineCacheEntry {
+		out := []EngineCacheEntry{}
+
+		for i := range fields {
+			val := EngineCacheEntry{id: &fields[i].Id}
+			val.Query = querybuilder.Query().Select("loadEngineCacheEntryFromID").Arg("id", fields[i].Id)
+			val.Client = r.Client
+			out = append(out, val)
+		}
+
+		return out
+	}
+	var response []entries
+
+	q = q.Bind(&response)
+
+	err := q.Execute(ctx, r.Client)
+	if err != nil {
+		return nil, err
+	}
+
+	return convert(response), nil
+}
+
+// A unique identifier for this EngineCache.
+func (r *EngineCache) ID(ctx context.Context) (EngineCacheID, error) {
+	if r.id != nil {
+		return *r.id, nil
+	}
+	q := r.Query.Select("id")
+
+	var response EngineCacheID
+
+	q = q.Bind(&response)
+	return response, q.Execute(ctx, r.Client)
+}
+
+// XXX_GraphQLType is an internal function. It returns the native GraphQL type name
+func (r *EngineCache) XXX_GraphQLType() string {
+	return "EngineCache"
+}
+
+// XXX_GraphQLIDType is an internal function. It returns the native GraphQL type name for the ID of this object
+func (r *EngineCache) XXX_GraphQLIDType() string {
+	return "EngineCacheID"
+}
+
+// XXX_GraphQLID is an internal function. It returns the underlying type ID
+func (r *EngineCache) XXX_GraphQLID(ctx context.Context) (string, error) {
+	id, err := r.ID(ctx)
+	if err != nil {
+		return "", err
+	}
+	return string(id), nil
+}
+
+func (r *EngineCache) MarshalJSON() ([]byte, error) {
+	id, err := r.ID(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(id)
+}
+
+// The most space the engine's garbage collection policy lets the cache use, in bytes, or 0 if it's unbounded.
+func (r *EngineCache) KeepBytes(ctx context.Context) (int, error) {
+	if r.keepBytes != nil {
+		return *r.keepBytes, nil
+	}
+	q := r.Query.Select("keepBytes")
+
+	var response int
+
+	q = q.Bind(&response)
+	return response, q.Execute(ctx, r.Client)
+}
+
+// EngineCachePruneOpts contains options for EngineCache.Prune
+type EngineCachePruneOpts struct {
+	// Remove every entry that isn't in use.
+	All bool
+	// Only remove entries matching all of the filters (e.g., "type==exec.cachemount").
+	Filters []string
+	// Keep entries until the cache uses at most this many bytes.
+	KeepBytes int
+	// Only remove entries that were not used in this many seconds.
+	OlderThan int
+	// Apply the engine's garbage collection policy instead of the other arguments.
+	UseDefaultPolicy bool
+}
+
+// Removes entries that aren't in use from the cache, returning the entries that were removed.
+//
+// Unless all is set, the entries to remove must be narrowed down with at least one of the other arguments. This can't be called from within a module.
+func (r *EngineCache) Prune(ctx context.Context, opts ...EngineCachePruneOpts) ([]EngineCacheEntry, error) {
+	q := r.Query.Select("prune")
+	for i := len(opts) - 1; i >= 0; i-- {
+		// `all` optional argument
+		if !querybuilder.IsZeroValue(opts[i].All) {
+			q = q.Arg("all", opts[i].All)
+		}
+		// `filters` optional argument
+		if !querybuilder.IsZeroValue(opts[i].Filters) {
+			q = q.Arg("filters", opts[i].Filters)
+		}
+		// `keepBytes` optional argument
+		if !querybuilder.IsZeroValue(opts[i].KeepBytes) {
+			q = q.Arg("keepBytes", opts[i].KeepBytes)
+		}
+		// `olderThan` optional argument
+		if !querybuilder.IsZeroValue(opts[i].OlderThan) {
+			q = q.Arg("olderThan", opts[i].OlderThan)
+		}
+		// `useDefaultPolicy` optional argument
+		if !querybuilder.IsZeroValue(opts[i].UseDefaultPolicy) {
+			q = q.Arg("useDefaultPolicy", opts[i].UseDefaultPolicy)
+		}
+	}
+
+	q = q.Select("id")
+
+	type prune struct {
+		Id EngineCacheEntryID
+	}
+
+	convert := func(fields []prune) []EngineCacheEntry {
+		out := []EngineCacheEntry{}
+
+		for i := range fields {
+			val := EngineCacheEntry{id: &fields[i].Id}
+			val.Query = querybuilder.Query().Select("loadEngineCacheEntryFromID").Arg("id", fields[i].Id)
+			val.Client = r.Client
+			out = append(out, val)
+		}
+
+		return out
+	}
+	var response []prune
+
+	q = q.Bind(&response)
+
+	err := q.Execute(ctx, r.Client)
+	if err != nil {
+		return nil, err
+	}
+
+	return convert(response), nil
+}
+
+// An entry in the local cache of the Dagger engine.
+type EngineCacheEntry struct {
+	Query  *querybuilder.Selection
+	Client graphql.Client
+
+	activelyUsed      *bool
+	createdTime       *int
+	description       *string
+	diskSpaceBytes    *int
+	id                *EngineCacheEntryID
+	mostRecentUseTime *int
+	recordType        *string
+	shared            *bool
+}
+
+// Whether the cache entry is in use, in which case it can't be pruned.
+func (r *EngineCacheEntry) ActivelyUsed(ctx context.Context) (bool, error) {
+	if r.activelyUsed != nil {
+		return *r.activelyUsed, nil
+	}
+	q := r.Query.Select("activelyUsed")
+
+	var response bool
+
+	q = q.Bind(&response)
+	return response, q.Execute(ctx, r.Client)
+}
+
+// The time the cache entry was created, in seconds following Unix epoch.
+func (r *EngineCacheEntry) CreatedTime(ctx context.Context) (int, error) {
+	if r.createdTime != nil {
+		return *r.createdTime, nil
+	}
+	q := r.Query.Select("createdTime")
+
+	var response int
+
+	q = q.Bind(&response)
+	return response, q.Execute(ctx, r.Client)
+}
+
+// The description of the cache entry.
+func (r *EngineCacheEntry) Description(ctx context.Context) (string, error) {
+	if r.description != nil {
+		return *r.description, nil
+	}
+	q := r.Query.Select("description")
+
+	var response string
+
+	q = q.Bind(&response)
+	return response, q.Execute(ctx, r.Client)
+}
+
+// The disk space used by the cache entry, in bytes.
+func (r *EngineCacheEntry) DiskSpaceBytes(ctx context.Context) (int, error) {
+	if r.diskSpaceBytes != nil {
+		return *r.diskSpaceBytes, nil
+	}
+	q := r.Query.Select("diskSpaceBytes")
+
+	var response int
+
+	q = q.Bind(&response)
+	return response, q.Execute(ctx, r.Client)
+}
+
+// A unique identifier for this EngineCacheEntry.
+func (r *EngineCacheEntry) ID(ctx context.Context) (EngineCacheEntryID, error) {
+	if r.id != nil {
+		return *r.id, nil
+	}
+	q := r.Query.Select("id")
+
+	var response EngineCacheEntryID
+
+	q = q.Bind(&response)
+	return response, q.Execute(ctx, r.Client)
+}
+
+// XXX_GraphQLType is an internal function. It returns the native GraphQL type name
+func (r *EngineCacheEntry) XXX_GraphQLType() string {
+	return "EngineCacheEntry"
+}
+
+// XXX_GraphQLIDType is an internal function. It returns the native GraphQL type name for the ID of this object
+func (r *EngineCacheEntry) XXX_GraphQLIDType() string {
+	return "EngineCacheEntryID"
+}
+
+// XXX_GraphQLID is an internal function. It returns the underlying type ID
+func (r *EngineCacheEntry) XXX_GraphQLID(ctx context.Context) (string, error) {
+	id, err := r.ID(ctx)
+	if err != nil {
+		return "", err
+	}
+	return string(id), nil
+}
+
+func (r *EngineCacheEntry) MarshalJSON() ([]byte, error) {
+	id, err := r.ID(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(id)
+}
+
+// The time the cache entry was last used, in seconds following Unix epoch.
+func (r *EngineCacheEntry) MostRecentUseTime(ctx context.Context) (int, error) {
+	if r.mostRecentUseTime != nil {
+		return *r.mostRecentUseTime, nil
+	}
+	q := r.Query.Select("mostRecentUseTime")
+
+	var response int
+
+	q = q.Bind(&response)
+	return response, q.Execute(ctx, r.Client)
+}
+
+// The type of the cache entry (e.g., "regular", "exec.cachemount", "source.local").
+func (r *EngineCacheEntry) RecordType(ctx context.Context) (string, error) {
+	if r.recordType != nil {
+		return *r.recordType, nil
+	}
+	q := r.Query.Select("recordType")
+
+	var response string
+
+	q = q.Bind(&response)
+	return response, q.Execute(ctx, r.Client)
+}
+
+// Whether the cache entry shares its data with other entries.
+func (r *EngineCacheEntry) Shared(ctx context.Context) (bool, error) {
+	if r.shared != nil {
+		return *r.shared, nil
+	}
+	q := r.Query.Select("shared")
+
+	var response bool
+
+	q = q.Bind(&response)
+	return response, q.Execute(ctx, r.Client)
+}
+
 // An environment variable name and value.
 type EnvVariable struct {
 	Query  *querybuilder.Selection
@@ -2381,6 +3400,7 @@ type File struct {
 	Client graphql.Client
 
 	contents *string
+	digest   *string
 	export   *bool
 	id       *FileID
 	name     *string
@@ -2409,6 +3429,19 @@ func (r *File) Contents(ctx context.Context) (string, error) {
 	return response, q.Execute(ctx, r.Client)
 }
 
+// Returns the SHA-256 digest of the file's contents (e.g., "sha256:...").
+func (r *File) Digest(ctx context.Context) (string, error) {
+	if r.digest != nil {
+		return *r.digest, nil
+	}
+	q := r.Query.Select("digest")
+
+	var response string
+
+	q = q.Bind(&response)
+	return response, q.Execute(ctx, r.Client)
+}
+
 // FileExportOpts contains options for File.Export
 type FileExportOpts struct {
 	// If allowParentDirPath is true, the path argument can be a directory path, in which case the file will be created in that directory.
@@ -2435,6 +3468,30 @@ func (r *File) Export(ctx context.Context, path string, opts ...FileExportOpts)
 	return response, q.Execute(ctx, r.Client)
 }
 
+// FileExtractOpts contains options for File.Extract
+type FileExtractOpts struct {
+	// File format of the archive.
+	//
+	// If not set, it's detected from the file. Compressed tarballs are detected automatically.
+	Format ArchiveFormat
+}
+
+// Extracts this file as an archive.
+func (r *File) Extract(opts ...FileExtractOpts) *Directory {
+	q := r.Query.Select("extract")
+	for i := len(opts) - 1; i >= 0; i-- {
+		// `format` optional argument
+		if !querybuilder.IsZeroValue(opts[i].Format) {
+			q = q.Arg("format", opts[i].Format)
+		}
+	}
+
+	return &Directory{
+		Query:  q,
+		Client: r.Client,
+	}
+}
+
 // A unique identifier for this File.
 func (r *File) ID(ctx context.Context) (FileID, error) {
 	if r.id != nil {
@@ -2501,6 +3558,16 @@ func (r *File) Size(ctx context.Context) (int, error) {
 	return response, q.Execute(ctx, r.Client)
 }
 
+// Retrieves the metadata of the file.
+func (r *File) Stat() *Stat {
+	q := r.Query.Select("stat")
+
+	return &Stat{
+		Query:  q,
+		Client: r.Client,
+	}
+}
+
 // Force evaluation in the engine.
 func (r *File) Sync(ctx context.Context) (*File, error) {
 	q := r.Query.Select("sync")
@@ -2508,6 +3575,17 @@ func (r *File) Sync(ctx context.Context) (*File, error) {
 	return r, q.Execute(ctx, r.Client)
 }
 
+// Retrieves this file with the given name.
+func (r *File) WithName(name string) *File {
+	q := r.Query.Select("withName")
+	q = q.Arg("name", name)
+
+	return &File{
+		Query:  q,
+		Client: r.Client,
+	}
+}
+
 // Retrieves this file with its created/modified timestamps set to the given time.
 func (r *File) WithTimestamps(timestamp int) *File {
 	q := r.Query.Select("withTimestamps")
@@ -2526,9 +3604,11 @@ type Function struct {
 	Query  *querybuilder.Selection
 	Client graphql.Client
 
-	description *string
-	id          *FunctionID
-	name        *string
+	cachePolicy     *FunctionCachePolicy
+	cacheTimeToLive *int
+	description     *string
+	id              *FunctionID
+	name            *string
 }
 type WithFunctionFunc func(r *Function) *Function
 
@@ -2573,6 +3653,32 @@ func (r *Function) Args(ctx context.Context) ([]FunctionArg, error) {
 	return convert(response), nil
 }
 
+// How the results of calls to the function are cached.
+func (r *Function) CachePolicy(ctx context.Context) (FunctionCachePolicy, error) {
+	if r.cachePolicy != nil {
+		return *r.cachePolicy, nil
+	}
+	q := r.Query.Select("cachePolicy")
+
+	var response FunctionCachePolicy
+
+	q = q.Bind(&response)
+	return response, q.Execute(ctx, r.Client)
+}
+
+// How long the results of calls to the function are cached for, in seconds, if its cache policy is TTL_CACHE.
+func (r *Function) CacheTimeToLive(ctx context.Context) (int, error) {
+	if r.cacheTimeToLive != nil {
+		return *r.cacheTimeToLive, nil
+	}
+	q := r.Query.Select("cacheTimeToLive")
+
+	var response int
+
+	q = q.Bind(&response)
+	return response, q.Execute(ctx, r.Client)
+}
+
 // A doc string for the function, if any.
 func (r *Function) Description(ctx context.Context) (string, error) {
 	if r.description != nil {
@@ -2680,6 +3786,29 @@ func (r *Function) WithArg(name string, typeDef *TypeDef, opts ...FunctionWithAr
 	}
 }
 
+// FunctionWithCachePolicyOpts contains options for Function.WithCachePolicy
+type FunctionWithCachePolicyOpts struct {
+	// How long the results are reused for, as a duration (e.g., "10m"). Required for TTL_CACHE.
+	TimeToLive string
+}
+
+// Returns the function with the given cache policy.
+func (r *Function) WithCachePolicy(policy FunctionCachePolicy, opts ...FunctionWithCachePolicyOpts) *Function {
+	q := r.Query.Select("withCachePolicy")
+	for i := len(opts) - 1; i >= 0; i-- {
+		// `timeToLive` optional argument
+		if !querybuilder.IsZeroValue(opts[i].TimeToLive) {
+			q = q.Arg("timeToLive", opts[i].TimeToLive)
+		}
+	}
+	q = q.Arg("policy", policy)
+
+	return &Function{
+		Query:  q,
+		Client: r.Client,
+	}
+}
+
 // Returns the function with the given doc string.
 func (r *Function) WithDescription(description string) *Function {
 	q := r.Query.Select("withDescription")
@@ -4757,6 +5886,17 @@ func (r *ModuleSource) WithName(name string) *ModuleSource {
 	}
 }
 
+// Load the module source as a dependency of another module, resolving git modules required with version constraints anywhere in its dependency tree to the given versions.
+func (r *ModuleSource) WithResolvedDependencyVersions(versions []string) *ModuleSource {
+	q := r.Query.Select("withResolvedDependencyVersions")
+	q = q.Arg("versions", versions)
+
+	return &ModuleSource{
+		Query:  q,
+		Client: r.Client,
+	}
+}
+
 // Update the module source with a new SDK.
 func (r *ModuleSource) WithSDK(sdk string) *ModuleSource {
 	q := r.Query.Select("withSDK")
@@ -4779,6 +5919,28 @@ func (r *ModuleSource) WithSourceSubpath(path string) *ModuleSource {
 	}
 }
 
+// Re-resolve the named configured dependencies from their source ref's version, ignoring their pins.
+func (r *ModuleSource) WithUpdateDependencies(dependencies []string) *ModuleSource {
+	q := r.Query.Select("withUpdateDependencies")
+	q = q.Arg("dependencies", dependencies)
+
+	return &ModuleSource{
+		Query:  q,
+		Client: r.Client,
+	}
+}
+
+// Remove the named dependencies from the module source's dependency list.
+func (r *ModuleSource) WithoutDependencies(dependencies []string) *ModuleSource {
+	q := r.Query.Select("withoutDependencies")
+	q = q.Arg("dependencies", dependencies)
+
+	return &ModuleSource{
+		Query:  q,
+		Client: r.Client,
+	}
+}
+
 // A definition of a custom object defined in a Module.
 type ObjectTypeDef struct {
 	Query  *querybuilder.Selection
@@ -5079,21 +6241,81 @@ func (r *Client) BuiltinContainer(digest string) *Container {
 	q := r.Query.Select("builtinContainer")
 	q = q.Arg("digest", digest)
 
-	return &Container{
-		Query:  q,
-		Client: r.Client,
+	return &Container{
+		Query:  q,
+		Client: r.Client,
+	}
+}
+
+// CacheVolumeOpts contains options for Client.CacheVolume
+type CacheVolumeOpts struct {
+	// A namespace to scope the cache volume to instead of the calling module.
+	//
+	// Cache volumes with the same key and namespace are shared.
+	Namespace string
+	// Share the cache volume with every module and client that uses the same key.
+	Shared bool
+}
+
+// Constructs a cache volume for a given cache key.
+//
+// By default, cache volumes constructed by a module are scoped to that module, so that modules using the same key don't share a volume. Cache volumes constructed by the main client are shared.
+//
+// Namespaces keep modules from sharing cache volumes by accident, but are not access control: any module can construct a cache volume in another module's namespace, and use the cache volumes other modules give it.
+func (r *Client) CacheVolume(key string, opts ...CacheVolumeOpts) *CacheVolume {
+	q := r.Query.Select("cacheVolume")
+	for i := len(opts) - 1; i >= 0; i-- {
+		// `namespace` optional argument
+		if !querybuilder.IsZeroValue(opts[i].Namespace) {
+			q = q.Arg("namespace", opts[i].Namespace)
+		}
+		// `shared` optional argument
+		if !querybuilder.IsZeroValue(opts[i].Shared) {
+			q = q.Arg("shared", opts[i].Shared)
+		}
+	}
+	q = q.Arg("key", key)
+
+	return &CacheVolume{
+		Query:  q,
+		Client: r.Client,
+	}
+}
+
+// Lists the cache volumes used since the engine started, sorted by namespace and key.
+//
+// Modules only see their own cache volumes and shared ones.
+func (r *Client) CacheVolumes(ctx context.Context) ([]CacheVolume, error) {
+	q := r.Query.Select("cacheVolumes")
+
+	q = q.Select("id")
+
+	type cacheVolumes struct {
+		Id CacheVolumeID
+	}
+
+	convert := func(fields []cacheVolumes) []CacheVolume {
+		out := []CacheVolume{}
+
+		for i := range fields {
+			val := CacheVolume{id: &fields[i].Id}
+			val.Query = querybuilder.Query().Select("loadCacheVolumeFromID").Arg("id", fields[i].Id)
+			val.Client = r.Client
+			out = append(out, val)
+		}
+
+		return out
 	}
-}
+	var response []cacheVolumes
 
-// Constructs a cache volume for a given cache key.
-func (r *Client) CacheVolume(key string) *CacheVolume {
-	q := r.Query.Select("cacheVolume")
-	q = q.Arg("key", key)
+	q = q.Bind(&response)
 
-	return &CacheVolume{
-		Query:  q,
-		Client: r.Client,
+	err := q.Execute(ctx, r.Client)
+	if err != nil {
+		return nil, err
 	}
+
+	return convert(response), nil
 }
 
 // Checks if the current Dagger Engine is compatible with an SDK's required version.
@@ -5225,6 +6447,16 @@ func (r *Client) Directory(opts ...DirectoryOpts) *Directory {
 	}
 }
 
+// Queries the Dagger engine the client is connected to.
+func (r *Client) Engine() *Engine {
+	q := r.Query.Select("engine")
+
+	return &Engine{
+		Query:  q,
+		Client: r.Client,
+	}
+}
+
 // Deprecated: Use LoadFileFromID instead.
 func (r *Client) File(id FileID) *File {
 	q := r.Query.Select("file")
@@ -5316,6 +6548,18 @@ func (r *Client) Host() *Host {
 type HTTPOpts struct {
 	// A service which must be started before the URL is fetched.
 	ExperimentalServiceHost *Service
+	// Expected digest of the content (e.g., "sha256:...").
+	//
+	// The download fails if the content does not match.
+	Checksum string
+	// Additional headers to send with the request.
+	Headers []HTTPHeader
+	// Secret used as the value of the Authorization header (e.g., "Bearer ...").
+	AuthHeader *Secret
+	// Name of the downloaded file (defaults to a digest of the URL).
+	Name string
+	// Permission given to the downloaded file (e.g., 0600).
+	Permissions int
 }
 
 // Returns a file containing an http remote url content.
@@ -5326,6 +6570,26 @@ func (r *Client) HTTP(url string, opts ...HTTPOpts) *File {
 		if !querybuilder.IsZeroValue(opts[i].ExperimentalServiceHost) {
 			q = q.Arg("experimentalServiceHost", opts[i].ExperimentalServiceHost)
 		}
+		// `checksum` optional argument
+		if !querybuilder.IsZeroValue(opts[i].Checksum) {
+			q = q.Arg("checksum", opts[i].Checksum)
+		}
+		// `headers` optional argument
+		if !querybuilder.IsZeroValue(opts[i].Headers) {
+			q = q.Arg("headers", opts[i].Headers)
+		}
+		// `authHeader` optional argument
+		if !querybuilder.IsZeroValue(opts[i].AuthHeader) {
+			q = q.Arg("authHeader", opts[i].AuthHeader)
+		}
+		// `name` optional argument
+		if !querybuilder.IsZeroValue(opts[i].Name) {
+			q = q.Arg("name", opts[i].Name)
+		}
+		// `permissions` optional argument
+		if !querybuilder.IsZeroValue(opts[i].Permissions) {
+			q = q.Arg("permissions", opts[i].Permissions)
+		}
 	}
 	q = q.Arg("url", url)
 
@@ -5379,6 +6643,39 @@ func (r *Client) LoadDirectoryFromID(id DirectoryID) *Directory {
 	}
 }
 
+// Load a EngineCacheEntry from its ID.
+func (r *Client) LoadEngineCacheEntryFromID(id EngineCacheEntryID) *EngineCacheEntry {
+	q := r.Query.Select("loadEngineCacheEntryFromID")
+	q = q.Arg("id", id)
+
+	return &EngineCacheEntry{
+		Query:  q,
+		Client: r.Client,
+	}
+}
+
+// Load a EngineCache from its ID.
+func (r *Client) LoadEngineCacheFromID(id EngineCacheID) *EngineCache {
+	q := r.Query.Select("loadEngineCacheFromID")
+	q = q.Arg("id", id)
+
+	return &EngineCache{
+		Query:  q,
+		Client: r.Client,
+	}
+}
+
+// Load a Engine from its ID.
+func (r *Client) LoadEngineFromID(id EngineID) *Engine {
+	q := r.Query.Select("loadEngineFromID")
+	q = q.Arg("id", id)
+
+	return &Engine{
+		Query:  q,
+		Client: r.Client,
+	}
+}
+
 // Load a EnvVariable from its ID.
 func (r *Client) LoadEnvVariableFromID(id EnvVariableID) *EnvVariable {
 	q := r.Query.Select("loadEnvVariableFromID")
@@ -5654,6 +6951,17 @@ func (r *Client) LoadSocketFromID(id SocketID) *Socket {
 	}
 }
 
+// Load a Stat from its ID.
+func (r *Client) LoadStatFromID(id StatID) *Stat {
+	q := r.Query.Select("loadStatFromID")
+	q = q.Arg("id", id)
+
+	return &Stat{
+		Query:  q,
+		Client: r.Client,
+	}
+}
+
 // Load a Terminal from its ID.
 func (r *Client) LoadTerminalFromID(id TerminalID) *Terminal {
 	q := r.Query.Select("loadTerminalFromID")
@@ -5712,6 +7020,8 @@ func (r *Client) ModuleDependency(source *ModuleSource, opts ...ModuleDependency
 
 // ModuleSourceOpts contains options for Client.ModuleSource
 type ModuleSourceOpts struct {
+	// The pinned version of the module source, e.g. the resolved commit of a git source. If set, the ref's version is not resolved again.
+	RefPin string
 	// If true, enforce that the source is a stable version for source kinds that support versioning.
 	Stable bool
 }
@@ -5720,6 +7030,10 @@ type ModuleSourceOpts struct {
 func (r *Client) ModuleSource(refString string, opts ...ModuleSourceOpts) *ModuleSource {
 	q := r.Query.Select("moduleSource")
 	for i := len(opts) - 1; i >= 0; i-- {
+		// `refPin` optional argument
+		if !querybuilder.IsZeroValue(opts[i].RefPin) {
+			q = q.Arg("refPin", opts[i].RefPin)
+		}
 		// `stable` optional argument
 		if !querybuilder.IsZeroValue(opts[i].Stable) {
 			q = q.Arg("stable", opts[i].Stable)
@@ -5765,9 +7079,13 @@ func (r *Client) Pipeline(name string, opts ...PipelineOpts) *Client {
 // SecretOpts contains options for Client.Secret
 type SecretOpts struct {
 	Accessor string
+	// The URI of the secret in a secret provider, e.g. "vault://secret/data/ci#token", "env://TOKEN", "file:///run/secrets/token", "cmd://gcloud auth print-access-token", "op://vault/item/field" or "sops:///secrets.yaml#db.password".
+	URI string
 }
 
 // Reference a secret by name.
+//
+// If a URI is given, the secret's plaintext is fetched by the engine from the matching secret provider each time the secret is used. Secret providers run in the engine, so they must be enabled in its configuration first.
 func (r *Client) Secret(name string, opts ...SecretOpts) *Secret {
 	q := r.Query.Select("secret")
 	for i := len(opts) - 1; i >= 0; i-- {
@@ -5775,6 +7093,10 @@ func (r *Client) Secret(name string, opts ...SecretOpts) *Secret {
 		if !querybuilder.IsZeroValue(opts[i].Accessor) {
 			q = q.Arg("accessor", opts[i].Accessor)
 		}
+		// `uri` optional argument
+		if !querybuilder.IsZeroValue(opts[i].URI) {
+			q = q.Arg("uri", opts[i].URI)
+		}
 	}
 	q = q.Arg("name", name)
 
@@ -5889,9 +7211,12 @@ type Service struct {
 	Client graphql.Client
 
 	endpoint *string
+	exitCode *int
 	hostname *string
 	id       *ServiceID
+	logs     *string
 	start    *ServiceID
+	status   *ServiceStatus
 	stop     *ServiceID
 	up       *Void
 }
@@ -5931,6 +7256,21 @@ func (r *Service) Endpoint(ctx context.Context, opts ...ServiceEndpointOpts) (st
 	return response, q.Execute(ctx, r.Client)
 }
 
+// The exit code of the last run of the service.
+//
+// Returns an error if the service has not exited yet.
+func (r *Service) ExitCode(ctx context.Context) (int, error) {
+	if r.exitCode != nil {
+		return *r.exitCode, nil
+	}
+	q := r.Query.Select("exitCode")
+
+	var response int
+
+	q = q.Bind(&response)
+	return response, q.Execute(ctx, r.Client)
+}
+
 // Retrieves a hostname which can be used by clients to reach this container.
 func (r *Service) Hostname(ctx context.Context) (string, error) {
 	if r.hostname != nil {
@@ -5984,6 +7324,45 @@ func (r *Service) MarshalJSON() ([]byte, error) {
 	return json.Marshal(id)
 }
 
+// ServiceLogsOpts contains options for Service.Logs
+type ServiceLogsOpts struct {
+	// Only return output written after this time.
+	//
+	// Either an RFC 3339 timestamp (e.g., "2024-01-02T15:04:05Z") or a duration relative to now (e.g., "10m").
+	Since string
+	// Only return this many of the most recent lines.
+	Tail int
+	// Wait for the service to exit before returning its output.
+	Follow bool
+}
+
+// The combined stdout and stderr of the current or last run of the service.
+func (r *Service) Logs(ctx context.Context, opts ...ServiceLogsOpts) (string, error) {
+	if r.logs != nil {
+		return *r.logs, nil
+	}
+	q := r.Query.Select("logs")
+	for i := len(opts) - 1; i >= 0; i-- {
+		// `since` optional argument
+		if !querybuilder.IsZeroValue(opts[i].Since) {
+			q = q.Arg("since", opts[i].Since)
+		}
+		// `tail` optional argument
+		if !querybuilder.IsZeroValue(opts[i].Tail) {
+			q = q.Arg("tail", opts[i].Tail)
+		}
+		// `follow` optional argument
+		if !querybuilder.IsZeroValue(opts[i].Follow) {
+			q = q.Arg("follow", opts[i].Follow)
+		}
+	}
+
+	var response string
+
+	q = q.Bind(&response)
+	return response, q.Execute(ctx, r.Client)
+}
+
 // Retrieves the list of ports provided by the service.
 func (r *Service) Ports(ctx context.Context) ([]Port, error) {
 	q := r.Query.Select("ports")
@@ -6027,6 +7406,19 @@ func (r *Service) Start(ctx context.Context) (*Service, error) {
 	return r, q.Execute(ctx, r.Client)
 }
 
+// The status of the current or last run of the service.
+func (r *Service) Status(ctx context.Context) (ServiceStatus, error) {
+	if r.status != nil {
+		return *r.status, nil
+	}
+	q := r.Query.Select("status")
+
+	var response ServiceStatus
+
+	q = q.Bind(&response)
+	return response, q.Execute(ctx, r.Client)
+}
+
 // ServiceStopOpts contains options for Service.Stop
 type ServiceStopOpts struct {
 	// Immediately kill the service without waiting for a graceful exit
@@ -6127,6 +7519,166 @@ func (r *Socket) MarshalJSON() ([]byte, error) {
 	return json.Marshal(id)
 }
 
+// The metadata of a file, directory or symlink.
+type Stat struct {
+	Query  *querybuilder.Selection
+	Client graphql.Client
+
+	fileType   *FileType
+	gid        *int
+	id         *StatID
+	linkTarget *string
+	mode       *int
+	mtime      *int
+	name       *string
+	size       *int
+	uid        *int
+}
+
+// The type of the entry.
+func (r *Stat) FileType(ctx context.Context) (FileType, error) {
+	if r.fileType != nil {
+		return *r.fileType, nil
+	}
+	q := r.Query.Select("fileType")
+
+	var response FileType
+
+	q = q.Bind(&response)
+	return response, q.Execute(ctx, r.Client)
+}
+
+// The ID of the group that owns the entry.
+func (r *Stat) Gid(ctx context.Context) (int, error) {
+	if r.gid != nil {
+		return *r.gid, nil
+	}
+	q := r.Query.Select("gid")
+
+	var response int
+
+	q = q.Bind(&response)
+	return response, q.Execute(ctx, r.Client)
+}
+
+// A unique identifier for this Stat.
+func (r *Stat) ID(ctx context.Context) (StatID, error) {
+	if r.id != nil {
+		return *r.id, nil
+	}
+	q := r.Query.Select("id")
+
+	var response StatID
+
+	q = q.Bind(&response)
+	return response, q.Execute(ctx, r.Client)
+}
+
+// XXX_GraphQLType is an internal function. It returns the native GraphQL type name
+func (r *Stat) XXX_GraphQLType() string {
+	return "Stat"
+}
+
+// XXX_GraphQLIDType is an internal function. It returns the native GraphQL type name for the ID of this object
+func (r *Stat) XXX_GraphQLIDType() string {
+	return "StatID"
+}
+
+// XXX_GraphQLID is an internal function. It returns the underlying type ID
+func (r *Stat) XXX_GraphQLID(ctx context.Context) (string, error) {
+	id, err := r.ID(ctx)
+	if err != nil {
+		return "", err
+	}
+	return string(id), nil
+}
+
+func (r *Stat) MarshalJSON() ([]byte, error) {
+	id, err := r.ID(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(id)
+}
+
+// The path a symlink points to, or an empty string for other types of entries.
+func (r *Stat) LinkTarget(ctx context.Context) (string, error) {
+	if r.linkTarget != nil {
+		return *r.linkTarget, nil
+	}
+	q := r.Query.Select("linkTarget")
+
+	var response string
+
+	q = q.Bind(&response)
+	return response, q.Execute(ctx, r.Client)
+}
+
+// The permission bits of the entry, including setuid, setgid and sticky bits (e.g., 0755).
+func (r *Stat) Mode(ctx context.Context) (int, error) {
+	if r.mode != nil {
+		return *r.mode, nil
+	}
+	q := r.Query.Select("mode")
+
+	var response int
+
+	q = q.Bind(&response)
+	return response, q.Execute(ctx, r.Client)
+}
+
+// The last modification time of the entry, in seconds following Unix epoch.
+func (r *Stat) Mtime(ctx context.Context) (int, error) {
+	if r.mtime != nil {
+		return *r.mtime, nil
+	}
+	q := r.Query.Select("mtime")
+
+	var response int
+
+	q = q.Bind(&response)
+	return response, q.Execute(ctx, r.Client)
+}
+
+// The base name of the entry.
+func (r *Stat) Name(ctx context.Context) (string, error) {
+	if r.name != nil {
+		return *r.name, nil
+	}
+	q := r.Query.Select("name")
+
+	var response string
+
+	q = q.Bind(&response)
+	return response, q.Execute(ctx, r.Client)
+}
+
+// The size of the entry, in bytes.
+func (r *Stat) Size(ctx context.Context) (int, error) {
+	if r.size != nil {
+		return *r.size, nil
+	}
+	q := r.Query.Select("size")
+
+	var response int
+
+	q = q.Bind(&response)
+	return response, q.Execute(ctx, r.Client)
+}
+
+// The ID of the user that owns the entry.
+func (r *Stat) UID(ctx context.Context) (int, error) {
+	if r.uid != nil {
+		return *r.uid, nil
+	}
+	q := r.Query.Select("uid")
+
+	var response int
+
+	q = q.Bind(&response)
+	return response, q.Execute(ctx, r.Client)
+}
+
 // An interactive terminal that clients can connect to.
 type Terminal struct {
 	Query  *querybuilder.Selection
@@ -6442,6 +7994,18 @@ func (r *TypeDef) WithOptional(optional bool) *TypeDef {
 	}
 }
 
+type ArchiveFormat string
+
+func (ArchiveFormat) IsEnum() {}
+
+const (
+	// A tarball
+	Tar ArchiveFormat = "TAR"
+
+	// A zip archive
+	Zip ArchiveFormat = "ZIP"
+)
+
 type CacheSharingMode string
 
 func (CacheSharingMode) IsEnum() {}
@@ -6457,6 +8021,41 @@ const (
 	Shared CacheSharingMode = "SHARED"
 )
 
+type FileType string
+
+func (FileType) IsEnum() {}
+
+const (
+	// A directory
+	Dir FileType = "DIR"
+
+	// Any other type of entry, like a device, pipe or socket
+	Other FileType = "OTHER"
+
+	// A regular file
+	Regular FileType = "REGULAR"
+
+	// A symbolic link
+	Symlink FileType = "SYMLINK"
+)
+
+type FunctionCachePolicy string
+
+func (FunctionCachePolicy) IsEnum() {}
+
+const (
+	// The function always returns the same result for the same inputs, so its results are reused across sessions.
+	PureCache FunctionCachePolicy = "PURE_CACHE"
+
+	// The function is called at most once per session for the same inputs.
+	//
+	// This is the default.
+	SessionCache FunctionCachePolicy = "SESSION_CACHE"
+
+	// The results of the function are reused across sessions until they are older than its time to live.
+	TtlCache FunctionCachePolicy = "TTL_CACHE"
+)
+
 type ImageLayerCompression string
 
 func (ImageLayerCompression) IsEnum() {}
@@ -6501,6 +8100,54 @@ const (
 	Udp NetworkProtocol = "UDP"
 )
 
+type ReturnType string
+
+func (ReturnType) IsEnum() {}
+
+const (
+	// Any execution, regardless of its exit code
+	Any ReturnType = "ANY"
+
+	// A failed execution (non-zero exit code)
+	Failure ReturnType = "FAILURE"
+
+	// A successful execution (exit code 0)
+	Success ReturnType = "SUCCESS"
+)
+
+type ServiceRestartPolicy string
+
+func (ServiceRestartPolicy) IsEnum() {}
+
+const (
+	// Restart the service whenever it exits, unless it was stopped
+	Always ServiceRestartPolicy = "ALWAYS"
+
+	// Never restart the service
+	Never ServiceRestartPolicy = "NEVER"
+
+	// Restart the service if it exits with a non-zero exit code
+	OnFailure ServiceRestartPolicy = "ON_FAILURE"
+)
+
+type ServiceStatus string
+
+func (ServiceStatus) IsEnum() {}
+
+const (
+	// The service has exited or been stopped
+	Exited ServiceStatus = "EXITED"
+
+	// The service is running and its health checks have passed
+	Healthy ServiceStatus = "HEALTHY"
+
+	// The service has not been started
+	NotStarted ServiceStatus = "NOT_STARTED"
+
+	// The service is starting and waiting for its health checks to pass
+	Starting ServiceStatus = "STARTING"
+)
+
 type TypeDefKind string
 
 func (TypeDefKind) IsEnum() {}
@@ -12,6 +12,7 @@ from ._exceptions import ExecuteTimeoutError as ExecuteTimeoutError
 from ._exceptions import InvalidQueryError as InvalidQueryError
 from ._exceptions import QueryError as QueryError
 from ._exceptions import ExecError as ExecError
+from ._exceptions import ExecTimeoutError as ExecTimeoutError
 
 # Make sure Config is first as it's a dependency in Connection.
 from ._config import Config as Config
@@ -87,7 +87,7 @@ class QueryError(ClientError):
     def __new__(cls, errors: list[QueryErrorValue], *_):
         error_types = {
             subclass._type: subclass  # noqa: SLF001
-            for subclass in cls.__subclasses__()
+            for subclass in _subclasses(cls)
             if subclass._type  # noqa: SLF001
         }
         try:
@@ -132,6 +132,12 @@ class QueryError(ClientError):
         return "\n".join(res)
 
 
+def _subclasses(cls):
+    for subclass in cls.__subclasses__():
+        yield subclass
+        yield from _subclasses(subclass)
+
+
 def _query_error_from_transport(exc: TransportQueryError, query: graphql.DocumentNode):
     """Create instance from a gql exception."""
     try:
@@ -184,6 +190,26 @@ class ExecError(QueryError):
         return f"{self.message}\nStdout:\n{self.stdout}\nStderr:\n{self.stderr}"
 
 
+class ExecTimeoutError(ExecError):
+    """API error from an exec operation that ran longer than its timeout.
+
+    Attributes
+    ----------
+    timeout:
+        The timeout the command exceeded, in seconds.
+    """
+
+    _type = "EXEC_TIMEOUT_ERROR"
+
+    timeout: int
+
+    def __init__(self, *args, **kwargs):
+        super().__init__(*args, **kwargs)
+
+        error: QueryErrorValue = self.args[0]
+        self.timeout = error.extensions["timeout"]
+
+
 __all__ = [
     "VersionMismatch",
     "DaggerError",
@@ -197,4 +223,5 @@ __all__ = [
     "InvalidQueryError",
     "QueryError",
     "ExecError",
+    "ExecTimeoutError",
 ]
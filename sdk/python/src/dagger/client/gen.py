@@ -29,6 +29,21 @@ class DirectoryID(Scalar):
     object of type Directory."""
 
 
+class EngineCacheEntryID(Scalar):
+    """The `EngineCacheEntryID` scalar type represents an identifier for
+    an object of type EngineCacheEntry."""
+
+
+class EngineCacheID(Scalar):
+    """The `EngineCacheID` scalar type represents an identifier for an
+    object of type EngineCache."""
+
+
+class EngineID(Scalar):
+    """The `EngineID` scalar type represents an identifier for an object
+    of type Engine."""
+
+
 class EnvVariableID(Scalar):
     """The `EnvVariableID` scalar type represents an identifier for an
     object of type EnvVariable."""
@@ -164,6 +179,11 @@ class SocketID(Scalar):
     of type Socket."""
 
 
+class StatID(Scalar):
+    """The `StatID` scalar type represents an identifier for an object of
+    type Stat."""
+
+
 class TerminalID(Scalar):
     """The `TerminalID` scalar type represents an identifier for an object
     of type Terminal."""
@@ -179,6 +199,16 @@ class Void(Scalar):
     resolvers that do not return anything."""
 
 
+class ArchiveFormat(Enum):
+    """File format of an archive."""
+
+    TAR = "TAR"
+    """A tarball"""
+
+    ZIP = "ZIP"
+    """A zip archive"""
+
+
 class CacheSharingMode(Enum):
     """Sharing mode of the cache volume."""
 
@@ -192,6 +222,38 @@ class CacheSharingMode(Enum):
     """Shares the cache volume amongst many build pipelines"""
 
 
+class FileType(Enum):
+    """The type of a filesystem entry."""
+
+    DIR = "DIR"
+    """A directory"""
+
+    OTHER = "OTHER"
+    """Any other type of entry, like a device, pipe or socket"""
+
+    REGULAR = "REGULAR"
+    """A regular file"""
+
+    SYMLINK = "SYMLINK"
+    """A symbolic link"""
+
+
+class FunctionCachePolicy(Enum):
+    """How the results of calls to a function are cached."""
+
+    PURE_CACHE = "PURE_CACHE"
+    """The function always returns the same result for the same inputs, so its results are reused across sessions."""
+
+    SESSION_CACHE = "SESSION_CACHE"
+    """The function is called at most once per session for the same inputs.
+
+    This is the default.
+    """
+
+    TTL_CACHE = "TTL_CACHE"
+    """The results of the function are reused across sessions until they are older than its time to live."""
+
+
 class ImageLayerCompression(Enum):
     """Compression algorithm to use for image layers."""
 
@@ -228,6 +290,48 @@ class NetworkProtocol(Enum):
     UDP = "UDP"
 
 
+class ReturnType(Enum):
+    """Expected return type of an execution"""
+
+    ANY = "ANY"
+    """Any execution, regardless of its exit code"""
+
+    FAILURE = "FAILURE"
+    """A failed execution (non-zero exit code)"""
+
+    SUCCESS = "SUCCESS"
+    """A successful execution (exit code 0)"""
+
+
+class ServiceRestartPolicy(Enum):
+    """When to restart a service whose process exits."""
+
+    ALWAYS = "ALWAYS"
+    """Restart the service whenever it exits, unless it was stopped"""
+
+    NEVER = "NEVER"
+    """Never restart the service"""
+
+    ON_FAILURE = "ON_FAILURE"
+    """Restart the service if it exits with a non-zero exit code"""
+
+
+class ServiceStatus(Enum):
+    """The status of a service."""
+
+    EXITED = "EXITED"
+    """The service has exited or been stopped"""
+
+    HEALTHY = "HEALTHY"
+    """The service is running and its health checks have passed"""
+
+    NOT_STARTED = "NOT_STARTED"
+    """The service has not been started"""
+
+    STARTING = "STARTING"
+    """The service is starting and waiting for its health checks to pass"""
+
+
 class TypeDefKind(Enum):
     """Distinguishes the different kinds of TypeDefs."""
 
@@ -279,6 +383,31 @@ class BuildArg(Input):
     """The build argument value."""
 
 
+@dataclass(slots=True)
+class HTTPHeader(Input):
+    """Key value object that represents an HTTP header."""
+
+    name: str
+    """The header name."""
+
+    value: str
+    """The header value."""
+
+
+@dataclass(slots=True)
+class HealthcheckHTTPGet(Input):
+    """An HTTP request used to probe a service's health."""
+
+    port: int
+    """The port to send the request to."""
+
+    expect_status: int | None = 200
+    """The response status the service is healthy with."""
+
+    path: str | None = "/"
+    """The path to request."""
+
+
 @dataclass(slots=True)
 class PipelineLabel(Input):
     """Key value object that represents a pipeline label."""
@@ -307,6 +436,30 @@ class PortForward(Input):
 class CacheVolume(Type):
     """A directory whose contents persist across runs."""
 
+    @typecheck
+    async def clear(self) -> Void | None:
+        """Empties the cache volume.
+
+        Subsequent uses of the cache volume start from an empty directory,
+        while pipelines using it right now keep their contents.
+
+        Returns
+        -------
+        Void | None
+            The absence of a value.  A Null Void is used as a placeholder for
+            resolvers that do not return anything.
+
+        Raises
+        ------
+        ExecuteTimeoutError
+            If the time to execute the query exceeds the configured timeout.
+        QueryError
+            If the API returns an error.
+        """
+        _args: list[Arg] = []
+        _ctx = self._select("clear", _args)
+        return await _ctx.execute(Void | None)
+
     @typecheck
     async def id(self) -> CacheVolumeID:
         """A unique identifier for this CacheVolume.
@@ -332,17 +485,162 @@ class CacheVolume(Type):
         _ctx = self._select("id", _args)
         return await _ctx.execute(CacheVolumeID)
 
+    @typecheck
+    async def key(self) -> str:
+        """The key the cache volume was constructed with.
+
+        Returns
+        -------
+        str
+            The `String` scalar type represents textual data, represented as
+            UTF-8 character sequences. The String type is most often used by
+            GraphQL to represent free-form human-readable text.
+
+        Raises
+        ------
+        ExecuteTimeoutError
+            If the time to execute the query exceeds the configured timeout.
+        QueryError
+            If the API returns an error.
+        """
+        _args: list[Arg] = []
+        _ctx = self._select("key", _args)
+        return await _ctx.execute(str)
+
+    @typecheck
+    async def most_recent_use_time(self) -> int:
+        """The time the cache volume was last used, in seconds following Unix
+        epoch, or 0 if it was never used.
+
+        Returns
+        -------
+        int
+            The `Int` scalar type represents non-fractional signed whole
+            numeric values. Int can represent values between -(2^31) and 2^31
+            - 1.
+
+        Raises
+        ------
+        ExecuteTimeoutError
+            If the time to execute the query exceeds the configured timeout.
+        QueryError
+            If the API returns an error.
+        """
+        _args: list[Arg] = []
+        _ctx = self._select("mostRecentUseTime", _args)
+        return await _ctx.execute(int)
+
+    @typecheck
+    async def namespace(self) -> str:
+        """The namespace the cache volume is scoped to, or an empty string if
+        it's shared.
+
+        Returns
+        -------
+        str
+            The `String` scalar type represents textual data, represented as
+            UTF-8 character sequences. The String type is most often used by
+            GraphQL to represent free-form human-readable text.
+
+        Raises
+        ------
+        ExecuteTimeoutError
+            If the time to execute the query exceeds the configured timeout.
+        QueryError
+            If the API returns an error.
+        """
+        _args: list[Arg] = []
+        _ctx = self._select("namespace", _args)
+        return await _ctx.execute(str)
+
+    @typecheck
+    async def seed(self, source: "Directory") -> Void | None:
+        """Copies the contents of a directory into the cache volume.
+
+        Files already in the cache volume are kept unless the directory has a
+        file at the same path.
+
+        Parameters
+        ----------
+        source:
+            The directory to copy into the cache volume.
+
+        Returns
+        -------
+        Void | None
+            The absence of a value.  A Null Void is used as a placeholder for
+            resolvers that do not return anything.
+
+        Raises
+        ------
+        ExecuteTimeoutError
+            If the time to execute the query exceeds the configured timeout.
+        QueryError
+            If the API returns an error.
+        """
+        _args = [
+            Arg("source", source),
+        ]
+        _ctx = self._select("seed", _args)
+        return await _ctx.execute(Void | None)
+
+    @typecheck
+    async def size(self) -> int:
+        """The disk space used by the cache volume, in bytes.
+
+        Returns
+        -------
+        int
+            The `Int` scalar type represents non-fractional signed whole
+            numeric values. Int can represent values between -(2^31) and 2^31
+            - 1.
+
+        Raises
+        ------
+        ExecuteTimeoutError
+            If the time to execute the query exceeds the configured timeout.
+        QueryError
+            If the API returns an error.
+        """
+        _args: list[Arg] = []
+        _ctx = self._select("size", _args)
+        return await _ctx.execute(int)
+
+    @typecheck
+    def snapshot(self) -> "Directory":
+        """Returns a copy of the current contents of the cache volume."""
+        _args: list[Arg] = []
+        _ctx = self._select("snapshot", _args)
+        return Directory(_ctx)
+
 
 class Container(Type):
     """An OCI-compatible container, also known as a Docker container."""
 
     @typecheck
-    def as_service(self) -> "Service":
+    def as_service(
+        self,
+        *,
+        restart_policy: ServiceRestartPolicy | None = "NEVER",
+        max_restarts: int | None = 0,
+    ) -> "Service":
         """Turn the container into a Service.
 
         Be sure to set any exposed ports before this conversion.
+
+        Parameters
+        ----------
+        restart_policy:
+            Whether to restart the service when its process exits.
+            A service is never restarted after it has been stopped.
+        max_restarts:
+            Maximum number of times to restart the service. Zero means no
+            limit.
         """
-        _args: list[Arg] = []
+        _args = [
+            Arg("restartPolicy", restart_policy, "NEVER"),
+            Arg("maxRestarts", max_restarts, 0),
+        ]
         _ctx = self._select("asService", _args)
         return Service(_ctx)
 
@@ -536,6 +834,31 @@ class Container(Type):
             for v in _ids
         ]
 
+    @typecheck
+    async def exit_code(self) -> int:
+        """The exit code of the last executed command.
+
+        Will execute default command if none is set, or error if there's no
+        default.
+
+        Returns
+        -------
+        int
+            The `Int` scalar type represents non-fractional signed whole
+            numeric values. Int can represent values between -(2^31) and 2^31
+            - 1.
+
+        Raises
+        ------
+        ExecuteTimeoutError
+            If the time to execute the query exceeds the configured timeout.
+        QueryError
+            If the API returns an error.
+        """
+        _args: list[Arg] = []
+        _ctx = self._select("exitCode", _args)
+        return await _ctx.execute(int)
+
     @typecheck
     def experimental_with_all_gp_us(self) -> "Container":
         """EXPERIMENTAL API! Subject to change/removal at any time.
@@ -1203,6 +1526,11 @@ class Container(Type):
         redirect_stderr: str | None = "",
         experimental_privileged_nesting: bool | None = False,
         insecure_root_capabilities: bool | None = False,
+        expect: ReturnType | None = "SUCCESS",
+        timeout: int | None = 0,
+        memory_limit: str | None = "",
+        cpu_quota: int | None = 0,
+        pids_limit: int | None = 0,
     ) -> "Container":
         """Retrieves this container after executing the specified command inside
         it.
@@ -1236,6 +1564,24 @@ class Container(Type):
             --privileged" flag. Containerization does not provide any security
             guarantees when using this option. It should only be used when
             absolutely necessary and only with trusted commands.
+        expect:
+            Exit codes this command is allowed to exit with without error.
+            Use FAILURE or ANY to keep the container's files when the command
+            fails, and read the exit code with exitCode.
+        timeout:
+            Maximum duration of the command in seconds, after which it is
+            killed and fails with a timeout error. Overrides the container's
+            default set by withResourceLimits.
+        memory_limit:
+            Maximum amount of memory the command may use (e.g., "512M", "2G").
+            Overrides the container's default set by withResourceLimits.
+        cpu_quota:
+            CPU time the command may use per 100ms period, in microseconds
+            (e.g., 150000 for 1.5 CPUs). Overrides the container's default set
+            by withResourceLimits.
+        pids_limit:
+            Maximum number of processes the command may run. Overrides the
+            container's default set by withResourceLimits.
         """
         _args = [
             Arg("args", args),
@@ -1247,6 +1593,11 @@ class Container(Type):
                 "experimentalPrivilegedNesting", experimental_privileged_nesting, False
             ),
             Arg("insecureRootCapabilities", insecure_root_capabilities, False),
+            Arg("expect", expect, "SUCCESS"),
+            Arg("timeout", timeout, 0),
+            Arg("memoryLimit", memory_limit, ""),
+            Arg("cpuQuota", cpu_quota, 0),
+            Arg("pidsLimit", pids_limit, 0),
         ]
         _ctx = self._select("withExec", _args)
         return Container(_ctx)
@@ -1367,6 +1718,59 @@ class Container(Type):
         _ctx = self._select("withFocus", _args)
         return Container(_ctx)
 
+    @typecheck
+    def with_healthcheck(
+        self,
+        *,
+        command: Sequence[str] | None = [],
+        http_get: HealthcheckHTTPGet | None = None,
+        tcp_port: int | None = None,
+        interval: int | None = 1,
+        timeout: int | None = 30,
+        retries: int | None = 3,
+        start_period: int | None = 0,
+    ) -> "Container":
+        """Configures how to check that the container is ready when run as a
+        service.
+
+        Exactly one of command, httpGet or tcpPort must be set. The service is
+        probed from inside its container until a probe succeeds, replacing the
+        default check that all exposed ports accept connections.
+
+        Images that configure a HEALTHCHECK use it by default.
+
+        Parameters
+        ----------
+        command:
+            Command to run in the service's container, healthy if it exits 0.
+        http_get:
+            HTTP request to send to the service, healthy if it responds with
+            the expected status.
+        tcp_port:
+            Port the service must accept TCP connections on.
+        interval:
+            Number of seconds to wait between probes.
+        timeout:
+            Number of seconds after which a probe fails.
+        retries:
+            Number of consecutive failed probes after which the service fails
+            to start.
+        start_period:
+            Number of seconds to give the service to start, during which
+            failed probes are not counted.
+        """
+        _args = [
+            Arg("command", command, []),
+            Arg("httpGet", http_get, None),
+            Arg("tcpPort", tcp_port, None),
+            Arg("interval", interval, 1),
+            Arg("timeout", timeout, 30),
+            Arg("retries", retries, 3),
+            Arg("startPeriod", start_period, 0),
+        ]
+        _ctx = self._select("withHealthcheck", _args)
+        return Container(_ctx)
+
     @typecheck
     def with_label(self, name: str, value: str) -> "Container":
         """Retrieves this container plus the given label.
@@ -1603,6 +2007,43 @@ class Container(Type):
         _ctx = self._select("withRegistryAuth", _args)
         return Container(_ctx)
 
+    @typecheck
+    def with_resource_limits(
+        self,
+        *,
+        timeout: int | None = 0,
+        memory_limit: str | None = "",
+        cpu_quota: int | None = 0,
+        pids_limit: int | None = 0,
+    ) -> "Container":
+        """Retrieves this container with default limits for subsequently executed
+        commands.
+
+        A zero value means no limit. Limits passed to withExec take
+        precedence.
+
+        Parameters
+        ----------
+        timeout:
+            Maximum duration of each command in seconds.
+        memory_limit:
+            Maximum amount of memory each command may use (e.g., "512M",
+            "2G").
+        cpu_quota:
+            CPU time each command may use per 100ms period, in microseconds
+            (e.g., 150000 for 1.5 CPUs).
+        pids_limit:
+            Maximum number of processes each command may run.
+        """
+        _args = [
+            Arg("timeout", timeout, 0),
+            Arg("memoryLimit", memory_limit, ""),
+            Arg("cpuQuota", cpu_quota, 0),
+            Arg("pidsLimit", pids_limit, 0),
+        ]
+        _ctx = self._select("withResourceLimits", _args)
+        return Container(_ctx)
+
     @typecheck
     def with_rootfs(self, directory: "Directory") -> "Container":
         """Retrieves the container with the given directory mounted to /.
@@ -1638,7 +2079,13 @@ class Container(Type):
         return Container(_ctx)
 
     @typecheck
-    def with_service_binding(self, alias: str, service: "Service") -> "Container":
+    def with_service_binding(
+        self,
+        alias: str,
+        service: "Service",
+        *,
+        depends_on: Sequence[str] | None = [],
+    ) -> "Container":
         """Establish a runtime dependency on a service.
 
         The service will be started automatically when needed and detached
@@ -1657,10 +2104,14 @@ class Container(Type):
             A name that can be used to reach the service from the container
         service:
             Identifier of the service container
+        depends_on:
+            Aliases of other services bound to the container that must be
+            started and healthy before this service is started.
         """
         _args = [
             Arg("alias", alias),
             Arg("service", service),
+            Arg("dependsOn", depends_on, []),
         ]
         _ctx = self._select("withServiceBinding", _args)
         return Container(_ctx)
@@ -1803,6 +2254,17 @@ class Container(Type):
         _ctx = self._select("withoutFocus", _args)
         return Container(_ctx)
 
+    @typecheck
+    def without_healthcheck(self) -> "Container":
+        """Retrieves this container without a healthcheck, including one
+        configured by its image.
+
+        Its exposed ports will be checked instead when run as a service.
+        """
+        _args: list[Arg] = []
+        _ctx = self._select("withoutHealthcheck", _args)
+        return Container(_ctx)
+
     @typecheck
     def without_label(self, name: str) -> "Container":
         """Retrieves this container minus the given environment label.
@@ -2031,6 +2493,37 @@ class CurrentModule(Type):
 class Directory(Type):
     """A directory."""
 
+    @typecheck
+    def as_archive(
+        self,
+        *,
+        format: ArchiveFormat | None = "TAR",
+        compression: ImageLayerCompression | None = "Uncompressed",
+        reproducible: bool | None = True,
+    ) -> "File":
+        """Packs this directory's contents into an archive file.
+
+        Parameters
+        ----------
+        format:
+            File format of the archive.
+        compression:
+            Compression to apply to the archive.
+            Zip archives compress each entry with DEFLATE when set to Gzip,
+            and don't support Zstd.
+            EStarGZ is not supported.
+        reproducible:
+            Set every timestamp to a fixed time and omit user and group names,
+            so that the same contents always produce the same archive.
+        """
+        _args = [
+            Arg("format", format, "TAR"),
+            Arg("compression", compression, "Uncompressed"),
+            Arg("reproducible", reproducible, True),
+        ]
+        _ctx = self._select("asArchive", _args)
+        return File(_ctx)
+
     @typecheck
     def as_module(
         self,
@@ -2058,8 +2551,9 @@ class Directory(Type):
         return Module(_ctx)
 
     @typecheck
-    def diff(self, other: "Directory") -> "Directory":
-        """Gets the difference between this directory and an another directory.
+    def as_patch(self, other: "Directory") -> "File":
+        """Returns a unified diff of the changes from this directory to another
+        directory, in the format of git diff.
 
         Parameters
         ----------
@@ -2069,8 +2563,57 @@ class Directory(Type):
         _args = [
             Arg("other", other),
         ]
-        _ctx = self._select("diff", _args)
-        return Directory(_ctx)
+        _ctx = self._select("asPatch", _args)
+        return File(_ctx)
+
+    @typecheck
+    def diff(self, other: "Directory") -> "Directory":
+        """Gets the difference between this directory and an another directory.
+
+        Parameters
+        ----------
+        other:
+            Identifier of the directory to compare.
+        """
+        _args = [
+            Arg("other", other),
+        ]
+        _ctx = self._select("diff", _args)
+        return Directory(_ctx)
+
+    @typecheck
+    async def digest(
+        self,
+        *,
+        exclude_metadata: bool | None = False,
+    ) -> str:
+        """Returns a digest of the directory's contents (e.g., "sha256:...").
+
+        Parameters
+        ----------
+        exclude_metadata:
+            Only hash file names and contents, ignoring permissions, ownership
+            and timestamps.
+
+        Returns
+        -------
+        str
+            The `String` scalar type represents textual data, represented as
+            UTF-8 character sequences. The String type is most often used by
+            GraphQL to represent free-form human-readable text.
+
+        Raises
+        ------
+        ExecuteTimeoutError
+            If the time to execute the query exceeds the configured timeout.
+        QueryError
+            If the API returns an error.
+        """
+        _args = [
+            Arg("excludeMetadata", exclude_metadata, False),
+        ]
+        _ctx = self._select("digest", _args)
+        return await _ctx.execute(str)
 
     @typecheck
     def directory(self, path: str) -> "Directory":
@@ -2153,13 +2696,53 @@ class Directory(Type):
         return await _ctx.execute(list[str])
 
     @typecheck
-    async def export(self, path: str) -> bool:
+    async def entries_with_stat(self, *, path: str | None = None) -> list["Stat"]:
+        """Returns the metadata of each file and directory at the given path.
+
+        Parameters
+        ----------
+        path:
+            Location of the directory to look at (e.g., "/src").
+        """
+        _args = [
+            Arg("path", path, None),
+        ]
+        _ctx = self._select("entriesWithStat", _args)
+        _ctx = Stat(_ctx)._select("id", [])
+
+        @dataclass
+        class Response:
+            id: StatID
+
+        _ids = await _ctx.execute(list[Response])
+        return [
+            Stat(
+                Client.from_context(_ctx)._select(
+                    "loadStatFromID",
+                    [Arg("id", v.id)],
+                )
+            )
+            for v in _ids
+        ]
+
+    @typecheck
+    async def export(
+        self,
+        path: str,
+        *,
+        wipe: bool | None = False,
+    ) -> bool:
         """Writes the contents of the directory to a path on the host.
 
         Parameters
         ----------
         path:
             Location of the copied directory (e.g., "logs/").
+        wipe:
+            Remove anything under the path that isn't in this directory,
+            making the path an exact copy of it.
+            The filesystem root, the home directory and the working directory,
+            and any directory containing them, cannot be wiped.
 
         Returns
         -------
@@ -2175,10 +2758,53 @@ class Directory(Type):
         """
         _args = [
             Arg("path", path),
+            Arg("wipe", wipe, False),
         ]
         _ctx = self._select("export", _args)
         return await _ctx.execute(bool)
 
+    @typecheck
+    async def export_dry_run(
+        self,
+        path: str,
+        *,
+        wipe: bool | None = False,
+    ) -> list[str]:
+        """Reports the changes that exporting the directory to a path on the host
+        would make, without writing anything.
+
+        Each change is formatted as "A path" for added, "M path" for modified
+        and "D path" for deleted entries, with directories ending in a slash.
+
+        Parameters
+        ----------
+        path:
+            Location of the copied directory (e.g., "logs/").
+        wipe:
+            Report anything under the path that isn't in this directory as
+            deleted.
+
+        Returns
+        -------
+        list[str]
+            The `String` scalar type represents textual data, represented as
+            UTF-8 character sequences. The String type is most often used by
+            GraphQL to represent free-form human-readable text.
+
+        Raises
+        ------
+        ExecuteTimeoutError
+            If the time to execute the query exceeds the configured timeout.
+        QueryError
+            If the API returns an error.
+        """
+        _args = [
+            Arg("path", path),
+            Arg("wipe", wipe, False),
+        ]
+        _ctx = self._select("exportDryRun", _args)
+        return await _ctx.execute(list[str])
+
     @typecheck
     def file(self, path: str) -> "File":
         """Retrieves a file at the given path.
@@ -2275,6 +2901,24 @@ class Directory(Type):
         _ctx = self._select("pipeline", _args)
         return Directory(_ctx)
 
+    @typecheck
+    def stat(self, path: str) -> "Stat":
+        """Retrieves the metadata of the file, directory or symlink at the given
+        path.
+
+        Symlinks are not followed.
+
+        Parameters
+        ----------
+        path:
+            Location of the entry to look at (e.g., "bin/run.sh").
+        """
+        _args = [
+            Arg("path", path),
+        ]
+        _ctx = self._select("stat", _args)
+        return Stat(_ctx)
+
     @typecheck
     async def sync(self) -> "Directory":
         """Force evaluation in the engine.
@@ -2297,6 +2941,27 @@ class Directory(Type):
     def __await__(self):
         return self.sync().__await__()
 
+    @typecheck
+    def with_archive(self, path: str, file: "File") -> "Directory":
+        """Retrieves this directory plus the contents of the given archive
+        extracted at the given path.
+
+        The archive's format and compression are detected automatically.
+
+        Parameters
+        ----------
+        path:
+            Location to extract the archive to (e.g., "/vendor").
+        file:
+            Identifier of the archive to extract.
+        """
+        _args = [
+            Arg("path", path),
+            Arg("file", file),
+        ]
+        _ctx = self._select("withArchive", _args)
+        return Directory(_ctx)
+
     @typecheck
     def with_directory(
         self,
@@ -2386,6 +3051,27 @@ class Directory(Type):
         _ctx = self._select("withFiles", _args)
         return Directory(_ctx)
 
+    @typecheck
+    def with_move(self, src: str, dest: str) -> "Directory":
+        """Retrieves this directory with the file or directory at src moved to
+        dest.
+
+        If dest is an existing directory, src is moved into it.
+
+        Parameters
+        ----------
+        src:
+            Location of the file or directory to move (e.g., "/build/out").
+        dest:
+            Location to move it to (e.g., "/dist").
+        """
+        _args = [
+            Arg("src", src),
+            Arg("dest", dest),
+        ]
+        _ctx = self._select("withMove", _args)
+        return Directory(_ctx)
+
     @typecheck
     def with_new_directory(
         self,
@@ -2437,6 +3123,108 @@ class Directory(Type):
         _ctx = self._select("withNewFile", _args)
         return Directory(_ctx)
 
+    @typecheck
+    def with_owner(self, path: str, owner: str) -> "Directory":
+        """Retrieves this directory with the ownership of the given path changed.
+
+        Directories are changed recursively.
+
+        Parameters
+        ----------
+        path:
+            Location of the file or directory to change (e.g., "/src").
+        owner:
+            A user:group to set for the path.
+            The user and group can either be an ID (1000:1000) or a name
+            (foo:bar).
+            If the group is omitted, it defaults to the same as the user.
+            Names are resolved using /etc/passwd and /etc/group in this
+            directory.
+        """
+        _args = [
+            Arg("path", path),
+            Arg("owner", owner),
+        ]
+        _ctx = self._select("withOwner", _args)
+        return Directory(_ctx)
+
+    @typecheck
+    def with_patch(
+        self,
+        patch: "File",
+        *,
+        strip: int | None = 1,
+    ) -> "Directory":
+        """Retrieves this directory with the given unified diff applied, as
+        written by diff -u, git diff or git format-patch.
+
+        Fails without applying anything if any part of the patch does not
+        apply.
+
+        Parameters
+        ----------
+        patch:
+            Identifier of the file containing the patch.
+        strip:
+            Number of leading path components to strip from file names in the
+            patch, as with patch -p.
+        """
+        _args = [
+            Arg("patch", patch),
+            Arg("strip", strip, 1),
+        ]
+        _ctx = self._select("withPatch", _args)
+        return Directory(_ctx)
+
+    @typecheck
+    def with_permissions(
+        self,
+        path: str,
+        mode: int,
+        *,
+        recursive: bool | None = False,
+    ) -> "Directory":
+        """Retrieves this directory with the permissions of the given path
+        changed.
+
+        Parameters
+        ----------
+        path:
+            Location of the file or directory to change (e.g., "/bin/run.sh").
+        mode:
+            Permissions to set (e.g., 0755).
+        recursive:
+            If the path is a directory, also change the permissions of its
+            contents.
+        """
+        _args = [
+            Arg("path", path),
+            Arg("mode", mode),
+            Arg("recursive", recursive, False),
+        ]
+        _ctx = self._select("withPermissions", _args)
+        return Directory(_ctx)
+
+    @typecheck
+    def with_symlink(self, target: str, link_name: str) -> "Directory":
+        """Retrieves this directory plus a symlink at the given path.
+
+        Any existing file or directory at linkName is replaced.
+
+        Parameters
+        ----------
+        target:
+            Location the symlink points to (e.g., "../lib/foo.so").
+        link_name:
+            Location of the created symlink (e.g., "/lib/bar.so").
+        """
+        _args = [
+            Arg("target", target),
+            Arg("linkName", link_name),
+        ]
+        _ctx = self._select("withSymlink", _args)
+        return Directory(_ctx)
+
     @typecheck
     def with_timestamps(self, timestamp: int) -> "Directory":
         """Retrieves this directory with all file/dir timestamps set to the given
@@ -2455,41 +3243,402 @@ class Directory(Type):
         return Directory(_ctx)
 
     @typecheck
-    def without_directory(self, path: str) -> "Directory":
-        """Retrieves this directory with the directory at the given path removed.
+    def without_directory(self, path: str) -> "Directory":
+        """Retrieves this directory with the directory at the given path removed.
+
+        Parameters
+        ----------
+        path:
+            Location of the directory to remove (e.g., ".github/").
+        """
+        _args = [
+            Arg("path", path),
+        ]
+        _ctx = self._select("withoutDirectory", _args)
+        return Directory(_ctx)
+
+    @typecheck
+    def without_file(self, path: str) -> "Directory":
+        """Retrieves this directory with the file at the given path removed.
+
+        Parameters
+        ----------
+        path:
+            Location of the file to remove (e.g., "/file.txt").
+        """
+        _args = [
+            Arg("path", path),
+        ]
+        _ctx = self._select("withoutFile", _args)
+        return Directory(_ctx)
+
+    def with_(self, cb: Callable[["Directory"], "Directory"]) -> "Directory":
+        """Call the provided callable with current Directory.
+
+        This is useful for reusability and readability by not breaking the calling chain.
+        """
+        return cb(self)
+
+
+class Engine(Type):
+    """The Dagger engine the client is connected to."""
+
+    @typecheck
+    async def id(self) -> EngineID:
+        """A unique identifier for this Engine.
+
+        Note
+        ----
+        This is lazily evaluated, no operation is actually run.
+
+        Returns
+        -------
+        EngineID
+            The `EngineID` scalar type represents an identifier for an object
+            of type Engine.
+
+        Raises
+        ------
+        ExecuteTimeoutError
+            If the time to execute the query exceeds the configured timeout.
+        QueryError
+            If the API returns an error.
+        """
+        _args: list[Arg] = []
+        _ctx = self._select("id", _args)
+        return await _ctx.execute(EngineID)
+
+    @typecheck
+    def local_cache(self) -> "EngineCache":
+        """The local cache of the engine."""
+        _args: list[Arg] = []
+        _ctx = self._select("localCache", _args)
+        return EngineCache(_ctx)
+
+
+class EngineCache(Type):
+    """The local cache of the Dagger engine."""
+
+    @typecheck
+    async def entries(
+        self,
+        *,
+        filters: Sequence[str] | None = [],
+    ) -> list["EngineCacheEntry"]:
+        """The entries in the cache, largest first.
+
+        Parameters
+        ----------
+        filters:
+            Only list entries matching all of the filters (e.g.,
+            "type==exec.cachemount").
+        """
+        _args = [
+            Arg("filters", filters, []),
+        ]
+        _ctx = self._select("entries", _args)
+        _ctx = EngineCacheEntry(_ctx)._select("id", [])
+
+        @dataclass
+        class Response:
+            id: EngineCacheEntryID
+
+        _ids = await _ctx.execute(list[Response])
+        return [
+            EngineCacheEntry(
+                Client.from_context(_ctx)._select(
+                    "loadEngineCacheEntryFromID",
+                    [Arg("id", v.id)],
+                )
+            )
+            for v in _ids
+        ]
+
+    @typecheck
+    async def id(self) -> EngineCacheID:
+        """A unique identifier for this EngineCache.
+
+        Note
+        ----
+        This is lazily evaluated, no operation is actually run.
+
+        Returns
+        -------
+        EngineCacheID
+            The `EngineCacheID` scalar type represents an identifier for an
+            object of type EngineCache.
+
+        Raises
+        ------
+        ExecuteTimeoutError
+            If the time to execute the query exceeds the configured timeout.
+        QueryError
+            If the API returns an error.
+        """
+        _args: list[Arg] = []
+        _ctx = self._select("id", _args)
+        return await _ctx.execute(EngineCacheID)
+
+    @typecheck
+    async def keep_bytes(self) -> int:
+        """The most space the engine's garbage collection policy lets the cache
+        use, in bytes, or 0 if it's unbounded.
+
+        Returns
+        -------
+        int
+            The `Int` scalar type represents non-fractional signed whole
+            numeric values. Int can represent values between -(2^31) and 2^31
+            - 1.
+
+        Raises
+        ------
+        ExecuteTimeoutError
+            If the time to execute the query exceeds the configured timeout.
+        QueryError
+            If the API returns an error.
+        """
+        _args: list[Arg] = []
+        _ctx = self._select("keepBytes", _args)
+        return await _ctx.execute(int)
+
+    @typecheck
+    async def prune(
+        self,
+        *,
+        all: bool | None = False,
+        filters: Sequence[str] | None = [],
+        keep_bytes: int | None = 0,
+        older_than: int | None = 0,
+        use_default_policy: bool | None = False,
+    ) -> list["EngineCacheEntry"]:
+        """Removes entries that aren't in use from the cache, returning the
+        entries that were removed.
+
+        Unless all is set, the entries to remove must be narrowed down with at
+        least one of the other arguments. This can't be called from within a
+        module.
+
+        Parameters
+        ----------
+        all:
+            Remove every entry that isn't in use.
+        filters:
+            Only remove entries matching all of the filters (e.g.,
+            "type==exec.cachemount").
+        keep_bytes:
+            Keep entries until the cache uses at most this many bytes.
+        older_than:
+            Only remove entries that were not used in this many seconds.
+        use_default_policy:
+            Apply the engine's garbage collection policy instead of the other
+            arguments.
+        """
+        _args = [
+            Arg("all", all, False),
+            Arg("filters", filters, []),
+            Arg("keepBytes", keep_bytes, 0),
+            Arg("olderThan", older_than, 0),
+            Arg("useDefaultPolicy", use_default_policy, False),
+        ]
+        _ctx = self._select("prune", _args)
+        _ctx = EngineCacheEntry(_ctx)._select("id", [])
+
+        @dataclass
+        class Response:
+            id: EngineCacheEntryID
+
+        _ids = await _ctx.execute(list[Response])
+        return [
+            EngineCacheEntry(
+                Client.from_context(_ctx)._select(
+                    "loadEngineCacheEntryFromID",
+                    [Arg("id", v.id)],
+                )
+            )
+            for v in _ids
+        ]
+
+
+class EngineCacheEntry(Type):
+    """An entry in the local cache of the Dagger engine."""
+
+    @typecheck
+    async def actively_used(self) -> bool:
+        """Whether the cache entry is in use, in which case it can't be pruned.
+
+        Returns
+        -------
+        bool
+            The `Boolean` scalar type represents `true` or `false`.
+
+        Raises
+        ------
+        ExecuteTimeoutError
+            If the time to execute the query exceeds the configured timeout.
+        QueryError
+            If the API returns an error.
+        """
+        _args: list[Arg] = []
+        _ctx = self._select("activelyUsed", _args)
+        return await _ctx.execute(bool)
+
+    @typecheck
+    async def created_time(self) -> int:
+        """The time the cache entry was created, in seconds following Unix epoch.
+
+        Returns
+        -------
+        int
+            The `Int` scalar type represents non-fractional signed whole
+            numeric values. Int can represent values between -(2^31) and 2^31
+            - 1.
+
+        Raises
+        ------
+        ExecuteTimeoutError
+            If the time to execute the query exceeds the configured timeout.
+        QueryError
+            If the API returns an error.
+        """
+        _args: list[Arg] = []
+        _ctx = self._select("createdTime", _args)
+        return await _ctx.execute(int)
+
+    @typecheck
+    async def description(self) -> str:
+        """The description of the cache entry.
+
+        Returns
+        -------
+        str
+            The `String` scalar type represents textual data, represented as
+            UTF-8 character sequences. The String type is most often used by
+            GraphQL to represent free-form human-readable text.
+
+        Raises
+        ------
+        ExecuteTimeoutError
+            If the time to execute the query exceeds the configured timeout.
+        QueryError
+            If the API returns an error.
+        """
+        _args: list[Arg] = []
+        _ctx = self._select("description", _args)
+        return await _ctx.execute(str)
+
+    @typecheck
+    async def disk_space_bytes(self) -> int:
+        """The disk space used by the cache entry, in bytes.
+
+        Returns
+        -------
+        int
+            The `Int` scalar type represents non-fractional signed whole
+            numeric values. Int can represent values between -(2^31) and 2^31
+            - 1.
+
+        Raises
+        ------
+        ExecuteTimeoutError
+            If the time to execute the query exceeds the configured timeout.
+        QueryError
+            If the API returns an error.
+        """
+        _args: list[Arg] = []
+        _ctx = self._select("diskSpaceBytes", _args)
+        return await _ctx.execute(int)
+
+    @typecheck
+    async def id(self) -> EngineCacheEntryID:
+        """A unique identifier for this EngineCacheEntry.
+
+        Note
+        ----
+        This is lazily evaluated, no operation is actually run.
+
+        Returns
+        -------
+        EngineCacheEntryID
+            The `EngineCacheEntryID` scalar type represents an identifier for
+            an object of type EngineCacheEntry.
+
+        Raises
+        ------
+        ExecuteTimeoutError
+            If the time to execute the query exceeds the configured timeout.
+        QueryError
+            If the API returns an error.
+        """
+        _args: list[Arg] = []
+        _ctx = self._select("id", _args)
+        return await _ctx.execute(EngineCacheEntryID)
+
+    @typecheck
+    async def most_recent_use_time(self) -> int:
+        """The time the cache entry was last used, in seconds following Unix
+        epoch.
 
-        Parameters
-        ----------
-        path:
-            Location of the directory to remove (e.g., ".github/").
+        Returns
+        -------
+        int
+            The `Int` scalar type represents non-fractional signed whole
+            numeric values. Int can represent values between -(2^31) and 2^31
+            - 1.
+
+        Raises
+        ------
+        ExecuteTimeoutError
+            If the time to execute the query exceeds the configured timeout.
+        QueryError
+            If the API returns an error.
         """
-        _args = [
-            Arg("path", path),
-        ]
-        _ctx = self._select("withoutDirectory", _args)
-        return Directory(_ctx)
+        _args: list[Arg] = []
+        _ctx = self._select("mostRecentUseTime", _args)
+        return await _ctx.execute(int)
 
     @typecheck
-    def without_file(self, path: str) -> "Directory":
-        """Retrieves this directory with the file at the given path removed.
+    async def record_type(self) -> str:
+        """The type of the cache entry (e.g., "regular", "exec.cachemount",
+        "source.local").
 
-        Parameters
-        ----------
-        path:
-            Location of the file to remove (e.g., "/file.txt").
+        Returns
+        -------
+        str
+            The `String` scalar type represents textual data, represented as
+            UTF-8 character sequences. The String type is most often used by
+            GraphQL to represent free-form human-readable text.
+
+        Raises
+        ------
+        ExecuteTimeoutError
+            If the time to execute the query exceeds the configured timeout.
+        QueryError
+            If the API returns an error.
         """
-        _args = [
-            Arg("path", path),
-        ]
-        _ctx = self._select("withoutFile", _args)
-        return Directory(_ctx)
+        _args: list[Arg] = []
+        _ctx = self._select("recordType", _args)
+        return await _ctx.execute(str)
 
-    def with_(self, cb: Callable[["Directory"], "Directory"]) -> "Directory":
-        """Call the provided callable with current Directory.
+    @typecheck
+    async def shared(self) -> bool:
+        """Whether the cache entry shares its data with other entries.
 
-        This is useful for reusability and readability by not breaking the calling chain.
+        Returns
+        -------
+        bool
+            The `Boolean` scalar type represents `true` or `false`.
+
+        Raises
+        ------
+        ExecuteTimeoutError
+            If the time to execute the query exceeds the configured timeout.
+        QueryError
+            If the API returns an error.
         """
-        return cb(self)
+        _args: list[Arg] = []
+        _ctx = self._select("shared", _args)
+        return await _ctx.execute(bool)
 
 
 class EnvVariable(Type):
@@ -2673,6 +3822,29 @@ class File(Type):
         _ctx = self._select("contents", _args)
         return await _ctx.execute(str)
 
+    @typecheck
+    async def digest(self) -> str:
+        """Returns the SHA-256 digest of the file's contents (e.g.,
+        "sha256:...").
+
+        Returns
+        -------
+        str
+            The `String` scalar type represents textual data, represented as
+            UTF-8 character sequences. The String type is most often used by
+            GraphQL to represent free-form human-readable text.
+
+        Raises
+        ------
+        ExecuteTimeoutError
+            If the time to execute the query exceeds the configured timeout.
+        QueryError
+            If the API returns an error.
+        """
+        _args: list[Arg] = []
+        _ctx = self._select("digest", _args)
+        return await _ctx.execute(str)
+
     @typecheck
     async def export(
         self,
@@ -2710,6 +3882,27 @@ class File(Type):
         _ctx = self._select("export", _args)
         return await _ctx.execute(bool)
 
+    @typecheck
+    def extract(
+        self,
+        *,
+        format: ArchiveFormat | None = None,
+    ) -> Directory:
+        """Extracts this file as an archive.
+
+        Parameters
+        ----------
+        format:
+            File format of the archive.
+            If not set, it's detected from the file. Compressed tarballs are
+            detected automatically.
+        """
+        _args = [
+            Arg("format", format, None),
+        ]
+        _ctx = self._select("extract", _args)
+        return Directory(_ctx)
+
     @typecheck
     async def id(self) -> FileID:
         """A unique identifier for this File.
@@ -2779,6 +3972,13 @@ class File(Type):
         _ctx = self._select("size", _args)
         return await _ctx.execute(int)
 
+    @typecheck
+    def stat(self) -> "Stat":
+        """Retrieves the metadata of the file."""
+        _args: list[Arg] = []
+        _ctx = self._select("stat", _args)
+        return Stat(_ctx)
+
     @typecheck
     async def sync(self) -> "File":
         """Force evaluation in the engine.
@@ -2799,6 +3999,21 @@ class File(Type):
     def __await__(self):
         return self.sync().__await__()
 
+    @typecheck
+    def with_name(self, name: str) -> "File":
+        """Retrieves this file with the given name.
+
+        Parameters
+        ----------
+        name:
+            Name to give the file (e.g., "main.go").
+        """
+        _args = [
+            Arg("name", name),
+        ]
+        _ctx = self._select("withName", _args)
+        return File(_ctx)
+
     @typecheck
     def with_timestamps(self, timestamp: int) -> "File":
         """Retrieves this file with its created/modified timestamps set to the
@@ -2851,6 +4066,49 @@ class Function(Type):
             for v in _ids
         ]
 
+    @typecheck
+    async def cache_policy(self) -> FunctionCachePolicy:
+        """How the results of calls to the function are cached.
+
+        Returns
+        -------
+        FunctionCachePolicy
+            How the results of calls to a function are cached.
+
+        Raises
+        ------
+        ExecuteTimeoutError
+            If the time to execute the query exceeds the configured timeout.
+        QueryError
+            If the API returns an error.
+        """
+        _args: list[Arg] = []
+        _ctx = self._select("cachePolicy", _args)
+        return await _ctx.execute(FunctionCachePolicy)
+
+    @typecheck
+    async def cache_time_to_live(self) -> int:
+        """How long the results of calls to the function are cached for, in
+        seconds, if its cache policy is TTL_CACHE.
+
+        Returns
+        -------
+        int
+            The `Int` scalar type represents non-fractional signed whole
+            numeric values. Int can represent values between -(2^31) and 2^31
+            - 1.
+
+        Raises
+        ------
+        ExecuteTimeoutError
+            If the time to execute the query exceeds the configured timeout.
+        QueryError
+            If the API returns an error.
+        """
+        _args: list[Arg] = []
+        _ctx = self._select("cacheTimeToLive", _args)
+        return await _ctx.execute(int)
+
     @typecheck
     async def description(self) -> str:
         """A doc string for the function, if any.
@@ -2959,6 +4217,30 @@ class Function(Type):
         _ctx = self._select("withArg", _args)
         return Function(_ctx)
 
+    @typecheck
+    def with_cache_policy(
+        self,
+        policy: FunctionCachePolicy,
+        *,
+        time_to_live: str | None = "",
+    ) -> "Function":
+        """Returns the function with the given cache policy.
+
+        Parameters
+        ----------
+        policy:
+            How the results of calls to the function are cached.
+        time_to_live:
+            How long the results are reused for, as a duration (e.g., "10m").
+            Required for TTL_CACHE.
+        """
+        _args = [
+            Arg("policy", policy),
+            Arg("timeToLive", time_to_live, ""),
+        ]
+        _ctx = self._select("withCachePolicy", _args)
+        return Function(_ctx)
+
     @typecheck
     def with_description(self, description: str) -> "Function":
         """Returns the function with the given doc string.
@@ -4980,6 +6262,27 @@ class ModuleSource(Type):
         _ctx = self._select("withName", _args)
         return ModuleSource(_ctx)
 
+    @typecheck
+    def with_resolved_dependency_versions(
+        self, versions: Sequence[str]
+    ) -> "ModuleSource":
+        """Load the module source as a dependency of another module, resolving
+        git modules required with version constraints anywhere in its
+        dependency tree to the given versions.
+
+        Parameters
+        ----------
+        versions:
+            The resolved versions, each formatted as a git module's symbolic
+            ref and tag separated by @, e.g.
+            https://github.com/org/repo/mod@mod/v1.2.3.
+        """
+        _args = [
+            Arg("versions", versions),
+        ]
+        _ctx = self._select("withResolvedDependencyVersions", _args)
+        return ModuleSource(_ctx)
+
     @typecheck
     def with_sdk(self, sdk: str) -> "ModuleSource":
         """Update the module source with a new SDK.
@@ -5010,6 +6313,38 @@ class ModuleSource(Type):
         _ctx = self._select("withSourceSubpath", _args)
         return ModuleSource(_ctx)
 
+    @typecheck
+    def with_update_dependencies(self, dependencies: Sequence[str]) -> "ModuleSource":
+        """Re-resolve the named configured dependencies from their source ref's
+        version, ignoring their pins.
+
+        Parameters
+        ----------
+        dependencies:
+            The names of the dependencies to update.
+        """
+        _args = [
+            Arg("dependencies", dependencies),
+        ]
+        _ctx = self._select("withUpdateDependencies", _args)
+        return ModuleSource(_ctx)
+
+    @typecheck
+    def without_dependencies(self, dependencies: Sequence[str]) -> "ModuleSource":
+        """Remove the named dependencies from the module source's dependency
+        list.
+
+        Parameters
+        ----------
+        dependencies:
+            The names of the dependencies to remove.
+        """
+        _args = [
+            Arg("dependencies", dependencies),
+        ]
+        _ctx = self._select("withoutDependencies", _args)
+        return ModuleSource(_ctx)
+
     def with_(self, cb: Callable[["ModuleSource"], "ModuleSource"]) -> "ModuleSource":
         """Call the provided callable with current ModuleSource.
 
@@ -5327,21 +6662,71 @@ class Client(Root):
         return Container(_ctx)
 
     @typecheck
-    def cache_volume(self, key: str) -> CacheVolume:
+    def cache_volume(
+        self,
+        key: str,
+        *,
+        namespace: str | None = None,
+        shared: bool | None = False,
+    ) -> CacheVolume:
         """Constructs a cache volume for a given cache key.
 
+        By default, cache volumes constructed by a module are scoped to that
+        module, so that modules using the same key don't share a volume. Cache
+        volumes constructed by the main client are shared.
+
+        Namespaces keep modules from sharing cache volumes by accident, but
+        are not access control: any module can construct a cache volume in
+        another module's namespace, and use the cache volumes other modules
+        give it.
+
         Parameters
         ----------
         key:
             A string identifier to target this cache volume (e.g., "modules-
             cache").
+        namespace:
+            A namespace to scope the cache volume to instead of the calling
+            module.
+            Cache volumes with the same key and namespace are shared.
+        shared:
+            Share the cache volume with every module and client that uses the
+            same key.
         """
         _args = [
             Arg("key", key),
+            Arg("namespace", namespace, None),
+            Arg("shared", shared, False),
         ]
         _ctx = self._select("cacheVolume", _args)
         return CacheVolume(_ctx)
 
+    @typecheck
+    async def cache_volumes(self) -> list[CacheVolume]:
+        """Lists the cache volumes used since the engine started, sorted by
+        namespace and key.
+
+        Modules only see their own cache volumes and shared ones.
+        """
+        _args: list[Arg] = []
+        _ctx = self._select("cacheVolumes", _args)
+        _ctx = CacheVolume(_ctx)._select("id", [])
+
+        @dataclass
+        class Response:
+            id: CacheVolumeID
+
+        _ids = await _ctx.execute(list[Response])
+        return [
+            CacheVolume(
+                Client.from_context(_ctx)._select(
+                    "loadCacheVolumeFromID",
+                    [Arg("id", v.id)],
+                )
+            )
+            for v in _ids
+        ]
+
     @typecheck
     async def check_version_compatibility(self, version: str) -> bool:
         """Checks if the current Dagger Engine is compatible with an SDK's
@@ -5477,6 +6862,13 @@ class Client(Root):
         _ctx = self._select("directory", _args)
         return Directory(_ctx)
 
+    @typecheck
+    def engine(self) -> Engine:
+        """Queries the Dagger engine the client is connected to."""
+        _args: list[Arg] = []
+        _ctx = self._select("engine", _args)
+        return Engine(_ctx)
+
     @typecheck
     def file(self, id: FileID) -> File:
         """.. deprecated::
@@ -5574,6 +6966,11 @@ class Client(Root):
         url: str,
         *,
         experimental_service_host: "Service | None" = None,
+        checksum: str | None = "",
+        headers: Sequence[HTTPHeader] | None = [],
+        auth_header: "Secret | None" = None,
+        name: str | None = "",
+        permissions: int | None = None,
     ) -> File:
         """Returns a file containing an http remote url content.
 
@@ -5583,10 +6980,27 @@ class Client(Root):
             HTTP url to get the content from (e.g., "https://docs.dagger.io").
         experimental_service_host:
             A service which must be started before the URL is fetched.
+        checksum:
+            Expected digest of the content (e.g., "sha256:...").
+            The download fails if the content does not match.
+        headers:
+            Additional headers to send with the request.
+        auth_header:
+            Secret used as the value of the Authorization header (e.g.,
+            "Bearer ...").
+        name:
+            Name of the downloaded file (defaults to a digest of the URL).
+        permissions:
+            Permission given to the downloaded file (e.g., 0600).
         """
         _args = [
             Arg("url", url),
             Arg("experimentalServiceHost", experimental_service_host, None),
+            Arg("checksum", checksum, ""),
+            Arg("headers", headers, []),
+            Arg("authHeader", auth_header, None),
+            Arg("name", name, ""),
+            Arg("permissions", permissions, None),
         ]
         _ctx = self._select("http", _args)
         return File(_ctx)
@@ -5601,31 +7015,60 @@ class Client(Root):
         return CacheVolume(_ctx)
 
     @typecheck
-    def load_container_from_id(self, id: ContainerID) -> Container:
-        """Load a Container from its ID."""
+    def load_container_from_id(self, id: ContainerID) -> Container:
+        """Load a Container from its ID."""
+        _args = [
+            Arg("id", id),
+        ]
+        _ctx = self._select("loadContainerFromID", _args)
+        return Container(_ctx)
+
+    @typecheck
+    def load_current_module_from_id(self, id: CurrentModuleID) -> CurrentModule:
+        """Load a CurrentModule from its ID."""
+        _args = [
+            Arg("id", id),
+        ]
+        _ctx = self._select("loadCurrentModuleFromID", _args)
+        return CurrentModule(_ctx)
+
+    @typecheck
+    def load_directory_from_id(self, id: DirectoryID) -> Directory:
+        """Load a Directory from its ID."""
+        _args = [
+            Arg("id", id),
+        ]
+        _ctx = self._select("loadDirectoryFromID", _args)
+        return Directory(_ctx)
+
+    @typecheck
+    def load_engine_cache_entry_from_id(
+        self, id: EngineCacheEntryID
+    ) -> EngineCacheEntry:
+        """Load a EngineCacheEntry from its ID."""
         _args = [
             Arg("id", id),
         ]
-        _ctx = self._select("loadContainerFromID", _args)
-        return Container(_ctx)
+        _ctx = self._select("loadEngineCacheEntryFromID", _args)
+        return EngineCacheEntry(_ctx)
 
     @typecheck
-    def load_current_module_from_id(self, id: CurrentModuleID) -> CurrentModule:
-        """Load a CurrentModule from its ID."""
+    def load_engine_cache_from_id(self, id: EngineCacheID) -> EngineCache:
+        """Load a EngineCache from its ID."""
         _args = [
             Arg("id", id),
         ]
-        _ctx = self._select("loadCurrentModuleFromID", _args)
-        return CurrentModule(_ctx)
+        _ctx = self._select("loadEngineCacheFromID", _args)
+        return EngineCache(_ctx)
 
     @typecheck
-    def load_directory_from_id(self, id: DirectoryID) -> Directory:
-        """Load a Directory from its ID."""
+    def load_engine_from_id(self, id: EngineID) -> Engine:
+        """Load a Engine from its ID."""
         _args = [
             Arg("id", id),
         ]
-        _ctx = self._select("loadDirectoryFromID", _args)
-        return Directory(_ctx)
+        _ctx = self._select("loadEngineFromID", _args)
+        return Engine(_ctx)
 
     @typecheck
     def load_env_variable_from_id(self, id: EnvVariableID) -> EnvVariable:
@@ -5860,6 +7303,15 @@ class Client(Root):
         _ctx = self._select("loadSocketFromID", _args)
         return Socket(_ctx)
 
+    @typecheck
+    def load_stat_from_id(self, id: StatID) -> "Stat":
+        """Load a Stat from its ID."""
+        _args = [
+            Arg("id", id),
+        ]
+        _ctx = self._select("loadStatFromID", _args)
+        return Stat(_ctx)
+
     @typecheck
     def load_terminal_from_id(self, id: TerminalID) -> "Terminal":
         """Load a Terminal from its ID."""
@@ -5916,6 +7368,7 @@ class Client(Root):
         self,
         ref_string: str,
         *,
+        ref_pin: str | None = "",
         stable: bool | None = False,
     ) -> ModuleSource:
         """Create a new module source instance from a source ref string.
@@ -5924,12 +7377,16 @@ class Client(Root):
         ----------
         ref_string:
             The string ref representation of the module source
+        ref_pin:
+            The pinned version of the module source, e.g. the resolved commit
+            of a git source. If set, the ref's version is not resolved again.
         stable:
             If true, enforce that the source is a stable version for source
             kinds that support versioning.
         """
         _args = [
             Arg("refString", ref_string),
+            Arg("refPin", ref_pin, ""),
             Arg("stable", stable, False),
         ]
         _ctx = self._select("moduleSource", _args)
@@ -5968,11 +7425,30 @@ class Client(Root):
         name: str,
         *,
         accessor: str | None = None,
+        uri: str | None = None,
     ) -> "Secret":
-        """Reference a secret by name."""
+        """Reference a secret by name.
+
+        If a URI is given, the secret's plaintext is fetched by the engine
+        from the matching secret provider each time the secret is used. Secret
+        providers run in the engine, so they must be enabled in its
+        configuration first.
+
+        Parameters
+        ----------
+        name:
+        accessor:
+        uri:
+            The URI of the secret in a secret provider, e.g.
+            "vault://secret/data/ci#token", "env://TOKEN",
+            "file:///run/secrets/token", "cmd://gcloud auth print-access-
+            token", "op://vault/item/field" or
+            "sops:///secrets.yaml#db.password".
+        """
         _args = [
             Arg("name", name),
             Arg("accessor", accessor, None),
+            Arg("uri", uri, None),
         ]
         _ctx = self._select("secret", _args)
         return Secret(_ctx)
@@ -6129,6 +7605,30 @@ class Service(Type):
         _ctx = self._select("endpoint", _args)
         return await _ctx.execute(str)
 
+    @typecheck
+    async def exit_code(self) -> int:
+        """The exit code of the last run of the service.
+
+        Returns an error if the service has not exited yet.
+
+        Returns
+        -------
+        int
+            The `Int` scalar type represents non-fractional signed whole
+            numeric values. Int can represent values between -(2^31) and 2^31
+            - 1.
+
+        Raises
+        ------
+        ExecuteTimeoutError
+            If the time to execute the query exceeds the configured timeout.
+        QueryError
+            If the API returns an error.
+        """
+        _args: list[Arg] = []
+        _ctx = self._select("exitCode", _args)
+        return await _ctx.execute(int)
+
     @typecheck
     async def hostname(self) -> str:
         """Retrieves a hostname which can be used by clients to reach this
@@ -6177,6 +7677,50 @@ class Service(Type):
         _ctx = self._select("id", _args)
         return await _ctx.execute(ServiceID)
 
+    @typecheck
+    async def logs(
+        self,
+        *,
+        since: str | None = "",
+        tail: int | None = None,
+        follow: bool | None = False,
+    ) -> str:
+        """The combined stdout and stderr of the current or last run of the
+        service.
+
+        Parameters
+        ----------
+        since:
+            Only return output written after this time.
+            Either an RFC 3339 timestamp (e.g., "2024-01-02T15:04:05Z") or a
+            duration relative to now (e.g., "10m").
+        tail:
+            Only return this many of the most recent lines.
+        follow:
+            Wait for the service to exit before returning its output.
+
+        Returns
+        -------
+        str
+            The `String` scalar type represents textual data, represented as
+            UTF-8 character sequences. The String type is most often used by
+            GraphQL to represent free-form human-readable text.
+
+        Raises
+        ------
+        ExecuteTimeoutError
+            If the time to execute the query exceeds the configured timeout.
+        QueryError
+            If the API returns an error.
+        """
+        _args = [
+            Arg("since", since, ""),
+            Arg("tail", tail, None),
+            Arg("follow", follow, False),
+        ]
+        _ctx = self._select("logs", _args)
+        return await _ctx.execute(str)
+
     @typecheck
     async def ports(self) -> list[Port]:
         """Retrieves the list of ports provided by the service."""
@@ -6218,6 +7762,26 @@ class Service(Type):
         _ctx = Client.from_context(_ctx)._select("loadServiceFromID", [Arg("id", _id)])
         return Service(_ctx)
 
+    @typecheck
+    async def status(self) -> ServiceStatus:
+        """The status of the current or last run of the service.
+
+        Returns
+        -------
+        ServiceStatus
+            The status of a service.
+
+        Raises
+        ------
+        ExecuteTimeoutError
+            If the time to execute the query exceeds the configured timeout.
+        QueryError
+            If the API returns an error.
+        """
+        _args: list[Arg] = []
+        _ctx = self._select("status", _args)
+        return await _ctx.execute(ServiceStatus)
+
     @typecheck
     async def stop(self, *, kill: bool | None = False) -> "Service":
         """Stop the service.
@@ -6311,6 +7875,212 @@ class Socket(Type):
         return await _ctx.execute(SocketID)
 
 
+class Stat(Type):
+    """The metadata of a file, directory or symlink."""
+
+    @typecheck
+    async def file_type(self) -> FileType:
+        """The type of the entry.
+
+        Returns
+        -------
+        FileType
+            The type of a filesystem entry.
+
+        Raises
+        ------
+        ExecuteTimeoutError
+            If the time to execute the query exceeds the configured timeout.
+        QueryError
+            If the API returns an error.
+        """
+        _args: list[Arg] = []
+        _ctx = self._select("fileType", _args)
+        return await _ctx.execute(FileType)
+
+    @typecheck
+    async def gid(self) -> int:
+        """The ID of the group that owns the entry.
+
+        Returns
+        -------
+        int
+            The `Int` scalar type represents non-fractional signed whole
+            numeric values. Int can represent values between -(2^31) and 2^31
+            - 1.
+
+        Raises
+        ------
+        ExecuteTimeoutError
+            If the time to execute the query exceeds the configured timeout.
+        QueryError
+            If the API returns an error.
+        """
+        _args: list[Arg] = []
+        _ctx = self._select("gid", _args)
+        return await _ctx.execute(int)
+
+    @typecheck
+    async def id(self) -> StatID:
+        """A unique identifier for this Stat.
+
+        Note
+        ----
+        This is lazily evaluated, no operation is actually run.
+
+        Returns
+        -------
+        StatID
+            The `StatID` scalar type represents an identifier for an object of
+            type Stat.
+
+        Raises
+        ------
+        ExecuteTimeoutError
+            If the time to execute the query exceeds the configured timeout.
+        QueryError
+            If the API returns an error.
+        """
+        _args: list[Arg] = []
+        _ctx = self._select("id", _args)
+        return await _ctx.execute(StatID)
+
+    @typecheck
+    async def link_target(self) -> str:
+        """The path a symlink points to, or an empty string for other types of
+        entries.
+
+        Returns
+        -------
+        str
+            The `String` scalar type represents textual data, represented as
+            UTF-8 character sequences. The String type is most often used by
+            GraphQL to represent free-form human-readable text.
+
+        Raises
+        ------
+        ExecuteTimeoutError
+            If the time to execute the query exceeds the configured timeout.
+        QueryError
+            If the API returns an error.
+        """
+        _args: list[Arg] = []
+        _ctx = self._select("linkTarget", _args)
+        return await _ctx.execute(str)
+
+    @typecheck
+    async def mode(self) -> int:
+        """The permission bits of the entry, including setuid, setgid and sticky
+        bits (e.g., 0755).
+
+        Returns
+        -------
+        int
+            The `Int` scalar type represents non-fractional signed whole
+            numeric values. Int can represent values between -(2^31) and 2^31
+            - 1.
+
+        Raises
+        ------
+        ExecuteTimeoutError
+            If the time to execute the query exceeds the configured timeout.
+        QueryError
+            If the API returns an error.
+        """
+        _args: list[Arg] = []
+        _ctx = self._select("mode", _args)
+        return await _ctx.execute(int)
+
+    @typecheck
+    async def mtime(self) -> int:
+        """The last modification time of the entry, in seconds following Unix
+        epoch.
+
+        Returns
+        -------
+        int
+            The `Int` scalar type represents non-fractional signed whole
+            numeric values. Int can represent values between -(2^31) and 2^31
+            - 1.
+
+        Raises
+        ------
+        ExecuteTimeoutError
+            If the time to execute the query exceeds the configured timeout.
+        QueryError
+            If the API returns an error.
+        """
+        _args: list[Arg] = []
+        _ctx = self._select("mtime", _args)
+        return await _ctx.execute(int)
+
+    @typecheck
+    async def name(self) -> str:
+        """The base name of the entry.
+
+        Returns
+        -------
+        str
+            The `String` scalar type represents textual data, represented as
+            UTF-8 character sequences. The String type is most often used by
+            GraphQL to represent free-form human-readable text.
+
+        Raises
+        ------
+        ExecuteTimeoutError
+            If the time to execute the query exceeds the configured timeout.
+        QueryError
+            If the API returns an error.
+        """
+        _args: list[Arg] = []
+        _ctx = self._select("name", _args)
+        return await _ctx.execute(str)
+
+    @typecheck
+    async def size(self) -> int:
+        """The size of the entry, in bytes.
+
+        Returns
+        -------
+        int
+            The `Int` scalar type represents non-fractional signed whole
+            numeric values. Int can represent values between -(2^31) and 2^31
+            - 1.
+
+        Raises
+        ------
+        ExecuteTimeoutError
+            If the time to execute the query exceeds the configured timeout.
+        QueryError
+            If the API returns an error.
+        """
+        _args: list[Arg] = []
+        _ctx = self._select("size", _args)
+        return await _ctx.execute(int)
+
+    @typecheck
+    async def uid(self) -> int:
+        """The ID of the user that owns the entry.
+
+        Returns
+        -------
+        int
+            The `Int` scalar type represents non-fractional signed whole
+            numeric values. Int can represent values between -(2^31) and 2^31
+            - 1.
+
+        Raises
+        ------
+        ExecuteTimeoutError
+            If the time to execute the query exceeds the configured timeout.
+        QueryError
+            If the API returns an error.
+        """
+        _args: list[Arg] = []
+        _ctx = self._select("uid", _args)
+        return await _ctx.execute(int)
+
+
 class Terminal(Type):
     """An interactive terminal that clients can connect to."""
 
@@ -6593,6 +8363,7 @@ dag = Client()
 """The global client instance."""
 
 __all__ = [
+    "ArchiveFormat",
     "BuildArg",
     "CacheSharingMode",
     "CacheVolume",
@@ -6604,15 +8375,23 @@ __all__ = [
     "CurrentModuleID",
     "Directory",
     "DirectoryID",
+    "Engine",
+    "EngineCache",
+    "EngineCacheEntry",
+    "EngineCacheEntryID",
+    "EngineCacheID",
+    "EngineID",
     "EnvVariable",
     "EnvVariableID",
     "FieldTypeDef",
     "FieldTypeDefID",
     "File",
     "FileID",
+    "FileType",
     "Function",
     "FunctionArg",
     "FunctionArgID",
+    "FunctionCachePolicy",
     "FunctionCall",
     "FunctionCallArgValue",
     "FunctionCallArgValueID",
@@ -6626,6 +8405,8 @@ __all__ = [
     "GitRefID",
     "GitRepository",
     "GitRepositoryID",
+    "HTTPHeader",
+    "HealthcheckHTTPGet",
     "Host",
     "HostID",
     "ImageLayerCompression",
@@ -6656,12 +8437,17 @@ __all__ = [
     "Port",
     "PortForward",
     "PortID",
+    "ReturnType",
     "Secret",
     "SecretID",
     "Service",
     "ServiceID",
+    "ServiceRestartPolicy",
+    "ServiceStatus",
     "Socket",
     "SocketID",
+    "Stat",
+    "StatID",
     "Terminal",
     "TerminalID",
     "TypeDef",
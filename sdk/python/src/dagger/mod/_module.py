@@ -385,6 +385,7 @@ class Module:
         *,
         name: APIName | None = None,
         doc: str | None = None,
+        cache: str | None = None,
     ) -> Func[P, R]:
         ...
 
@@ -394,6 +395,7 @@ class Module:
         *,
         name: APIName | None = None,
         doc: str | None = None,
+        cache: str | None = None,
     ) -> Callable[[Func[P, R]], Func[P, R]]:
         ...
 
@@ -403,6 +405,7 @@ class Module:
         *,
         name: APIName | None = None,
         doc: str | None = None,
+        cache: str | None = None,
     ) -> Func[P, R] | Callable[[Func[P, R]], Func[P, R]]:
         """Exposes a Python function as a :py:class:`dagger.Function`.
 
@@ -424,6 +427,11 @@ class Module:
         doc:
             An alternative description for the API. Useful to use the
             docstring for other purposes.
+        cache:
+            How the results of calls to the function are cached: "pure" to
+            reuse them across sessions, "session" (the default) to reuse them
+            within a session only, or a duration (e.g., "10m") to reuse them
+            across sessions for that long.
         """
 
         def wrapper(func: Func[P, R]) -> Func[P, R]:
@@ -431,7 +439,7 @@ class Module:
                 msg = f"Expected a callable, got {type(func)}."
                 raise UserError(msg)
 
-            f = Function(func, name, doc)
+            f = Function(func, name, doc, cache)
             self.add_resolver(f.resolver)
 
             return f
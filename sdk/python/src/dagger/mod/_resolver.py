@@ -116,6 +116,7 @@ class FunctionResolver(Resolver, Generic[P, R]):
     """Base class for wrapping user-defined functions."""
 
     wrapped_func: Func[P, R]
+    cache: str | None = dataclasses.field(default=None, repr=False)
 
     def __str__(self):
         return repr(self.sig_func)
@@ -128,6 +129,9 @@ class FunctionResolver(Resolver, Generic[P, R]):
         if self.func_doc is not None:
             fn = fn.with_description(self.func_doc)
 
+        if self.cache is not None:
+            fn = self._with_cache_policy(fn)
+
         for param in self.parameters.values():
             arg_type = to_typedef(param.resolved_type)
             default = self._get_default_value(param)
@@ -146,6 +150,19 @@ class FunctionResolver(Resolver, Generic[P, R]):
 
         return typedef.with_function(fn) if self.name else typedef.with_constructor(fn)
 
+    def _with_cache_policy(self, fn: dagger.Function) -> dagger.Function:
+        match self.cache:
+            case "pure":
+                return fn.with_cache_policy(dagger.FunctionCachePolicy.PURE_CACHE)
+            case "session":
+                return fn.with_cache_policy(dagger.FunctionCachePolicy.SESSION_CACHE)
+            case ttl:
+                # The API validates the duration.
+                return fn.with_cache_policy(
+                    dagger.FunctionCachePolicy.TTL_CACHE,
+                    time_to_live=ttl,
+                )
+
     def _get_default_value(self, param: Parameter) -> dagger.JSON | None:
         if not param.has_default:
             return None
@@ -355,6 +372,7 @@ class Function(Generic[P, R]):
     func: Func[P, R]
     name: APIName | None = None
     doc: str | None = None
+    cache: str | None = None
     resolver: FunctionResolver = dataclasses.field(init=False)
 
     def __post_init__(self):
@@ -374,6 +392,7 @@ class Function(Generic[P, R]):
             wrapped_func=self.func,
             doc=self.doc,
             origin=origin,
+            cache=self.cache,
         )
 
     def __set_name__(self, owner: type, name: str):
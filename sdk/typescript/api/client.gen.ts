@@ -47,6 +47,20 @@ class BaseClient {
   }
 }
 
+/**
+ * File format of an archive.
+ */
+export enum ArchiveFormat {
+  /**
+   * A tarball
+   */
+  Tar = "TAR",
+
+  /**
+   * A zip archive
+   */
+  Zip = "ZIP",
+}
 export type BuildArg = {
   /**
    * The build argument name.
@@ -83,6 +97,20 @@ export enum CacheSharingMode {
  */
 export type CacheVolumeID = string & { __CacheVolumeID: never }
 
+export type ContainerAsServiceOpts = {
+  /**
+   * Whether to restart the service when its process exits.
+   *
+   * A service is never restarted after it has been stopped.
+   */
+  restartPolicy?: ServiceRestartPolicy
+
+  /**
+   * Maximum number of times to restart the service. Zero means no limit.
+   */
+  maxRestarts?: number
+}
+
 export type ContainerAsTarballOpts = {
   /**
    * Identifiers for other platform specific containers.
@@ -271,6 +299,33 @@ export type ContainerWithExecOpts = {
    * Execute the command with all root capabilities. This is similar to running a command with "sudo" or executing "docker run" with the "--privileged" flag. Containerization does not provide any security guarantees when using this option. It should only be used when absolutely necessary and only with trusted commands.
    */
   insecureRootCapabilities?: boolean
+
+  /**
+   * Exit codes this command is allowed to exit with without error.
+   *
+   * Use FAILURE or ANY to keep the container's files when the command fails, and read the exit code with exitCode.
+   */
+  expect?: ReturnType
+
+  /**
+   * Maximum duration of the command in seconds, after which it is killed and fails with a timeout error. Overrides the container's default set by withResourceLimits.
+   */
+  timeout?: number
+
+  /**
+   * Maximum amount of memory the command may use (e.g., "512M", "2G"). Overrides the container's default set by withResourceLimits.
+   */
+  memoryLimit?: string
+
+  /**
+   * CPU time the command may use per 100ms period, in microseconds (e.g., 150000 for 1.5 CPUs). Overrides the container's default set by withResourceLimits.
+   */
+  cpuQuota?: number
+
+  /**
+   * Maximum number of processes the command may run. Overrides the container's default set by withResourceLimits.
+   */
+  pidsLimit?: number
 }
 
 export type ContainerWithExposedPortOpts = {
@@ -322,6 +377,43 @@ export type ContainerWithFilesOpts = {
   owner?: string
 }
 
+export type ContainerWithHealthcheckOpts = {
+  /**
+   * Command to run in the service's container, healthy if it exits 0.
+   */
+  command?: string[]
+
+  /**
+   * HTTP request to send to the service, healthy if it responds with the expected status.
+   */
+  httpGet?: HealthcheckHTTPGet
+
+  /**
+   * Port the service must accept TCP connections on.
+   */
+  tcpPort?: number
+
+  /**
+   * Number of seconds to wait between probes.
+   */
+  interval?: number
+
+  /**
+   * Number of seconds after which a probe fails.
+   */
+  timeout?: number
+
+  /**
+   * Number of consecutive failed probes after which the service fails to start.
+   */
+  retries?: number
+
+  /**
+   * Number of seconds to give the service to start, during which failed probes are not counted.
+   */
+  startPeriod?: number
+}
+
 export type ContainerWithMountedCacheOpts = {
   /**
    * Identifier of the directory to use as the cache volume's root.
@@ -406,6 +498,35 @@ export type ContainerWithNewFileOpts = {
   owner?: string
 }
 
+export type ContainerWithResourceLimitsOpts = {
+  /**
+   * Maximum duration of each command in seconds.
+   */
+  timeout?: number
+
+  /**
+   * Maximum amount of memory each command may use (e.g., "512M", "2G").
+   */
+  memoryLimit?: string
+
+  /**
+   * CPU time each command may use per 100ms period, in microseconds (e.g., 150000 for 1.5 CPUs).
+   */
+  cpuQuota?: number
+
+  /**
+   * Maximum number of processes each command may run.
+   */
+  pidsLimit?: number
+}
+
+export type ContainerWithServiceBindingOpts = {
+  /**
+   * Aliases of other services bound to the container that must be started and healthy before this service is started.
+   */
+  dependsOn?: string[]
+}
+
 export type ContainerWithUnixSocketOpts = {
   /**
    * A user:group to set for the mounted socket.
@@ -453,6 +574,27 @@ export type CurrentModuleWorkdirOpts = {
  */
 export type CurrentModuleID = string & { __CurrentModuleID: never }
 
+export type DirectoryAsArchiveOpts = {
+  /**
+   * File format of the archive.
+   */
+  format?: ArchiveFormat
+
+  /**
+   * Compression to apply to the archive.
+   *
+   * Zip archives compress each entry with DEFLATE when set to Gzip, and don't support Zstd.
+   *
+   * EStarGZ is not supported.
+   */
+  compression?: ImageLayerCompression
+
+  /**
+   * Set every timestamp to a fixed time and omit user and group names, so that the same contents always produce the same archive.
+   */
+  reproducible?: boolean
+}
+
 export type DirectoryAsModuleOpts = {
   /**
    * An optional subpath of the directory which contains the module's configuration file.
@@ -464,6 +606,13 @@ export type DirectoryAsModuleOpts = {
   sourceRootPath?: string
 }
 
+export type DirectoryDigestOpts = {
+  /**
+   * Only hash file names and contents, ignoring permissions, ownership and timestamps.
+   */
+  excludeMetadata?: boolean
+}
+
 export type DirectoryDockerBuildOpts = {
   /**
    * The platform to build.
@@ -500,6 +649,29 @@ export type DirectoryEntriesOpts = {
   path?: string
 }
 
+export type DirectoryEntriesWithStatOpts = {
+  /**
+   * Location of the directory to look at (e.g., "/src").
+   */
+  path?: string
+}
+
+export type DirectoryExportOpts = {
+  /**
+   * Remove anything under the path that isn't in this directory, making the path an exact copy of it.
+   *
+   * The filesystem root, the home directory and the working directory, and any directory containing them, cannot be wiped.
+   */
+  wipe?: boolean
+}
+
+export type DirectoryExportDryRunOpts = {
+  /**
+   * Report anything under the path that isn't in this directory as deleted.
+   */
+  wipe?: boolean
+}
+
 export type DirectoryPipelineOpts = {
   /**
    * Description of the sub-pipeline.
@@ -552,11 +724,74 @@ export type DirectoryWithNewFileOpts = {
   permissions?: number
 }
 
+export type DirectoryWithPatchOpts = {
+  /**
+   * Number of leading path components to strip from file names in the patch, as with patch -p.
+   */
+  strip?: number
+}
+
+export type DirectoryWithPermissionsOpts = {
+  /**
+   * If the path is a directory, also change the permissions of its contents.
+   */
+  recursive?: boolean
+}
+
 /**
  * The `DirectoryID` scalar type represents an identifier for an object of type Directory.
  */
 export type DirectoryID = string & { __DirectoryID: never }
 
+export type EngineCacheEntriesOpts = {
+  /**
+   * Only list entries matching all of the filters (e.g., "type==exec.cachemount").
+   */
+  filters?: string[]
+}
+
+export type EngineCachePruneOpts = {
+  /**
+   * Remove every entry that isn't in use.
+   */
+  all?: boolean
+
+  /**
+   * Only remove entries matching all of the filters (e.g., "type==exec.cachemount").
+   */
+  filters?: string[]
+
+  /**
+   * Keep entries until the cache uses at most this many bytes.
+   */
+  keepBytes?: number
+
+  /**
+   * Only remove entries that were not used in this many seconds.
+   */
+  olderThan?: number
+
+  /**
+   * Apply the engine's garbage collection policy instead of the other arguments.
+   */
+  useDefaultPolicy?: boolean
+}
+
+/**
+ * The `EngineCacheEntryID` scalar type represents an identifier for an object of type EngineCacheEntry.
+ */
+export type EngineCacheEntryID = string & { __EngineCacheEntryID: never }
+
+/**
+ * The `EngineCacheID` scalar type represents an identifier for an object of type EngineCache.
+ */
+export type EngineCacheID = string & { __EngineCacheID: never }
+
+/**
+ * The `EngineID` scalar type represents an identifier for an object of type Engine.
+ */
+export type EngineID = string & { __EngineID: never }
+
 /**
  * The `EnvVariableID` scalar type represents an identifier for an object of type EnvVariable.
  */
@@ -574,11 +809,44 @@ export type FileExportOpts = {
   allowParentDirPath?: boolean
 }
 
+export type FileExtractOpts = {
+  /**
+   * File format of the archive.
+   *
+   * If not set, it's detected from the file. Compressed tarballs are detected automatically.
+   */
+  format?: ArchiveFormat
+}
+
 /**
  * The `FileID` scalar type represents an identifier for an object of type File.
  */
 export type FileID = string & { __FileID: never }
 
+/**
+ * The type of a filesystem entry.
+ */
+export enum FileType {
+  /**
+   * A directory
+   */
+  Dir = "DIR",
+
+  /**
+   * Any other type of entry, like a device, pipe or socket
+   */
+  Other = "OTHER",
+
+  /**
+   * A regular file
+   */
+  Regular = "REGULAR",
+
+  /**
+   * A symbolic link
+   */
+  Symlink = "SYMLINK",
+}
 export type FunctionWithArgOpts = {
   /**
    * A doc string for the argument, if any
@@ -591,11 +859,39 @@ export type FunctionWithArgOpts = {
   defaultValue?: JSON
 }
 
+export type FunctionWithCachePolicyOpts = {
+  /**
+   * How long the results are reused for, as a duration (e.g., "10m"). Required for TTL_CACHE.
+   */
+  timeToLive?: string
+}
+
 /**
  * The `FunctionArgID` scalar type represents an identifier for an object of type FunctionArg.
  */
 export type FunctionArgID = string & { __FunctionArgID: never }
 
+/**
+ * How the results of calls to a function are cached.
+ */
+export enum FunctionCachePolicy {
+  /**
+   * The function always returns the same result for the same inputs, so its results are reused across sessions.
+   */
+  PureCache = "PURE_CACHE",
+
+  /**
+   * The function is called at most once per session for the same inputs.
+   *
+   * This is the default.
+   */
+  SessionCache = "SESSION_CACHE",
+
+  /**
+   * The results of the function are reused across sessions until they are older than its time to live.
+   */
+  TtlCache = "TTL_CACHE",
+}
 /**
  * The `FunctionCallArgValueID` scalar type represents an identifier for an object of type FunctionCallArgValue.
  */
@@ -645,6 +941,35 @@ export type GitRefID = string & { __GitRefID: never }
  */
 export type GitRepositoryID = string & { __GitRepositoryID: never }
 
+export type HTTPHeader = {
+  /**
+   * The header name.
+   */
+  name: string
+
+  /**
+   * The header value.
+   */
+  value: string
+}
+
+export type HealthcheckHTTPGet = {
+  /**
+   * The response status the service is healthy with.
+   */
+  expectStatus?: number
+
+  /**
+   * The path to request.
+   */
+  path?: string
+
+  /**
+   * The port to send the request to.
+   */
+  port: number
+}
+
 export type HostDirectoryOpts = {
   /**
    * Exclude artifacts that match the given pattern (e.g., ["node_modules/", ".git*"]).
@@ -819,6 +1144,20 @@ export type PortForward = {
  */
 export type PortID = string & { __PortID: never }
 
+export type ClientCacheVolumeOpts = {
+  /**
+   * A namespace to scope the cache volume to instead of the calling module.
+   *
+   * Cache volumes with the same key and namespace are shared.
+   */
+  namespace?: string
+
+  /**
+   * Share the cache volume with every module and client that uses the same key.
+   */
+  shared?: boolean
+}
+
 export type ClientContainerOpts = {
   /**
    * DEPRECATED: Use `loadContainerFromID` instead.
@@ -865,6 +1204,33 @@ export type ClientHttpOpts = {
    * A service which must be started before the URL is fetched.
    */
   experimentalServiceHost?: Service
+
+  /**
+   * Expected digest of the content (e.g., "sha256:...").
+   *
+   * The download fails if the content does not match.
+   */
+  checksum?: string
+
+  /**
+   * Additional headers to send with the request.
+   */
+  headers?: HTTPHeader[]
+
+  /**
+   * Secret used as the value of the Authorization header (e.g., "Bearer ...").
+   */
+  authHeader?: Secret
+
+  /**
+   * Name of the downloaded file (defaults to a digest of the URL).
+   */
+  name?: string
+
+  /**
+   * Permission given to the downloaded file (e.g., 0600).
+   */
+  permissions?: number
 }
 
 export type ClientModuleDependencyOpts = {
@@ -875,6 +1241,11 @@ export type ClientModuleDependencyOpts = {
 }
 
 export type ClientModuleSourceOpts = {
+  /**
+   * The pinned version of the module source, e.g. the resolved commit of a git source. If set, the ref's version is not resolved again.
+   */
+  refPin?: string
+
   /**
    * If true, enforce that the source is a stable version for source kinds that support versioning.
    */
@@ -895,8 +1266,32 @@ export type ClientPipelineOpts = {
 
 export type ClientSecretOpts = {
   accessor?: string
+
+  /**
+   * The URI of the secret in a secret provider, e.g. "vault://secret/data/ci#token", "env://TOKEN", "file:///run/secrets/token", "cmd://gcloud auth print-access-token", "op://vault/item/field" or "sops:///secrets.yaml#db.password".
+   */
+  uri?: string
 }
 
+/**
+ * Expected return type of an execution
+ */
+export enum ReturnType {
+  /**
+   * Any execution, regardless of its exit code
+   */
+  Any = "ANY",
+
+  /**
+   * A failed execution (non-zero exit code)
+   */
+  Failure = "FAILURE",
+
+  /**
+   * A successful execution (exit code 0)
+   */
+  Success = "SUCCESS",
+}
 /**
  * The `SecretID` scalar type represents an identifier for an object of type Secret.
  */
@@ -914,6 +1309,25 @@ export type ServiceEndpointOpts = {
   scheme?: string
 }
 
+export type ServiceLogsOpts = {
+  /**
+   * Only return output written after this time.
+   *
+   * Either an RFC 3339 timestamp (e.g., "2024-01-02T15:04:05Z") or a duration relative to now (e.g., "10m").
+   */
+  since?: string
+
+  /**
+   * Only return this many of the most recent lines.
+   */
+  tail?: number
+
+  /**
+   * Wait for the service to exit before returning its output.
+   */
+  follow?: boolean
+}
+
 export type ServiceStopOpts = {
   /**
    * Immediately kill the service without waiting for a graceful exit
@@ -941,14 +1355,62 @@ export type ServiceUpOpts = {
 export type ServiceID = string & { __ServiceID: never }
 
 /**
- * The `SocketID` scalar type represents an identifier for an object of type Socket.
+ * When to restart a service whose process exits.
  */
-export type SocketID = string & { __SocketID: never }
+export enum ServiceRestartPolicy {
+  /**
+   * Restart the service whenever it exits, unless it was stopped
+   */
+  Always = "ALWAYS",
 
-/**
- * The `TerminalID` scalar type represents an identifier for an object of type Terminal.
- */
-export type TerminalID = string & { __TerminalID: never }
+  /**
+   * Never restart the service
+   */
+  Never = "NEVER",
+
+  /**
+   * Restart the service if it exits with a non-zero exit code
+   */
+  OnFailure = "ON_FAILURE",
+}
+/**
+ * The status of a service.
+ */
+export enum ServiceStatus {
+  /**
+   * The service has exited or been stopped
+   */
+  Exited = "EXITED",
+
+  /**
+   * The service is running and its health checks have passed
+   */
+  Healthy = "HEALTHY",
+
+  /**
+   * The service has not been started
+   */
+  NotStarted = "NOT_STARTED",
+
+  /**
+   * The service is starting and waiting for its health checks to pass
+   */
+  Starting = "STARTING",
+}
+/**
+ * The `SocketID` scalar type represents an identifier for an object of type Socket.
+ */
+export type SocketID = string & { __SocketID: never }
+
+/**
+ * The `StatID` scalar type represents an identifier for an object of type Stat.
+ */
+export type StatID = string & { __StatID: never }
+
+/**
+ * The `TerminalID` scalar type represents an identifier for an object of type Terminal.
+ */
+export type TerminalID = string & { __TerminalID: never }
 
 export type TypeDefWithFieldOpts = {
   /**
@@ -1042,6 +1504,12 @@ export type __TypeFieldsOpts = {
  */
 export class CacheVolume extends BaseClient {
   private readonly _id?: CacheVolumeID = undefined
+  private readonly _clear?: Void = undefined
+  private readonly _key?: string = undefined
+  private readonly _mostRecentUseTime?: number = undefined
+  private readonly _namespace?: string = undefined
+  private readonly _seed?: Void = undefined
+  private readonly _size?: number = undefined
 
   /**
    * Constructor is used for internal usage only, do not create object from it.
@@ -1049,10 +1517,22 @@ export class CacheVolume extends BaseClient {
   constructor(
     parent?: { queryTree?: QueryTree[]; ctx: Context },
     _id?: CacheVolumeID,
+    _clear?: Void,
+    _key?: string,
+    _mostRecentUseTime?: number,
+    _namespace?: string,
+    _seed?: Void,
+    _size?: number,
   ) {
     super(parent)
 
     this._id = _id
+    this._clear = _clear
+    this._key = _key
+    this._mostRecentUseTime = _mostRecentUseTime
+    this._namespace = _namespace
+    this._seed = _seed
+    this._size = _size
   }
 
   /**
@@ -1075,6 +1555,153 @@ export class CacheVolume extends BaseClient {
 
     return response
   }
+
+  /**
+   * Empties the cache volume.
+   *
+   * Subsequent uses of the cache volume start from an empty directory, while pipelines using it right now keep their contents.
+   */
+  clear = async (): Promise<Void> => {
+    if (this._clear) {
+      return this._clear
+    }
+
+    const response: Awaited<Void> = await computeQuery(
+      [
+        ...this._queryTree,
+        {
+          operation: "clear",
+        },
+      ],
+      await this._ctx.connection(),
+    )
+
+    return response
+  }
+
+  /**
+   * The key the cache volume was constructed with.
+   */
+  key = async (): Promise<string> => {
+    if (this._key) {
+      return this._key
+    }
+
+    const response: Awaited<string> = await computeQuery(
+      [
+        ...this._queryTree,
+        {
+          operation: "key",
+        },
+      ],
+      await this._ctx.connection(),
+    )
+
+    return response
+  }
+
+  /**
+   * The time the cache volume was last used, in seconds following Unix epoch, or 0 if it was never used.
+   */
+  mostRecentUseTime = async (): Promise<number> => {
+    if (this._mostRecentUseTime) {
+      return this._mostRecentUseTime
+    }
+
+    const response: Awaited<number> = await computeQuery(
+      [
+        ...this._queryTree,
+        {
+          operation: "mostRecentUseTime",
+        },
+      ],
+      await this._ctx.connection(),
+    )
+
+    return response
+  }
+
+  /**
+   * The namespace the cache volume is scoped to, or an empty string if it's shared.
+   */
+  namespace_ = async (): Promise<string> => {
+    if (this._namespace) {
+      return this._namespace
+    }
+
+    const response: Awaited<string> = await computeQuery(
+      [
+        ...this._queryTree,
+        {
+          operation: "namespace",
+        },
+      ],
+      await this._ctx.connection(),
+    )
+
+    return response
+  }
+
+  /**
+   * Copies the contents of a directory into the cache volume.
+   *
+   * Files already in the cache volume are kept unless the directory has a file at the same path.
+   * @param source The directory to copy into the cache volume.
+   */
+  seed = async (source: Directory): Promise<Void> => {
+    if (this._seed) {
+      return this._seed
+    }
+
+    const response: Awaited<Void> = await computeQuery(
+      [
+        ...this._queryTree,
+        {
+          operation: "seed",
+          args: { source },
+        },
+      ],
+      await this._ctx.connection(),
+    )
+
+    return response
+  }
+
+  /**
+   * The disk space used by the cache volume, in bytes.
+   */
+  size = async (): Promise<number> => {
+    if (this._size) {
+      return this._size
+    }
+
+    const response: Awaited<number> = await computeQuery(
+      [
+        ...this._queryTree,
+        {
+          operation: "size",
+        },
+      ],
+      await this._ctx.connection(),
+    )
+
+    return response
+  }
+
+  /**
+   * Returns a copy of the current contents of the cache volume.
+   */
+  snapshot = (): Directory => {
+    return new Directory({
+      queryTree: [
+        ...this._queryTree,
+        {
+          operation: "snapshot",
+        },
+      ],
+      ctx: this._ctx,
+    })
+  }
 }
 
 /**
@@ -1083,6 +1710,7 @@ export class CacheVolume extends BaseClient {
 export class Container extends BaseClient {
   private readonly _id?: ContainerID = undefined
   private readonly _envVariable?: string = undefined
+  private readonly _exitCode?: number = undefined
   private readonly _export?: boolean = undefined
   private readonly _imageRef?: string = undefined
   private readonly _label?: string = undefined
@@ -1101,6 +1729,7 @@ export class Container extends BaseClient {
     parent?: { queryTree?: QueryTree[]; ctx: Context },
     _id?: ContainerID,
     _envVariable?: string,
+    _exitCode?: number,
     _export?: boolean,
     _imageRef?: string,
     _label?: string,
@@ -1116,6 +1745,7 @@ export class Container extends BaseClient {
 
     this._id = _id
     this._envVariable = _envVariable
+    this._exitCode = _exitCode
     this._export = _export
     this._imageRef = _imageRef
     this._label = _label
@@ -1153,13 +1783,22 @@ export class Container extends BaseClient {
    * Turn the container into a Service.
    *
    * Be sure to set any exposed ports before this conversion.
+   * @param opts.restartPolicy Whether to restart the service when its process exits.
+   *
+   * A service is never restarted after it has been stopped.
+   * @param opts.maxRestarts Maximum number of times to restart the service. Zero means no limit.
    */
-  asService = (): Service => {
+  asService = (opts?: ContainerAsServiceOpts): Service => {
+    const metadata: Metadata = {
+      restartPolicy: { is_enum: true },
+    }
+
     return new Service({
       queryTree: [
         ...this._queryTree,
         {
           operation: "asService",
+          args: { ...opts, __metadata: metadata },
         },
       ],
       ctx: this._ctx,
@@ -1335,6 +1974,29 @@ export class Container extends BaseClient {
     )
   }
 
+  /**
+   * The exit code of the last executed command.
+   *
+   * Will execute default command if none is set, or error if there's no default.
+   */
+  exitCode = async (): Promise<number> => {
+    if (this._exitCode) {
+      return this._exitCode
+    }
+
+    const response: Awaited<number> = await computeQuery(
+      [
+        ...this._queryTree,
+        {
+          operation: "exitCode",
+        },
+      ],
+      await this._ctx.connection(),
+    )
+
+    return response
+  }
+
   /**
    * EXPERIMENTAL API! Subject to change/removal at any time.
    *
@@ -1940,14 +2602,25 @@ export class Container extends BaseClient {
    *
    * Do not use this option unless you trust the command being executed; the command being executed WILL BE GRANTED FULL ACCESS TO YOUR HOST FILESYSTEM.
    * @param opts.insecureRootCapabilities Execute the command with all root capabilities. This is similar to running a command with "sudo" or executing "docker run" with the "--privileged" flag. Containerization does not provide any security guarantees when using this option. It should only be used when absolutely necessary and only with trusted commands.
+   * @param opts.expect Exit codes this command is allowed to exit with without error.
+   *
+   * Use FAILURE or ANY to keep the container's files when the command fails, and read the exit code with exitCode.
+   * @param opts.timeout Maximum duration of the command in seconds, after which it is killed and fails with a timeout error. Overrides the container's default set by withResourceLimits.
+   * @param opts.memoryLimit Maximum amount of memory the command may use (e.g., "512M", "2G"). Overrides the container's default set by withResourceLimits.
+   * @param opts.cpuQuota CPU time the command may use per 100ms period, in microseconds (e.g., 150000 for 1.5 CPUs). Overrides the container's default set by withResourceLimits.
+   * @param opts.pidsLimit Maximum number of processes the command may run. Overrides the container's default set by withResourceLimits.
    */
   withExec = (args: string[], opts?: ContainerWithExecOpts): Container => {
+    const metadata: Metadata = {
+      expect: { is_enum: true },
+    }
+
     return new Container({
       queryTree: [
         ...this._queryTree,
         {
           operation: "withExec",
-          args: { args, ...opts },
+          args: { args, ...opts, __metadata: metadata },
         },
       ],
       ctx: this._ctx,
@@ -2058,6 +2731,33 @@ export class Container extends BaseClient {
     })
   }
 
+  /**
+   * Configures how to check that the container is ready when run as a service.
+   *
+   * Exactly one of command, httpGet or tcpPort must be set. The service is probed from inside its container until a probe succeeds, replacing the default check that all exposed ports accept connections.
+   *
+   * Images that configure a HEALTHCHECK use it by default.
+   * @param opts.command Command to run in the service's container, healthy if it exits 0.
+   * @param opts.httpGet HTTP request to send to the service, healthy if it responds with the expected status.
+   * @param opts.tcpPort Port the service must accept TCP connections on.
+   * @param opts.interval Number of seconds to wait between probes.
+   * @param opts.timeout Number of seconds after which a probe fails.
+   * @param opts.retries Number of consecutive failed probes after which the service fails to start.
+   * @param opts.startPeriod Number of seconds to give the service to start, during which failed probes are not counted.
+   */
+  withHealthcheck = (opts?: ContainerWithHealthcheckOpts): Container => {
+    return new Container({
+      queryTree: [
+        ...this._queryTree,
+        {
+          operation: "withHealthcheck",
+          args: { ...opts },
+        },
+      ],
+      ctx: this._ctx,
+    })
+  }
+
   /**
    * Retrieves this container plus the given label.
    * @param name The name of the label (e.g., "org.opencontainers.artifact.created").
@@ -2261,6 +2961,28 @@ export class Container extends BaseClient {
     })
   }
 
+  /**
+   * Retrieves this container with default limits for subsequently executed commands.
+   *
+   * A zero value means no limit. Limits passed to withExec take precedence.
+   * @param opts.timeout Maximum duration of each command in seconds.
+   * @param opts.memoryLimit Maximum amount of memory each command may use (e.g., "512M", "2G").
+   * @param opts.cpuQuota CPU time each command may use per 100ms period, in microseconds (e.g., 150000 for 1.5 CPUs).
+   * @param opts.pidsLimit Maximum number of processes each command may run.
+   */
+  withResourceLimits = (opts?: ContainerWithResourceLimitsOpts): Container => {
+    return new Container({
+      queryTree: [
+        ...this._queryTree,
+        {
+          operation: "withResourceLimits",
+          args: { ...opts },
+        },
+      ],
+      ctx: this._ctx,
+    })
+  }
+
   /**
    * Retrieves the container with the given directory mounted to /.
    * @param directory Directory to mount.
@@ -2306,14 +3028,19 @@ export class Container extends BaseClient {
    * The service dependency will also convey to any files or directories produced by the container.
    * @param alias A name that can be used to reach the service from the container
    * @param service Identifier of the service container
+   * @param opts.dependsOn Aliases of other services bound to the container that must be started and healthy before this service is started.
    */
-  withServiceBinding = (alias: string, service: Service): Container => {
+  withServiceBinding = (
+    alias: string,
+    service: Service,
+    opts?: ContainerWithServiceBindingOpts,
+  ): Container => {
     return new Container({
       queryTree: [
         ...this._queryTree,
         {
           operation: "withServiceBinding",
-          args: { alias, service },
+          args: { alias, service, ...opts },
         },
       ],
       ctx: this._ctx,
@@ -2472,6 +3199,23 @@ export class Container extends BaseClient {
     })
   }
 
+  /**
+   * Retrieves this container without a healthcheck, including one configured by its image.
+   *
+   * Its exposed ports will be checked instead when run as a service.
+   */
+  withoutHealthcheck = (): Container => {
+    return new Container({
+      queryTree: [
+        ...this._queryTree,
+        {
+          operation: "withoutHealthcheck",
+        },
+      ],
+      ctx: this._ctx,
+    })
+  }
+
   /**
    * Retrieves this container minus the given environment label.
    * @param name The name of the label to remove (e.g., "org.opencontainers.artifact.created").
@@ -2727,6 +3471,7 @@ export class CurrentModule extends BaseClient {
  */
 export class Directory extends BaseClient {
   private readonly _id?: DirectoryID = undefined
+  private readonly _digest?: string = undefined
   private readonly _export?: boolean = undefined
   private readonly _sync?: DirectoryID = undefined
 
@@ -2736,12 +3481,14 @@ export class Directory extends BaseClient {
   constructor(
     parent?: { queryTree?: QueryTree[]; ctx: Context },
     _id?: DirectoryID,
+    _digest?: string,
     _export?: boolean,
     _sync?: DirectoryID,
   ) {
     super(parent)
 
     this._id = _id
+    this._digest = _digest
     this._export = _export
     this._sync = _sync
   }
@@ -2767,6 +3514,34 @@ export class Directory extends BaseClient {
     return response
   }
 
+  /**
+   * Packs this directory's contents into an archive file.
+   * @param opts.format File format of the archive.
+   * @param opts.compression Compression to apply to the archive.
+   *
+   * Zip archives compress each entry with DEFLATE when set to Gzip, and don't support Zstd.
+   *
+   * EStarGZ is not supported.
+   * @param opts.reproducible Set every timestamp to a fixed time and omit user and group names, so that the same contents always produce the same archive.
+   */
+  asArchive = (opts?: DirectoryAsArchiveOpts): File => {
+    const metadata: Metadata = {
+      format: { is_enum: true },
+      compression: { is_enum: true },
+    }
+
+    return new File({
+      queryTree: [
+        ...this._queryTree,
+        {
+          operation: "asArchive",
+          args: { ...opts, __metadata: metadata },
+        },
+      ],
+      ctx: this._ctx,
+    })
+  }
+
   /**
    * Load the directory as a Dagger module
    * @param opts.sourceRootPath An optional subpath of the directory which contains the module's configuration file.
@@ -2789,15 +3564,15 @@ export class Directory extends BaseClient {
   }
 
   /**
-   * Gets the difference between this directory and an another directory.
+   * Returns a unified diff of the changes from this directory to another directory, in the format of git diff.
    * @param other Identifier of the directory to compare.
    */
-  diff = (other: Directory): Directory => {
-    return new Directory({
+  asPatch = (other: Directory): File => {
+    return new File({
       queryTree: [
         ...this._queryTree,
         {
-          operation: "diff",
+          operation: "asPatch",
           args: { other },
         },
       ],
@@ -2806,16 +3581,16 @@ export class Directory extends BaseClient {
   }
 
   /**
-   * Retrieves a directory at the given path.
-   * @param path Location of the directory to retrieve (e.g., "/src").
+   * Gets the difference between this directory and an another directory.
+   * @param other Identifier of the directory to compare.
    */
-  directory = (path: string): Directory => {
+  diff = (other: Directory): Directory => {
     return new Directory({
       queryTree: [
         ...this._queryTree,
         {
-          operation: "directory",
-          args: { path },
+          operation: "diff",
+          args: { other },
         },
       ],
       ctx: this._ctx,
@@ -2823,8 +3598,48 @@ export class Directory extends BaseClient {
   }
 
   /**
-   * Builds a new Docker container from this directory.
-   * @param opts.platform The platform to build.
+   * Returns a digest of the directory's contents (e.g., "sha256:...").
+   * @param opts.excludeMetadata Only hash file names and contents, ignoring permissions, ownership and timestamps.
+   */
+  digest = async (opts?: DirectoryDigestOpts): Promise<string> => {
+    if (this._digest) {
+      return this._digest
+    }
+
+    const response: Awaited<string> = await computeQuery(
+      [
+        ...this._queryTree,
+        {
+          operation: "digest",
+          args: { ...opts },
+        },
+      ],
+      await this._ctx.connection(),
+    )
+
+    return response
+  }
+
+  /**
+   * Retrieves a directory at the given path.
+   * @param path Location of the directory to retrieve (e.g., "/src").
+   */
+  directory = (path: string): Directory => {
+    return new Directory({
+      queryTree: [
+        ...this._queryTree,
+        {
+          operation: "directory",
+          args: { path },
+        },
+      ],
+      ctx: this._ctx,
+    })
+  }
+
+  /**
+   * Builds a new Docker container from this directory.
+   * @param opts.platform The platform to build.
    * @param opts.dockerfile Path to the Dockerfile to use (e.g., "frontend.Dockerfile").
    * @param opts.target Target build stage to build.
    * @param opts.buildArgs Build arguments to use in the build.
@@ -2864,11 +3679,59 @@ export class Directory extends BaseClient {
     return response
   }
 
+  /**
+   * Returns the metadata of each file and directory at the given path.
+   * @param opts.path Location of the directory to look at (e.g., "/src").
+   */
+  entriesWithStat = async (
+    opts?: DirectoryEntriesWithStatOpts,
+  ): Promise<Stat[]> => {
+    type entriesWithStat = {
+      id: StatID
+    }
+
+    const response: Awaited<entriesWithStat[]> = await computeQuery(
+      [
+        ...this._queryTree,
+        {
+          operation: "entriesWithStat",
+          args: { ...opts },
+        },
+        {
+          operation: "id",
+        },
+      ],
+      await this._ctx.connection(),
+    )
+
+    return response.map(
+      (r) =>
+        new Stat(
+          {
+            queryTree: [
+              {
+                operation: "loadStatFromID",
+                args: { id: r.id },
+              },
+            ],
+            ctx: this._ctx,
+          },
+          r.id,
+        ),
+    )
+  }
+
   /**
    * Writes the contents of the directory to a path on the host.
    * @param path Location of the copied directory (e.g., "logs/").
+   * @param opts.wipe Remove anything under the path that isn't in this directory, making the path an exact copy of it.
+   *
+   * The filesystem root, the home directory and the working directory, and any directory containing them, cannot be wiped.
    */
-  export = async (path: string): Promise<boolean> => {
+  export = async (
+    path: string,
+    opts?: DirectoryExportOpts,
+  ): Promise<boolean> => {
     if (this._export) {
       return this._export
     }
@@ -2878,7 +3741,32 @@ export class Directory extends BaseClient {
         ...this._queryTree,
         {
           operation: "export",
-          args: { path },
+          args: { path, ...opts },
+        },
+      ],
+      await this._ctx.connection(),
+    )
+
+    return response
+  }
+
+  /**
+   * Reports the changes that exporting the directory to a path on the host would make, without writing anything.
+   *
+   * Each change is formatted as "A path" for added, "M path" for modified and "D path" for deleted entries, with directories ending in a slash.
+   * @param path Location of the copied directory (e.g., "logs/").
+   * @param opts.wipe Report anything under the path that isn't in this directory as deleted.
+   */
+  exportDryRun = async (
+    path: string,
+    opts?: DirectoryExportDryRunOpts,
+  ): Promise<string[]> => {
+    const response: Awaited<string[]> = await computeQuery(
+      [
+        ...this._queryTree,
+        {
+          operation: "exportDryRun",
+          args: { path, ...opts },
         },
       ],
       await this._ctx.connection(),
@@ -2942,6 +3830,25 @@ export class Directory extends BaseClient {
     })
   }
 
+  /**
+   * Retrieves the metadata of the file, directory or symlink at the given path.
+   *
+   * Symlinks are not followed.
+   * @param path Location of the entry to look at (e.g., "bin/run.sh").
+   */
+  stat = (path: string): Stat => {
+    return new Stat({
+      queryTree: [
+        ...this._queryTree,
+        {
+          operation: "stat",
+          args: { path },
+        },
+      ],
+      ctx: this._ctx,
+    })
+  }
+
   /**
    * Force evaluation in the engine.
    */
@@ -2959,6 +3866,26 @@ export class Directory extends BaseClient {
     return this
   }
 
+  /**
+   * Retrieves this directory plus the contents of the given archive extracted at the given path.
+   *
+   * The archive's format and compression are detected automatically.
+   * @param path Location to extract the archive to (e.g., "/vendor").
+   * @param file Identifier of the archive to extract.
+   */
+  withArchive = (path: string, file: File): Directory => {
+    return new Directory({
+      queryTree: [
+        ...this._queryTree,
+        {
+          operation: "withArchive",
+          args: { path, file },
+        },
+      ],
+      ctx: this._ctx,
+    })
+  }
+
   /**
    * Retrieves this directory plus a directory written at the given path.
    * @param path Location of the written directory (e.g., "/src/").
@@ -3029,6 +3956,26 @@ export class Directory extends BaseClient {
     })
   }
 
+  /**
+   * Retrieves this directory with the file or directory at src moved to dest.
+   *
+   * If dest is an existing directory, src is moved into it.
+   * @param src Location of the file or directory to move (e.g., "/build/out").
+   * @param dest Location to move it to (e.g., "/dist").
+   */
+  withMove = (src: string, dest: string): Directory => {
+    return new Directory({
+      queryTree: [
+        ...this._queryTree,
+        {
+          operation: "withMove",
+          args: { src, dest },
+        },
+      ],
+      ctx: this._ctx,
+    })
+  }
+
   /**
    * Retrieves this directory plus a new directory created at the given path.
    * @param path Location of the directory created (e.g., "/logs").
@@ -3073,6 +4020,95 @@ export class Directory extends BaseClient {
     })
   }
 
+  /**
+   * Retrieves this directory with the ownership of the given path changed.
+   *
+   * Directories are changed recursively.
+   * @param path Location of the file or directory to change (e.g., "/src").
+   * @param owner A user:group to set for the path.
+   *
+   * The user and group can either be an ID (1000:1000) or a name (foo:bar).
+   *
+   * If the group is omitted, it defaults to the same as the user.
+   *
+   * Names are resolved using /etc/passwd and /etc/group in this directory.
+   */
+  withOwner = (path: string, owner: string): Directory => {
+    return new Directory({
+      queryTree: [
+        ...this._queryTree,
+        {
+          operation: "withOwner",
+          args: { path, owner },
+        },
+      ],
+      ctx: this._ctx,
+    })
+  }
+
+  /**
+   * Retrieves this directory with the given unified diff applied, as written by diff -u, git diff or git format-patch.
+   *
+   * Fails without applying anything if any part of the patch does not apply.
+   * @param patch Identifier of the file containing the patch.
+   * @param opts.strip Number of leading path components to strip from file names in the patch, as with patch -p.
+   */
+  withPatch = (patch: File, opts?: DirectoryWithPatchOpts): Directory => {
+    return new Directory({
+      queryTree: [
+        ...this._queryTree,
+        {
+          operation: "withPatch",
+          args: { patch, ...opts },
+        },
+      ],
+      ctx: this._ctx,
+    })
+  }
+
+  /**
+   * Retrieves this directory with the permissions of the given path changed.
+   * @param path Location of the file or directory to change (e.g., "/bin/run.sh").
+   * @param mode Permissions to set (e.g., 0755).
+   * @param opts.recursive If the path is a directory, also change the permissions of its contents.
+   */
+  withPermissions = (
+    path: string,
+    mode: number,
+    opts?: DirectoryWithPermissionsOpts,
+  ): Directory => {
+    return new Directory({
+      queryTree: [
+        ...this._queryTree,
+        {
+          operation: "withPermissions",
+          args: { path, mode, ...opts },
+        },
+      ],
+      ctx: this._ctx,
+    })
+  }
+
+  /**
+   * Retrieves this directory plus a symlink at the given path.
+   *
+   * Any existing file or directory at linkName is replaced.
+   * @param target Location the symlink points to (e.g., "../lib/foo.so").
+   * @param linkName Location of the created symlink (e.g., "/lib/bar.so").
+   */
+  withSymlink = (target: string, linkName: string): Directory => {
+    return new Directory({
+      queryTree: [
+        ...this._queryTree,
+        {
+          operation: "withSymlink",
+          args: { target, linkName },
+        },
+      ],
+      ctx: this._ctx,
+    })
+  }
+
   /**
    * Retrieves this directory with all file/dir timestamps set to the given time.
    * @param timestamp Timestamp to set dir/files in.
@@ -3118,21 +4154,436 @@ export class Directory extends BaseClient {
       queryTree: [
         ...this._queryTree,
         {
-          operation: "withoutFile",
-          args: { path },
+          operation: "withoutFile",
+          args: { path },
+        },
+      ],
+      ctx: this._ctx,
+    })
+  }
+
+  /**
+   * Call the provided function with current Directory.
+   *
+   * This is useful for reusability and readability by not breaking the calling chain.
+   */
+  with = (arg: (param: Directory) => Directory) => {
+    return arg(this)
+  }
+}
+
+/**
+ * The Dagger engine the client is connected to.
+ */
+export class Engine extends BaseClient {
+  private readonly _id?: EngineID = undefined
+
+  /**
+   * Constructor is used for internal usage only, do not create object from it.
+   */
+  constructor(
+    parent?: { queryTree?: QueryTree[]; ctx: Context },
+    _id?: EngineID,
+  ) {
+    super(parent)
+
+    this._id = _id
+  }
+
+  /**
+   * A unique identifier for this Engine.
+   */
+  id = async (): Promise<EngineID> => {
+    if (this._id) {
+      return this._id
+    }
+
+    const response: Awaited<EngineID> = await computeQuery(
+      [
+        ...this._queryTree,
+        {
+          operation: "id",
+        },
+      ],
+      await this._ctx.connection(),
+    )
+
+    return response
+  }
+
+  /**
+   * The local cache of the engine.
+   */
+  localCache = (): EngineCache => {
+    return new EngineCache({
+      queryTree: [
+        ...this._queryTree,
+        {
+          operation: "localCache",
+        },
+      ],
+      ctx: this._ctx,
+    })
+  }
+}
+
+/**
+ * The local cache of the Dagger engine.
+ */
+export class EngineCache extends BaseClient {
+  private readonly _id?: EngineCacheID = undefined
+  private readonly _keepBytes?: number = undefined
+
+  /**
+   * Constructor is used for internal usage only, do not create object from it.
+   */
+  constructor(
+    parent?: { queryTree?: QueryTree[]; ctx: Context },
+    _id?: EngineCacheID,
+    _keepBytes?: number,
+  ) {
+    super(parent)
+
+    this._id = _id
+    this._keepBytes = _keepBytes
+  }
+
+  /**
+   * A unique identifier for this EngineCache.
+   */
+  id = async (): Promise<EngineCacheID> => {
+    if (this._id) {
+      return this._id
+    }
+
+    const response: Awaited<EngineCacheID> = await computeQuery(
+      [
+        ...this._queryTree,
+        {
+          operation: "id",
+        },
+      ],
+      await this._ctx.connection(),
+    )
+
+    return response
+  }
+
+  /**
+   * The entries in the cache, largest first.
+   * @param opts.filters Only list entries matching all of the filters (e.g., "type==exec.cachemount").
+   */
+  entries = async (
+    opts?: EngineCacheEntriesOpts,
+  ): Promise<EngineCacheEntry[]> => {
+    type entries = {
+      id: EngineCacheEntryID
+    }
+
+    const response: Awaited<entries[]> = await computeQuery(
+      [
+        ...this._queryTree,
+        {
+          operation: "entries",
+          args: { ...opts },
+        },
+        {
+          operation: "id",
+        },
+      ],
+      await this._ctx.connection(),
+    )
+
+    return response.map(
+      (r) =>
+        new EngineCacheEntry(
+          {
+            queryTree: [
+              {
+                operation: "loadEngineCacheEntryFromID",
+                args: { id: r.id },
+              },
+            ],
+            ctx: this._ctx,
+          },
+          r.id,
+        ),
+    )
+  }
+
+  /**
+   * The most space the engine's garbage collection policy lets the cache use, in bytes, or 0 if it's unbounded.
+   */
+  keepBytes = async (): Promise<number> => {
+    if (this._keepBytes) {
+      return this._keepBytes
+    }
+
+    const response: Awaited<number> = await computeQuery(
+      [
+        ...this._queryTree,
+        {
+          operation: "keepBytes",
+        },
+      ],
+      await this._ctx.connection(),
+    )
+
+    return response
+  }
+
+  /**
+   * Removes entries that aren't in use from the cache, returning the entries that were removed.
+   *
+   * Unless all is set, the entries to remove must be narrowed down with at least one of the other arguments. This can't be called from within a module.
+   * @param opts.all Remove every entry that isn't in use.
+   * @param opts.filters Only remove entries matching all of the filters (e.g., "type==exec.cachemount").
+   * @param opts.keepBytes Keep entries until the cache uses at most this many bytes.
+   * @param opts.olderThan Only remove entries that were not used in this many seconds.
+   * @param opts.useDefaultPolicy Apply the engine's garbage collection policy instead of the other arguments.
+   */
+  prune = async (opts?: EngineCachePruneOpts): Promise<EngineCacheEntry[]> => {
+    type prune = {
+      id: EngineCacheEntryID
+    }
+
+    const response: Awaited<prune[]> = await computeQuery(
+      [
+        ...this._queryTree,
+        {
+          operation: "prune",
+          args: { ...opts },
+        },
+        {
+          operation: "id",
+        },
+      ],
+      await this._ctx.connection(),
+    )
+
+    return response.map(
+      (r) =>
+        new EngineCacheEntry(
+          {
+            queryTree: [
+              {
+                operation: "loadEngineCacheEntryFromID",
+                args: { id: r.id },
+              },
+            ],
+            ctx: this._ctx,
+          },
+          r.id,
+        ),
+    )
+  }
+}
+
+/**
+ * An entry in the local cache of the Dagger engine.
+ */
+export class EngineCacheEntry extends BaseClient {
+  private readonly _id?: EngineCacheEntryID = undefined
+  private readonly _activelyUsed?: boolean = undefined
+  private readonly _createdTime?: number = undefined
+  private readonly _description?: string = undefined
+  private readonly _diskSpaceBytes?: number = undefined
+  private readonly _mostRecentUseTime?: number = undefined
+  private readonly _recordType?: string = undefined
+  private readonly _shared?: boolean = undefined
+
+  /**
+   * Constructor is used for internal usage only, do not create object from it.
+   */
+  constructor(
+    parent?: { queryTree?: QueryTree[]; ctx: Context },
+    _id?: EngineCacheEntryID,
+    _activelyUsed?: boolean,
+    _createdTime?: number,
+    _description?: string,
+    _diskSpaceBytes?: number,
+    _mostRecentUseTime?: number,
+    _recordType?: string,
+    _shared?: boolean,
+  ) {
+    super(parent)
+
+    this._id = _id
+    this._activelyUsed = _activelyUsed
+    this._createdTime = _createdTime
+    this._description = _description
+    this._diskSpaceBytes = _diskSpaceBytes
+    this._mostRecentUseTime = _mostRecentUseTime
+    this._recordType = _recordType
+    this._shared = _shared
+  }
+
+  /**
+   * A unique identifier for this EngineCacheEntry.
+   */
+  id = async (): Promise<EngineCacheEntryID> => {
+    if (this._id) {
+      return this._id
+    }
+
+    const response: Awaited<EngineCacheEntryID> = await computeQuery(
+      [
+        ...this._queryTree,
+        {
+          operation: "id",
+        },
+      ],
+      await this._ctx.connection(),
+    )
+
+    return response
+  }
+
+  /**
+   * Whether the cache entry is in use, in which case it can't be pruned.
+   */
+  activelyUsed = async (): Promise<boolean> => {
+    if (this._activelyUsed) {
+      return this._activelyUsed
+    }
+
+    const response: Awaited<boolean> = await computeQuery(
+      [
+        ...this._queryTree,
+        {
+          operation: "activelyUsed",
+        },
+      ],
+      await this._ctx.connection(),
+    )
+
+    return response
+  }
+
+  /**
+   * The time the cache entry was created, in seconds following Unix epoch.
+   */
+  createdTime = async (): Promise<number> => {
+    if (this._createdTime) {
+      return this._createdTime
+    }
+
+    const response: Awaited<number> = await computeQuery(
+      [
+        ...this._queryTree,
+        {
+          operation: "createdTime",
+        },
+      ],
+      await this._ctx.connection(),
+    )
+
+    return response
+  }
+
+  /**
+   * The description of the cache entry.
+   */
+  description = async (): Promise<string> => {
+    if (this._description) {
+      return this._description
+    }
+
+    const response: Awaited<string> = await computeQuery(
+      [
+        ...this._queryTree,
+        {
+          operation: "description",
+        },
+      ],
+      await this._ctx.connection(),
+    )
+
+    return response
+  }
+
+  /**
+   * The disk space used by the cache entry, in bytes.
+   */
+  diskSpaceBytes = async (): Promise<number> => {
+    if (this._diskSpaceBytes) {
+      return this._diskSpaceBytes
+    }
+
+    const response: Awaited<number> = await computeQuery(
+      [
+        ...this._queryTree,
+        {
+          operation: "diskSpaceBytes",
+        },
+      ],
+      await this._ctx.connection(),
+    )
+
+    return response
+  }
+
+  /**
+   * The time the cache entry was last used, in seconds following Unix epoch.
+   */
+  mostRecentUseTime = async (): Promise<number> => {
+    if (this._mostRecentUseTime) {
+      return this._mostRecentUseTime
+    }
+
+    const response: Awaited<number> = await computeQuery(
+      [
+        ...this._queryTree,
+        {
+          operation: "mostRecentUseTime",
+        },
+      ],
+      await this._ctx.connection(),
+    )
+
+    return response
+  }
+
+  /**
+   * The type of the cache entry (e.g., "regular", "exec.cachemount", "source.local").
+   */
+  recordType = async (): Promise<string> => {
+    if (this._recordType) {
+      return this._recordType
+    }
+
+    const response: Awaited<string> = await computeQuery(
+      [
+        ...this._queryTree,
+        {
+          operation: "recordType",
+        },
+      ],
+      await this._ctx.connection(),
+    )
+
+    return response
+  }
+
+  /**
+   * Whether the cache entry shares its data with other entries.
+   */
+  shared = async (): Promise<boolean> => {
+    if (this._shared) {
+      return this._shared
+    }
+
+    const response: Awaited<boolean> = await computeQuery(
+      [
+        ...this._queryTree,
+        {
+          operation: "shared",
         },
       ],
-      ctx: this._ctx,
-    })
-  }
+      await this._ctx.connection(),
+    )
 
-  /**
-   * Call the provided function with current Directory.
-   *
-   * This is useful for reusability and readability by not breaking the calling chain.
-   */
-  with = (arg: (param: Directory) => Directory) => {
-    return arg(this)
+    return response
   }
 }
 
@@ -3335,6 +4786,7 @@ export class FieldTypeDef extends BaseClient {
 export class File extends BaseClient {
   private readonly _id?: FileID = undefined
   private readonly _contents?: string = undefined
+  private readonly _digest?: string = undefined
   private readonly _export?: boolean = undefined
   private readonly _name?: string = undefined
   private readonly _size?: number = undefined
@@ -3347,6 +4799,7 @@ export class File extends BaseClient {
     parent?: { queryTree?: QueryTree[]; ctx: Context },
     _id?: FileID,
     _contents?: string,
+    _digest?: string,
     _export?: boolean,
     _name?: string,
     _size?: number,
@@ -3356,6 +4809,7 @@ export class File extends BaseClient {
 
     this._id = _id
     this._contents = _contents
+    this._digest = _digest
     this._export = _export
     this._name = _name
     this._size = _size
@@ -3404,6 +4858,27 @@ export class File extends BaseClient {
     return response
   }
 
+  /**
+   * Returns the SHA-256 digest of the file's contents (e.g., "sha256:...").
+   */
+  digest = async (): Promise<string> => {
+    if (this._digest) {
+      return this._digest
+    }
+
+    const response: Awaited<string> = await computeQuery(
+      [
+        ...this._queryTree,
+        {
+          operation: "digest",
+        },
+      ],
+      await this._ctx.connection(),
+    )
+
+    return response
+  }
+
   /**
    * Writes the file to a file path on the host.
    * @param path Location of the written directory (e.g., "output.txt").
@@ -3428,6 +4903,29 @@ export class File extends BaseClient {
     return response
   }
 
+  /**
+   * Extracts this file as an archive.
+   * @param opts.format File format of the archive.
+   *
+   * If not set, it's detected from the file. Compressed tarballs are detected automatically.
+   */
+  extract = (opts?: FileExtractOpts): Directory => {
+    const metadata: Metadata = {
+      format: { is_enum: true },
+    }
+
+    return new Directory({
+      queryTree: [
+        ...this._queryTree,
+        {
+          operation: "extract",
+          args: { ...opts, __metadata: metadata },
+        },
+      ],
+      ctx: this._ctx,
+    })
+  }
+
   /**
    * Retrieves the name of the file.
    */
@@ -3470,6 +4968,21 @@ export class File extends BaseClient {
     return response
   }
 
+  /**
+   * Retrieves the metadata of the file.
+   */
+  stat = (): Stat => {
+    return new Stat({
+      queryTree: [
+        ...this._queryTree,
+        {
+          operation: "stat",
+        },
+      ],
+      ctx: this._ctx,
+    })
+  }
+
   /**
    * Force evaluation in the engine.
    */
@@ -3487,6 +5000,23 @@ export class File extends BaseClient {
     return this
   }
 
+  /**
+   * Retrieves this file with the given name.
+   * @param name Name to give the file (e.g., "main.go").
+   */
+  withName = (name: string): File => {
+    return new File({
+      queryTree: [
+        ...this._queryTree,
+        {
+          operation: "withName",
+          args: { name },
+        },
+      ],
+      ctx: this._ctx,
+    })
+  }
+
   /**
    * Retrieves this file with its created/modified timestamps set to the given time.
    * @param timestamp Timestamp to set dir/files in.
@@ -3523,6 +5053,8 @@ export class File extends BaseClient {
  */
 export class Function_ extends BaseClient {
   private readonly _id?: FunctionID = undefined
+  private readonly _cachePolicy?: FunctionCachePolicy = undefined
+  private readonly _cacheTimeToLive?: number = undefined
   private readonly _description?: string = undefined
   private readonly _name?: string = undefined
 
@@ -3532,12 +5064,16 @@ export class Function_ extends BaseClient {
   constructor(
     parent?: { queryTree?: QueryTree[]; ctx: Context },
     _id?: FunctionID,
+    _cachePolicy?: FunctionCachePolicy,
+    _cacheTimeToLive?: number,
     _description?: string,
     _name?: string,
   ) {
     super(parent)
 
     this._id = _id
+    this._cachePolicy = _cachePolicy
+    this._cacheTimeToLive = _cacheTimeToLive
     this._description = _description
     this._name = _name
   }
@@ -3601,6 +5137,48 @@ export class Function_ extends BaseClient {
     )
   }
 
+  /**
+   * How the results of calls to the function are cached.
+   */
+  cachePolicy = async (): Promise<FunctionCachePolicy> => {
+    if (this._cachePolicy) {
+      return this._cachePolicy
+    }
+
+    const response: Awaited<FunctionCachePolicy> = await computeQuery(
+      [
+        ...this._queryTree,
+        {
+          operation: "cachePolicy",
+        },
+      ],
+      await this._ctx.connection(),
+    )
+
+    return response
+  }
+
+  /**
+   * How long the results of calls to the function are cached for, in seconds, if its cache policy is TTL_CACHE.
+   */
+  cacheTimeToLive = async (): Promise<number> => {
+    if (this._cacheTimeToLive) {
+      return this._cacheTimeToLive
+    }
+
+    const response: Awaited<number> = await computeQuery(
+      [
+        ...this._queryTree,
+        {
+          operation: "cacheTimeToLive",
+        },
+      ],
+      await this._ctx.connection(),
+    )
+
+    return response
+  }
+
   /**
    * A doc string for the function, if any.
    */
@@ -3682,6 +5260,31 @@ export class Function_ extends BaseClient {
     })
   }
 
+  /**
+   * Returns the function with the given cache policy.
+   * @param policy How the results of calls to the function are cached.
+   * @param opts.timeToLive How long the results are reused for, as a duration (e.g., "10m"). Required for TTL_CACHE.
+   */
+  withCachePolicy = (
+    policy: FunctionCachePolicy,
+    opts?: FunctionWithCachePolicyOpts,
+  ): Function_ => {
+    const metadata: Metadata = {
+      policy: { is_enum: true },
+    }
+
+    return new Function_({
+      queryTree: [
+        ...this._queryTree,
+        {
+          operation: "withCachePolicy",
+          args: { policy, ...opts, __metadata: metadata },
+        },
+      ],
+      ctx: this._ctx,
+    })
+  }
+
   /**
    * Returns the function with the given doc string.
    * @param description The doc string to set.
@@ -6184,6 +7787,23 @@ export class ModuleSource extends BaseClient {
     })
   }
 
+  /**
+   * Load the module source as a dependency of another module, resolving git modules required with version constraints anywhere in its dependency tree to the given versions.
+   * @param versions The resolved versions, each formatted as a git module's symbolic ref and tag separated by @, e.g. https://github.com/org/repo/mod@mod/v1.2.3.
+   */
+  withResolvedDependencyVersions = (versions: string[]): ModuleSource => {
+    return new ModuleSource({
+      queryTree: [
+        ...this._queryTree,
+        {
+          operation: "withResolvedDependencyVersions",
+          args: { versions },
+        },
+      ],
+      ctx: this._ctx,
+    })
+  }
+
   /**
    * Update the module source with a new SDK.
    * @param sdk The SDK to set.
@@ -6218,6 +7838,40 @@ export class ModuleSource extends BaseClient {
     })
   }
 
+  /**
+   * Re-resolve the named configured dependencies from their source ref's version, ignoring their pins.
+   * @param dependencies The names of the dependencies to update.
+   */
+  withUpdateDependencies = (dependencies: string[]): ModuleSource => {
+    return new ModuleSource({
+      queryTree: [
+        ...this._queryTree,
+        {
+          operation: "withUpdateDependencies",
+          args: { dependencies },
+        },
+      ],
+      ctx: this._ctx,
+    })
+  }
+
+  /**
+   * Remove the named dependencies from the module source's dependency list.
+   * @param dependencies The names of the dependencies to remove.
+   */
+  withoutDependencies = (dependencies: string[]): ModuleSource => {
+    return new ModuleSource({
+      queryTree: [
+        ...this._queryTree,
+        {
+          operation: "withoutDependencies",
+          args: { dependencies },
+        },
+      ],
+      ctx: this._ctx,
+    })
+  }
+
   /**
    * Call the provided function with current ModuleSource.
    *
@@ -6632,21 +8286,69 @@ export class Client extends BaseClient {
 
   /**
    * Constructs a cache volume for a given cache key.
+   *
+   * By default, cache volumes constructed by a module are scoped to that module, so that modules using the same key don't share a volume. Cache volumes constructed by the main client are shared.
+   *
+   * Namespaces keep modules from sharing cache volumes by accident, but are not access control: any module can construct a cache volume in another module's namespace, and use the cache volumes other modules give it.
    * @param key A string identifier to target this cache volume (e.g., "modules-cache").
+   * @param opts.namespace A namespace to scope the cache volume to instead of the calling module.
+   *
+   * Cache volumes with the same key and namespace are shared.
+   * @param opts.shared Share the cache volume with every module and client that uses the same key.
    */
-  cacheVolume = (key: string): CacheVolume => {
+  cacheVolume = (key: string, opts?: ClientCacheVolumeOpts): CacheVolume => {
     return new CacheVolume({
       queryTree: [
         ...this._queryTree,
         {
           operation: "cacheVolume",
-          args: { key },
+          args: { key, ...opts },
         },
       ],
       ctx: this._ctx,
     })
   }
 
+  /**
+   * Lists the cache volumes used since the engine started, sorted by namespace and key.
+   *
+   * Modules only see their own cache volumes and shared ones.
+   */
+  cacheVolumes = async (): Promise<CacheVolume[]> => {
+    type cacheVolumes = {
+      id: CacheVolumeID
+    }
+
+    const response: Awaited<cacheVolumes[]> = await computeQuery(
+      [
+        ...this._queryTree,
+        {
+          operation: "cacheVolumes",
+        },
+        {
+          operation: "id",
+        },
+      ],
+      await this._ctx.connection(),
+    )
+
+    return response.map(
+      (r) =>
+        new CacheVolume(
+          {
+            queryTree: [
+              {
+                operation: "loadCacheVolumeFromID",
+                args: { id: r.id },
+              },
+            ],
+            ctx: this._ctx,
+          },
+          r.id,
+        ),
+    )
+  }
+
   /**
    * Checks if the current Dagger Engine is compatible with an SDK's required version.
    * @param version Version required by the SDK.
@@ -6790,6 +8492,21 @@ export class Client extends BaseClient {
     })
   }
 
+  /**
+   * Queries the Dagger engine the client is connected to.
+   */
+  engine = (): Engine => {
+    return new Engine({
+      queryTree: [
+        ...this._queryTree,
+        {
+          operation: "engine",
+        },
+      ],
+      ctx: this._ctx,
+    })
+  }
+
   /**
    * @deprecated Use loadFileFromID instead.
    */
@@ -6884,6 +8601,13 @@ export class Client extends BaseClient {
    * Returns a file containing an http remote url content.
    * @param url HTTP url to get the content from (e.g., "https://docs.dagger.io").
    * @param opts.experimentalServiceHost A service which must be started before the URL is fetched.
+   * @param opts.checksum Expected digest of the content (e.g., "sha256:...").
+   *
+   * The download fails if the content does not match.
+   * @param opts.headers Additional headers to send with the request.
+   * @param opts.authHeader Secret used as the value of the Authorization header (e.g., "Bearer ...").
+   * @param opts.name Name of the downloaded file (defaults to a digest of the URL).
+   * @param opts.permissions Permission given to the downloaded file (e.g., 0600).
    */
   http = (url: string, opts?: ClientHttpOpts): File => {
     return new File({
@@ -6901,12 +8625,60 @@ export class Client extends BaseClient {
   /**
    * Load a CacheVolume from its ID.
    */
-  loadCacheVolumeFromID = (id: CacheVolumeID): CacheVolume => {
-    return new CacheVolume({
+  loadCacheVolumeFromID = (id: CacheVolumeID): CacheVolume => {
+    return new CacheVolume({
+      queryTree: [
+        ...this._queryTree,
+        {
+          operation: "loadCacheVolumeFromID",
+          args: { id },
+        },
+      ],
+      ctx: this._ctx,
+    })
+  }
+
+  /**
+   * Load a Container from its ID.
+   */
+  loadContainerFromID = (id: ContainerID): Container => {
+    return new Container({
+      queryTree: [
+        ...this._queryTree,
+        {
+          operation: "loadContainerFromID",
+          args: { id },
+        },
+      ],
+      ctx: this._ctx,
+    })
+  }
+
+  /**
+   * Load a CurrentModule from its ID.
+   */
+  loadCurrentModuleFromID = (id: CurrentModuleID): CurrentModule => {
+    return new CurrentModule({
+      queryTree: [
+        ...this._queryTree,
+        {
+          operation: "loadCurrentModuleFromID",
+          args: { id },
+        },
+      ],
+      ctx: this._ctx,
+    })
+  }
+
+  /**
+   * Load a Directory from its ID.
+   */
+  loadDirectoryFromID = (id: DirectoryID): Directory => {
+    return new Directory({
       queryTree: [
         ...this._queryTree,
         {
-          operation: "loadCacheVolumeFromID",
+          operation: "loadDirectoryFromID",
           args: { id },
         },
       ],
@@ -6915,14 +8687,14 @@ export class Client extends BaseClient {
   }
 
   /**
-   * Load a Container from its ID.
+   * Load a EngineCacheEntry from its ID.
    */
-  loadContainerFromID = (id: ContainerID): Container => {
-    return new Container({
+  loadEngineCacheEntryFromID = (id: EngineCacheEntryID): EngineCacheEntry => {
+    return new EngineCacheEntry({
       queryTree: [
         ...this._queryTree,
         {
-          operation: "loadContainerFromID",
+          operation: "loadEngineCacheEntryFromID",
           args: { id },
         },
       ],
@@ -6931,14 +8703,14 @@ export class Client extends BaseClient {
   }
 
   /**
-   * Load a CurrentModule from its ID.
+   * Load a EngineCache from its ID.
    */
-  loadCurrentModuleFromID = (id: CurrentModuleID): CurrentModule => {
-    return new CurrentModule({
+  loadEngineCacheFromID = (id: EngineCacheID): EngineCache => {
+    return new EngineCache({
       queryTree: [
         ...this._queryTree,
         {
-          operation: "loadCurrentModuleFromID",
+          operation: "loadEngineCacheFromID",
           args: { id },
         },
       ],
@@ -6947,14 +8719,14 @@ export class Client extends BaseClient {
   }
 
   /**
-   * Load a Directory from its ID.
+   * Load a Engine from its ID.
    */
-  loadDirectoryFromID = (id: DirectoryID): Directory => {
-    return new Directory({
+  loadEngineFromID = (id: EngineID): Engine => {
+    return new Engine({
       queryTree: [
         ...this._queryTree,
         {
-          operation: "loadDirectoryFromID",
+          operation: "loadEngineFromID",
           args: { id },
         },
       ],
@@ -7366,6 +9138,22 @@ export class Client extends BaseClient {
     })
   }
 
+  /**
+   * Load a Stat from its ID.
+   */
+  loadStatFromID = (id: StatID): Stat => {
+    return new Stat({
+      queryTree: [
+        ...this._queryTree,
+        {
+          operation: "loadStatFromID",
+          args: { id },
+        },
+      ],
+      ctx: this._ctx,
+    })
+  }
+
   /**
    * Load a Terminal from its ID.
    */
@@ -7437,6 +9225,7 @@ export class Client extends BaseClient {
   /**
    * Create a new module source instance from a source ref string.
    * @param refString The string ref representation of the module source
+   * @param opts.refPin The pinned version of the module source, e.g. the resolved commit of a git source. If set, the ref's version is not resolved again.
    * @param opts.stable If true, enforce that the source is a stable version for source kinds that support versioning.
    */
   moduleSource = (
@@ -7476,6 +9265,9 @@ export class Client extends BaseClient {
 
   /**
    * Reference a secret by name.
+   *
+   * If a URI is given, the secret's plaintext is fetched by the engine from the matching secret provider each time the secret is used. Secret providers run in the engine, so they must be enabled in its configuration first.
+   * @param opts.uri The URI of the secret in a secret provider, e.g. "vault://secret/data/ci#token", "env://TOKEN", "file:///run/secrets/token", "cmd://gcloud auth print-access-token", "op://vault/item/field" or "sops:///secrets.yaml#db.password".
    */
   secret = (name: string, opts?: ClientSecretOpts): Secret => {
     return new Secret({
@@ -7622,8 +9414,11 @@ export class Secret extends BaseClient {
 export class Service extends BaseClient {
   private readonly _id?: ServiceID = undefined
   private readonly _endpoint?: string = undefined
+  private readonly _exitCode?: number = undefined
   private readonly _hostname?: string = undefined
+  private readonly _logs?: string = undefined
   private readonly _start?: ServiceID = undefined
+  private readonly _status?: ServiceStatus = undefined
   private readonly _stop?: ServiceID = undefined
   private readonly _up?: Void = undefined
 
@@ -7634,8 +9429,11 @@ export class Service extends BaseClient {
     parent?: { queryTree?: QueryTree[]; ctx: Context },
     _id?: ServiceID,
     _endpoint?: string,
+    _exitCode?: number,
     _hostname?: string,
+    _logs?: string,
     _start?: ServiceID,
+    _status?: ServiceStatus,
     _stop?: ServiceID,
     _up?: Void,
   ) {
@@ -7643,8 +9441,11 @@ export class Service extends BaseClient {
 
     this._id = _id
     this._endpoint = _endpoint
+    this._exitCode = _exitCode
     this._hostname = _hostname
+    this._logs = _logs
     this._start = _start
+    this._status = _status
     this._stop = _stop
     this._up = _up
   }
@@ -7698,6 +9499,29 @@ export class Service extends BaseClient {
     return response
   }
 
+  /**
+   * The exit code of the last run of the service.
+   *
+   * Returns an error if the service has not exited yet.
+   */
+  exitCode = async (): Promise<number> => {
+    if (this._exitCode) {
+      return this._exitCode
+    }
+
+    const response: Awaited<number> = await computeQuery(
+      [
+        ...this._queryTree,
+        {
+          operation: "exitCode",
+        },
+      ],
+      await this._ctx.connection(),
+    )
+
+    return response
+  }
+
   /**
    * Retrieves a hostname which can be used by clients to reach this container.
    */
@@ -7719,6 +9543,33 @@ export class Service extends BaseClient {
     return response
   }
 
+  /**
+   * The combined stdout and stderr of the current or last run of the service.
+   * @param opts.since Only return output written after this time.
+   *
+   * Either an RFC 3339 timestamp (e.g., "2024-01-02T15:04:05Z") or a duration relative to now (e.g., "10m").
+   * @param opts.tail Only return this many of the most recent lines.
+   * @param opts.follow Wait for the service to exit before returning its output.
+   */
+  logs = async (opts?: ServiceLogsOpts): Promise<string> => {
+    if (this._logs) {
+      return this._logs
+    }
+
+    const response: Awaited<string> = await computeQuery(
+      [
+        ...this._queryTree,
+        {
+          operation: "logs",
+          args: { ...opts },
+        },
+      ],
+      await this._ctx.connection(),
+    )
+
+    return response
+  }
+
   /**
    * Retrieves the list of ports provided by the service.
    */
@@ -7776,6 +9627,27 @@ export class Service extends BaseClient {
     return this
   }
 
+  /**
+   * The status of the current or last run of the service.
+   */
+  status = async (): Promise<ServiceStatus> => {
+    if (this._status) {
+      return this._status
+    }
+
+    const response: Awaited<ServiceStatus> = await computeQuery(
+      [
+        ...this._queryTree,
+        {
+          operation: "status",
+        },
+      ],
+      await this._ctx.connection(),
+    )
+
+    return response
+  }
+
   /**
    * Stop the service.
    * @param opts.kill Immediately kill the service without waiting for a graceful exit
@@ -7862,6 +9734,238 @@ export class Socket extends BaseClient {
   }
 }
 
+/**
+ * The metadata of a file, directory or symlink.
+ */
+export class Stat extends BaseClient {
+  private readonly _id?: StatID = undefined
+  private readonly _fileType?: FileType = undefined
+  private readonly _gid?: number = undefined
+  private readonly _linkTarget?: string = undefined
+  private readonly _mode?: number = undefined
+  private readonly _mtime?: number = undefined
+  private readonly _name?: string = undefined
+  private readonly _size?: number = undefined
+  private readonly _uid?: number = undefined
+
+  /**
+   * Constructor is used for internal usage only, do not create object from it.
+   */
+  constructor(
+    parent?: { queryTree?: QueryTree[]; ctx: Context },
+    _id?: StatID,
+    _fileType?: FileType,
+    _gid?: number,
+    _linkTarget?: string,
+    _mode?: number,
+    _mtime?: number,
+    _name?: string,
+    _size?: number,
+    _uid?: number,
+  ) {
+    super(parent)
+
+    this._id = _id
+    this._fileType = _fileType
+    this._gid = _gid
+    this._linkTarget = _linkTarget
+    this._mode = _mode
+    this._mtime = _mtime
+    this._name = _name
+    this._size = _size
+    this._uid = _uid
+  }
+
+  /**
+   * A unique identifier for this Stat.
+   */
+  id = async (): Promise<StatID> => {
+    if (this._id) {
+      return this._id
+    }
+
+    const response: Awaited<StatID> = await computeQuery(
+      [
+        ...this._queryTree,
+        {
+          operation: "id",
+        },
+      ],
+      await this._ctx.connection(),
+    )
+
+    return response
+  }
+
+  /**
+   * The type of the entry.
+   */
+  fileType = async (): Promise<FileType> => {
+    if (this._fileType) {
+      return this._fileType
+    }
+
+    const response: Awaited<FileType> = await computeQuery(
+      [
+        ...this._queryTree,
+        {
+          operation: "fileType",
+        },
+      ],
+      await this._ctx.connection(),
+    )
+
+    return response
+  }
+
+  /**
+   * The ID of the group that owns the entry.
+   */
+  gid = async (): Promise<number> => {
+    if (this._gid) {
+      return this._gid
+    }
+
+    const response: Awaited<number> = await computeQuery(
+      [
+        ...this._queryTree,
+        {
+          operation: "gid",
+        },
+      ],
+      await this._ctx.connection(),
+    )
+
+    return response
+  }
+
+  /**
+   * The path a symlink points to, or an empty string for other types of entries.
+   */
+  linkTarget = async (): Promise<string> => {
+    if (this._linkTarget) {
+      return this._linkTarget
+    }
+
+    const response: Awaited<string> = await computeQuery(
+      [
+        ...this._queryTree,
+        {
+          operation: "linkTarget",
+        },
+      ],
+      await this._ctx.connection(),
+    )
+
+    return response
+  }
+
+  /**
+   * The permission bits of the entry, including setuid, setgid and sticky bits (e.g., 0755).
+   */
+  mode = async (): Promise<number> => {
+    if (this._mode) {
+      return this._mode
+    }
+
+    const response: Awaited<number> = await computeQuery(
+      [
+        ...this._queryTree,
+        {
+          operation: "mode",
+        },
+      ],
+      await this._ctx.connection(),
+    )
+
+    return response
+  }
+
+  /**
+   * The last modification time of the entry, in seconds following Unix epoch.
+   */
+  mtime = async (): Promise<number> => {
+    if (this._mtime) {
+      return this._mtime
+    }
+
+    const response: Awaited<number> = await computeQuery(
+      [
+        ...this._queryTree,
+        {
+          operation: "mtime",
+        },
+      ],
+      await this._ctx.connection(),
+    )
+
+    return response
+  }
+
+  /**
+   * The base name of the entry.
+   */
+  name = async (): Promise<string> => {
+    if (this._name) {
+      return this._name
+    }
+
+    const response: Awaited<string> = await computeQuery(
+      [
+        ...this._queryTree,
+        {
+          operation: "name",
+        },
+      ],
+      await this._ctx.connection(),
+    )
+
+    return response
+  }
+
+  /**
+   * The size of the entry, in bytes.
+   */
+  size = async (): Promise<number> => {
+    if (this._size) {
+      return this._size
+    }
+
+    const response: Awaited<number> = await computeQuery(
+      [
+        ...this._queryTree,
+        {
+          operation: "size",
+        },
+      ],
+      await this._ctx.connection(),
+    )
+
+    return response
+  }
+
+  /**
+   * The ID of the user that owns the entry.
+   */
+  uid = async (): Promise<number> => {
+    if (this._uid) {
+      return this._uid
+    }
+
+    const response: Awaited<number> = await computeQuery(
+      [
+        ...this._queryTree,
+        {
+          operation: "uid",
+        },
+      ],
+      await this._ctx.connection(),
+    )
+
+    return response
+  }
+}
+
 /**
  * An interactive terminal that clients can connect to.
  */
@@ -7,6 +7,7 @@ import {
   UnknownDaggerError,
   NotAwaitedRequestError,
   ExecError,
+  ExecTimeoutError,
 } from "../common/errors/index.js"
 import { Metadata, QueryTree } from "./client.gen.js"
 
@@ -205,6 +206,16 @@ export async function compute<T>(
       const msg = e.response.errors?.[0]?.message ?? `API Error`
       const ext = e.response.errors?.[0]?.extensions
 
+      if (ext?._type === "EXEC_TIMEOUT_ERROR") {
+        throw new ExecTimeoutError(msg, {
+          cmd: (ext.cmd as string[]) ?? [],
+          exitCode: (ext.exitCode as number) ?? -1,
+          stdout: (ext.stdout as string) ?? "",
+          stderr: (ext.stderr as string) ?? "",
+          timeout: (ext.timeout as number) ?? 0,
+        })
+      }
+
       if (ext?._type === "EXEC_ERROR") {
         throw new ExecError(msg, {
           cmd: (ext.cmd as string[]) ?? [],
@@ -1,7 +1,12 @@
 import { DaggerSDKError, DaggerSDKErrorOptions } from "./DaggerSDKError.js"
-import { ERROR_CODES, ERROR_NAMES } from "./errors-codes.js"
-
-interface ExecErrorOptions extends DaggerSDKErrorOptions {
+import {
+  ERROR_CODES,
+  ERROR_NAMES,
+  ErrorCodes,
+  ErrorNames,
+} from "./errors-codes.js"
+
+export interface ExecErrorOptions extends DaggerSDKErrorOptions {
   cmd: string[]
   exitCode: number
   stdout: string
@@ -12,8 +17,8 @@ interface ExecErrorOptions extends DaggerSDKErrorOptions {
  *  API error from an exec operation in a pipeline.
  */
 export class ExecError extends DaggerSDKError {
-  name = ERROR_NAMES.ExecError
-  code = ERROR_CODES.ExecError
+  name: ErrorNames = ERROR_NAMES.ExecError
+  code: ErrorCodes = ERROR_CODES.ExecError
 
   /**
    *  The command that caused the error.
@@ -0,0 +1,27 @@
+import { ExecError, ExecErrorOptions } from "./ExecError.js"
+import { ERROR_CODES, ERROR_NAMES } from "./errors-codes.js"
+
+interface ExecTimeoutErrorOptions extends ExecErrorOptions {
+  timeout: number
+}
+
+/**
+ *  API error from an exec operation that ran longer than its timeout.
+ */
+export class ExecTimeoutError extends ExecError {
+  name = ERROR_NAMES.ExecTimeoutError
+  code = ERROR_CODES.ExecTimeoutError
+
+  /**
+   *  The timeout the command exceeded, in seconds.
+   */
+  timeout: number
+
+  /**
+   *  @hidden
+   */
+  constructor(message: string, options: ExecTimeoutErrorOptions) {
+    super(message, options)
+    this.timeout = options.timeout
+  }
+}
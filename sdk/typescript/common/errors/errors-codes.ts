@@ -48,6 +48,11 @@ export const ERROR_CODES = {
    * (@link ExecError}
    */
   ExecError: "D109",
+
+  /**
+   * (@link ExecTimeoutError}
+   */
+  ExecTimeoutError: "D110",
 } as const
 
 type ErrorCodesType = typeof ERROR_CODES
@@ -3,6 +3,7 @@ export { UnknownDaggerError } from "./UnknownDaggerError.js"
 export { DockerImageRefValidationError } from "./DockerImageRefValidationError.js"
 export { EngineSessionConnectParamsParseError } from "./EngineSessionConnectParamsParseError.js"
 export { ExecError } from "./ExecError.js"
+export { ExecTimeoutError } from "./ExecTimeoutError.js"
 export { GraphQLRequestError } from "./GraphQLRequestError.js"
 export { InitEngineSessionBinaryError } from "./InitEngineSessionBinaryError.js"
 export { TooManyNestedObjectsError } from "./TooManyNestedObjectsError.js"
@@ -1,6 +1,7 @@
 import {
   dag,
   Function_,
+  FunctionCachePolicy,
   FunctionWithArgOpts,
   ModuleID,
   TypeDef,
@@ -87,9 +88,31 @@ function addFunction(fct: FunctionTypedef): Function_ {
   return dag
     .function_(fct.alias ?? fct.name, addTypeDef(fct.returnType))
     .withDescription(fct.description)
+    .with(addCachePolicy(fct.cache))
     .with(addArg(fct.args))
 }
 
+/**
+ * Set the cache policy of the function, if any.
+ */
+function addCachePolicy(cache?: string): (fct: Function_) => Function_ {
+  return function (fct: Function_): Function_ {
+    switch (cache) {
+      case undefined:
+        return fct
+      case "pure":
+        return fct.withCachePolicy(FunctionCachePolicy.PureCache)
+      case "session":
+        return fct.withCachePolicy(FunctionCachePolicy.SessionCache)
+      default:
+        // The API validates the duration.
+        return fct.withCachePolicy(FunctionCachePolicy.TtlCache, {
+          timeToLive: cache,
+        })
+    }
+  }
+}
+
 /**
  * Register all arguments in the function.
  */
@@ -11,6 +11,24 @@ export type State = { [property: string]: any }
 
 export type Args = Record<string, unknown>
 
+/**
+ * Options of the @func decorator.
+ */
+export type FuncOptions = {
+  /**
+   * The alias to use for the function when exposed on the API.
+   */
+  alias?: string
+
+  /**
+   * How the results of calls to the function are cached: "pure" to reuse
+   * them across sessions, "session" (the default) to reuse them within a
+   * session only, or a duration (e.g., "10m") to reuse them across sessions
+   * for that long.
+   */
+  cache?: string
+}
+
 /**
  * Datastructures that store the class constructor to allow invoking it
  * from the registry and store method's name.
@@ -62,9 +80,12 @@ export class Registry {
   /**
    * The definition of @func decorator that should be on top of any
    * class' method that must be exposed to the Dagger API.
+   *
+   * @param opts The alias to use for the function when exposed on the API,
+   * or its options.
    */
   func = (
-    alias?: string,
+    opts?: string | FuncOptions,
   ): ((
     target: object,
     propertyKey: string | symbol,
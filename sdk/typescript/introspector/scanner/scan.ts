@@ -11,6 +11,7 @@ import {
 } from "./typeDefs.js"
 import {
   getAlias,
+  getCache,
   isFunction,
   isMainObject,
   isObject,
@@ -248,6 +249,7 @@ function introspectMethod(
     name: methodMetadata.name,
     description: methodMetadata.description,
     alias: getAlias(method, "func"),
+    cache: getCache(method),
     args: methodSignature.params.reduce(
       (
         acc: { [name: string]: FunctionArg },
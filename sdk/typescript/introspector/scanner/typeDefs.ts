@@ -67,6 +67,7 @@ export type FunctionTypedef = {
   name: string
   description: string
   alias?: string
+  cache?: string
   args: { [name: string]: FunctionArg }
   returnType: TypeDef<TypeDefKind>
 }
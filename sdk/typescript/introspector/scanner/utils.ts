@@ -91,6 +91,27 @@ export function isField(property: ts.PropertyDeclaration): boolean {
 export function getAlias(
   elem: ts.HasDecorators,
   kind: "field" | "func",
+): string | undefined {
+  return getDecoratorOption(elem, kind, "alias")
+}
+
+/**
+ * Return the cache policy set on the @func decorator of the given method,
+ * if any.
+ */
+export function getCache(elem: ts.HasDecorators): string | undefined {
+  return getDecoratorOption(elem, "func", "cache")
+}
+
+/**
+ * Return an option of the given decorator, which is either passed as an
+ * object (e.g. `@func({ alias: "foo", cache: "pure" })`) or, for the alias,
+ * as a string (e.g. `@func("foo")`).
+ */
+function getDecoratorOption(
+  elem: ts.HasDecorators,
+  kind: "field" | "func",
+  option: "alias" | "cache",
 ): string | undefined {
   const decorator = ts.getDecorators(elem)?.find((d) => {
     if (ts.isCallExpression(d.expression)) {
@@ -105,11 +126,26 @@ export function getAlias(
   }
 
   const expression = decorator.expression as ts.CallExpression
-  const args = expression.arguments
+  const arg = expression.arguments[0]
+  if (!arg) {
+    return undefined
+  }
+
+  if (ts.isObjectLiteralExpression(arg)) {
+    for (const property of arg.properties) {
+      if (
+        ts.isPropertyAssignment(property) &&
+        property.name.getText() === option
+      ) {
+        return JSON.parse(property.initializer.getText().replace(/'/g, '"'))
+      }
+    }
+
+    return undefined
+  }
 
-  const alias = args[0]?.getText()
-  if (alias) {
-    return JSON.parse(alias.replace(/'/g, '"'))
+  if (option === "alias") {
+    return JSON.parse(arg.getText().replace(/'/g, '"'))
   }
 
   return undefined
@@ -30,6 +30,7 @@ describe("scan static TypeScript", function () {
             helloWorld: {
               name: "helloWorld",
               alias: undefined,
+              cache: undefined,
               returnType: {
                 kind: TypeDefKind.StringKind,
               },
@@ -86,6 +87,7 @@ describe("scan static TypeScript", function () {
             exec: {
               name: "exec",
               alias: undefined,
+              cache: undefined,
               description: "Execute the command and return its result",
               returnType: { kind: TypeDefKind.StringKind },
               args: {
@@ -115,6 +117,7 @@ describe("scan static TypeScript", function () {
             bar: {
               name: "bar",
               alias: undefined,
+              cache: undefined,
               description: "Return Bar object",
               returnType: {
                 kind: TypeDefKind.ObjectKind,
@@ -149,6 +152,7 @@ describe("scan static TypeScript", function () {
             greeting: {
               name: "greeting",
               alias: undefined,
+              cache: undefined,
               returnType: { kind: TypeDefKind.StringKind },
               description: "",
               args: {
@@ -165,6 +169,7 @@ describe("scan static TypeScript", function () {
             helloWorld: {
               name: "helloWorld",
               alias: undefined,
+              cache: undefined,
               returnType: { kind: TypeDefKind.StringKind },
               description: "",
               args: {
@@ -243,6 +248,7 @@ describe("scan static TypeScript", function () {
             base: {
               name: "base",
               alias: undefined,
+              cache: undefined,
               returnType: {
                 kind: TypeDefKind.ObjectKind,
                 name: "Alpine",
@@ -262,6 +268,7 @@ describe("scan static TypeScript", function () {
             install: {
               name: "install",
               alias: undefined,
+              cache: undefined,
               returnType: {
                 kind: TypeDefKind.ObjectKind,
                 name: "Alpine",
@@ -286,6 +293,7 @@ describe("scan static TypeScript", function () {
             exec: {
               name: "exec",
               alias: undefined,
+              cache: undefined,
               returnType: { kind: TypeDefKind.StringKind },
               description: "",
               args: {
@@ -331,6 +339,7 @@ describe("scan static TypeScript", function () {
             helloWorld: {
               name: "helloWorld",
               alias: undefined,
+              cache: undefined,
               returnType: { kind: TypeDefKind.StringKind },
               description: "",
               args: {
@@ -347,6 +356,7 @@ describe("scan static TypeScript", function () {
             isTrue: {
               name: "isTrue",
               alias: undefined,
+              cache: undefined,
               returnType: { kind: TypeDefKind.BooleanKind },
               description: "",
               args: {
@@ -363,6 +373,7 @@ describe("scan static TypeScript", function () {
             add: {
               name: "add",
               alias: undefined,
+              cache: undefined,
               returnType: { kind: TypeDefKind.IntegerKind },
               description: "",
               args: {
@@ -387,6 +398,7 @@ describe("scan static TypeScript", function () {
             sayBool: {
               name: "sayBool",
               alias: undefined,
+              cache: undefined,
               returnType: { kind: TypeDefKind.BooleanKind },
               description: "",
               args: {
@@ -427,6 +439,7 @@ describe("scan static TypeScript", function () {
             helloWorld: {
               name: "helloWorld",
               alias: undefined,
+              cache: undefined,
               returnType: { kind: TypeDefKind.VoidKind },
               description: "",
               args: {
@@ -443,6 +456,7 @@ describe("scan static TypeScript", function () {
             asyncHelloWorld: {
               name: "asyncHelloWorld",
               alias: undefined,
+              cache: undefined,
               returnType: { kind: TypeDefKind.VoidKind },
               description: "",
               args: {
@@ -504,6 +518,7 @@ describe("scan static TypeScript", function () {
             sayHello: {
               name: "sayHello",
               alias: undefined,
+              cache: undefined,
               returnType: {
                 kind: TypeDefKind.StringKind,
               },
@@ -546,6 +561,7 @@ describe("scan static TypeScript", function () {
             fullVariadicStr: {
               name: "fullVariadicStr",
               alias: undefined,
+              cache: undefined,
               returnType: {
                 kind: TypeDefKind.StringKind,
               },
@@ -567,6 +583,7 @@ describe("scan static TypeScript", function () {
             semiVariadicStr: {
               name: "semiVariadicStr",
               alias: undefined,
+              cache: undefined,
               returnType: {
                 kind: TypeDefKind.StringKind,
               },
@@ -596,6 +613,7 @@ describe("scan static TypeScript", function () {
             fullVariadicNum: {
               name: "fullVariadicNum",
               alias: undefined,
+              cache: undefined,
               returnType: {
                 kind: TypeDefKind.IntegerKind,
               },
@@ -617,6 +635,7 @@ describe("scan static TypeScript", function () {
             semiVariadicNum: {
               name: "semiVariadicNum",
               alias: undefined,
+              cache: undefined,
               returnType: {
                 kind: TypeDefKind.IntegerKind,
               },
@@ -702,6 +721,7 @@ describe("scan static TypeScript", function () {
             testBar: {
               name: "bar",
               alias: "testBar",
+              cache: undefined,
               returnType: {
                 kind: TypeDefKind.ObjectKind,
                 name: "Bar",
@@ -712,6 +732,7 @@ describe("scan static TypeScript", function () {
             bar: {
               name: "customBar",
               alias: "bar",
+              cache: undefined,
               returnType: {
                 kind: TypeDefKind.ObjectKind,
                 name: "Bar",
@@ -739,6 +760,7 @@ describe("scan static TypeScript", function () {
             greet: {
               name: "helloWorld",
               alias: "greet",
+              cache: undefined,
               returnType: {
                 kind: TypeDefKind.StringKind,
               },
@@ -757,6 +779,7 @@ describe("scan static TypeScript", function () {
             customGreet: {
               name: "customHelloWorld",
               alias: "customGreet",
+              cache: undefined,
               returnType: {
                 kind: TypeDefKind.StringKind,
               },
@@ -775,6 +798,7 @@ describe("scan static TypeScript", function () {
             version: {
               name: "displayVersion",
               alias: "version",
+              cache: undefined,
               returnType: {
                 kind: TypeDefKind.StringKind,
               },
@@ -826,6 +850,7 @@ describe("scan static TypeScript", function () {
             zoo: {
               name: "za",
               alias: "zoo",
+              cache: undefined,
               returnType: {
                 kind: TypeDefKind.StringKind,
               },
@@ -838,6 +863,53 @@ describe("scan static TypeScript", function () {
       functions: {},
     }
 
+    assert.deepEqual(result, expected)
+  })
+  it("Should correctly scan cache policies", async function () {
+    const files = await listFiles(`${rootDirectory}/cache`)
+
+    const result = scan(files, "Cache")
+    const expected: ScanResult = {
+      module: {
+        description: undefined,
+      },
+      classes: {
+        Cache: {
+          name: "Cache",
+          description: "",
+          fields: {},
+          constructor: undefined,
+          methods: {
+            pure: {
+              name: "pure",
+              alias: undefined,
+              cache: "pure",
+              returnType: { kind: TypeDefKind.StringKind },
+              description: "",
+              args: {},
+            },
+            ttl: {
+              name: "fresh",
+              alias: "ttl",
+              cache: "10m",
+              returnType: { kind: TypeDefKind.StringKind },
+              description: "",
+              args: {},
+            },
+            session: {
+              name: "session",
+              alias: undefined,
+              cache: undefined,
+              returnType: { kind: TypeDefKind.StringKind },
+              description: "",
+              args: {},
+            },
+          },
+        },
+      },
+      functions: {},
+    }
+
     assert.deepEqual(result, expected)
   })
 })
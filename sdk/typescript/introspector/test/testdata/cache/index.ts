@@ -0,0 +1,19 @@
+import { func, object } from '../../../decorators/decorators.js'
+
+@object()
+export class Cache {
+    @func({ cache: "pure" })
+    pure(): string {
+        return "pure"
+    }
+
+    @func({ alias: "ttl", cache: "10m" })
+    fresh(): string {
+        return "fresh"
+    }
+
+    @func()
+    session(): string {
+        return "session"
+    }
+}
@@ -0,0 +1,278 @@
+package telemetry
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/vito/progrock"
+)
+
+// JUnitSuites is the root element of a JUnit XML report.
+type JUnitSuites struct {
+	XMLName  xml.Name     `xml:"testsuites"`
+	Name     string       `xml:"name,attr,omitempty"`
+	Tests    int          `xml:"tests,attr"`
+	Failures int          `xml:"failures,attr"`
+	Errors   int          `xml:"errors,attr"`
+	Skipped  int          `xml:"skipped,attr"`
+	Time     float64      `xml:"time,attr"`
+	Suites   []JUnitSuite `xml:"testsuite"`
+}
+
+// JUnitSuite is a test suite, which corresponds to a pipeline.
+type JUnitSuite struct {
+	Name      string      `xml:"name,attr"`
+	Tests     int         `xml:"tests,attr"`
+	Failures  int         `xml:"failures,attr"`
+	Errors    int         `xml:"errors,attr"`
+	Skipped   int         `xml:"skipped,attr"`
+	Time      float64     `xml:"time,attr"`
+	Timestamp string      `xml:"timestamp,attr,omitempty"`
+	Cases     []JUnitCase `xml:"testcase"`
+}
+
+// JUnitCase is a test case, which corresponds to a vertex.
+type JUnitCase struct {
+	Name      string        `xml:"name,attr"`
+	Classname string        `xml:"classname,attr"`
+	Time      float64       `xml:"time,attr"`
+	Failure   *JUnitFailure `xml:"failure,omitempty"`
+	Error     *JUnitFailure `xml:"error,omitempty"`
+	Skipped   *JUnitSkipped `xml:"skipped,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+	SystemErr string        `xml:"system-err,omitempty"`
+}
+
+type JUnitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr,omitempty"`
+	Text    string `xml:",chardata"`
+}
+
+type JUnitSkipped struct {
+	Message string `xml:"message,attr,omitempty"`
+}
+
+// JUnit collects Progrock events into a JUnit XML report. Pipelines are
+// mapped to test suites and vertices to test cases, with their durations,
+// errors and logs.
+type JUnit struct {
+	pipeliner *Pipeliner
+
+	// logs stores the output of each vertex, by stream
+	logs map[string]map[progrock.LogStream]*bytes.Buffer
+
+	mu sync.Mutex
+}
+
+var _ progrock.Writer = (*JUnit)(nil)
+
+func NewJUnit() *JUnit {
+	return &JUnit{
+		pipeliner: NewPipeliner(),
+		logs:      map[string]map[progrock.LogStream]*bytes.Buffer{},
+	}
+}
+
+func (j *JUnit) WriteStatus(ev *progrock.StatusUpdate) error {
+	j.pipeliner.TrackUpdate(ev)
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	for _, l := range ev.Logs {
+		streams, found := j.logs[l.Vertex]
+		if !found {
+			streams = map[progrock.LogStream]*bytes.Buffer{}
+			j.logs[l.Vertex] = streams
+		}
+		buf, found := streams[l.Stream]
+		if !found {
+			buf = new(bytes.Buffer)
+			streams[l.Stream] = buf
+		}
+		buf.Write(l.Data)
+	}
+	return nil
+}
+
+func (j *JUnit) Close() error {
+	return j.pipeliner.Close()
+}
+
+// Report builds the report from the events received so far. Internal
+// vertices are left out.
+func (j *JUnit) Report(name string) *JUnitSuites {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	type suite struct {
+		JUnitSuite
+		started   time.Time
+		completed time.Time
+		starts    []time.Time
+	}
+	suites := map[string]*suite{}
+	var order []*suite
+
+	for _, v := range j.pipeliner.Vertices() {
+		if v.Internal {
+			continue
+		}
+
+		suiteName := name
+		if len(v.Pipelines) > 0 && len(v.Pipelines[0]) > 0 {
+			suiteName = v.Pipelines[0].String()
+		}
+		s, found := suites[suiteName]
+		if !found {
+			s = &suite{JUnitSuite: JUnitSuite{Name: suiteName}}
+			suites[suiteName] = s
+			order = append(order, s)
+		}
+
+		tc := JUnitCase{
+			Name:      v.Name,
+			Classname: suiteName,
+		}
+
+		var started, completed time.Time
+		if v.Started != nil {
+			started = v.Started.AsTime()
+		}
+		if v.Completed != nil {
+			completed = v.Completed.AsTime()
+		}
+		if !started.IsZero() && !completed.IsZero() {
+			tc.Time = completed.Sub(started).Seconds()
+		}
+
+		switch {
+		case v.Error != nil && v.Canceled:
+			tc.Error = &JUnitFailure{Message: "canceled", Text: v.GetError()}
+			s.Errors++
+		case v.Error != nil:
+			tc.Failure = &JUnitFailure{Message: firstLine(v.GetError()), Text: v.GetError()}
+			s.Failures++
+		case v.Canceled:
+			tc.Skipped = &JUnitSkipped{Message: "canceled"}
+			s.Skipped++
+		case started.IsZero() && !v.Cached:
+			tc.Skipped = &JUnitSkipped{Message: "not started"}
+			s.Skipped++
+		case completed.IsZero() && !v.Cached:
+			tc.Error = &JUnitFailure{Message: "did not complete"}
+			s.Errors++
+		}
+
+		if streams, found := j.logs[v.Id]; found {
+			if buf, found := streams[progrock.LogStream_STDOUT]; found {
+				tc.SystemOut = buf.String()
+			}
+			if buf, found := streams[progrock.LogStream_STDERR]; found {
+				tc.SystemErr = buf.String()
+			}
+		}
+
+		s.Tests++
+		s.Cases = append(s.Cases, tc)
+		s.starts = append(s.starts, started)
+		if !started.IsZero() && (s.started.IsZero() || started.Before(s.started)) {
+			s.started = started
+		}
+		if completed.After(s.completed) {
+			s.completed = completed
+		}
+	}
+
+	sort.SliceStable(order, func(a, b int) bool {
+		sa, sb := order[a].started, order[b].started
+		switch {
+		case sa.IsZero() != sb.IsZero():
+			return sb.IsZero()
+		case sa.Equal(sb):
+			return order[a].Name < order[b].Name
+		default:
+			return sa.Before(sb)
+		}
+	})
+
+	report := &JUnitSuites{Name: name}
+	var started, completed time.Time
+	for _, s := range order {
+		sort.Sort(casesByStart{s.Cases, s.starts})
+		if !s.started.IsZero() {
+			s.Timestamp = s.started.UTC().Format(time.RFC3339)
+			if s.completed.After(s.started) {
+				s.Time = s.completed.Sub(s.started).Seconds()
+			}
+			if started.IsZero() || s.started.Before(started) {
+				started = s.started
+			}
+		}
+		if s.completed.After(completed) {
+			completed = s.completed
+		}
+
+		report.Tests += s.Tests
+		report.Failures += s.Failures
+		report.Errors += s.Errors
+		report.Skipped += s.Skipped
+		report.Suites = append(report.Suites, s.JUnitSuite)
+	}
+	if !started.IsZero() && completed.After(started) {
+		report.Time = completed.Sub(started).Seconds()
+	}
+	return report
+}
+
+// WriteXML writes the report built from the events received so far.
+func (j *JUnit) WriteXML(w io.Writer, name string) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(j.Report(name)); err != nil {
+		return fmt.Errorf("encode junit report: %w", err)
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// casesByStart sorts test cases by their start time, with cases that never
+// started last.
+type casesByStart struct {
+	cases  []JUnitCase
+	starts []time.Time
+}
+
+func (c casesByStart) Len() int { return len(c.cases) }
+
+func (c casesByStart) Less(i, j int) bool {
+	a, b := c.starts[i], c.starts[j]
+	switch {
+	case a.IsZero() != b.IsZero():
+		return b.IsZero()
+	case a.Equal(b):
+		return c.cases[i].Name < c.cases[j].Name
+	default:
+		return a.Before(b)
+	}
+}
+
+func (c casesByStart) Swap(i, j int) {
+	c.cases[i], c.cases[j] = c.cases[j], c.cases[i]
+	c.starts[i], c.starts[j] = c.starts[j], c.starts[i]
+}
+
+func firstLine(s string) string {
+	line, _, _ := strings.Cut(s, "\n")
+	return line
+}
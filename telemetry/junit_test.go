@@ -0,0 +1,72 @@
+package telemetry
+
+import (
+	"bytes"
+	"encoding/xml"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/vito/progrock"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestJUnit(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	at := func(d time.Duration) *timestamppb.Timestamp {
+		return timestamppb.New(start.Add(d))
+	}
+	errMsg := "exit code: 1\nmore details"
+
+	j := NewJUnit()
+	require.NoError(t, j.WriteStatus(&progrock.StatusUpdate{
+		Groups: []*progrock.Group{
+			{Id: "lint", Name: "lint"},
+			{Id: "test", Name: "test"},
+		},
+		Memberships: []*progrock.Membership{
+			{Group: "lint", Vertexes: []string{"golangci"}},
+			{Group: "test", Vertexes: []string{"unit", "integration", "hidden"}},
+		},
+		Vertexes: []*progrock.Vertex{
+			{Id: "integration", Name: "go test ./integration", Started: at(3 * time.Second), Completed: at(5 * time.Second), Error: &errMsg},
+			{Id: "unit", Name: "go test ./...", Started: at(time.Second), Completed: at(3 * time.Second)},
+			{Id: "hidden", Name: "internal", Internal: true},
+			{Id: "golangci", Name: "golangci-lint run", Started: at(0), Completed: at(time.Second), Cached: true},
+			{Id: "orphan", Name: "connect", Started: at(0)},
+		},
+		Logs: []*progrock.VertexLog{
+			{Vertex: "unit", Stream: progrock.LogStream_STDOUT, Data: []byte("ok\n")},
+			{Vertex: "integration", Stream: progrock.LogStream_STDERR, Data: []byte("FAIL\n")},
+		},
+	}))
+
+	var buf bytes.Buffer
+	require.NoError(t, j.WriteXML(&buf, "dagger"))
+
+	var report JUnitSuites
+	require.NoError(t, xml.Unmarshal(buf.Bytes(), &report))
+	require.Equal(t, 4, report.Tests)
+	require.Equal(t, 1, report.Failures)
+	require.Equal(t, 1, report.Errors)
+	require.Equal(t, 5.0, report.Time)
+
+	require.Len(t, report.Suites, 3)
+	require.Equal(t, "dagger", report.Suites[0].Name)
+	require.Equal(t, "did not complete", report.Suites[0].Cases[0].Error.Message)
+	require.Equal(t, "lint", report.Suites[1].Name)
+
+	test := report.Suites[2]
+	require.Equal(t, "test", test.Name)
+	require.Equal(t, 2, test.Tests)
+	require.Equal(t, 4.0, test.Time)
+	require.Equal(t, "2024-01-01T00:00:01Z", test.Timestamp)
+	require.Equal(t, "go test ./...", test.Cases[0].Name)
+	require.Equal(t, 2.0, test.Cases[0].Time)
+	require.Equal(t, "ok\n", test.Cases[0].SystemOut)
+	require.Nil(t, test.Cases[0].Failure)
+	require.Equal(t, "go test ./integration", test.Cases[1].Name)
+	require.Equal(t, "exit code: 1", test.Cases[1].Failure.Message)
+	require.Equal(t, errMsg, test.Cases[1].Failure.Text)
+	require.Equal(t, "FAIL\n", test.Cases[1].SystemErr)
+}